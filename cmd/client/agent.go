@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/keyagent"
+)
+
+// defaultUnlockAgentSocket returns the unlock agent socket path in the user runtime directory,
+// or in the temporary directory if there is none.
+func defaultUnlockAgentSocket() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "gokeeper", "agent.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("gokeeper-%d", os.Getuid()), "agent.sock")
+}
+
+func runAgentCommand(command string, cli *CLI) error {
+	agent := keyagent.NewClient(cli.AgentSocket)
+
+	switch command {
+	case "agent start":
+		l, err := keyagent.Listen(cli.AgentSocket)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(cli.AgentSocket)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		log.Printf("Agent is listening on %s, locks after %s idle.", cli.AgentSocket, cli.Agent.Start.Idle)
+		return keyagent.New(cli.Agent.Start.Idle).Serve(ctx, l)
+
+	case "agent status":
+		status, err := agent.Status()
+		if err != nil {
+			return fmt.Errorf("agent is not running: %w", err)
+		}
+		if len(status.Profiles) == 0 && len(status.Keys) == 0 {
+			fmt.Println("Agent is locked.")
+			return nil
+		}
+		if len(status.Profiles) > 0 {
+			fmt.Printf("Agent is unlocked for %s until %s.\n", strings.Join(status.Profiles, ", "),
+				status.LocksAt.Format(time.TimeOnly))
+		}
+		if len(status.Keys) > 0 {
+			fmt.Printf("Sharing keys are unlocked for %s until %s.\n", strings.Join(status.Keys, ", "),
+				status.LocksAt.Format(time.TimeOnly))
+		}
+		return nil
+
+	case "agent lock":
+		if err := agent.Lock(); err != nil {
+			return err
+		}
+		log.Println("Agent is locked.")
+		return nil
+
+	case "agent stop":
+		if err := agent.Stop(); err != nil {
+			return err
+		}
+		log.Println("Agent is stopped.")
+		return nil
+	}
+	return fmt.Errorf("unknown command %q", command)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/client"
+	"github.com/adettelle/go-keeper/internal/clipboard"
+)
+
+func newClipService(cli *CLI) (*client.ClipService, error) {
+	board, err := clipboard.New(cli.Clipboard)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewClipService(board, cli.ClipTimeout, func(digest string, timeout time.Duration) error {
+		return startClipClear(board.Name(), digest, timeout)
+	}), nil
+}
+
+// startClipClear starts the hidden clip-clear command in the background, detached from the terminal,
+// so that the clipboard is cleared after the command exits.
+// Only the digest of the copied secret is passed, the secret itself does not outlive the command.
+// The digest goes over a stdin pipe rather than the arguments or the environment,
+// which other processes of the user can read.
+func startClipClear(backend, digest string, timeout time.Duration) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	cmd := exec.Command(self, "--clipboard", backend, "clip-clear", "--after", timeout.String())
+	cmd.Stdin = r
+	detach(cmd)
+	err = cmd.Start()
+	r.Close()
+	if err != nil {
+		return err
+	}
+	// the digest is far smaller than the pipe buffer, so the write does not wait for the reader
+	if _, err := io.WriteString(w, digest+"\n"); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
+
+func runClipClear(cli *CLI) error {
+	board, err := clipboard.New(cli.Clipboard)
+	if err != nil {
+		return err
+	}
+	digest, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return err
+	}
+	return client.ClearClipboard(board, strings.TrimSpace(digest), cli.ClipClear.After)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// helperArgs lets the binary be installed as git-credential-<name> or docker-credential-<name>,
+// e.g. by a symlink: git and docker call such helpers with the action as the only argument.
+func helperArgs(args []string) []string {
+	name := filepath.Base(args[0])
+	switch {
+	case strings.HasPrefix(name, "git-credential-"):
+		return append([]string{"git-credential"}, args[1:]...)
+	case strings.HasPrefix(name, "docker-credential-"):
+		return append([]string{"docker-credential"}, args[1:]...)
+	}
+	return args[1:]
+}
+
+// printDockerError reports the error on stdout, where docker reads helper errors from, and exits.
+func printDockerError(err error) {
+	if err != nil {
+		os.Stdout.WriteString(err.Error() + "\n")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/adettelle/go-keeper/internal/passgen"
+)
+
+// GeneratorFlags are the password generator options shared by generate, add-password and update-password.
+type GeneratorFlags struct {
+	Length           int    `help:"Generated password length." default:"20"`
+	NoLower          bool   `help:"Do not use lower case letters."`
+	NoUpper          bool   `help:"Do not use upper case letters."`
+	NoDigits         bool   `help:"Do not use digits."`
+	NoSymbols        bool   `help:"Do not use symbols."`
+	ExcludeAmbiguous bool   `help:"Exclude ambiguous characters like 0/O and 1/l."`
+	Words            int    `help:"Generate diceware passphrase of this number of words instead of characters."`
+	Separator        string `help:"Passphrase words separator." default:"-"`
+	Capitalize       bool   `help:"Capitalize passphrase words."`
+}
+
+// generatePassword generates a password or a passphrase according to the flags
+// and reports its entropy to stderr.
+func generatePassword(flags GeneratorFlags) (string, error) {
+	var (
+		pwd     string
+		entropy float64
+		err     error
+	)
+
+	if flags.Words > 0 {
+		policy := passgen.PassphrasePolicy{
+			Words:      flags.Words,
+			Separator:  flags.Separator,
+			Capitalize: flags.Capitalize,
+		}
+		pwd, err = passgen.GeneratePassphrase(policy)
+		entropy = policy.Entropy()
+	} else {
+		policy := passgen.Policy{
+			Length:           flags.Length,
+			Lower:            !flags.NoLower,
+			Upper:            !flags.NoUpper,
+			Digits:           !flags.NoDigits,
+			Symbols:          !flags.NoSymbols,
+			ExcludeAmbiguous: flags.ExcludeAmbiguous,
+		}
+		pwd, err = passgen.Generate(policy)
+		entropy = policy.Entropy()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(os.Stderr, "Entropy: %.1f bits\n", entropy)
+	return pwd, nil
+}
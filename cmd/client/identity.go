@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/adettelle/go-keeper/internal/client"
+)
+
+// IdentityFieldFlags are the identity document fields, shared by add-identity and update-identity.
+type IdentityFieldFlags struct {
+	Field       map[string]string `help:"Document field as name=value, e.g. number=\"45 01 123456\". Repeatable. See identity-types for the fields."`
+	PromptField []string          `help:"Document field name, the value is prompted for. Repeatable."`
+}
+
+// fields returns the document fields; values of the prompted fields are read as secrets.
+func (f IdentityFieldFlags) fields(secrets *client.SecretReader) (map[string]string, error) {
+	res := maps.Clone(f.Field)
+	if res == nil {
+		res = map[string]string{}
+	}
+	for _, name := range f.PromptField {
+		value, err := secrets.Read(fmt.Sprintf("Field %s", name), "", false)
+		if err != nil {
+			return nil, err
+		}
+		res[name] = value
+	}
+	return res, nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import "github.com/adettelle/go-keeper/pkg/keeperclient"
+
+// ListFlags are the listing filters and pagination shared by passwords, cards, files, identities, ssh keys, notes and search.
+type ListFlags struct {
+	Folder    string   `help:"Show only items of the folder and its subfolders, e.g. work/prod."`
+	Tag       []string `help:"Show only items having the tag. Repeat to require several tags."`
+	Favorites bool     `help:"Show only favorite items."`
+	Search    string   `help:"Show only items with the text in the title, description or a tag." short:"q"`
+	Sort      string   `help:"Sort order: title, -title, created or -created. Favorites always go first." enum:"title,-title,created,-created" default:"title"`
+	Limit     int      `help:"Page size. All items are shown if omitted."`
+	Cursor    string   `help:"Continue the listing from the cursor printed with the previous page."`
+}
+
+func (f ListFlags) options() keeperclient.ListOptions {
+	return keeperclient.ListOptions{
+		Folder:        f.Folder,
+		Tags:          f.Tag,
+		FavoritesOnly: f.Favorites,
+		Search:        f.Search,
+		Sort:          f.Sort,
+		Limit:         f.Limit,
+		Cursor:        f.Cursor,
+	}
+}
+
+// ItemFlags identify an item of any kind.
+type ItemFlags struct {
+	Kind  string `help:"Item kind: password, card, file, identity, sshkey or note." short:"k" enum:"password,card,file,identity,sshkey,note" default:"password"`
+	Title string `help:"Item unique title." short:"t" required:""`
+}
+
+func (f ItemFlags) kind() keeperclient.ItemKind {
+	return keeperclient.ItemKind(f.Kind)
+}
@@ -2,60 +2,166 @@
 package main
 
 import (
-	"crypto/tls"
-	"errors"
+	"context"
 	"fmt"
+	"io"
 	"log"
-	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/99designs/keyring"
+	"github.com/adettelle/go-keeper/cmd/settings"
+	"github.com/adettelle/go-keeper/internal/access"
+	"github.com/adettelle/go-keeper/internal/breach"
 	"github.com/adettelle/go-keeper/internal/client"
+	"github.com/adettelle/go-keeper/internal/client/config"
+	"github.com/adettelle/go-keeper/internal/credhelper"
+	"github.com/adettelle/go-keeper/internal/keyagent"
 	"github.com/adettelle/go-keeper/internal/localstorage"
-	"github.com/adettelle/go-keeper/pkg/cert"
+	"github.com/adettelle/go-keeper/internal/sshkey"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
 	"github.com/alecthomas/kong"
 )
 
 type CLI struct {
+	Profile string `help:"Profile from the client config file. Defaults to the current profile." short:"P" env:"GOKEEPER_PROFILE"`
+
+	// Secrets are prompted for with echo disabled. For scripts they can be read from stdin
+	// or a file descriptor, one per line. Secret flags are refused unless forced.
+	SecretStdin      bool `help:"Read secrets from stdin, one per line, instead of prompting."`
+	SecretFD         int  `help:"Read secrets from the file descriptor, one per line, instead of prompting." name:"secret-fd" default:"-1"`
+	ForceSecretFlags bool `help:"Allow secrets passed as flags (unsafe: they end up in shell history and ps output)."`
+
+	AgentSocket string `help:"Unlock agent socket path." env:"GOKEEPER_AGENT_SOCK" default:"${agent_socket}"`
+	NoAgent     bool   `help:"Do not use the unlock agent, read the token from the keyring every time." env:"GOKEEPER_NO_AGENT"`
+
+	Clipboard   string        `help:"Clipboard backend for --clip: auto, wl-copy, xclip, xsel, pbcopy or clip.exe." env:"GOKEEPER_CLIPBOARD" default:"auto"`
+	ClipTimeout time.Duration `help:"Clear the clipboard after this period if it still holds the copied secret; 0 leaves it." env:"GOKEEPER_CLIP_TIMEOUT" default:"45s"`
+
+	Collection string `help:"Shared collection to work with instead of the personal vault, e.g. acme/staging." short:"C" env:"GOKEEPER_COLLECTION"`
+
+	NoReminders    bool          `help:"Do not print the reminders of expiring cards and passwords due for rotation after commands." env:"GOKEEPER_NO_REMINDERS"`
+	BannerInterval time.Duration `help:"Check reminders and emergency access notifications after commands at most this often." env:"GOKEEPER_BANNER_INTERVAL" default:"15m"`
+
+	// ------------ config ------------
+	Config struct {
+		Show struct {
+		} `cmd:"" help:"Shows config file path and profiles."`
+
+		Set struct {
+			Name       string `help:"Profile name." short:"n" required:""`
+			ServerURL  string `help:"Server address, e.g. keeper.example.com:8080." short:"s"`
+			CACert     string `help:"Path to CA bundle (PEM) to verify the server certificate."`
+			ClientCert string `help:"Path to client TLS certificate (PEM)."`
+			ClientKey  string `help:"Path to client TLS private key (PEM)."`
+			Keyring    string `help:"Keyring backend for tokens: keychain, wincred, secret-service, kwallet, pass, keyctl or file."`
+		} `cmd:"" help:"Creates or updates profile. With no flag the value would not change."`
+
+		Use struct {
+			Name string `help:"Profile name." short:"n" required:""`
+		} `cmd:"" help:"Makes profile current."`
+
+		Delete struct {
+			Name string `help:"Profile name." short:"n" required:""`
+		} `cmd:"" help:"Deletes profile."`
+	} `cmd:"" help:"Manages client config file and server profiles."`
+
+	// ------------ unlock agent ------------
+	Agent struct {
+		Start struct {
+			Idle time.Duration `help:"Forget the tokens after this idle period." default:"15m"`
+		} `cmd:"" help:"Runs the agent until stopped. It caches the tokens read from the keyring, so other commands do not open the keyring every time."`
+
+		Status struct {
+		} `cmd:"" help:"Shows whether the agent is unlocked."`
+
+		Lock struct {
+		} `cmd:"" help:"Makes the agent forget the tokens."`
+
+		Stop struct {
+		} `cmd:"" help:"Stops the agent."`
+	} `cmd:"" help:"Manages the local unlock agent."`
+
 	Register struct {
 		Name           string `help:"User name." short:"n"`
 		Login          string `help:"User login." short:"l"`
-		MasterPassword string `help:"User masterpassword." short:"p"`
+		MasterPassword string `help:"User masterpassword. Prompted if omitted." short:"p"`
 		SignIn         bool   `help:"Sign in now. If present user gets signed in instantly. Otherwise manual login is required (see login command)." short:"s" default:"false"`
 	} `cmd:"" help:"Registration with optional authentication."`
 
 	Login struct {
 		Login    string `help:"User login." short:"l"`
-		Password string `help:"User password." short:"p"`
+		Password string `help:"User password. Prompted if omitted." short:"p"`
 	} `cmd:"" help:"Login."`
 
 	// ------------ password ------------
 	Passwords struct {
-	} `cmd:"" help:"Shows list of passwords."`
+		ListFlags `embed:""`
+	} `cmd:"" help:"Shows list of passwords, favorites first."`
+
+	Generate struct {
+		GeneratorFlags `embed:""`
+	} `cmd:"" help:"Generates random password or diceware passphrase and prints it. Entropy is reported to stderr."`
 
 	AddPassword struct {
-		Password    string `help:"User password." short:"p"`
-		Title       string `help:"Password uniqe title." short:"t"`
-		Description string `help:"Password description." short:"d"`
-	} `cmd:"" help:"Adds password."`
+		Password     string `help:"User password. Prompted if omitted." short:"p"`
+		Title        string `help:"Password uniqe title." short:"t"`
+		Description  string `help:"Password description." short:"d"`
+		Generate     bool   `help:"Generate password instead of prompting for it." short:"g"`
+		BreachCheck  bool   `help:"Warn if the password is found in breaches."`
+		BreachCorpus string `help:"Sorted SHA-1 breached passwords file. The server range API is used if empty." env:"GOKEEPER_BREACH_CORPUS" type:"existingfile"`
+
+		DetailsFlags   `embed:"" group:"Details"`
+		GeneratorFlags `embed:"" group:"Generator"`
+	} `cmd:"" help:"Adds password with optional username, URLs and custom fields."`
 
 	GetPassword struct {
 		Title string `help:"Password uniqe title." short:"t"`
-	} `cmd:"" help:"Retrieves password by unique title. To retrieve it into file, use: command > filename."`
+		Field string `help:"Print the field instead of the password: username, url or a custom field name." short:"f"`
+		Clip  bool   `help:"Copy to the clipboard instead of printing." short:"c"`
+	} `cmd:"" help:"Retrieves password or one of its fields by unique title. With --clip it is copied to the clipboard and cleared after --clip-timeout."`
 
 	// UpdatePassword changes values of password and description by title of password.
 	// With no flag value would not change.
 	// With empty string ("") the value becomes null.
 	UpdatePassword struct {
-		Title       string `help:"Password unique title." short:"t"`
-		Password    string `help:"User password." short:"p"`
-		Description string `help:"Description." short:"d"`
-	} `cmd:"" help:"Updates password and it's description by unique title. With no flag the value would not change."`
+		Title       string   `help:"Password unique title." short:"t"`
+		Password    string   `help:"User password." short:"p"`
+		SetPassword bool     `help:"Prompt for a new password."`
+		Description string   `help:"Description." short:"d"`
+		Generate    bool     `help:"Generate a new password." short:"g"`
+		RemoveURL   []string `help:"Remove the URL. Repeatable." name:"remove-url" sep:"none" group:"Details"`
+		RemoveField []string `help:"Remove the custom field by name. Repeatable." group:"Details"`
+
+		DetailsFlags   `embed:"" group:"Details"`
+		GeneratorFlags `embed:"" group:"Generator"`
+	} `cmd:"" help:"Updates password, it's description and details by unique title. With no flag the value would not change."`
 
 	DeletePassword struct {
 		Title string `help:"Password title." short:"t"`
 	} `cmd:"" help:"Deletes password by unique title."`
 
+	Find struct {
+		URL   string `help:"Site URL, e.g. https://github.com/login." name:"url" required:""`
+		Field string `help:"Print only the field of the best match: password, username, url or a custom field name." short:"f"`
+	} `cmd:"" help:"Finds passwords for the site by registrable domain, host or exact URL, according to the match rule of every password URL."`
+
+	BreachCheck struct {
+		Title        string `help:"Password title. All passwords are checked if omitted." short:"t"`
+		BreachCorpus string `help:"Sorted SHA-1 breached passwords file. The server range API is used if empty." env:"GOKEEPER_BREACH_CORPUS" type:"existingfile"`
+	} `cmd:"" help:"Checks passwords against breached passwords. Passwords are hashed locally, only a hash prefix is sent to the server."`
+
+	Health struct {
+		MaxAge       time.Duration `help:"Report passwords not changed for this long." default:"8760h"`
+		ExpiryWindow time.Duration `help:"Report cards which expire within this period." default:"1440h"`
+	} `cmd:"" help:"Reports weak, reused and old passwords and cards near expiry. Checks are done locally."`
+
+	Reminders struct {
+		Days int `help:"Report cards which expire and passwords due for rotation within this many days. Server default (30) if omitted." default:"-1"`
+	} `cmd:"" help:"Shows expired and expiring cards and passwords due for rotation."`
+
 	// ------------ file ------------
 	AddFile struct {
 		FileName    string `help:"File path." short:"p"`
@@ -68,7 +174,8 @@ type CLI struct {
 	} `cmd:"" help:"Retrieves file by unique title. To retrieve it into file, use: command > filename."`
 
 	Files struct {
-	} `cmd:"" help:"Shows list of added files."`
+		ListFlags `embed:""`
+	} `cmd:"" help:"Shows list of added files, favorites first."`
 
 	UpdateFile struct {
 		Title       string `help:"File unique title." short:"t"`
@@ -82,152 +189,510 @@ type CLI struct {
 
 	// ------------ card ------------
 	Cards struct {
-	} `cmd:"" help:"Shows list of added cards."`
+		ListFlags `embed:""`
+	} `cmd:"" help:"Shows list of added cards, favorites first."`
 
 	AddCard struct {
-		Num         string `help:"Card number, lehgth 16." short:"n"`
-		Expire      string `help:"Date of expire, lehgth 4." short:"e"`
-		Cvc         string `help:"Card cvc, lehgth 3." short:"c"`
+		Num         string `help:"Card number, 12 to 19 digits, spaces allowed." short:"n"`
+		Expire      string `help:"Date of expire, MM/YY." short:"e"`
+		Cvc         string `help:"Card cvc, 3 digits (4 for Amex). Prompted if omitted." short:"c"`
+		Holder      string `help:"Cardholder name as printed on the card."`
+		Address     string `help:"Billing address."`
+		Pin         string `help:"Card PIN."`
+		SetPin      bool   `help:"Prompt for the card PIN."`
 		Title       string `help:"Card title, alphanumeric, min lehgth 4." short:"t"`
 		Description string `help:"Description." short:"d"`
-	} `cmd:"" help:"Adds card."`
+	} `cmd:"" help:"Adds card. Brand is detected by the number."`
 
 	GetCard struct {
 		Title string `help:"Card title." short:"t"`
-	} `cmd:"" help:"Retrieves card's details by unique title. To retrieve it into file, use command > filename"`
+		Field string `help:"Print only the field: number, brand, expiry, cvc, pin, cardholder, address or description." short:"f"`
+		Clip  bool   `help:"Copy the field (number if omitted) to the clipboard instead of printing." short:"c"`
+	} `cmd:"" help:"Retrieves card's details by unique title. With --clip the field is copied to the clipboard and cleared after --clip-timeout."`
 
 	UpdateCard struct {
 		Title       string `help:"Card title." short:"t"`
-		Num         string `help:"Card number, lehgth 16." short:"n"`
-		Expire      string `help:"Date of expire, lehgth 4." short:"e"`
-		Cvc         string `help:"Card cvc, lehgth 3." short:"c"`
+		Num         string `help:"Card number, 12 to 19 digits, spaces allowed." short:"n"`
+		Expire      string `help:"Date of expire, MM/YY." short:"e"`
+		Cvc         string `help:"Card cvc, 3 digits (4 for Amex)." short:"c"`
+		SetCvc      bool   `help:"Prompt for a new card cvc."`
+		Holder      string `help:"Cardholder name as printed on the card."`
+		Address     string `help:"Billing address."`
+		Pin         string `help:"Card PIN."`
+		SetPin      bool   `help:"Prompt for a new card PIN."`
 		Description string `help:"Description." short:"d"`
-	} `cmd:"" help:"Updates card's numbber, date of expire, cvc and description by unique title. With no flag the value would not change."`
+	} `cmd:"" help:"Updates card's numbber, date of expire, cvc, cardholder, address, PIN and description by unique title. With no flag the value would not change."`
 
 	DeleteCard struct {
 		Title string `help:"Card title." short:"t"`
 	} `cmd:"" help:"Deletes card by unique title."`
-}
 
-const service = "gokeeper"
+	// ------------ identity ------------
+	IdentityTypes struct {
+	} `cmd:"" help:"Shows identity document types and their fields."`
 
-func fileExists(filePath string) (bool, error) {
-	if _, err := os.Stat(filePath); err == nil {
-		// path/to/whatever exists
-		return true, nil
+	Identities struct {
+		ListFlags `embed:""`
+	} `cmd:"" help:"Shows list of identity documents, favorites first."`
 
-	} else if errors.Is(err, os.ErrNotExist) {
-		// path/to/whatever does *not* exist
-		return false, nil
+	AddIdentity struct {
+		Type               string `help:"Document type: passport, driver_license, national_id or address." enum:"passport,driver_license,national_id,address" required:""`
+		Title              string `help:"Document unique title." short:"t"`
+		Description        string `help:"Description." short:"d"`
+		IdentityFieldFlags `embed:""`
+	} `cmd:"" help:"Adds identity document. Fields are checked by the document type: dates are YYYY-MM-DD, countries are ISO 3166-1 alpha-2 codes."`
 
-	} else {
-		// Schrodinger: file may or may not exist. See err for details.
+	GetIdentity struct {
+		Title string `help:"Document title." short:"t"`
+	} `cmd:"" help:"Retrieves identity document fields by unique title."`
 
-		// Therefore, do *NOT* use !os.IsNotExist(err) to test for file existence
-		return false, err
+	UpdateIdentity struct {
+		Title              string   `help:"Document title." short:"t"`
+		Description        string   `help:"Description." short:"d"`
+		RemoveField        []string `help:"Remove the field by name. Repeatable."`
+		IdentityFieldFlags `embed:""`
+	} `cmd:"" help:"Sets and removes identity document fields by unique title. Other fields would not change."`
 
-	}
-}
+	DeleteIdentity struct {
+		Title string `help:"Document title." short:"t"`
+	} `cmd:"" help:"Deletes identity document by unique title."`
 
-type keysPath struct {
-	cert       string
-	privateKey string
-}
+	// ------------ ssh keys ------------
+	SSHKeys struct {
+		ListFlags `embed:""`
+	} `cmd:"" name:"sshkeys" help:"Shows list of SSH keys with their fingerprints, favorites first."`
 
-func MustInitCerts() keysPath {
-	existsDir, err := fileExists("./keys/")
-	if err != nil {
-		log.Fatal(err)
-	}
-	if !existsDir {
-		err := os.Mkdir("./keys/", 0777)
-		if err != nil {
-			log.Fatal(err)
-		}
-	}
+	AddSSHKey struct {
+		Title       string `help:"SSH key unique title." short:"t" required:""`
+		Description string `help:"Description." short:"d"`
+		Type        string `help:"Type of the generated key: ed25519 or rsa." enum:"ed25519,rsa" default:"ed25519"`
+		Bits        int    `help:"Size of the generated RSA key, 3072 if omitted."`
+		Import      string `help:"Import the private key from the file instead of generating one. The passphrase is prompted for if the key is protected." type:"existingfile"`
+		Comment     string `help:"Public key comment, e.g. ane@laptop. On import defaults to the comment of the .pub file."`
+		Confirm     bool   `help:"Ask for confirmation every time ssh-agent uses the key."`
+	} `cmd:"" name:"add-sshkey" help:"Generates an Ed25519 or RSA key or imports an existing one. The generated public key is printed."`
 
-	pathToClientCert := "./keys/client_cert.pem"
-	pathToClientPrivateKey := "./keys/client_privatekey.pem"
+	GetSSHKey struct {
+		Title  string `help:"SSH key title." short:"t"`
+		Public bool   `help:"Print the public key for authorized_keys instead of the private key."`
+	} `cmd:"" name:"get-sshkey" help:"Retrieves the private key by unique title. To retrieve it into file, use: command > filename."`
 
-	existsCertFile, err := fileExists(pathToClientCert)
-	if err != nil {
-		log.Fatal(err)
-	}
-	existsClientPrivateKey, err := fileExists(pathToClientPrivateKey)
-	if err != nil {
-		log.Fatal(err)
-	}
+	UpdateSSHKey struct {
+		Title       string `help:"SSH key title." short:"t"`
+		Description string `help:"Description." short:"d"`
+		Confirm     bool   `help:"Ask for confirmation every time ssh-agent uses the key." xor:"confirm"`
+		NoConfirm   bool   `help:"Use the key without confirmation." xor:"confirm"`
+	} `cmd:"" name:"update-sshkey" help:"Updates SSH key description and confirmation by unique title. With no flag the value would not change."`
 
-	if !existsCertFile || !existsClientPrivateKey {
-		if existsCertFile {
-			err := os.Remove(pathToClientCert)
-			if err != nil {
-				log.Fatal(err)
-			}
-		}
-		if existsClientPrivateKey {
-			err := os.Remove(pathToClientPrivateKey)
-			if err != nil {
-				log.Fatal(err)
-			}
-		}
-		cert.MustGenCert("client")
-	}
-	return keysPath{cert: pathToClientCert, privateKey: pathToClientPrivateKey}
+	DeleteSSHKey struct {
+		Title string `help:"SSH key title." short:"t"`
+	} `cmd:"" name:"delete-sshkey" help:"Deletes SSH key by unique title."`
+
+	SSHAgent struct {
+		Socket     string   `help:"Unix socket path. Its directory must be private: owned by you with mode 0700." default:"${ssh_agent_socket}"`
+		Title      []string `help:"Serve only the key with the title. Repeatable. All keys are served if omitted." short:"t"`
+		ConfirmAll bool     `help:"Ask for confirmation on every use of any key."`
+	} `cmd:"" name:"ssh-agent" help:"Serves SSH keys over the ssh-agent protocol until interrupted. Set SSH_AUTH_SOCK to the printed socket path in other shells; confirmations are asked in this terminal."`
+
+	// ------------ notes ------------
+	Notes struct {
+		ListFlags `embed:""`
+	} `cmd:"" help:"Shows list of secure notes without their text, favorites first."`
+
+	AddNote struct {
+		Title         string `help:"Note unique title." short:"t" required:""`
+		Description   string `help:"Description." short:"d"`
+		NoteTextFlags `embed:""`
+	} `cmd:"" help:"Adds secure note. The text is read from stdin if neither --text nor --file is given."`
+
+	GetNote struct {
+		Title string `help:"Note title." short:"t"`
+		Field string `help:"Print only the field: text or description." short:"f"`
+		Clip  bool   `help:"Copy the field (text if omitted) to the clipboard instead of printing." short:"c"`
+	} `cmd:"" help:"Retrieves note text by unique title. To retrieve it into file, use: command > filename. With --clip it is copied to the clipboard and cleared after --clip-timeout."`
+
+	UpdateNote struct {
+		Title         string `help:"Note title." short:"t"`
+		Description   string `help:"Description." short:"d"`
+		NoteTextFlags `embed:""`
+	} `cmd:"" help:"Replaces note text and description by unique title. With no flag the value would not change."`
+
+	DeleteNote struct {
+		Title string `help:"Note title." short:"t"`
+	} `cmd:"" help:"Deletes note by unique title."`
+
+	// ------------ secret references ------------
+	Run struct {
+		RunFlags `embed:""`
+		Command  []string `arg:"" passthrough:"" help:"Command and its arguments, after --."`
+	} `cmd:"" help:"Runs the command with secret references resolved into its environment, e.g. run --env DB_PASS=keeper://prod-db/password -- ./migrate. Secrets are not written to disk. Exits with the command exit code."`
+
+	Inject struct {
+		In  string `help:"Template file. Read from stdin if omitted or -." short:"i"`
+		Out string `help:"Output file, written with 0600 permissions. Printed to stdout if omitted." short:"o"`
+	} `cmd:"" help:"Renders the Go text/template with secret references, e.g. {{ keeper://password/prod-db/password }} or {{ secret \"keeper://card/visa/cvc\" }}. Fails without output if any reference is not resolved."`
+
+	// ------------ credential helpers ------------
+	GitCredential struct {
+		Action string `arg:"" help:"Action: get, store or erase."`
+	} `cmd:"" name:"git-credential" help:"Implements the git credential helper protocol. Configure with: git config --global credential.helper '!go-keeper git-credential'."`
+
+	DockerCredential struct {
+		Action string `arg:"" enum:"get,store,erase,list" help:"Action: get, store, erase or list."`
+	} `cmd:"" name:"docker-credential" help:"Implements the docker credential helper protocol. Install the binary or a symlink as docker-credential-gokeeper and set \"credsStore\": \"gokeeper\" in ~/.docker/config.json."`
+
+	ClipClear struct {
+		After time.Duration `help:"Wait this long before clearing." required:""`
+	} `cmd:"" name:"clip-clear" hidden:"" help:"Clears the clipboard later if it still holds the secret with the digest read from stdin."`
+
+	// ------------ terminal UI ------------
+	TUI struct {
+	} `cmd:"" name:"tui" help:"Full-screen interface to browse the vault with fuzzy search, reveal and copy fields, edit entries and download files."`
+
+	// ------------ organizations ------------
+	CreateOrg struct {
+		Name string `arg:"" help:"Organization name: lowercase letters, digits, dots, dashes and underscores."`
+	} `cmd:"" help:"Creates organization with you as its owner."`
+
+	Orgs struct {
+	} `cmd:"" help:"Shows your organizations and your roles in them."`
+
+	DeleteOrg struct {
+		Name string `arg:"" help:"Organization name."`
+	} `cmd:"" help:"Deletes organization. Owners only; its collections must be deleted first."`
+
+	Members struct {
+		Org string `arg:"" help:"Organization name."`
+	} `cmd:"" help:"Shows members of the organization and their roles."`
+
+	SetMember struct {
+		Org   string `arg:"" help:"Organization name."`
+		Login string `help:"User login." short:"l" required:""`
+		Role  string `help:"Role: owner, admin, editor or viewer." enum:"owner,admin,editor,viewer" required:""`
+	} `cmd:"" help:"Adds the user to the organization or changes the member role. Admins grant editor and viewer roles, owners any role."`
+
+	RemoveMember struct {
+		Org   string `arg:"" help:"Organization name."`
+		Login string `help:"User login." short:"l" required:""`
+	} `cmd:"" help:"Removes the member from the organization. Anyone may leave by themselves."`
+
+	CreateCollection struct {
+		Org  string `arg:"" help:"Organization name."`
+		Name string `arg:"" help:"Collection name: lowercase letters, digits, dots, dashes and underscores."`
+	} `cmd:"" help:"Creates shared collection in the organization. Work with its items using --collection org/name."`
+
+	Collections struct {
+		Org string `arg:"" help:"Organization name."`
+	} `cmd:"" help:"Shows collections of the organization."`
+
+	DeleteCollection struct {
+		Org  string `arg:"" help:"Organization name."`
+		Name string `arg:"" help:"Collection name."`
+	} `cmd:"" help:"Deletes empty shared collection."`
+
+	// ------------ sharing between users ------------
+	InitKeys struct {
+		MasterPassword string `help:"User masterpassword to seal the private key with. Prompted if omitted." short:"p"`
+	} `cmd:"" help:"Creates your key pair for sharing items. The private key is kept on the server sealed with your master password and a new account key, which is kept on this device and printed to be written down."`
+
+	ImportAccountKey struct {
+		AccountKey string `help:"Account key printed by init-keys. Prompted if omitted." short:"k"`
+	} `cmd:"" help:"Stores your account key on this device, to open your private key here too."`
+
+	AccountKey struct {
+	} `cmd:"" help:"Prints the account key stored on this device, to be imported on another one."`
+
+	Share struct {
+		ItemFlags `embed:""`
+		With      []string `help:"Login of the user to share with. Repeat for several users." short:"w" required:""`
+	} `cmd:"" help:"Shares item with users, end-to-end encrypted. Share it again to send them its current state."`
+
+	Unshare struct {
+		ItemFlags `embed:""`
+		With      []string `help:"Login of the user to stop sharing with. Repeat for several users; omit to stop sharing with everybody." short:"w"`
+	} `cmd:"" help:"Stops sharing item with users. The item key is rotated for the remaining users."`
+
+	Shares struct {
+	} `cmd:"" help:"Shows items you share and the users you share them with."`
+
+	SharedWithMe struct {
+	} `cmd:"" help:"Shows items other users share with you."`
+
+	GetShared struct {
+		Owner          string `help:"Login of the user who shares the item." short:"o" required:""`
+		ItemFlags      `embed:""`
+		MasterPassword string `help:"User masterpassword to open your private key. Prompted if omitted." short:"p"`
+	} `cmd:"" help:"Shows item another user shares with you."`
+
+	// ------------ secret links ------------
+	ShareLink struct {
+		ItemFlags `embed:""`
+		TTL       *time.Duration `help:"How long the link works, from a minute to a week (1h by default). Not for files: they use the lifetime set on the server."`
+		Views     *int           `help:"How many times the link can be opened, up to 100 (once by default). Not for files: they can be downloaded any number of times."`
+	} `cmd:"" help:"Creates a secret link to the item for anybody, also without an account. The key is only in the link."`
+
+	OpenLink struct {
+		URL string `arg:"" help:"Secret link."`
+	} `cmd:"" help:"Shows the item of a secret link of the server. Uses up one of the link views."`
+
+	// ------------ emergency access ------------
+	AddTrustedContact struct {
+		Login string        `help:"Login of the user to designate." short:"l" required:""`
+		Wait  time.Duration `help:"Waiting period after a request during which you can reject it, from 1h to 2160h." default:"72h"`
+	} `cmd:"" help:"Designates a trusted contact who can request read-only access to your vault in an emergency, or changes its waiting period."`
+
+	UpdateEmergencyKit struct {
+	} `cmd:"" help:"Gives your trusted contacts the current state of your vault. Their kits do not follow later changes otherwise."`
+
+	TrustedContacts struct {
+	} `cmd:"" help:"Shows your trusted contacts and the state of their access."`
+
+	RemoveTrustedContact struct {
+		Login string `help:"Login of the trusted contact." short:"l" required:""`
+	} `cmd:"" help:"Removes trusted contact with its emergency kit."`
+
+	RejectEmergency struct {
+		Login string `help:"Login of the trusted contact." short:"l" required:""`
+	} `cmd:"" help:"Rejects the request of a trusted contact during its waiting period. Granted access ends by removing the contact."`
+
+	EmergencyGrantors struct {
+	} `cmd:"" help:"Shows users who designated you as a trusted contact and the state of your access."`
+
+	RequestEmergency struct {
+		Owner string `help:"Login of the user who designated you." short:"o" required:""`
+	} `cmd:"" help:"Requests emergency access. It is granted after the waiting period unless the user rejects it."`
+
+	EmergencyAccess struct {
+		Owner          string `help:"Login of the user who designated you." short:"o" required:""`
+		MasterPassword string `help:"User masterpassword to open your private key. Prompted if omitted." short:"p"`
+	} `cmd:"" help:"Shows the vault of the user once emergency access is granted."`
+
+	EmergencyEvents struct {
+	} `cmd:"" help:"Shows the history of emergency access to your vault and to the vaults of users who designated you."`
+
+	// ------------ folders, tags and favorites ------------
+	Folders struct {
+	} `cmd:"" help:"Shows folders of all items as a tree."`
+
+	Search struct {
+		Text      string `arg:"" optional:"" help:"Text to find in titles, descriptions and tags."`
+		ListFlags `embed:""`
+	} `cmd:"" help:"Searches passwords, cards and files at once, favorites first."`
+
+	Move struct {
+		ItemFlags `embed:""`
+		Folder    string `help:"Destination folder, e.g. work/prod. Empty moves the item to the root." short:"f"`
+	} `cmd:"" help:"Moves item to another folder."`
+
+	Retag struct {
+		ItemFlags `embed:""`
+		Tag       []string `help:"New tag. Repeat for several tags; omit to remove all tags."`
+	} `cmd:"" help:"Replaces item tags."`
+
+	Favorite struct {
+		ItemFlags `embed:""`
+	} `cmd:"" help:"Marks item as favorite, so that it is listed first."`
+
+	Unfavorite struct {
+		ItemFlags `embed:""`
+	} `cmd:"" help:"Removes item from favorites."`
 }
 
+const service = "gokeeper"
+
 func main() {
-	keyPaths := MustInitCerts()
+	var cli CLI
+	parser := kong.Must(&cli, kong.Vars{
+		"ssh_agent_socket": defaultAgentSocket(),
+		"agent_socket":     defaultUnlockAgentSocket(),
+	})
+	ctx, err := parser.Parse(helperArgs(os.Args))
+	parser.FatalIfErrorf(err)
 
-	cert, err := tls.LoadX509KeyPair(keyPaths.cert, keyPaths.privateKey)
-	if err != nil {
-		fmt.Printf("error in loading key pair: %v", err)
-		log.Fatal(err)
-	}
+	cfgPath, err := config.DefaultPath()
+	AssertNoError(err)
+	cfg, err := config.Load(cfgPath, settings.ServerURL)
+	AssertNoError(err)
 
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: os.Getenv("DEBUG_RUN_INSECURE") == "true",
-			//RootCAs:      caCertPool,
-			Certificates: []tls.Certificate{cert},
-		},
+	// commands that do not talk to the server
+	switch {
+	case strings.HasPrefix(ctx.Command(), "config "):
+		AssertNoError(runConfigCommand(ctx.Command(), &cli, cfg, cfgPath))
+		return
+	case strings.HasPrefix(ctx.Command(), "agent "):
+		AssertNoError(runAgentCommand(ctx.Command(), &cli))
+		return
+	case ctx.Command() == "clip-clear":
+		AssertNoError(runClipClear(&cli))
+		return
+	case ctx.Command() == "identity-types":
+		client.PrintIdentityTypes()
+		return
+	case ctx.Command() == "generate":
+		password, err := generatePassword(cli.Generate.GeneratorFlags)
+		AssertNoError(err)
+		fmt.Println(password)
+		return
 	}
 
-	var cli CLI
-	ctx := kong.Parse(&cli)
+	secrets, err := client.NewSecretReader(cli.SecretStdin, cli.SecretFD, cli.ForceSecretFlags)
+	AssertNoError(err)
+
+	profileName, profile, err := cfg.Profile(cli.Profile)
+	AssertNoError(err)
+
+	tlsConfig, err := newTLSConfig(profile)
+	AssertNoError(err)
+
+	backends, err := profile.KeyringBackends()
+	AssertNoError(err)
 
-	keyStore := localstorage.NewKeyStore(&keyring.Config{
+	var keyStore localstorage.IKeyStorage = localstorage.NewKeyStore(&keyring.Config{
 		ServiceName:      service,
-		AllowedBackends:  nil, // []keyring.BackendType{keyring.FileBackend}
+		AllowedBackends:  backends,
 		FilePasswordFunc: keyring.TerminalPrompt,
-		// AllowedBackends: []keyring.BackendType{keyring.KWalletBackend},
-		FileDir: "~/",
+		FileDir:          "~/",
+	}, profileName)
+	accountKeys := localstorage.NewAccountKeyStore(&keyring.Config{
+		ServiceName:      service,
+		AllowedBackends:  backends,
+		FilePasswordFunc: keyring.TerminalPrompt,
+		FileDir:          "~/",
+	}, profileName)
+	var keyCache client.KeyCache
+	if !cli.NoAgent {
+		// токен берётся из агента, если он запущен, иначе из keyring
+		agent := keyagent.NewClient(cli.AgentSocket)
+		keyStore = keyagent.NewStore(agent, profileName, keyStore)
+		keyCache = keyagent.NewKeyCache(agent, profileName)
+	}
+
+	if cli.Collection != "" {
+		_, _, err := access.ParseCollection(cli.Collection)
+		AssertNoError(err)
+	}
+
+	keeperClient, err := keeperclient.New(keeperclient.Config{
+		BaseURL:    profile.ServerURL,
+		TLSConfig:  tlsConfig,
+		Tokens:     keeperclient.TokenFunc(keyStore.Get),
+		Collection: cli.Collection,
 	})
+	AssertNoError(err)
 
-	cardService := client.NewCardService(transport, keyStore)
-	passwordService := client.NewPasswordService(transport, keyStore)
-	fileService := client.NewFileService(transport, keyStore)
-	userService := client.NewUserService(transport, keyStore)
+	cardService := client.NewCardService(keeperClient)
+	passwordService := client.NewPasswordService(keeperClient)
+	fileService := client.NewFileService(keeperClient)
+	userService := client.NewUserService(keeperClient, keyStore)
+	healthService := client.NewHealthService(keeperClient)
+	identityService := client.NewIdentityService(keeperClient)
+	sshKeyService := client.NewSSHKeyService(keeperClient)
+	noteService := client.NewNoteService(keeperClient)
+	itemService := client.NewItemService(keeperClient)
+	reminderService := client.NewReminderService(keeperClient)
+	runService := client.NewRunService(keeperClient)
+	injectService := client.NewInjectService(keeperClient)
+	credentialService := client.NewCredentialService(keeperClient)
+	orgService := client.NewOrgService(keeperClient)
+	shareService := client.NewShareService(keeperClient)
+	linkService := client.NewLinkService(keeperClient)
+	emergencyService := client.NewEmergencyService(keeperClient)
+	shareService.SetAccountKeyStore(accountKeys)
+	emergencyService.SetAccountKeyStore(accountKeys)
+	if keyCache != nil {
+		shareService.SetKeyCache(keyCache)
+		emergencyService.SetKeyCache(keyCache)
+	}
 
 	switch ctx.Command() {
 	case "register":
+		masterPassword, err := secrets.Read("Master password", cli.Register.MasterPassword, true)
+		AssertNoError(err)
 		AssertNoError(userService.Register(cli.Register.Name, cli.Register.Login,
-			cli.Register.MasterPassword, cli.Register.SignIn))
+			masterPassword, cli.Register.SignIn))
 	case "login":
-		AssertNoError(userService.LogIn(cli.Login.Login, cli.Login.Password))
+		password, err := secrets.Read("Password", cli.Login.Password, false)
+		AssertNoError(err)
+		AssertNoError(userService.LogIn(cli.Login.Login, password))
 
 	case "passwords":
-		AssertNoError(passwordService.AllPass())
+		AssertNoError(passwordService.AllPass(cli.Passwords.options()))
 	case "add-password":
-		AssertNoError(passwordService.AddPassword(cli.AddPassword.Password,
-			cli.AddPassword.Title, cli.AddPassword.Description))
+		var password string
+		if cli.AddPassword.Generate {
+			password, err = generatePassword(cli.AddPassword.GeneratorFlags)
+		} else {
+			password, err = secrets.Read("Password", cli.AddPassword.Password, true)
+		}
+		AssertNoError(err)
+		if cli.AddPassword.BreachCheck {
+			breachService, closeCorpus, err := newBreachService(keeperClient, cli.AddPassword.BreachCorpus)
+			AssertNoError(err)
+			err = breachService.WarnIfBreached(password)
+			AssertNoError(closeCorpus())
+			AssertNoError(err)
+		}
+		fields, err := cli.AddPassword.fields(secrets)
+		AssertNoError(err)
+		AssertNoError(passwordService.AddPassword(keeperclient.PwdToAdd{
+			Password:    password,
+			Title:       cli.AddPassword.Title,
+			Description: cli.AddPassword.Description,
+			Username:    cli.AddPassword.Username,
+			URLs:        cli.AddPassword.urls(),
+			Fields:      fields,
+			MaxAgeDays:  max(cli.AddPassword.MaxAgeDays, 0),
+		}))
 	case "get-password":
-		AssertNoError(passwordService.GetPasswordByTitle(cli.GetPassword.Title))
+		if cli.GetPassword.Clip {
+			field := cli.GetPassword.Field
+			if field == "" {
+				field = client.FieldPassword
+			}
+			value, err := passwordService.PasswordField(cli.GetPassword.Title, field)
+			AssertNoError(err)
+			clipService, err := newClipService(&cli)
+			AssertNoError(err)
+			AssertNoError(clipService.Copy(field, value))
+		} else if cli.GetPassword.Field != "" {
+			AssertNoError(passwordService.GetPasswordField(cli.GetPassword.Title, cli.GetPassword.Field))
+		} else {
+			AssertNoError(passwordService.GetPasswordByTitle(cli.GetPassword.Title))
+		}
 	case "update-password":
+		var password string
+		if cli.UpdatePassword.Generate {
+			password, err = generatePassword(cli.UpdatePassword.GeneratorFlags)
+			AssertNoError(err)
+		} else if cli.UpdatePassword.SetPassword || cli.UpdatePassword.Password != "" {
+			password, err = secrets.Read("New password", cli.UpdatePassword.Password, true)
+			AssertNoError(err)
+		}
+		fields, err := cli.UpdatePassword.fields(secrets)
+		AssertNoError(err)
 		AssertNoError(passwordService.UpdatePassword(cli.UpdatePassword.Title,
-			cli.UpdatePassword.Password, cli.UpdatePassword.Description))
+			password, cli.UpdatePassword.Description, client.DetailsChanges{
+				Username:     cli.UpdatePassword.Username,
+				AddURLs:      cli.UpdatePassword.urls(),
+				RemoveURLs:   cli.UpdatePassword.RemoveURL,
+				SetFields:    fields,
+				RemoveFields: cli.UpdatePassword.RemoveField,
+				MaxAgeDays:   cli.UpdatePassword.maxAgeDays(),
+			}))
 	case "delete-password":
 		AssertNoError(passwordService.DeletePasswordByTitle(cli.DeletePassword.Title))
+	case "find":
+		AssertNoError(passwordService.Find(cli.Find.URL, cli.Find.Field))
+	case "breach-check":
+		breachService, closeCorpus, err := newBreachService(keeperClient, cli.BreachCheck.BreachCorpus)
+		AssertNoError(err)
+		err = breachService.CheckPasswords(cli.BreachCheck.Title)
+		AssertNoError(closeCorpus())
+		AssertNoError(err)
+	case "health":
+		AssertNoError(healthService.Report(cli.Health.MaxAge, cli.Health.ExpiryWindow))
+	case "reminders":
+		AssertNoError(reminderService.List(cli.Reminders.Days))
 
 	case "add-file":
 		AssertNoError(fileService.AddFile(cli.AddFile.FileName,
@@ -235,26 +700,321 @@ func main() {
 	case "get-file":
 		AssertNoError(fileService.GetFile(cli.GetFile.Title))
 	case "files":
-		AssertNoError(fileService.AllFiles())
+		AssertNoError(fileService.AllFiles(cli.Files.options()))
 	case "update-file":
 		AssertNoError(fileService.UpdateFile(cli.UpdateFile.Title, cli.UpdateFile.FileName,
-			cli.UpdateFile.Description, cli.UpdatePassword.Description))
+			cli.UpdateFile.Description))
 	case "delete-file":
 		AssertNoError(fileService.DeleteFileByTitle(cli.DeleteFile.Title))
 
 	case "cards":
-		AssertNoError(cardService.AllCards())
+		AssertNoError(cardService.AllCards(cli.Cards.options()))
 	case "add-card":
-		AssertNoError(cardService.AddCard(cli.AddCard.Num, cli.AddCard.Expire,
-			cli.AddCard.Cvc, cli.AddCard.Title, cli.AddCard.Description))
+		cvc, err := secrets.Read("Card cvc", cli.AddCard.Cvc, true)
+		AssertNoError(err)
+		var pin string
+		if cli.AddCard.SetPin || cli.AddCard.Pin != "" {
+			pin, err = secrets.Read("Card PIN", cli.AddCard.Pin, true)
+			AssertNoError(err)
+		}
+		AssertNoError(cardService.AddCard(keeperclient.CardToAdd{
+			Num:         cli.AddCard.Num,
+			Expire:      cli.AddCard.Expire,
+			Cvc:         cvc,
+			Holder:      cli.AddCard.Holder,
+			Address:     cli.AddCard.Address,
+			Pin:         pin,
+			Title:       cli.AddCard.Title,
+			Description: cli.AddCard.Description,
+		}))
 	case "get-card":
-		AssertNoError(cardService.GetCardByTitle(cli.GetCard.Title))
+		switch {
+		case cli.GetCard.Clip:
+			field := cli.GetCard.Field
+			if field == "" {
+				field = client.FieldNumber
+			}
+			value, err := cardService.CardField(cli.GetCard.Title, field)
+			AssertNoError(err)
+			clipService, err := newClipService(&cli)
+			AssertNoError(err)
+			AssertNoError(clipService.Copy(field, value))
+		case cli.GetCard.Field != "":
+			AssertNoError(cardService.GetCardField(cli.GetCard.Title, cli.GetCard.Field))
+		default:
+			AssertNoError(cardService.GetCardByTitle(cli.GetCard.Title))
+		}
 	case "update-card":
-		AssertNoError(cardService.UpdateCard(cli.UpdateCard.Title, cli.UpdateCard.Num, cli.UpdateCard.Expire,
-			cli.UpdateCard.Cvc, cli.UpdateCard.Description))
+		var cvc string
+		if cli.UpdateCard.SetCvc || cli.UpdateCard.Cvc != "" {
+			cvc, err = secrets.Read("New card cvc", cli.UpdateCard.Cvc, true)
+			AssertNoError(err)
+		}
+		var pin string
+		if cli.UpdateCard.SetPin || cli.UpdateCard.Pin != "" {
+			pin, err = secrets.Read("New card PIN", cli.UpdateCard.Pin, true)
+			AssertNoError(err)
+		}
+		AssertNoError(cardService.UpdateCard(cli.UpdateCard.Title, keeperclient.CardToUpdate{
+			Num:         cli.UpdateCard.Num,
+			Expire:      cli.UpdateCard.Expire,
+			Cvc:         cvc,
+			Holder:      cli.UpdateCard.Holder,
+			Address:     cli.UpdateCard.Address,
+			Pin:         pin,
+			Description: cli.UpdateCard.Description,
+		}))
 	case "delete-card":
 		AssertNoError(cardService.DeleteCardByTitle(cli.DeleteCard.Title))
+
+	case "identities":
+		AssertNoError(identityService.AllIdentities(cli.Identities.options()))
+	case "add-identity":
+		fields, err := cli.AddIdentity.fields(secrets)
+		AssertNoError(err)
+		AssertNoError(identityService.AddIdentity(keeperclient.IdentityToAdd{
+			DocType:     keeperclient.DocType(cli.AddIdentity.Type),
+			Title:       cli.AddIdentity.Title,
+			Description: cli.AddIdentity.Description,
+			Fields:      fields,
+		}))
+	case "get-identity":
+		AssertNoError(identityService.GetIdentityByTitle(cli.GetIdentity.Title))
+	case "update-identity":
+		fields, err := cli.UpdateIdentity.fields(secrets)
+		AssertNoError(err)
+		AssertNoError(identityService.UpdateIdentity(cli.UpdateIdentity.Title,
+			cli.UpdateIdentity.Description, fields, cli.UpdateIdentity.RemoveField))
+	case "delete-identity":
+		AssertNoError(identityService.DeleteIdentityByTitle(cli.DeleteIdentity.Title))
+
+	case "sshkeys":
+		AssertNoError(sshKeyService.AllSSHKeys(cli.SSHKeys.options()))
+	case "add-sshkey":
+		key := keeperclient.SSHKeyToAdd{
+			Title:       cli.AddSSHKey.Title,
+			Description: cli.AddSSHKey.Description,
+			Comment:     cli.AddSSHKey.Comment,
+			Confirm:     cli.AddSSHKey.Confirm,
+		}
+		if cli.AddSSHKey.Import != "" {
+			AssertNoError(sshKeyService.ImportSSHKey(key, cli.AddSSHKey.Import, func() (string, error) {
+				return secrets.Read("Key passphrase", "", false)
+			}))
+		} else {
+			AssertNoError(sshKeyService.GenerateSSHKey(key, sshkey.Type(cli.AddSSHKey.Type), cli.AddSSHKey.Bits))
+		}
+	case "get-sshkey":
+		AssertNoError(sshKeyService.GetSSHKey(cli.GetSSHKey.Title, cli.GetSSHKey.Public))
+	case "update-sshkey":
+		var confirm *bool
+		if cli.UpdateSSHKey.Confirm || cli.UpdateSSHKey.NoConfirm {
+			confirm = &cli.UpdateSSHKey.Confirm
+		}
+		AssertNoError(sshKeyService.UpdateSSHKey(cli.UpdateSSHKey.Title, cli.UpdateSSHKey.Description, confirm))
+	case "delete-sshkey":
+		AssertNoError(sshKeyService.DeleteSSHKeyByTitle(cli.DeleteSSHKey.Title))
+	case "ssh-agent":
+		AssertNoError(sshKeyService.ServeAgent(cli.SSHAgent.Socket, cli.SSHAgent.Title,
+			cli.SSHAgent.ConfirmAll, client.TerminalConfirm))
+
+	case "notes":
+		AssertNoError(noteService.AllNotes(cli.Notes.options()))
+	case "add-note":
+		text, err := cli.AddNote.text(true)
+		AssertNoError(err)
+		AssertNoError(noteService.AddNote(keeperclient.NoteToAdd{
+			Title:       cli.AddNote.Title,
+			Description: cli.AddNote.Description,
+			Text:        *text,
+		}))
+	case "get-note":
+		switch {
+		case cli.GetNote.Clip:
+			field := cli.GetNote.Field
+			if field == "" {
+				field = client.FieldText
+			}
+			value, err := noteService.NoteField(cli.GetNote.Title, field)
+			AssertNoError(err)
+			clipService, err := newClipService(&cli)
+			AssertNoError(err)
+			AssertNoError(clipService.Copy(field, value))
+		case cli.GetNote.Field != "":
+			AssertNoError(noteService.GetNoteField(cli.GetNote.Title, cli.GetNote.Field))
+		default:
+			AssertNoError(noteService.GetNoteByTitle(cli.GetNote.Title))
+		}
+	case "update-note":
+		text, err := cli.UpdateNote.text(false)
+		AssertNoError(err)
+		AssertNoError(noteService.UpdateNote(cli.UpdateNote.Title, cli.UpdateNote.Description, text))
+	case "delete-note":
+		AssertNoError(noteService.DeleteNoteByTitle(cli.DeleteNote.Title))
+
+	case "run <command>":
+		vars, err := cli.Run.vars()
+		AssertNoError(err)
+		// passthrough keeps the -- separator in the arguments
+		command := cli.Run.Command
+		if len(command) > 0 && command[0] == "--" {
+			command = command[1:]
+		}
+		code, err := runService.Run(vars, command)
+		AssertNoError(err)
+		// the reminder banner is not printed, so that it does not mix with the command output
+		os.Exit(code)
+	case "inject":
+		AssertNoError(injectService.Inject(cli.Inject.In, cli.Inject.Out))
+
+	case "git-credential <action>":
+		AssertNoError(credhelper.Git(context.Background(), cli.GitCredential.Action,
+			os.Stdin, os.Stdout, credentialService))
+	case "docker-credential <action>":
+		printDockerError(credhelper.Docker(context.Background(), cli.DockerCredential.Action,
+			os.Stdin, os.Stdout, credentialService))
+
+	case "tui":
+		// the interface works without a clipboard, copying just reports an error
+		clipService, _ := newClipService(&cli)
+		AssertNoError(client.NewVaultService(keeperClient, clipService).RunTUI())
+
+	case "create-org <name>":
+		AssertNoError(orgService.CreateOrg(cli.CreateOrg.Name))
+	case "orgs":
+		AssertNoError(orgService.Orgs())
+	case "delete-org <name>":
+		AssertNoError(orgService.DeleteOrg(cli.DeleteOrg.Name))
+	case "members <org>":
+		AssertNoError(orgService.Members(cli.Members.Org))
+	case "set-member <org>":
+		AssertNoError(orgService.SetMember(cli.SetMember.Org, cli.SetMember.Login, cli.SetMember.Role))
+	case "remove-member <org>":
+		AssertNoError(orgService.RemoveMember(cli.RemoveMember.Org, cli.RemoveMember.Login))
+	case "create-collection <org> <name>":
+		AssertNoError(orgService.CreateCollection(cli.CreateCollection.Org, cli.CreateCollection.Name))
+	case "collections <org>":
+		AssertNoError(orgService.Collections(cli.Collections.Org))
+	case "delete-collection <org> <name>":
+		AssertNoError(orgService.DeleteCollection(cli.DeleteCollection.Org, cli.DeleteCollection.Name))
+
+	case "init-keys":
+		masterPassword, err := secrets.Read("Master password", cli.InitKeys.MasterPassword, true)
+		AssertNoError(err)
+		AssertNoError(shareService.InitKeys(masterPassword))
+	case "import-account-key":
+		accountKey, err := secrets.Read("Account key", cli.ImportAccountKey.AccountKey, false)
+		AssertNoError(err)
+		AssertNoError(shareService.ImportAccountKey(accountKey))
+	case "account-key":
+		AssertNoError(shareService.ShowAccountKey())
+	case "share":
+		AssertNoError(assertPersonalVault(&cli))
+		AssertNoError(shareService.Share(cli.Share.kind(), cli.Share.Title, cli.Share.With))
+	case "unshare":
+		AssertNoError(assertPersonalVault(&cli))
+		AssertNoError(shareService.Unshare(cli.Unshare.kind(), cli.Unshare.Title, cli.Unshare.With))
+	case "shares":
+		AssertNoError(shareService.Shares())
+	case "shared-with-me":
+		AssertNoError(shareService.SharedWithMe())
+	case "get-shared":
+		masterPassword := func() (string, error) {
+			return secrets.Read("Master password", cli.GetShared.MasterPassword, false)
+		}
+		AssertNoError(shareService.GetShared(cli.GetShared.Owner, cli.GetShared.kind(), cli.GetShared.Title, masterPassword))
+
+	case "share-link":
+		AssertNoError(linkService.ShareLink(cli.ShareLink.kind(), cli.ShareLink.Title, cli.ShareLink.TTL, cli.ShareLink.Views))
+	case "open-link <url>":
+		AssertNoError(linkService.OpenLink(cli.OpenLink.URL))
+
+	case "add-trusted-contact":
+		AssertNoError(assertPersonalVault(&cli))
+		AssertNoError(emergencyService.AddContact(cli.AddTrustedContact.Login, cli.AddTrustedContact.Wait))
+	case "update-emergency-kit":
+		AssertNoError(assertPersonalVault(&cli))
+		AssertNoError(emergencyService.UpdateKits())
+	case "trusted-contacts":
+		AssertNoError(emergencyService.Contacts())
+	case "remove-trusted-contact":
+		AssertNoError(emergencyService.RemoveContact(cli.RemoveTrustedContact.Login))
+	case "reject-emergency":
+		AssertNoError(emergencyService.Reject(cli.RejectEmergency.Login))
+	case "emergency-grantors":
+		AssertNoError(emergencyService.Grantors())
+	case "request-emergency":
+		AssertNoError(emergencyService.Request(cli.RequestEmergency.Owner))
+	case "emergency-access":
+		masterPassword := func() (string, error) {
+			return secrets.Read("Master password", cli.EmergencyAccess.MasterPassword, false)
+		}
+		AssertNoError(emergencyService.Access(cli.EmergencyAccess.Owner, masterPassword))
+	case "emergency-events":
+		AssertNoError(emergencyService.Events())
+
+	case "folders":
+		AssertNoError(itemService.AllFolders())
+	case "search", "search <text>":
+		opts := cli.Search.options()
+		if cli.Search.Text != "" {
+			opts.Search = cli.Search.Text
+		}
+		AssertNoError(itemService.Search(opts))
+	case "move":
+		AssertNoError(itemService.Move(cli.Move.kind(), cli.Move.Title, cli.Move.Folder))
+	case "retag":
+		AssertNoError(itemService.Retag(cli.Retag.kind(), cli.Retag.Title, cli.Retag.Tag))
+	case "favorite":
+		AssertNoError(itemService.SetFavorite(cli.Favorite.kind(), cli.Favorite.Title, true))
+	case "unfavorite":
+		AssertNoError(itemService.SetFavorite(cli.Unfavorite.kind(), cli.Unfavorite.Title, false))
+	}
+
+	switch ctx.Command() {
+	case "register", "login", "reminders", "ssh-agent", "inject", "open-link <url>", "import-account-key", "account-key",
+		"git-credential <action>", "docker-credential <action>":
+	default:
+		// emergency access notifications are not muted by --no-reminders
+		banners := []func(io.Writer){emergencyService.Banner}
+		if !cli.NoReminders {
+			banners = append(banners, reminderService.Banner)
+		}
+		dir, err := config.Dir()
+		if err != nil {
+			return
+		}
+		client.NewBanners(filepath.Join(dir, "banner-"+profileName), cli.BannerInterval).Show(os.Stderr, banners...)
+	}
+}
+
+// defaultAgentSocket returns the ssh-agent socket path next to the unlock agent socket,
+// in the private directory of the client.
+func defaultAgentSocket() string {
+	return filepath.Join(filepath.Dir(defaultUnlockAgentSocket()), "ssh-agent.sock")
+}
+
+// newBreachService creates a breach service checking passwords against the local corpus file
+// or, if corpusPath is empty, with the server range API. The returned function closes the corpus.
+func newBreachService(keeperClient *keeperclient.Client, corpusPath string) (*client.BreachService, func() error, error) {
+	if corpusPath == "" {
+		return client.NewBreachService(keeperClient, client.RemoteBreachChecker(keeperClient)),
+			func() error { return nil }, nil
+	}
+	corpus, err := breach.Open(corpusPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client.NewBreachService(keeperClient, corpus), corpus.Close, nil
+}
+
+// assertPersonalVault refuses to share items of shared collections: their members already have access,
+// and a share belongs to a user.
+func assertPersonalVault(cli *CLI) error {
+	if cli.Collection != "" {
+		return fmt.Errorf("only items of the personal vault can be shared, omit --collection")
 	}
+	return nil
 }
 
 func AssertNoError(err error) {
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"io"
+	"os"
+)
+
+// NoteTextFlags are the sources of the note text, shared by add-note and update-note.
+type NoteTextFlags struct {
+	Text string `help:"Note text. Prefer --file or stdin, so that the text does not stay in the shell history." xor:"text"`
+	File string `help:"Read the note text from the file." type:"existingfile" xor:"text"`
+}
+
+// text returns the note text from the flags. With fromStdin it is read from stdin if no flag is given,
+// otherwise nil is returned.
+func (f NoteTextFlags) text(fromStdin bool) (*string, error) {
+	switch {
+	case f.Text != "":
+		return &f.Text, nil
+	case f.File != "":
+		data, err := os.ReadFile(f.File)
+		if err != nil {
+			return nil, err
+		}
+		text := string(data)
+		return &text, nil
+	case fromStdin:
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		text := string(data)
+		return &text, nil
+	}
+	return nil, nil
+}
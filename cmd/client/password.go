@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/adettelle/go-keeper/internal/client"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+)
+
+// DetailsFlags are the username, URLs and custom fields of a password entry,
+// shared by add-password and update-password.
+type DetailsFlags struct {
+	Username    string            `help:"Login username on the site." short:"u"`
+	URL         []string          `help:"Site URL, optionally prefixed with a match rule: domain (default), host, exact or never, e.g. host=https://mail.example.com. Repeatable." name:"url" sep:"none"`
+	TextField   map[string]string `help:"Custom text field as name=value. Repeatable."`
+	HiddenField []string          `help:"Custom hidden field name, the value is prompted for. Repeatable."`
+	BoolField   map[string]bool   `help:"Custom boolean field as name=true or name=false. Repeatable."`
+	MaxAgeDays  int               `help:"Remind to change the password after this many days, 0 never reminds. Unchanged if omitted." default:"-1"`
+}
+
+// maxAgeDays returns the password max age or nil if the flag is omitted.
+func (f DetailsFlags) maxAgeDays() *int {
+	if f.MaxAgeDays < 0 {
+		return nil
+	}
+	return &f.MaxAgeDays
+}
+
+func (f DetailsFlags) urls() []keeperclient.PasswordURL {
+	var res []keeperclient.PasswordURL
+	for _, u := range f.URL {
+		res = append(res, client.ParsePasswordURL(u))
+	}
+	return res
+}
+
+// fields returns the custom fields in a stable order: text, hidden, then boolean ones, sorted by name.
+// Values of hidden fields are read as secrets.
+func (f DetailsFlags) fields(secrets *client.SecretReader) ([]keeperclient.PasswordField, error) {
+	var res []keeperclient.PasswordField
+	for _, name := range slices.Sorted(maps.Keys(f.TextField)) {
+		res = append(res, keeperclient.PasswordField{Name: name, Type: keeperclient.FieldText, Value: f.TextField[name]})
+	}
+	for _, name := range f.HiddenField {
+		value, err := secrets.Read(fmt.Sprintf("Field %s", name), "", false)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, keeperclient.PasswordField{Name: name, Type: keeperclient.FieldHidden, Value: value})
+	}
+	for _, name := range slices.Sorted(maps.Keys(f.BoolField)) {
+		res = append(res, keeperclient.PasswordField{
+			Name: name, Type: keeperclient.FieldBoolean, Value: strconv.FormatBool(f.BoolField[name]),
+		})
+	}
+	return res, nil
+}
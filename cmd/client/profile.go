@@ -0,0 +1,180 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/adettelle/go-keeper/internal/client/config"
+	"github.com/adettelle/go-keeper/pkg/cert"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+func fileExists(filePath string) (bool, error) {
+	if _, err := os.Stat(filePath); err == nil {
+		// path/to/whatever exists
+		return true, nil
+
+	} else if errors.Is(err, os.ErrNotExist) {
+		// path/to/whatever does *not* exist
+		return false, nil
+
+	} else {
+		// Schrodinger: file may or may not exist. See err for details.
+
+		// Therefore, do *NOT* use !os.IsNotExist(err) to test for file existence
+		return false, err
+
+	}
+}
+
+type keysPath struct {
+	cert       string
+	privateKey string
+}
+
+// MustInitCerts returns paths of the default client certificate and private key
+// stored in keysDir. If any of them is missing, a new pair is generated.
+func MustInitCerts(keysDir string) keysPath {
+	existsDir, err := fileExists(keysDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !existsDir {
+		err := os.MkdirAll(keysDir, 0700)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	pathToClientCert := filepath.Join(keysDir, "client_cert.pem")
+	pathToClientPrivateKey := filepath.Join(keysDir, "client_privatekey.pem")
+
+	existsCertFile, err := fileExists(pathToClientCert)
+	if err != nil {
+		log.Fatal(err)
+	}
+	existsClientPrivateKey, err := fileExists(pathToClientPrivateKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if !existsCertFile || !existsClientPrivateKey {
+		if existsCertFile {
+			err := os.Remove(pathToClientCert)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		if existsClientPrivateKey {
+			err := os.Remove(pathToClientPrivateKey)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		cert.MustGenCertFiles(pathToClientCert, pathToClientPrivateKey)
+	}
+	return keysPath{cert: pathToClientCert, privateKey: pathToClientPrivateKey}
+}
+
+// newTLSConfig builds the TLS configuration for the profile.
+// If the profile has no client certificate, the default one from the client config dir is used.
+func newTLSConfig(profile config.Profile) (*tls.Config, error) {
+	keyPaths := keysPath{cert: profile.ClientCert, privateKey: profile.ClientKey}
+	if keyPaths.cert == "" || keyPaths.privateKey == "" {
+		dir, err := config.Dir()
+		if err != nil {
+			return nil, err
+		}
+		keyPaths = MustInitCerts(filepath.Join(dir, "keys"))
+	}
+
+	clientCert, err := tls.LoadX509KeyPair(keyPaths.cert, keyPaths.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("error in loading key pair: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: os.Getenv("DEBUG_RUN_INSECURE") == "true",
+		Certificates:       []tls.Certificate{clientCert},
+	}
+
+	if profile.CACert != "" {
+		caCert, err := os.ReadFile(profile.CACert)
+		if err != nil {
+			return nil, err
+		}
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in %s", profile.CACert)
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+
+	return tlsConfig, nil
+}
+
+// runConfigCommand executes "config" subcommands which edit the client config file.
+func runConfigCommand(command string, cli *CLI, cfg *config.Config, cfgPath string) error {
+	switch command {
+	case "config show":
+		fmt.Println("Config file:", cfgPath)
+
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"", "Profile", "Server", "CA", "Client cert", "Keyring"})
+
+		for _, name := range cfg.ProfileNames() {
+			p := cfg.Profiles[name]
+			current := ""
+			if name == cfg.Current {
+				current = "*"
+			}
+			t.AppendRow([]interface{}{current, name, p.ServerURL, p.CACert, p.ClientCert, p.Keyring})
+		}
+		t.Render()
+		return nil
+
+	case "config set":
+		p := cfg.Profiles[cli.Config.Set.Name]
+		if cli.Config.Set.ServerURL != "" {
+			p.ServerURL = cli.Config.Set.ServerURL
+		}
+		if cli.Config.Set.CACert != "" {
+			p.CACert = cli.Config.Set.CACert
+		}
+		if cli.Config.Set.ClientCert != "" {
+			p.ClientCert = cli.Config.Set.ClientCert
+		}
+		if cli.Config.Set.ClientKey != "" {
+			p.ClientKey = cli.Config.Set.ClientKey
+		}
+		if cli.Config.Set.Keyring != "" {
+			p.Keyring = cli.Config.Set.Keyring
+		}
+		if err := cfg.SetProfile(cli.Config.Set.Name, p); err != nil {
+			return err
+		}
+
+	case "config use":
+		if err := cfg.Use(cli.Config.Use.Name); err != nil {
+			return err
+		}
+
+	case "config delete":
+		if err := cfg.DeleteProfile(cli.Config.Delete.Name); err != nil {
+			return err
+		}
+	}
+
+	err := cfg.Save(cfgPath)
+	if err != nil {
+		return err
+	}
+	log.Println("Config is saved.")
+	return nil
+}
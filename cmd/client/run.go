@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/adettelle/go-keeper/internal/secretref"
+)
+
+type RunFlags struct {
+	Env     []string `help:"Variable for the command, NAME=value. The value may be a secret reference, e.g. DB_PASS=keeper://prod-db/password. Repeatable." short:"e" sep:"none"`
+	EnvFile []string `help:"Env file with NAME=value lines; values may be secret references. Repeatable; --env takes precedence." type:"existingfile" sep:"none"`
+}
+
+// vars returns the variables of the env files followed by the --env ones, so that the latter win.
+func (f RunFlags) vars() ([]secretref.Var, error) {
+	var vars []secretref.Var
+	for _, path := range f.EnvFile {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		fileVars, err := secretref.ParseEnvFile(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		vars = append(vars, fileVars...)
+	}
+	for _, s := range f.Env {
+		v, err := secretref.ParseVar(s)
+		if err != nil {
+			return nil, err
+		}
+		vars = append(vars, v)
+	}
+	return vars, nil
+}
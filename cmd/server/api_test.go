@@ -7,9 +7,9 @@ import (
 	"testing"
 	"time"
 
-	"github.com/adettelle/go-keeper/internal/client"
 	"github.com/adettelle/go-keeper/internal/database"
 	"github.com/adettelle/go-keeper/internal/server/config"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
 	"github.com/carlmjohnson/requests"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -67,7 +67,7 @@ func Test(t *testing.T) {
 	name := uuid.NewString()
 	login := name + "@google.com"
 
-	customer := client.CustomerToReg{
+	customer := keeperclient.CustomerToReg{
 		Name:           name,
 		Login:          login,
 		MasterPassword: uuid.NewString(),
@@ -86,7 +86,7 @@ func Test(t *testing.T) {
 	require.NoError(t, err)
 
 	// Test login
-	customerToLogin := client.CustomerToLogin{
+	customerToLogin := keeperclient.CustomerToLogin{
 		Login:    login,
 		Password: customer.MasterPassword,
 	}
@@ -106,17 +106,17 @@ func Test(t *testing.T) {
 
 	// ----------------------------------- password -----------------------------------
 	// Test get all passwords, should be empty
-	var pwds []client.PasswordToGet
+	var pwds []keeperclient.PasswordToGet
 
 	getAllPass(t, cfg, []byte(jwtToken), pwds, 0)
 
 	// Test add password
-	pwdToAdd := client.PwdToAdd{
+	pwdToAdd := keeperclient.PwdToAdd{
 		Password:    "password1",
 		Title:       uuid.NewString(),
 		Description: "pass 1",
 	}
-	pwdToAdd2 := client.PwdToAdd{
+	pwdToAdd2 := keeperclient.PwdToAdd{
 		Password:    "password1",
 		Title:       uuid.NewString(),
 		Description: "pass 1",
@@ -134,7 +134,7 @@ func Test(t *testing.T) {
 	getPass(t, cfg, []byte(jwtToken), pwdToGet, pwdToAdd.Title, "password1")
 
 	// Test update password
-	pwdToUpdate := client.PasswordToUpdate{
+	pwdToUpdate := keeperclient.PasswordToUpdate{
 		Password: "newPassword1",
 	}
 
@@ -157,20 +157,20 @@ func Test(t *testing.T) {
 
 	// ----------------------------------- card -----------------------------------
 	// Test get all cards, should be empty
-	var cards []client.CardToGet
+	var cards []keeperclient.CardToGet
 
 	getAllCards(t, cfg, []byte(jwtToken), cards, 0)
 
 	// Test add card
 
-	cardToAdd := client.CardToAdd{
+	cardToAdd := keeperclient.CardToAdd{
 		Num:         "3530111333300000",
 		Expire:      "0131",
 		Cvc:         "111",
 		Title:       RandStringBytes(10),
 		Description: "pass 1",
 	}
-	cardToAdd2 := client.CardToAdd{
+	cardToAdd2 := keeperclient.CardToAdd{
 		Num:         "3566002020360505",
 		Expire:      "0232",
 		Cvc:         "222",
@@ -185,9 +185,11 @@ func Test(t *testing.T) {
 	getAllCards(t, cfg, []byte(jwtToken), cards, 2)
 
 	// Test get card by title
-	expectedCard := client.CardToGetByTitle{
+	// срок действия возвращается в виде MM/YY, платёжная система определяется по номеру
+	expectedCard := keeperclient.CardToGetByTitle{
 		Num:         cardToAdd.Num,
-		Expire:      cardToAdd.Expire,
+		Brand:       "jcb",
+		Expire:      "01/31",
 		Cvc:         cardToAdd.Cvc,
 		Description: cardToAdd.Description,
 	}
@@ -195,14 +197,15 @@ func Test(t *testing.T) {
 	getCard(t, cfg, []byte(jwtToken), cardToAdd.Title, expectedCard)
 
 	// Test update card
-	cardToUpdate := client.CardToUpdate{
+	cardToUpdate := keeperclient.CardToUpdate{
 		Expire:      "0130",
 		Description: "new description",
 	}
 
-	expectedCard2 := client.CardToGetByTitle{
+	expectedCard2 := keeperclient.CardToGetByTitle{
 		Num:         cardToAdd.Num,
-		Expire:      cardToUpdate.Expire,
+		Brand:       "jcb",
+		Expire:      "01/30",
 		Cvc:         cardToAdd.Cvc,
 		Description: cardToUpdate.Description,
 	}
@@ -228,7 +231,7 @@ func Test(t *testing.T) {
 // ----------------------------------- password -----------------------------------
 
 func getAllPass(t *testing.T, cfg *config.Config, jwtToken []byte,
-	pwds []client.PasswordToGet, len int) {
+	pwds []keeperclient.PasswordToGet, len int) {
 
 	err := requests.
 		URL("/api/user/passwords").
@@ -242,7 +245,7 @@ func getAllPass(t *testing.T, cfg *config.Config, jwtToken []byte,
 	require.Len(t, pwds, len)
 }
 
-func addPass(t *testing.T, cfg *config.Config, jwtToken []byte, pwd *client.PwdToAdd) {
+func addPass(t *testing.T, cfg *config.Config, jwtToken []byte, pwd *keeperclient.PwdToAdd) {
 	err := requests.
 		URL("/api/user/password").
 		Scheme("http").
@@ -270,7 +273,7 @@ func getPass(t *testing.T, cfg *config.Config, jwtToken []byte,
 }
 
 func updatePass(t *testing.T, cfg *config.Config, jwtToken []byte,
-	title string, pwdToUpdate client.PasswordToUpdate) {
+	title string, pwdToUpdate keeperclient.PasswordToUpdate) {
 
 	err := requests.
 		URL("/api/user/password/update/"+title).
@@ -295,7 +298,7 @@ func RandStringBytes(n int) string {
 }
 
 func getAllCards(t *testing.T, cfg *config.Config, jwtToken []byte,
-	cards []client.CardToGet, len int) {
+	cards []keeperclient.CardToGet, len int) {
 
 	err := requests.
 		URL("/api/user/cards").
@@ -309,7 +312,7 @@ func getAllCards(t *testing.T, cfg *config.Config, jwtToken []byte,
 	require.Len(t, cards, len)
 }
 
-func addCard(t *testing.T, cfg *config.Config, jwtToken []byte, card *client.CardToAdd) {
+func addCard(t *testing.T, cfg *config.Config, jwtToken []byte, card *keeperclient.CardToAdd) {
 	err := requests.
 		URL("/api/user/card").
 		Scheme("http").
@@ -322,9 +325,9 @@ func addCard(t *testing.T, cfg *config.Config, jwtToken []byte, card *client.Car
 }
 
 func getCard(t *testing.T, cfg *config.Config, jwtToken []byte,
-	title string, expectedCard client.CardToGetByTitle) {
+	title string, expectedCard keeperclient.CardToGetByTitle) {
 
-	var cardToGet client.CardToGetByTitle
+	var cardToGet keeperclient.CardToGetByTitle
 
 	err := requests.
 		URL("/api/user/card/"+title).
@@ -342,7 +345,7 @@ func getCard(t *testing.T, cfg *config.Config, jwtToken []byte,
 }
 
 func updateCard(t *testing.T, cfg *config.Config, jwtToken []byte,
-	title string, cardToUpdate client.CardToUpdate) {
+	title string, cardToUpdate keeperclient.CardToUpdate) {
 
 	err := requests.
 		URL("/api/user/card/update/"+title).
@@ -2,16 +2,18 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
-	"time"
 
+	"github.com/adettelle/go-keeper/internal/breach"
 	"github.com/adettelle/go-keeper/internal/database"
 	"github.com/adettelle/go-keeper/internal/migrator"
 	"github.com/adettelle/go-keeper/internal/repo"
 	"github.com/adettelle/go-keeper/internal/server/api"
 	"github.com/adettelle/go-keeper/internal/server/config"
+	"github.com/adettelle/go-keeper/internal/server/web"
 	"github.com/adettelle/go-keeper/internal/service"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -50,34 +52,84 @@ func initializeServer(cfg *config.Config) (*http.Server, error) {
 	pwdRepo := repo.NewPasswordRepo(db)
 	fileRepo := repo.NewFileRepo(db)
 	cardRepo := repo.NewCardRepo(db)
+	identityRepo := repo.NewIdentityRepo(db)
+	sshKeyRepo := repo.NewSSHKeyRepo(db)
+	noteRepo := repo.NewNoteRepo(db)
 	jwtRepo := repo.NewJwtRepo(db)
+	itemRepo := repo.NewItemRepo(db)
+	reminderRepo := repo.NewReminderRepo(db)
+	orgRepo := repo.NewOrgRepo(db)
+	shareRepo := repo.NewShareRepo(db)
+	linkRepo := repo.NewLinkRepo(db)
+	emergencyRepo := repo.NewEmergencyRepo(db)
 
 	// Initialize minio client object.
+	minioCreds := credentials.NewStaticV4(cfg.MinioAccessKeyID, cfg.MinioSecretAccessKey, "")
 	minioClient, err := minio.New(cfg.MinioEndPoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(cfg.MinioAccessKeyID, cfg.MinioSecretAccessKey, ""),
+		Creds:  minioCreds,
 		Secure: cfg.UseSSL,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	minioService := service.NewMinioService(minioClient, cfg.BucketName, 3*time.Minute)
+	minioService := service.NewMinioService(minioClient, cfg.BucketName, cfg.FileLinkLifetime)
 	err = minioService.CreateBucket()
 	if err != nil {
 		return nil, err
 	}
+	if cfg.MinioPublicURL != "" {
+		if err := minioService.UsePublicURL(cfg.MinioPublicURL, minioCreds); err != nil {
+			return nil, err
+		}
+	}
 	fmt.Println("Starting minio service")
 
 	handlers := api.NewCustomerHandlers(customerRepo, jwtRepo, []byte(cfg.SignKey), cfg)
 
 	cardHandlers := api.NewCardHandlers(cardRepo, []byte(cfg.SignKey), cfg)
 	passHandlers := api.NewPassHandlers(pwdRepo, []byte(cfg.SignKey), cfg)
+
+	// URLs saved before they were indexed are indexed once, the server holds the index key
+	indexed, err := pwdRepo.BackfillURLIndexes(context.Background(), passHandlers.IndexStoredURL)
+	if err != nil {
+		return nil, err
+	}
+	if indexed > 0 {
+		fmt.Println("Indexed password urls:", indexed)
+	}
 	fileHandlers := api.NewFileHandlers(fileRepo, minioService, []byte(cfg.SignKey), cfg)
+	identityHandlers := api.NewIdentityHandlers(identityRepo, []byte(cfg.SignKey))
+	sshKeyHandlers := api.NewSSHKeyHandlers(sshKeyRepo, []byte(cfg.SignKey))
+	noteHandlers := api.NewNoteHandlers(noteRepo, []byte(cfg.SignKey))
+	itemHandlers := api.NewItemHandlers(itemRepo)
+	reminderHandlers := api.NewReminderHandlers(reminderRepo, []byte(cfg.SignKey), cfg)
+	orgHandlers := api.NewOrgHandlers(orgRepo)
+	shareHandlers := api.NewShareHandlers(shareRepo)
+	linkHandlers := api.NewLinkHandlers(linkRepo, fileRepo, minioService, web.Static())
+	emergencyHandlers := api.NewEmergencyHandlers(emergencyRepo, shareRepo)
+
+	var breachHandlers *api.BreachHandlers
+	if cfg.BreachCorpus != "" {
+		corpus, err := breach.Open(cfg.BreachCorpus)
+		if err != nil {
+			return nil, err
+		}
+		breachHandlers = api.NewBreachHandlers(corpus)
+		fmt.Println("Serving breached passwords ranges from", cfg.BreachCorpus)
+	}
+
+	var webHandlers *api.WebHandlers
+	if cfg.WebVault {
+		webHandlers = api.NewWebHandlers(customerRepo, jwtRepo, []byte(cfg.SignKey), web.Static())
+		fmt.Println("Serving web vault at /web/")
+	}
 
 	address := cfg.Address
 	fmt.Println("Starting server at address:", address)
 
-	r := api.NewRouter(handlers, cardHandlers, passHandlers, fileHandlers, jwtRepo)
+	r := api.NewRouter(handlers, cardHandlers, passHandlers, fileHandlers, identityHandlers,
+		sshKeyHandlers, noteHandlers, itemHandlers, reminderHandlers, orgHandlers, shareHandlers, linkHandlers, emergencyHandlers, breachHandlers, webHandlers, jwtRepo)
 
 	srv := &http.Server{
 		Addr:    address,
@@ -0,0 +1,95 @@
+// Package access defines the roles of organization members and what they allow
+// with the shared collections of the organization.
+package access
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Role is the role of a member in an organization.
+type Role string
+
+const (
+	// Owner manages everything, including admins, other owners and the organization itself.
+	Owner Role = "owner"
+	// Admin manages collections, editors and viewers, and changes items.
+	Admin Role = "admin"
+	// Editor reads and changes items of the collections.
+	Editor Role = "editor"
+	// Viewer only reads items of the collections.
+	Viewer Role = "viewer"
+)
+
+// Roles are all the roles, the most powerful first.
+var Roles = []Role{Owner, Admin, Editor, Viewer}
+
+func (r Role) rank() int {
+	switch r {
+	case Owner:
+		return 4
+	case Admin:
+		return 3
+	case Editor:
+		return 2
+	case Viewer:
+		return 1
+	}
+	return 0
+}
+
+// Valid reports whether the role is known.
+func (r Role) Valid() bool {
+	return r.rank() > 0
+}
+
+// Permission is what a request does with the items of a vault.
+type Permission int
+
+const (
+	Read Permission = iota
+	Write
+)
+
+// Allows reports whether the role grants the permission on the items of the collections.
+func (r Role) Allows(p Permission) bool {
+	switch p {
+	case Read:
+		return r.rank() >= Viewer.rank()
+	case Write:
+		return r.rank() >= Editor.rank()
+	}
+	return false
+}
+
+// CanManage reports whether the role may manage members and collections.
+func (r Role) CanManage() bool {
+	return r.rank() >= Admin.rank()
+}
+
+// CanGrant reports whether the role may give a member the role or take it away:
+// owners grant any role, admins only editor and viewer.
+func (r Role) CanGrant(role Role) bool {
+	if r == Owner {
+		return true
+	}
+	return r == Admin && role.rank() < Admin.rank()
+}
+
+var nameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+// ValidName reports whether the name fits an organization or a collection:
+// up to 64 lowercase letters, digits, dots, dashes and underscores.
+func ValidName(name string) bool {
+	return nameRe.MatchString(name)
+}
+
+// ParseCollection splits the "org/collection" reference to a shared collection.
+func ParseCollection(ref string) (org, collection string, err error) {
+	org, collection, ok := strings.Cut(ref, "/")
+	if !ok || !ValidName(org) || !ValidName(collection) {
+		return "", "", fmt.Errorf("invalid collection %q, want org/collection", ref)
+	}
+	return org, collection, nil
+}
@@ -0,0 +1,46 @@
+package access
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRolePermissions(t *testing.T) {
+	tests := []struct {
+		role      Role
+		read      bool
+		write     bool
+		manage    bool
+		grantable []Role
+	}{
+		{Owner, true, true, true, []Role{Owner, Admin, Editor, Viewer}},
+		{Admin, true, true, true, []Role{Editor, Viewer}},
+		{Editor, true, true, false, nil},
+		{Viewer, true, false, false, nil},
+		{Role("guest"), false, false, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			require.Equal(t, tt.read, tt.role.Allows(Read))
+			require.Equal(t, tt.write, tt.role.Allows(Write))
+			require.Equal(t, tt.manage, tt.role.CanManage())
+			for _, role := range Roles {
+				require.Equal(t, slices.Contains(tt.grantable, role), tt.role.CanGrant(role), role)
+			}
+		})
+	}
+}
+
+func TestParseCollection(t *testing.T) {
+	org, collection, err := ParseCollection("acme/staging")
+	require.NoError(t, err)
+	require.Equal(t, "acme", org)
+	require.Equal(t, "staging", collection)
+
+	for _, ref := range []string{"", "acme", "acme/", "/staging", "Acme/staging", "acme/stag/ing", "acme/-x"} {
+		_, _, err := ParseCollection(ref)
+		require.Error(t, err, ref)
+	}
+}
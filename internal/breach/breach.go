@@ -0,0 +1,231 @@
+// Package breach checks passwords against a local corpus of breached password hashes.
+//
+// The corpus is a text file in the format of the "ordered by hash" Have I Been Pwned download:
+// one "SHA1:COUNT" line per password, upper case hex, sorted by hash. The file is searched
+// with a binary search over byte offsets, so it is neither loaded into memory nor indexed.
+// Only the first PrefixLength hex characters of a hash are needed to query a range,
+// which makes k-anonymity lookups possible: the full hash never leaves the client.
+package breach
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PrefixLength is the length of a hash prefix used for range queries.
+const PrefixLength = 5
+
+// Checker reports how many times a password appears in breaches.
+type Checker interface {
+	Count(ctx context.Context, password string) (int, error)
+}
+
+// Hash returns the upper case hex SHA-1 of the password, as used in the corpus.
+func Hash(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// ValidPrefix reports whether prefix is a valid range query prefix.
+func ValidPrefix(prefix string) bool {
+	if len(prefix) != PrefixLength {
+		return false
+	}
+	_, err := hex.DecodeString(prefix + "0")
+	return err == nil
+}
+
+// Entry is a corpus line of a range: the hash without the prefix and the number of occurrences.
+type Entry struct {
+	Suffix string
+	Count  int
+}
+
+// Corpus is a sorted hash file opened for lookups. It is safe for concurrent use.
+type Corpus struct {
+	f    *os.File
+	size int64
+}
+
+// Open opens the corpus file.
+func Open(path string) (*Corpus, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &Corpus{f: f, size: info.Size()}, nil
+}
+
+func (c *Corpus) Close() error {
+	return c.f.Close()
+}
+
+// Count returns the number of occurrences of the password; zero if it is not in the corpus.
+func (c *Corpus) Count(_ context.Context, password string) (int, error) {
+	hash := Hash(password)
+	entries, err := c.Range(hash[:PrefixLength])
+	if err != nil {
+		return 0, err
+	}
+	return countOf(entries, hash[PrefixLength:]), nil
+}
+
+// Range returns all the entries which hashes start with prefix.
+func (c *Corpus) Range(prefix string) ([]Entry, error) {
+	prefix = strings.ToUpper(prefix)
+
+	// the first offset which next line is not less than the prefix
+	lo, hi := int64(0), c.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		line, err := c.lineAfter(mid)
+		if err != nil {
+			return nil, err
+		}
+		if line == "" || hashOf(line) >= prefix {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	start, err := c.lineStart(lo)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Entry
+	scanner := bufio.NewScanner(io.NewSectionReader(c.f, start, c.size-start))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		hash := hashOf(line)
+		if !strings.HasPrefix(hash, prefix) {
+			break
+		}
+		entry, err := parseEntry(line)
+		if err != nil {
+			return nil, err
+		}
+		entry.Suffix = entry.Suffix[len(prefix):]
+		res = append(res, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// lineStart returns the offset of the first line starting at off or later.
+func (c *Corpus) lineStart(off int64) (int64, error) {
+	if off == 0 {
+		return 0, nil
+	}
+	// start from the previous byte: if it is a line break, off is a line start itself
+	r := bufio.NewReader(io.NewSectionReader(c.f, off-1, c.size-off+1))
+	skipped, err := r.ReadString('\n')
+	if errors.Is(err, io.EOF) {
+		return c.size, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return off - 1 + int64(len(skipped)), nil
+}
+
+// lineAfter returns the first line starting at off or later; empty at the end of file.
+func (c *Corpus) lineAfter(off int64) (string, error) {
+	start, err := c.lineStart(off)
+	if err != nil || start >= c.size {
+		return "", err
+	}
+	r := bufio.NewReader(io.NewSectionReader(c.f, start, c.size-start))
+	line, err := r.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func hashOf(line string) string {
+	hash, _, _ := strings.Cut(line, ":")
+	return strings.ToUpper(hash)
+}
+
+func parseEntry(line string) (Entry, error) {
+	hash, count, ok := strings.Cut(line, ":")
+	if !ok {
+		return Entry{}, fmt.Errorf("malformed corpus line %q", line)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil {
+		return Entry{}, fmt.Errorf("malformed count in corpus line %q", line)
+	}
+	return Entry{Suffix: strings.ToUpper(hash), Count: n}, nil
+}
+
+// ParseRange parses a range response of "SUFFIX:COUNT" lines.
+func ParseRange(r io.Reader) ([]Entry, error) {
+	var res []Entry
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		entry, err := parseEntry(line)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, entry)
+	}
+	return res, scanner.Err()
+}
+
+// WriteRange writes entries in the range response format.
+func WriteRange(w io.Writer, entries []Entry) error {
+	bw := bufio.NewWriter(w)
+	for _, e := range entries {
+		if _, err := fmt.Fprintf(bw, "%s:%d\r\n", e.Suffix, e.Count); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// RangeFunc queries a range of hashes by prefix, for example from a remote server.
+// It implements Checker, sending only the hash prefix.
+type RangeFunc func(ctx context.Context, prefix string) ([]Entry, error)
+
+func (f RangeFunc) Count(ctx context.Context, password string) (int, error) {
+	hash := Hash(password)
+	entries, err := f(ctx, hash[:PrefixLength])
+	if err != nil {
+		return 0, err
+	}
+	return countOf(entries, hash[PrefixLength:]), nil
+}
+
+func countOf(entries []Entry, suffix string) int {
+	for _, e := range entries {
+		if e.Suffix == suffix {
+			return e.Count
+		}
+	}
+	return 0
+}
@@ -0,0 +1,95 @@
+package breach
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeCorpus writes a sorted corpus of the given passwords and a lot of filler hashes.
+func writeCorpus(t *testing.T, counts map[string]int) string {
+	t.Helper()
+
+	var lines []string
+	for pwd, n := range counts {
+		lines = append(lines, fmt.Sprintf("%s:%d", Hash(pwd), n))
+	}
+	for i := range 2000 {
+		lines = append(lines, fmt.Sprintf("%s:%d", Hash(fmt.Sprintf("filler%d", i)), i+1))
+	}
+	sort.Strings(lines)
+
+	path := filepath.Join(t.TempDir(), "corpus.txt")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0600))
+	return path
+}
+
+func TestCorpusCount(t *testing.T) {
+	path := writeCorpus(t, map[string]int{"password": 9659365, "qwerty": 3912816})
+
+	c, err := Open(path)
+	require.NoError(t, err)
+	defer c.Close()
+
+	ctx := context.Background()
+	for pwd, want := range map[string]int{"password": 9659365, "qwerty": 3912816, "filler0": 1,
+		"filler1999": 2000, "not breached": 0} {
+		n, err := c.Count(ctx, pwd)
+		require.NoError(t, err)
+		require.Equal(t, want, n, pwd)
+	}
+}
+
+func TestCorpusRange(t *testing.T) {
+	path := writeCorpus(t, map[string]int{"password": 10})
+
+	c, err := Open(path)
+	require.NoError(t, err)
+	defer c.Close()
+
+	hash := Hash("password")
+	entries, err := c.Range(strings.ToLower(hash[:PrefixLength]))
+	require.NoError(t, err)
+	require.Contains(t, entries, Entry{Suffix: hash[PrefixLength:], Count: 10})
+
+	entries, err = c.Range("FFFFF")
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestRangeRoundTrip(t *testing.T) {
+	entries := []Entry{{Suffix: "1E4C9B93F3F0682250B6CF8331B7EE68FD8", Count: 10}, {Suffix: "00000000000000000000000000000000000", Count: 1}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteRange(&buf, entries))
+
+	parsed, err := ParseRange(&buf)
+	require.NoError(t, err)
+	require.Equal(t, entries, parsed)
+}
+
+func TestRangeFunc(t *testing.T) {
+	hash := Hash("password")
+	f := RangeFunc(func(_ context.Context, prefix string) ([]Entry, error) {
+		require.Equal(t, hash[:PrefixLength], prefix)
+		return []Entry{{Suffix: hash[PrefixLength:], Count: 42}}, nil
+	})
+
+	n, err := f.Count(context.Background(), "password")
+	require.NoError(t, err)
+	require.Equal(t, 42, n)
+}
+
+func TestValidPrefix(t *testing.T) {
+	require.True(t, ValidPrefix("5BAA6"))
+	require.True(t, ValidPrefix("5baa6"))
+	require.False(t, ValidPrefix("5BAA"))
+	require.False(t, ValidPrefix("5BAG6"))
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Banners prints the reminders and notifications after commands. They cost requests to the server,
+// so they are checked at most once per interval; the time of the last check is the modification
+// time of the stamp file.
+type Banners struct {
+	stampPath string
+	interval  time.Duration
+}
+
+func NewBanners(stampPath string, interval time.Duration) *Banners {
+	return &Banners{
+		stampPath: stampPath,
+		interval:  interval,
+	}
+}
+
+// due reports whether the interval has passed since the last check and records the check.
+func (b *Banners) due(now time.Time) bool {
+	if st, err := os.Stat(b.stampPath); err == nil && now.Sub(st.ModTime()) < b.interval {
+		return false
+	}
+	if err := os.MkdirAll(filepath.Dir(b.stampPath), 0o700); err != nil {
+		return true
+	}
+	if err := os.WriteFile(b.stampPath, nil, 0o600); err == nil {
+		_ = os.Chtimes(b.stampPath, now, now)
+	}
+	return true
+}
+
+// Show runs the banners concurrently, if they are due, and prints their output in order.
+func (b *Banners) Show(w io.Writer, banners ...func(io.Writer)) {
+	if !b.due(time.Now()) {
+		return
+	}
+
+	out := make([]bytes.Buffer, len(banners))
+	var wg sync.WaitGroup
+	for i, banner := range banners {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			banner(&out[i])
+		}()
+	}
+	wg.Wait()
+
+	for i := range out {
+		_, _ = out[i].WriteTo(w)
+	}
+}
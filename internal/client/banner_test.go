@@ -0,0 +1,32 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBannersThrottled(t *testing.T) {
+	b := NewBanners(filepath.Join(t.TempDir(), "banner-default"), time.Hour)
+
+	calls := 0
+	first := func(w io.Writer) { calls++; fmt.Fprintln(w, "first") }
+	second := func(w io.Writer) { fmt.Fprintln(w, "second") }
+
+	var out bytes.Buffer
+	b.Show(&out, first, second)
+	require.Equal(t, "first\nsecond\n", out.String())
+
+	// в течение интервала сервер не запрашивается
+	out.Reset()
+	b.Show(&out, first, second)
+	require.Empty(t, out.String())
+	require.Equal(t, 1, calls)
+
+	require.True(t, b.due(time.Now().Add(2*time.Hour)))
+}
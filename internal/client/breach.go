@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/adettelle/go-keeper/internal/breach"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// RemoteBreachChecker checks passwords with the server range API.
+// Only the first 5 characters of the password SHA-1 are sent to the server.
+func RemoteBreachChecker(client *keeperclient.Client) breach.Checker {
+	return breach.RangeFunc(func(ctx context.Context, prefix string) ([]breach.Entry, error) {
+		entries, err := client.BreachRange(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+		res := make([]breach.Entry, 0, len(entries))
+		for _, e := range entries {
+			res = append(res, breach.Entry{Suffix: e.Suffix, Count: e.Count})
+		}
+		return res, nil
+	})
+}
+
+// BreachService is a service for checking passwords against breached passwords.
+type BreachService struct {
+	client  *keeperclient.Client
+	checker breach.Checker
+}
+
+func NewBreachService(client *keeperclient.Client, checker breach.Checker) *BreachService {
+	return &BreachService{
+		client:  client,
+		checker: checker,
+	}
+}
+
+// CheckPasswords checks the password with the given title, or all passwords if title is empty,
+// and displays the breached ones in a tabular format.
+func (bs *BreachService) CheckPasswords(title string) error {
+	ctx := context.Background()
+
+	titles := []string{title}
+	if title == "" {
+		pwds, _, err := bs.client.Passwords(ctx, keeperclient.ListOptions{})
+		if err != nil {
+			return err
+		}
+		titles = titles[:0]
+		for _, pwd := range pwds {
+			titles = append(titles, pwd.Title)
+		}
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Title", "Times seen in breaches"})
+
+	breached := 0
+	for _, title := range titles {
+		pwd, err := bs.client.Password(ctx, title)
+		if err != nil {
+			return err
+		}
+		n, err := bs.checker.Count(ctx, pwd)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			breached++
+			t.AppendRow(table.Row{title, n})
+		}
+	}
+
+	if breached == 0 {
+		fmt.Printf("None of %d checked passwords is found in breaches.\n", len(titles))
+		return nil
+	}
+	t.Render()
+	return nil
+}
+
+// WarnIfBreached reports to the log if the password is found in breaches.
+func (bs *BreachService) WarnIfBreached(password string) error {
+	n, err := bs.checker.Count(context.Background(), password)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("Warning: the password has been seen %d times in breaches, consider another one.", n)
+	}
+	return nil
+}
@@ -6,217 +6,118 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
+	"strings"
 
-	"github.com/adettelle/go-keeper/cmd/settings"
-	"github.com/adettelle/go-keeper/internal/localstorage"
-	"github.com/carlmjohnson/requests"
-	"github.com/go-playground/validator/v10"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
 	"github.com/jedib0t/go-pretty/v6/table"
 )
 
-// use a single instance of Validate, it caches struct info
-var validate *validator.Validate = validator.New(validator.WithRequiredStructEnabled())
-
 // CardService is a service for managing card-related operations.
 type CardService struct {
-	transport *http.Transport
-	keyStore  localstorage.IKeyStorage
+	client *keeperclient.Client
 }
 
-func NewCardService(transport *http.Transport, keyStore localstorage.IKeyStorage) *CardService {
+func NewCardService(client *keeperclient.Client) *CardService {
 	return &CardService{
-		transport: transport,
-		keyStore:  keyStore,
+		client: client,
 	}
 }
 
-type CardToGet struct {
-	Title       string `json:"title"`
-	Num         string `json:"num"`
-	Description string `json:"description"`
-}
-
-// AllCards retrieves all cards associated with the user.
-// It fetches card data from the server and displays it in a tabular format.
-func (cs *CardService) AllCards() error {
-	jwtToken, err := cs.keyStore.Get()
+// AllCards retrieves cards associated with the user, filtered by the options.
+// It fetches card data from the server and displays it in a tabular format, favorites first.
+// Card numbers are masked by the server, only the last four digits are shown.
+func (cs *CardService) AllCards(opts keeperclient.ListOptions) error {
+	cards, next, err := cs.client.Cards(context.Background(), opts)
 	if err != nil {
 		return err
 	}
 
-	var cards []CardToGet
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"", "Title", "Brand", "Number", "Folder", "Tags", "Description"})
 
-	err = requests.
-		URL("/api/user/cards").
-		Host(settings.ServerURL).
-		Scheme("https").
-		Transport(cs.transport).
-		Header("Authorization", string(jwtToken)).
-		ToJSON(&cards).
-		Method(http.MethodGet).
-		Fetch(context.Background())
-
-	if err != nil {
-		return err
-	} else {
-		t := table.NewWriter()
-		t.SetOutputMirror(os.Stdout)
-		t.AppendHeader(table.Row{"Title", "Number", "Description"})
-
-		for _, card := range cards {
-			t.AppendRow([]interface{}{card.Title, card.Num, card.Description})
-		}
-		t.Render()
+	for _, card := range cards {
+		t.AppendRow([]interface{}{favoriteMark(card.ItemMeta), card.Title, card.Brand, card.Num,
+			card.Folder, strings.Join(card.Tags, ", "), card.Description})
 	}
+	t.Render()
+	printNextCursor(next)
 	return nil
 }
 
-type CardToAdd struct {
-	Num         string `json:"num" validate:"required,credit_card,len=16"`
-	Expire      string `json:"expires_at" validate:"required,numeric,len=4"`
-	Cvc         string `json:"cvc" validate:"required,numeric,len=3"`
-	Title       string `json:"title" validate:"required,min=1"`
-	Description string `json:"description"`
-}
-
-func (cs *CardService) AddCard(num, expire, cvc, title, description string) error {
-	cardToAdd := CardToAdd{
-		Num:         num,
-		Expire:      expire,
-		Cvc:         cvc,
-		Title:       title,
-		Description: description,
-	}
-
-	err := validate.Struct(cardToAdd)
-	if err != nil {
-		log.Println("error in validating:", err)
-		return err
-	}
-
-	jwtToken, err := cs.keyStore.Get()
+// AddCard stores a new card. Brand, number length, checksum and expiry date are validated
+// before sending it to the server.
+func (cs *CardService) AddCard(card keeperclient.CardToAdd) error {
+	err := cs.client.AddCard(context.Background(), card)
 	if err != nil {
 		return err
 	}
-
-	err = requests.
-		URL("/api/user/card").
-		Host(settings.ServerURL).
-		Scheme("https").
-		Header("Authorization", string(jwtToken)).
-		Transport(cs.transport).
-		BodyJSON(&cardToAdd).
-		Method(http.MethodPut).
-		Fetch(context.Background())
-
-	if err != nil {
-		return err
-	} else {
-		log.Println("Card is added.")
-	}
-
+	log.Println("Card is added.")
 	return nil
 }
 
 func (cs *CardService) DeleteCardByTitle(cardTitle string) error {
-	jwtToken, err := cs.keyStore.Get()
+	err := cs.client.DeleteCard(context.Background(), cardTitle)
 	if err != nil {
 		return err
 	}
-
-	err = requests.
-		URL("/api/user/card/"+cardTitle).
-		Host(settings.ServerURL).
-		Scheme("https").
-		Transport(cs.transport).
-		Header("Authorization", string(jwtToken)).
-		Method(http.MethodDelete).
-		Fetch(context.Background())
-
-	if err != nil {
-		return err
-	} else {
-		log.Println("Card is deleted.")
-	}
+	log.Println("Card is deleted.")
 	return nil
 }
 
-type CardToGetByTitle struct {
-	Num         string `json:"num"`
-	Expire      string `json:"expires_at"`
-	Cvc         string `json:"cvc"`
-	Description string `json:"description"`
-}
-
 func (cs *CardService) GetCardByTitle(title string) error {
-	jwtToken, err := cs.keyStore.Get()
+	card, err := cs.client.Card(context.Background(), title)
 	if err != nil {
 		return err
 	}
-
-	var card CardToGetByTitle
-
-	err = requests.
-		URL("/api/user/card/"+title).
-		Host(settings.ServerURL).
-		Scheme("https").
-		Transport(cs.transport).
-		Header("Authorization", string(jwtToken)).
-		Method(http.MethodGet).
-		ToJSON(&card).
-		Fetch(context.Background())
-
-	if err != nil {
-		return err
+	for _, line := range []struct{ name, value string }{
+		{"Number", card.Num},
+		{"Brand", card.Brand},
+		{"Expires", card.Expire},
+		{"CVC", card.Cvc},
+		{"Cardholder", card.Holder},
+		{"Address", card.Address},
+		{"PIN", card.Pin},
+		{"Description", card.Description},
+	} {
+		if line.value != "" {
+			fmt.Printf("%s: %s\n", line.name, line.value)
+		}
 	}
-	fmt.Printf("%s, %s, %s, %s\n", card.Num, card.Expire, card.Cvc, card.Description)
 	return nil
 }
 
-type CardToUpdate struct {
-	Num         string `json:"num,omitempty" validate:"omitnil,omitempty,credit_card,len=16"`
-	Expire      string `json:"expires_at,omitempty"  validate:"omitempty,omitnil,numeric,len=4"`
-	Cvc         string `json:"cvc,omitempty" validate:"omitempty,omitnil,numeric,len=3"`
-	Description string `json:"description,omitempty"`
-}
-
-// UpdateCard updates card's number, date of expire, cvc and description by unique title.
-// It updates only arguments which are provided.
-func (ps *CardService) UpdateCard(title string, args ...string) error {
-	jwtToken, err := ps.keyStore.Get()
+// CardField returns a single field of the card: number, brand, expiry, cvc, pin, cardholder, address or description.
+func (cs *CardService) CardField(title, field string) (string, error) {
+	card, err := cs.client.Card(context.Background(), title)
 	if err != nil {
-		return err
+		return "", err
 	}
-
-	card := CardToUpdate{
-		Num:         args[0],
-		Expire:      args[1],
-		Cvc:         args[2],
-		Description: args[3],
+	value, ok := cardValue(card, field)
+	if !ok {
+		return "", fmt.Errorf("card %q has no field %q", title, field)
 	}
+	return value, nil
+}
 
-	err = validate.Struct(card)
+// GetCardField prints a single field of the card, see CardField.
+func (cs *CardService) GetCardField(title, field string) error {
+	value, err := cs.CardField(title, field)
 	if err != nil {
-		log.Println("error in validating:", err)
 		return err
 	}
+	fmt.Printf("%s\n", value)
+	return nil
+}
 
-	err = requests.
-		URL("/api/user/card/update/"+title).
-		Host(settings.ServerURL).
-		Scheme("https").
-		Transport(ps.transport).
-		Header("Authorization", string(jwtToken)).
-		BodyJSON(&card).
-		Method(http.MethodPost).
-		Fetch(context.Background())
-
+// UpdateCard updates card's number, date of expire, cvc, cardholder, address, PIN
+// and description by unique title. It updates only arguments which are provided.
+func (cs *CardService) UpdateCard(title string, card keeperclient.CardToUpdate) error {
+	err := cs.client.UpdateCard(context.Background(), title, card)
 	if err != nil {
 		return err
-	} else {
-		log.Println("Card info is updated.")
 	}
+	log.Println("Card info is updated.")
 	return nil
 }
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/clipboard"
+)
+
+// ClipService copies secrets to the clipboard instead of printing them, and has the clipboard
+// cleared after the timeout if it still holds the secret.
+type ClipService struct {
+	board   clipboard.Backend
+	timeout time.Duration
+	// clearLater starts clearing the clipboard after the timeout in the background,
+	// the copied text is identified by its digest.
+	clearLater func(digest string, timeout time.Duration) error
+}
+
+func NewClipService(board clipboard.Backend, timeout time.Duration,
+	clearLater func(digest string, timeout time.Duration) error) *ClipService {
+	return &ClipService{board: board, timeout: timeout, clearLater: clearLater}
+}
+
+// Copy copies the text to the clipboard. A zero timeout leaves it there.
+func (cs *ClipService) Copy(what, text string) error {
+	if text == "" {
+		return errors.New(what + " is empty, nothing to copy")
+	}
+	if err := cs.board.Write(text); err != nil {
+		return err
+	}
+	if cs.timeout <= 0 {
+		log.Printf("%s is copied to the clipboard.", what)
+		return nil
+	}
+	if err := cs.clearLater(clipboard.Digest(text), cs.timeout); err != nil {
+		return err
+	}
+	log.Printf("%s is copied to the clipboard, it is cleared in %s.", what, cs.timeout)
+	return nil
+}
+
+// ClearClipboard waits for the timeout and clears the clipboard if it still holds the text with the digest.
+func ClearClipboard(board clipboard.Backend, digest string, timeout time.Duration) error {
+	time.Sleep(timeout)
+	_, err := clipboard.ClearIfUnchanged(board, digest)
+	return err
+}
@@ -0,0 +1,33 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/clipboard"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClipCopy(t *testing.T) {
+	board := &clipboard.Memory{}
+	var scheduled string
+	cs := NewClipService(board, time.Millisecond, func(digest string, timeout time.Duration) error {
+		scheduled = digest
+		return ClearClipboard(board, digest, timeout)
+	})
+
+	require.NoError(t, cs.Copy("password", "s3cret"))
+	require.Equal(t, clipboard.Digest("s3cret"), scheduled)
+	text, err := board.Read()
+	require.NoError(t, err)
+	require.Empty(t, text)
+
+	require.Error(t, cs.Copy("pin", ""))
+
+	// without timeout the clipboard is not cleared
+	cs = NewClipService(board, 0, nil)
+	require.NoError(t, cs.Copy("password", "s3cret"))
+	text, err = board.Read()
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", text)
+}
@@ -0,0 +1,180 @@
+// Package config provides functionality for loading and saving the client configuration file.
+// The file is stored in the user config directory (XDG_CONFIG_HOME on Linux) and holds named
+// profiles, each describing a server to connect to and the local TLS and keyring settings.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/99designs/keyring"
+)
+
+const (
+	appDir         = "go-keeper"   // appDir is the name of the client directory inside the user config dir.
+	fileName       = "config.json" // fileName is the name of the configuration file.
+	DefaultProfile = "default"     // DefaultProfile is the name of the profile used when none is selected.
+)
+
+// Profile describes how to connect to a single go-keeper server.
+type Profile struct {
+	ServerURL  string `json:"server_url"`            // ServerURL is the server address, e.g. "keeper.example.com:8080".
+	CACert     string `json:"ca_cert,omitempty"`     // CACert is a path to a PEM CA bundle used to verify the server.
+	ClientCert string `json:"client_cert,omitempty"` // ClientCert is a path to the client TLS certificate.
+	ClientKey  string `json:"client_key,omitempty"`  // ClientKey is a path to the client TLS private key.
+	Keyring    string `json:"keyring,omitempty"`     // Keyring is the keyring backend for tokens, empty means any available.
+}
+
+// Config is the content of the client configuration file.
+type Config struct {
+	Current  string             `json:"current"`
+	Profiles map[string]Profile `json:"profiles"`
+}
+
+// Dir returns the client directory inside the user config dir.
+func Dir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appDir), nil
+}
+
+// DefaultPath returns the path of the configuration file.
+func DefaultPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fileName), nil
+}
+
+// Load reads the configuration file at path.
+// If the file does not exist, a configuration with a single default profile
+// pointing to defaultServerURL is returned.
+func Load(path, defaultServerURL string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{
+			Current: DefaultProfile,
+			Profiles: map[string]Profile{
+				DefaultProfile: {ServerURL: defaultServerURL},
+			},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("error in parsing config %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]Profile{}
+	}
+	if cfg.Current == "" {
+		cfg.Current = DefaultProfile
+	}
+	return &cfg, nil
+}
+
+// Save writes the configuration to path, creating the directory if needed.
+func (cfg *Config) Save(path string) error {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Profile returns the profile with the given name. An empty name selects the current profile.
+func (cfg *Config) Profile(name string) (string, Profile, error) {
+	if name == "" {
+		name = cfg.Current
+	}
+	p, ok := cfg.Profiles[name]
+	if !ok {
+		return "", Profile{}, fmt.Errorf("profile %q not found", name)
+	}
+	return name, p, nil
+}
+
+// ProfileNames returns the sorted names of all profiles.
+func (cfg *Config) ProfileNames() []string {
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetProfile creates or replaces the profile with the given name.
+func (cfg *Config) SetProfile(name string, p Profile) error {
+	if name == "" {
+		return errors.New("profile name is empty")
+	}
+	if p.ServerURL == "" {
+		return errors.New("server url is empty")
+	}
+	if _, err := p.KeyringBackends(); err != nil {
+		return err
+	}
+	cfg.Profiles[name] = p
+	return nil
+}
+
+// DeleteProfile removes the profile with the given name. The current profile cannot be removed.
+func (cfg *Config) DeleteProfile(name string) error {
+	if _, ok := cfg.Profiles[name]; !ok {
+		return fmt.Errorf("profile %q not found", name)
+	}
+	if name == cfg.Current {
+		return fmt.Errorf("profile %q is current, switch to another profile first", name)
+	}
+	delete(cfg.Profiles, name)
+	return nil
+}
+
+// Use makes the profile with the given name current.
+func (cfg *Config) Use(name string) error {
+	if _, ok := cfg.Profiles[name]; !ok {
+		return fmt.Errorf("profile %q not found", name)
+	}
+	cfg.Current = name
+	return nil
+}
+
+var knownBackends = []keyring.BackendType{
+	keyring.SecretServiceBackend,
+	keyring.KeychainBackend,
+	keyring.KeyCtlBackend,
+	keyring.KWalletBackend,
+	keyring.WinCredBackend,
+	keyring.FileBackend,
+	keyring.PassBackend,
+}
+
+// KeyringBackends returns the keyring backends allowed by the profile.
+// nil means that any available backend may be used.
+func (p Profile) KeyringBackends() ([]keyring.BackendType, error) {
+	if p.Keyring == "" {
+		return nil, nil
+	}
+	backend := keyring.BackendType(p.Keyring)
+	if !slices.Contains(knownBackends, backend) {
+		return nil, fmt.Errorf("unknown keyring backend %q", p.Keyring)
+	}
+	return []keyring.BackendType{backend}, nil
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "config.json"), "localhost:8080")
+	require.NoError(t, err)
+
+	name, p, err := cfg.Profile("")
+	require.NoError(t, err)
+	require.Equal(t, DefaultProfile, name)
+	require.Equal(t, "localhost:8080", p.ServerURL)
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go-keeper", "config.json")
+
+	cfg, err := Load(path, "localhost:8080")
+	require.NoError(t, err)
+
+	err = cfg.SetProfile("work", Profile{ServerURL: "keeper.example.com:8443", Keyring: "file"})
+	require.NoError(t, err)
+	require.NoError(t, cfg.Use("work"))
+	require.NoError(t, cfg.Save(path))
+
+	loaded, err := Load(path, "")
+	require.NoError(t, err)
+
+	name, p, err := loaded.Profile("")
+	require.NoError(t, err)
+	require.Equal(t, "work", name)
+	require.Equal(t, "keeper.example.com:8443", p.ServerURL)
+	require.Equal(t, []string{DefaultProfile, "work"}, loaded.ProfileNames())
+}
+
+func TestSetProfileUnknownKeyring(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "config.json"), "localhost:8080")
+	require.NoError(t, err)
+
+	err = cfg.SetProfile("work", Profile{ServerURL: "keeper.example.com:8443", Keyring: "bogus"})
+	require.Error(t, err)
+}
+
+func TestDeleteCurrentProfile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "config.json"), "localhost:8080")
+	require.NoError(t, err)
+
+	require.Error(t, cfg.DeleteProfile(DefaultProfile))
+	require.Error(t, cfg.DeleteProfile("unknown"))
+}
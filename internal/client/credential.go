@@ -0,0 +1,184 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/adettelle/go-keeper/internal/credhelper"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+)
+
+// CredentialHelperTag marks the password entries created by the credential helpers.
+// Only such entries are erased and listed by the helpers, entries added by the user are never deleted by git or docker.
+const CredentialHelperTag = "credential-helper"
+
+// CredentialService stores git and docker credentials as password entries. Entries are found
+// by their URLs with the URL match rules, as in the find command.
+type CredentialService struct {
+	client *keeperclient.Client
+}
+
+func NewCredentialService(client *keeperclient.Client) *CredentialService {
+	return &CredentialService{client: client}
+}
+
+var _ credhelper.Store = (*CredentialService)(nil)
+
+// find returns the best matching password entry for the server URL with the username, if not empty.
+// If titles is not nil, only the entries with these titles are considered.
+func (cs *CredentialService) find(ctx context.Context, serverURL, username string, titles []string) (*FoundPassword, error) {
+	matches, err := cs.client.MatchPasswords(ctx, serverURL)
+	if err != nil {
+		return nil, err
+	}
+	found, err := rankMatches(serverURL, matches)
+	if err != nil {
+		return nil, err
+	}
+	return pickCredential(found, username, titles), nil
+}
+
+// pickCredential returns the first of the ranked entries with the username, if not empty,
+// and one of the titles, if not nil.
+func pickCredential(found []FoundPassword, username string, titles []string) *FoundPassword {
+	for _, f := range found {
+		if titles != nil && !slices.Contains(titles, f.Title) {
+			continue
+		}
+		if username == "" || f.Username == username {
+			return &f
+		}
+	}
+	return nil
+}
+
+// Get returns the username and password of the best matching password entry.
+func (cs *CredentialService) Get(ctx context.Context, serverURL, username string) (*credhelper.Credential, error) {
+	found, err := cs.find(ctx, serverURL, username, nil)
+	if err != nil || found == nil {
+		return nil, err
+	}
+	details, err := cs.client.PasswordDetails(ctx, found.Title)
+	if err != nil {
+		return nil, err
+	}
+	return &credhelper.Credential{URL: serverURL, Username: details.Username, Password: details.Password}, nil
+}
+
+// Store updates the password of the matching entry created by a credential helper if it has changed,
+// or adds an entry titled username@host with the host match rule and the credential helper tag.
+// Entries added by the user are never changed by git or docker.
+func (cs *CredentialService) Store(ctx context.Context, cred credhelper.Credential) error {
+	own, err := cs.helperEntries(ctx)
+	if err != nil {
+		return err
+	}
+	found, err := cs.find(ctx, cred.URL, cred.Username, own)
+	if err != nil {
+		return err
+	}
+	if found != nil {
+		details, err := cs.client.PasswordDetails(ctx, found.Title)
+		if err != nil {
+			return err
+		}
+		if details.Password == cred.Password {
+			return nil
+		}
+		return cs.client.UpdatePassword(ctx, found.Title, keeperclient.PasswordToUpdate{Password: cred.Password})
+	}
+
+	title, origin, err := credentialTitle(cred)
+	if err != nil {
+		return err
+	}
+	pwd := keeperclient.PwdToAdd{
+		Title:       title,
+		Description: "Stored by the credential helper.",
+		Username:    cred.Username,
+		Password:    cred.Password,
+		URLs:        []keeperclient.PasswordURL{{URL: origin, Match: keeperclient.MatchHost}},
+		Tags:        []string{CredentialHelperTag},
+	}
+	err = cs.client.AddPassword(ctx, pwd)
+	if errors.Is(err, keeperclient.ErrConflict) {
+		// the user has an entry with this title
+		pwd.Title = title + " (" + CredentialHelperTag + ")"
+		err = cs.client.AddPassword(ctx, pwd)
+	}
+	return err
+}
+
+// credentialTitle returns the title of a new entry, username@host, and the origin URL to match it by.
+func credentialTitle(cred credhelper.Credential) (string, string, error) {
+	u, err := url.Parse(cred.URL)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Host == "" {
+		return "", "", fmt.Errorf("url %q has no host", cred.URL)
+	}
+	title := u.Host
+	if cred.Username != "" {
+		title = cred.Username + "@" + u.Host
+	}
+	return title, u.Scheme + "://" + u.Host, nil
+}
+
+// Erase deletes the matching entry if it was created by a credential helper and, when the password
+// is given, still has that password.
+func (cs *CredentialService) Erase(ctx context.Context, cred credhelper.Credential) error {
+	own, err := cs.helperEntries(ctx)
+	if err != nil {
+		return err
+	}
+	found, err := cs.find(ctx, cred.URL, cred.Username, own)
+	if err != nil || found == nil {
+		return err
+	}
+	if cred.Password != "" {
+		details, err := cs.client.PasswordDetails(ctx, found.Title)
+		if err != nil {
+			return err
+		}
+		if details.Password != cred.Password {
+			return nil
+		}
+	}
+	return cs.client.DeletePassword(ctx, found.Title)
+}
+
+// List returns the URLs and usernames of the entries created by the credential helpers.
+func (cs *CredentialService) List(ctx context.Context) ([]credhelper.Credential, error) {
+	titles, err := cs.helperEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var res []credhelper.Credential
+	for _, title := range titles {
+		details, err := cs.client.PasswordDetails(ctx, title)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range details.URLs {
+			res = append(res, credhelper.Credential{URL: u.URL, Username: details.Username})
+		}
+	}
+	return res, nil
+}
+
+// helperEntries returns the titles of the password entries with the credential helper tag.
+func (cs *CredentialService) helperEntries(ctx context.Context) ([]string, error) {
+	pwds, _, err := cs.client.Passwords(ctx, keeperclient.ListOptions{Tags: []string{CredentialHelperTag}})
+	if err != nil {
+		return nil, err
+	}
+	titles := []string{} // not nil: no entries of the helpers means none can be changed
+	for _, p := range pwds {
+		titles = append(titles, p.Title)
+	}
+	return titles, nil
+}
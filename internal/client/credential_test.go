@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adettelle/go-keeper/internal/credhelper"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialTitle(t *testing.T) {
+	title, origin, err := credentialTitle(credhelper.Credential{URL: "https://github.com/org/repo.git", Username: "ane"})
+	require.NoError(t, err)
+	require.Equal(t, "ane@github.com", title)
+	require.Equal(t, "https://github.com", origin)
+
+	title, origin, err = credentialTitle(credhelper.Credential{URL: "https://registry.example.com:5000"})
+	require.NoError(t, err)
+	require.Equal(t, "registry.example.com:5000", title)
+	require.Equal(t, "https://registry.example.com:5000", origin)
+
+	_, _, err = credentialTitle(credhelper.Credential{URL: "registry"})
+	require.Error(t, err)
+}
+
+func TestPickCredential(t *testing.T) {
+	matches := []keeperclient.PasswordMatch{
+		{Title: "github", Username: "ane", URLs: []keeperclient.PasswordURL{{URL: "https://github.com", Match: keeperclient.MatchHost}}},
+		{Title: "ane@github.com", Username: "ane", URLs: []keeperclient.PasswordURL{{URL: "https://github.com", Match: keeperclient.MatchHost}}},
+	}
+	found, err := rankMatches("https://github.com", matches)
+	require.NoError(t, err)
+
+	// git get отдаёт и записи пользователя
+	require.Equal(t, "github", pickCredential(found, "ane", nil).Title)
+	// а store и erase меняют только записи, созданные помощником
+	require.Equal(t, "ane@github.com", pickCredential(found, "ane", []string{"ane@github.com"}).Title)
+	require.Nil(t, pickCredential(found, "ane", []string{}))
+	require.Nil(t, pickCredential(found, "bob", nil))
+
+	// запрос по http не получает данные https-сайта
+	found, err = rankMatches("http://github.com", matches)
+	require.NoError(t, err)
+	require.Empty(t, found)
+}
+
+func TestCredentialStoreTagsNewEntry(t *testing.T) {
+	var added keeperclient.PwdToAdd
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/user/passwords":
+			_, _ = w.Write([]byte("[]"))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/user/passwords/match":
+			_, _ = w.Write([]byte("[]"))
+		case r.Method == http.MethodPut && r.URL.Path == "/api/user/password":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&added))
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			// тег ставится при добавлении записи, а не отдельным запросом
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	c, err := keeperclient.New(keeperclient.Config{BaseURL: srv.URL, Tokens: keeperclient.StaticToken("Bearer token")})
+	require.NoError(t, err)
+
+	cs := NewCredentialService(c)
+	err = cs.Store(context.Background(), credhelper.Credential{
+		URL: "https://github.com/org/repo.git", Username: "ane", Password: "token",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "ane@github.com", added.Title)
+	require.Equal(t, []string{CredentialHelperTag}, added.Tags)
+}
@@ -0,0 +1,357 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/emergency"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/adettelle/go-keeper/pkg/sharing"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// EmergencyService manages trusted contacts and emergency access to the vaults of other users.
+//
+// The emergency kit of a contact is a snapshot of the vault of the user, except files, encrypted with
+// a fresh key wrapped for the public key of the contact. The contact opens it with the private key sealed
+// with its master password and account key; kits are not sealed for contacts whose private key is still
+// sealed with the master password alone, which the server could open. The server gives the kit to
+// the contact only after the waiting period passes without the user rejecting the request, so the kit
+// is read-only and must be updated to include later changes of the vault.
+type EmergencyService struct {
+	client *keeperclient.Client
+	shares *ShareService
+}
+
+func NewEmergencyService(client *keeperclient.Client) *EmergencyService {
+	return &EmergencyService{
+		client: client,
+		shares: NewShareService(client),
+	}
+}
+
+// sealKit encrypts the items with a new emergency key wrapped for the contact.
+func sealKit(items []sharedItem, contactKey sharing.Key) (*keeperclient.EmergencyKit, error) {
+	plain, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	key, err := sharing.NewItemKey()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := sharing.Encrypt(key, plain)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := sharing.WrapKey(key, contactKey)
+	if err != nil {
+		return nil, err
+	}
+	return &keeperclient.EmergencyKit{Payload: payload, WrappedKey: wrapped}, nil
+}
+
+// openKit decrypts the emergency kit given to the owner of the keys.
+func openKit(kit *keeperclient.EmergencyKit, keys *sharing.KeyPair) ([]sharedItem, error) {
+	key, err := sharing.UnwrapKey(kit.WrappedKey, keys)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := sharing.Decrypt(key, kit.Payload)
+	if err != nil {
+		return nil, err
+	}
+	var items []sharedItem
+	if err := json.Unmarshal(plain, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// SetKeyCache caches the private key once it is unlocked with the master password.
+func (es *EmergencyService) SetKeyCache(keys KeyCache) {
+	es.shares.SetKeyCache(keys)
+}
+
+// SetAccountKeyStore sets where the account key is kept on this device.
+func (es *EmergencyService) SetAccountKeyStore(accountKeys AccountKeyStore) {
+	es.shares.SetAccountKeyStore(accountKeys)
+}
+
+// vaultSnapshot returns the current fields of all items of the user except files.
+func (es *EmergencyService) vaultSnapshot(ctx context.Context) ([]sharedItem, error) {
+	items, err := es.shares.vault.Items(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]sharedItem, 0, len(items))
+	for _, it := range items {
+		if it.Kind == keeperclient.KindFile {
+			continue
+		}
+		item, err := snapshot(ctx, es.shares.vault, it.Kind, it.Title)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *item)
+	}
+	return res, nil
+}
+
+// put seals the snapshot of the vault for the contact and stores it with the waiting period.
+func (es *EmergencyService) put(ctx context.Context, login string, wait time.Duration, items []sharedItem) error {
+	pub, err := publicKey(ctx, es.client, login)
+	if err != nil {
+		return err
+	}
+
+	kit, err := sealKit(items, pub)
+	if err != nil {
+		return err
+	}
+	if err := es.client.PutTrustedContact(ctx, login, wait, *kit); err != nil {
+		return err
+	}
+	fmt.Printf("%s\t%s\n", login, sharing.Fingerprint(pub))
+	return nil
+}
+
+// AddContact designates the user with the login as a trusted contact, or changes the waiting period
+// of a designated one, and gives it the emergency kit with the current vault.
+// The fingerprint of the contact key is printed to be compared with the one the contact sees.
+func (es *EmergencyService) AddContact(login string, wait time.Duration) error {
+	if err := emergency.ValidWait(wait); err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	items, err := es.vaultSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	if err := es.put(ctx, login, wait, items); err != nil {
+		return err
+	}
+	log.Println("Trusted contact is saved.")
+	return nil
+}
+
+// UpdateKits gives every trusted contact a new emergency kit with the current vault.
+func (es *EmergencyService) UpdateKits() error {
+	ctx := context.Background()
+
+	contacts, err := es.client.TrustedContacts(ctx)
+	if err != nil {
+		return err
+	}
+	if len(contacts) == 0 {
+		fmt.Println("No trusted contacts.")
+		return nil
+	}
+
+	items, err := es.vaultSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	// a contact whose kit can not be sealed does not keep the others outdated
+	var errs []error
+	for _, c := range contacts {
+		if err := es.put(ctx, c.Contact, time.Duration(c.WaitSeconds)*time.Second, items); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	log.Println("Emergency kits are updated.")
+	return nil
+}
+
+// RemoveContact removes the trusted contact with its kit.
+func (es *EmergencyService) RemoveContact(login string) error {
+	err := es.client.DeleteTrustedContact(context.Background(), login)
+	if errors.Is(err, keeperclient.ErrNotFound) {
+		return fmt.Errorf("%s is not your trusted contact", login)
+	}
+	if err != nil {
+		return err
+	}
+	log.Println("Trusted contact is removed.")
+	return nil
+}
+
+// Reject rejects the pending request of the contact during its waiting period.
+func (es *EmergencyService) Reject(login string) error {
+	err := es.client.RejectEmergencyAccess(context.Background(), login)
+	if errors.Is(err, keeperclient.ErrNotFound) {
+		return fmt.Errorf("%s is not your trusted contact", login)
+	}
+	if errors.Is(err, keeperclient.ErrConflict) {
+		return fmt.Errorf("%s has no pending request: it is not requested or the waiting period has passed"+
+			" and the access is granted (remove-trusted-contact ends it)", login)
+	}
+	if err != nil {
+		return err
+	}
+	log.Println("Emergency access is rejected.")
+	return nil
+}
+
+// Contacts displays the trusted contacts of the user with the state of their access.
+func (es *EmergencyService) Contacts() error {
+	contacts, err := es.client.TrustedContacts(context.Background())
+	if err != nil {
+		return err
+	}
+	if len(contacts) == 0 {
+		fmt.Println("No trusted contacts.")
+		return nil
+	}
+	renderTrustedContacts("Contact", contacts, func(c keeperclient.TrustedContact) string { return c.Contact })
+	return nil
+}
+
+// Grantors displays the users who designated the user as their trusted contact.
+func (es *EmergencyService) Grantors() error {
+	grantors, err := es.client.Grantors(context.Background())
+	if err != nil {
+		return err
+	}
+	if len(grantors) == 0 {
+		fmt.Println("Nobody designated you as a trusted contact.")
+		return nil
+	}
+	renderTrustedContacts("Owner", grantors, func(c keeperclient.TrustedContact) string { return c.Owner })
+	return nil
+}
+
+func renderTrustedContacts(header string, contacts []keeperclient.TrustedContact,
+	login func(keeperclient.TrustedContact) string) {
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{header, "Waiting period", "Status", "Granted at", "Kit updated"})
+	for _, c := range contacts {
+		grantAt := ""
+		if c.GrantAt != nil {
+			grantAt = c.GrantAt.Local().Format("2006-01-02 15:04")
+		}
+		t.AppendRow(table.Row{login(c), time.Duration(c.WaitSeconds) * time.Second, c.Status, grantAt,
+			c.UpdatedAt.Local().Format("2006-01-02 15:04")})
+	}
+	t.Render()
+}
+
+// Request requests emergency access to the vault of the owner. The access is granted
+// when the waiting period passes unless the owner rejects the request.
+func (es *EmergencyService) Request(owner string) error {
+	err := es.client.RequestEmergencyAccess(context.Background(), owner)
+	if errors.Is(err, keeperclient.ErrNotFound) {
+		return fmt.Errorf("%s has not designated you as a trusted contact", owner)
+	}
+	if errors.Is(err, keeperclient.ErrConflict) {
+		return fmt.Errorf("emergency access to %s is already requested", owner)
+	}
+	if err != nil {
+		return err
+	}
+	log.Println("Emergency access is requested.")
+	return nil
+}
+
+// Access decrypts and displays the emergency kit of the owner once access is granted.
+// masterPassword is called if the private key is not cached.
+func (es *EmergencyService) Access(owner string, masterPassword func() (string, error)) error {
+	ctx := context.Background()
+
+	kit, err := es.client.EmergencyKit(ctx, owner)
+	if errors.Is(err, keeperclient.ErrNotFound) {
+		return fmt.Errorf("%s has not designated you as a trusted contact", owner)
+	}
+	if errors.Is(err, keeperclient.ErrUnauthorized) {
+		return fmt.Errorf("emergency access to %s is not granted, see emergency-grantors", owner)
+	}
+	if err != nil {
+		return err
+	}
+	keys, err := es.shares.unlockKeys(ctx, masterPassword)
+	if err != nil {
+		return err
+	}
+	items, err := openKit(kit, keys)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		fmt.Printf("%s %s\n", item.Kind, item.Title)
+		printSharedItem(&item)
+	}
+	return nil
+}
+
+// Events displays the latest steps of emergency access of the user, newest first.
+func (es *EmergencyService) Events() error {
+	events, err := es.client.EmergencyEvents(context.Background())
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		fmt.Println("No emergency access events.")
+		return nil
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Time", "Owner", "Contact", "Event"})
+	for _, e := range events {
+		t.AppendRow(table.Row{e.CreatedAt.Local().Format("2006-01-02 15:04"), e.Owner, e.Contact, e.Event})
+	}
+	t.Render()
+	return nil
+}
+
+// Banner prints the steps of emergency access the user has not been notified of yet.
+// It is shown after other commands, so errors are ignored.
+func (es *EmergencyService) Banner(w io.Writer) {
+	ctx, cancel := context.WithTimeout(context.Background(), bannerTimeout)
+	defer cancel()
+
+	events, err := es.client.Notifications(ctx)
+	if err != nil {
+		return
+	}
+	for _, e := range events {
+		fmt.Fprintln(w, notificationText(e))
+	}
+}
+
+// notificationText describes the event to the party it is notified to.
+func notificationText(e keeperclient.EmergencyEvent) string {
+	var text string
+	switch emergency.Event(e.Event) {
+	case emergency.EventAdded:
+		text = e.Owner + " designated you as a trusted contact"
+	case emergency.EventUpdated:
+		text = e.Owner + " updated your emergency kit"
+	case emergency.EventRequested:
+		text = e.Contact + " requested emergency access to your vault. Reject with: reject-emergency --login " + e.Contact
+	case emergency.EventRejected:
+		text = e.Owner + " rejected your emergency access"
+	case emergency.EventGranted:
+		text = e.Contact + " was granted emergency access to your vault"
+	case emergency.EventViewed:
+		text = e.Contact + " opened your emergency kit"
+	case emergency.EventRemoved:
+		text = e.Owner + " removed you from trusted contacts"
+	default:
+		text = e.Owner + ", " + e.Contact + ": " + e.Event
+	}
+	return fmt.Sprintf("Emergency access (%s): %s.", e.CreatedAt.Local().Format("2006-01-02 15:04"), text)
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/adettelle/go-keeper/pkg/sharing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmergencyKit(t *testing.T) {
+	bob, err := sharing.GenerateKeyPair()
+	require.NoError(t, err)
+	eve, err := sharing.GenerateKeyPair()
+	require.NoError(t, err)
+
+	items := []sharedItem{
+		{Kind: keeperclient.KindPassword, Title: "mail",
+			Fields: []sharedField{{Name: FieldPassword, Value: "s3cret", Secret: true}}},
+		{Kind: keeperclient.KindIdentity, Title: "passport", Fields: []sharedField{{Name: "number", Value: "1234"}}},
+	}
+
+	kit, err := sealKit(items, bob.Public)
+	require.NoError(t, err)
+	require.NotContains(t, kit.Payload, "s3cret")
+
+	got, err := openKit(kit, bob)
+	require.NoError(t, err)
+	require.Equal(t, items, got)
+
+	// набор открывается только ключом доверенного контакта
+	_, err = openKit(kit, eve)
+	require.ErrorIs(t, err, sharing.ErrDecrypt)
+}
+
+func TestEmergencyKitLegacyContact(t *testing.T) {
+	bob, err := sharing.GenerateKeyPair()
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// набор не отправляется контакту, закрытый ключ которого сервер может открыть
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/api/user/keys/bob", r.URL.Path)
+		_ = json.NewEncoder(w).Encode(keeperclient.UserPublicKey{
+			Login: "bob", PublicKey: sharing.EncodeKey(bob.Public), LegacySealed: true,
+		})
+	}))
+	t.Cleanup(srv.Close)
+	c, err := keeperclient.New(keeperclient.Config{BaseURL: srv.URL, Tokens: keeperclient.StaticToken("Bearer token")})
+	require.NoError(t, err)
+
+	es := NewEmergencyService(c)
+	err = es.put(context.Background(), "bob", time.Hour, nil)
+	require.ErrorContains(t, err, "init-keys")
+}
+
+func TestNotificationText(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local)
+
+	require.Equal(t,
+		"Emergency access (2026-01-02 03:04): bob@aaa.com requested emergency access to your vault. "+
+			"Reject with: reject-emergency --login bob@aaa.com.",
+		notificationText(keeperclient.EmergencyEvent{Owner: "ane@aaa.com", Contact: "bob@aaa.com",
+			Event: "requested", CreatedAt: createdAt}))
+	require.Equal(t,
+		"Emergency access (2026-01-02 03:04): ane@aaa.com rejected your emergency access.",
+		notificationText(keeperclient.EmergencyEvent{Owner: "ane@aaa.com", Contact: "bob@aaa.com",
+			Event: "rejected", CreatedAt: createdAt}))
+}
@@ -5,185 +5,88 @@ package client
 import (
 	"context"
 	"log"
-	"net/http"
 	"os"
-	"strconv"
+	"strings"
 
-	"github.com/adettelle/go-keeper/cmd/settings"
-	"github.com/adettelle/go-keeper/internal/localstorage"
-	"github.com/carlmjohnson/requests"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
 	"github.com/jedib0t/go-pretty/v6/table"
 )
 
 // FileService is a service for managing file-related operations.
 type FileService struct {
-	transport *http.Transport
-	keyStore  localstorage.IKeyStorage
+	client *keeperclient.Client
 }
 
-func NewFileService(transport *http.Transport, keyStore localstorage.IKeyStorage) *FileService {
+func NewFileService(client *keeperclient.Client) *FileService {
 	return &FileService{
-		transport: transport,
-		keyStore:  keyStore,
+		client: client,
 	}
 }
 
-// FileToGetAll retrieves all files associated with the user.
-// It fetches file data from the server and displays it in a tabular format.
-type FileToGetAll struct {
-	FileName    string `json:"file_name"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-}
-
-func (fs *FileService) AllFiles() error {
-	jwtToken, err := fs.keyStore.Get()
+// AllFiles retrieves files associated with the user, filtered by the options.
+// It fetches file data from the server and displays it in a tabular format, favorites first.
+func (fs *FileService) AllFiles(opts keeperclient.ListOptions) error {
+	files, next, err := fs.client.Files(context.Background(), opts)
 	if err != nil {
 		return err
 	}
 
-	var files []FileToGetAll
-
-	err = requests.
-		URL("/api/user/files").
-		Host(settings.ServerURL).
-		Scheme("https").
-		Transport(fs.transport).
-		Header("Authorization", string(jwtToken)).
-		ToJSON(&files).
-		Method(http.MethodGet).
-		Fetch(context.Background())
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"", "Title", "File Name", "Folder", "Tags", "Description"})
 
-	if err != nil {
-		return err
-	} else {
-		t := table.NewWriter()
-		t.SetOutputMirror(os.Stdout)
-		t.AppendHeader(table.Row{"Title", "File Name", "Description"})
-
-		for _, file := range files {
-			t.AppendRow([]interface{}{file.Title, file.FileName, file.Description})
-		}
-		t.Render()
+	for _, file := range files {
+		t.AppendRow([]interface{}{favoriteMark(file.ItemMeta), file.Title, file.FileName,
+			file.Folder, strings.Join(file.Tags, ", "), file.Description})
 	}
+	t.Render()
+	printNextCursor(next)
 	return nil
 }
 
 func (fs *FileService) DeleteFileByTitle(title string) error {
-	jwtToken, err := fs.keyStore.Get()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	err = requests.
-		URL("/api/user/file/"+title).
-		Host(settings.ServerURL).
-		Scheme("https").
-		Transport(fs.transport).
-		Header("Authorization", string(jwtToken)).
-		Method(http.MethodDelete).
-		Fetch(context.Background())
-
+	err := fs.client.DeleteFile(context.Background(), title)
 	if err != nil {
 		return err
-	} else {
-		log.Println("File is deleted.")
 	}
+	log.Println("File is deleted.")
 	return nil
 }
 
 func (fs *FileService) AddFile(fileName, title, description string) error {
-	jwtToken, err := fs.keyStore.Get()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	fileStat, err := os.Stat(fileName)
+	f, err := os.Open(fileName)
 	if err != nil {
 		return err
 	}
+	defer f.Close()
 
-	err = requests.
-		URL("/api/user/file").
-		Host(settings.ServerURL).
-		Scheme("https").
-		Header("Authorization", string(jwtToken)).
-		Header("x-file-name", fileName).
-		Header("x-file-title", title).
-		Header("x-file-description", description).
-		Header("Content-Length", strconv.Itoa(int(fileStat.Size()))).
-		Transport(fs.transport).
-		BodyFile(fileName).
-		Method(http.MethodPut).
-		Fetch(context.Background())
-
+	fileStat, err := f.Stat()
 	if err != nil {
 		return err
 	}
 
-	return nil
-}
-
-type FileToGetByTitle struct {
-	Title       string `json:"title"`
-	FileName    string `json:"fname"`
-	Description string `json:"description"`
+	return fs.client.AddFile(context.Background(), keeperclient.FileToAdd{
+		FileName:    fileName,
+		Title:       title,
+		Description: description,
+	}, f, fileStat.Size())
 }
 
+// GetFile writes the file content to stdout.
 func (fs *FileService) GetFile(title string) error {
-	jwtToken, err := fs.keyStore.Get()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	err = requests.
-		URL("/api/user/file/"+title).
-		Host(settings.ServerURL).
-		Scheme("https").
-		Header("Authorization", string(jwtToken)).
-		Transport(fs.transport).
-		Method(http.MethodGet).
-		ToWriter(os.Stdout).
-		Fetch(context.Background())
-
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-type FileToUpdate struct {
-	FileName    string `json:"fname,omitempty"`
-	Description string `json:"description,omitempty"`
+	return fs.client.File(context.Background(), title, os.Stdout)
 }
 
 // UpdateFile updates file's name and description by unique title.
 // It updates only arguments which are provided.
-func (ps *FileService) UpdateFile(title string, args ...string) error {
-	jwtToken, err := ps.keyStore.Get()
-	if err != nil {
-		return err
-	}
-
-	file := FileToUpdate{
-		FileName:    args[0],
-		Description: args[1],
-	}
-
-	err = requests.
-		URL("/api/user/file/update/"+title).
-		Host(settings.ServerURL).
-		Scheme("https").
-		Transport(ps.transport).
-		Header("Authorization", string(jwtToken)).
-		BodyJSON(&file).
-		Method(http.MethodPost).
-		Fetch(context.Background())
-
+func (fs *FileService) UpdateFile(title, fileName, description string) error {
+	err := fs.client.UpdateFile(context.Background(), title, keeperclient.FileToUpdate{
+		FileName:    fileName,
+		Description: description,
+	})
 	if err != nil {
 		return err
-	} else {
-		log.Println("File info is updated.")
 	}
+	log.Println("File info is updated.")
 	return nil
 }
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/health"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// HealthService is a service for checking the vault health.
+type HealthService struct {
+	client *keeperclient.Client
+}
+
+func NewHealthService(client *keeperclient.Client) *HealthService {
+	return &HealthService{
+		client: client,
+	}
+}
+
+// Report fetches all passwords and cards and prints weak, reused and old passwords
+// and cards which expire within expiryWindow. All checks are done locally.
+func (hs *HealthService) Report(maxAge, expiryWindow time.Duration) error {
+	ctx := context.Background()
+
+	pwds, _, err := hs.client.Passwords(ctx, keeperclient.ListOptions{})
+	if err != nil {
+		return err
+	}
+	passwords := make([]health.Password, 0, len(pwds))
+	for _, pwd := range pwds {
+		value, err := hs.client.Password(ctx, pwd.Title)
+		if err != nil {
+			return err
+		}
+		passwords = append(passwords, health.Password{Title: pwd.Title, Value: value, ChangedAt: pwd.ChangedAt})
+	}
+
+	cardList, _, err := hs.client.Cards(ctx, keeperclient.ListOptions{})
+	if err != nil {
+		return err
+	}
+	cards := make([]health.Card, 0, len(cardList))
+	for _, c := range cardList {
+		card, err := hs.client.Card(ctx, c.Title)
+		if err != nil {
+			return err
+		}
+		cards = append(cards, health.Card{Title: c.Title, Expire: card.Expire})
+	}
+
+	report := health.Check(passwords, cards, health.Options{MaxAge: maxAge, ExpiryWindow: expiryWindow})
+	if report.Empty() {
+		fmt.Println("No problems found.")
+		return nil
+	}
+
+	if len(report.Weak) > 0 {
+		t := newReportTable("Weak passwords", table.Row{"Title", "Score", "Warning"})
+		for _, w := range report.Weak {
+			t.AppendRow(table.Row{w.Title, fmt.Sprintf("%d/4", w.Score), w.Warning})
+		}
+		t.Render()
+	}
+
+	if len(report.Reused) > 0 {
+		t := newReportTable("Reused passwords", table.Row{"Titles"})
+		for _, titles := range report.Reused {
+			t.AppendRow(table.Row{strings.Join(titles, ", ")})
+		}
+		t.Render()
+	}
+
+	if len(report.Old) > 0 {
+		t := newReportTable("Old passwords", table.Row{"Title", "Changed", "Age, days"})
+		for _, o := range report.Old {
+			t.AppendRow(table.Row{o.Title, o.ChangedAt.Format(time.DateOnly), int(o.Age.Hours() / 24)})
+		}
+		t.Render()
+	}
+
+	if len(report.Expiring) > 0 || len(report.Invalid) > 0 {
+		t := newReportTable("Cards", table.Row{"Title", "Valid through", "Status"})
+		for _, c := range report.Expiring {
+			status := "expires soon"
+			if c.Expired {
+				status = "expired"
+			}
+			t.AppendRow(table.Row{c.Title, c.ExpiresAt.AddDate(0, 0, -1).Format("01/06"), status})
+		}
+		for _, title := range report.Invalid {
+			t.AppendRow(table.Row{title, "", "invalid expiry date"})
+		}
+		t.Render()
+	}
+	return nil
+}
+
+func newReportTable(title string, header table.Row) table.Writer {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetTitle(title)
+	t.AppendHeader(header)
+	return t
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/adettelle/go-keeper/internal/identity"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// IdentityService is a service for managing identity documents: passports, driver licenses,
+// national IDs and addresses.
+type IdentityService struct {
+	client *keeperclient.Client
+}
+
+func NewIdentityService(client *keeperclient.Client) *IdentityService {
+	return &IdentityService{
+		client: client,
+	}
+}
+
+// PrintIdentityTypes displays the document types with their fields in a tabular format.
+func PrintIdentityTypes() {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Type", "Field", "Value", "Required"})
+	for _, docType := range identity.DocTypes {
+		schema, _ := identity.Schema(docType)
+		for _, f := range schema {
+			required := ""
+			if f.Required {
+				required = "yes"
+			}
+			t.AppendRow(table.Row{docType, f.Name, fieldTypeHint(f.Type), required})
+		}
+		t.AppendSeparator()
+	}
+	t.Render()
+}
+
+func fieldTypeHint(ft identity.FieldType) string {
+	switch ft {
+	case identity.FieldDate:
+		return "date, YYYY-MM-DD"
+	case identity.FieldCountry:
+		return "country code, e.g. DE"
+	case identity.FieldDocNumber:
+		return "document number"
+	default:
+		return "text"
+	}
+}
+
+// AllIdentities retrieves identity documents associated with the user, filtered by the options,
+// and displays them in a tabular format, favorites first. Document fields are not shown.
+func (is *IdentityService) AllIdentities(opts keeperclient.ListOptions) error {
+	docs, next, err := is.client.Identities(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"", "Title", "Type", "Folder", "Tags", "Description"})
+
+	for _, doc := range docs {
+		t.AppendRow([]interface{}{favoriteMark(doc.ItemMeta), doc.Title, doc.DocType,
+			doc.Folder, strings.Join(doc.Tags, ", "), doc.Description})
+	}
+	t.Render()
+	printNextCursor(next)
+	return nil
+}
+
+// GetIdentityByTitle prints the identity document fields in the schema order.
+func (is *IdentityService) GetIdentityByTitle(title string) error {
+	doc, err := is.client.Identity(context.Background(), title)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("type: %s\n", doc.DocType)
+	schema, _ := identity.Schema(doc.DocType)
+	for _, f := range schema {
+		if value := doc.Fields[f.Name]; value != "" {
+			fmt.Printf("%s: %s\n", f.Name, value)
+		}
+	}
+	if doc.Description != "" {
+		fmt.Printf("description: %s\n", doc.Description)
+	}
+	return nil
+}
+
+// AddIdentity stores a new identity document. The fields are validated by the document type schema
+// before sending it to the server.
+func (is *IdentityService) AddIdentity(doc keeperclient.IdentityToAdd) error {
+	err := is.client.AddIdentity(context.Background(), doc)
+	if err != nil {
+		return err
+	}
+	log.Println("Identity is added.")
+	return nil
+}
+
+// UpdateIdentity sets and removes the fields and changes the description (if not empty)
+// of the identity document by unique title.
+func (is *IdentityService) UpdateIdentity(title, description string, setFields map[string]string,
+	removeFields []string) error {
+
+	doc := keeperclient.IdentityToUpdate{Fields: map[string]*string{}}
+	if description != "" {
+		doc.Description = &description
+	}
+	for name, value := range setFields {
+		doc.Fields[name] = &value
+	}
+	for _, name := range removeFields {
+		doc.Fields[name] = nil
+	}
+
+	err := is.client.UpdateIdentity(context.Background(), title, doc)
+	if err != nil {
+		return err
+	}
+	log.Println("Identity is updated.")
+	return nil
+}
+
+func (is *IdentityService) DeleteIdentityByTitle(title string) error {
+	err := is.client.DeleteIdentity(context.Background(), title)
+	if err != nil {
+		return err
+	}
+	log.Println("Identity is deleted.")
+	return nil
+}
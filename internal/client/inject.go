@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/adettelle/go-keeper/internal/secretref"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+)
+
+type InjectService struct {
+	resolver *Resolver
+}
+
+func NewInjectService(client *keeperclient.Client) *InjectService {
+	return &InjectService{resolver: NewResolver(client)}
+}
+
+// Inject renders the template file (stdin if inPath is empty or "-") with the secret references resolved
+// and writes the result to stdout, or to outPath with 0600 permissions. If any reference is not resolved,
+// nothing is written.
+func (is *InjectService) Inject(inPath, outPath string) error {
+	var text []byte
+	var err error
+	if inPath == "" || inPath == "-" {
+		inPath = "stdin"
+		text, err = io.ReadAll(os.Stdin)
+	} else {
+		text, err = os.ReadFile(inPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	out, err := secretref.Render(filepath.Base(inPath), string(text), func(ref secretref.Ref) (string, error) {
+		return is.resolver.Resolve(context.Background(), ref)
+	})
+	if err != nil {
+		return err
+	}
+
+	if outPath == "" {
+		_, err = os.Stdout.Write(out)
+		return err
+	}
+	return writeFilePrivate(outPath, out)
+}
+
+// writeFilePrivate replaces the file with the data. The data is written to a temporary file with 0600
+// permissions in the same directory first, so the file is never left half-written or readable by others.
+func writeFilePrivate(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// ItemService is a service for organizing items of all kinds: folders, tags and favorites.
+type ItemService struct {
+	client *keeperclient.Client
+}
+
+func NewItemService(client *keeperclient.Client) *ItemService {
+	return &ItemService{
+		client: client,
+	}
+}
+
+// Move moves the item to the folder; an empty folder moves it to the root.
+func (is *ItemService) Move(kind keeperclient.ItemKind, title, folder string) error {
+	err := is.client.UpdateItemMeta(context.Background(), kind, title, keeperclient.ItemMetaUpdate{
+		Folder: &folder,
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("Item is moved.")
+	return nil
+}
+
+// Retag replaces the item tags; no tags clears them.
+func (is *ItemService) Retag(kind keeperclient.ItemKind, title string, tags []string) error {
+	if tags == nil {
+		tags = []string{}
+	}
+	err := is.client.UpdateItemMeta(context.Background(), kind, title, keeperclient.ItemMetaUpdate{
+		Tags: &tags,
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("Item tags are changed.")
+	return nil
+}
+
+// SetFavorite marks or unmarks the item as favorite.
+func (is *ItemService) SetFavorite(kind keeperclient.ItemKind, title string, favorite bool) error {
+	err := is.client.UpdateItemMeta(context.Background(), kind, title, keeperclient.ItemMetaUpdate{
+		Favorite: &favorite,
+	})
+	if err != nil {
+		return err
+	}
+	if favorite {
+		log.Println("Item is added to favorites.")
+	} else {
+		log.Println("Item is removed from favorites.")
+	}
+	return nil
+}
+
+// AllFolders displays the folders as a tree.
+func (is *ItemService) AllFolders() error {
+	folders, err := is.client.Folders(context.Background())
+	if err != nil {
+		return err
+	}
+
+	// sort by path segments, so that subfolders follow their parents
+	paths := make([][]string, 0, len(folders))
+	for _, folder := range folders {
+		paths = append(paths, strings.Split(folder, "/"))
+	}
+	slices.SortFunc(paths, slices.Compare)
+
+	// parent folders without own items are shown too
+	printed := map[string]bool{}
+	for _, segments := range paths {
+		for i := range segments {
+			path := strings.Join(segments[:i+1], "/")
+			if printed[path] {
+				continue
+			}
+			printed[path] = true
+			fmt.Printf("%s%s/\n", strings.Repeat("  ", i), segments[i])
+		}
+	}
+	return nil
+}
+
+// Search finds items of all kinds and displays them in a tabular format, favorites first.
+func (is *ItemService) Search(opts keeperclient.ListOptions) error {
+	items, next, err := is.client.SearchItems(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"", "Kind", "Title", "Folder", "Tags", "Description"})
+
+	for _, item := range items {
+		t.AppendRow([]interface{}{favoriteMark(item.ItemMeta), item.Kind, item.Title,
+			item.Folder, strings.Join(item.Tags, ", "), item.Description})
+	}
+	t.Render()
+	printNextCursor(next)
+	return nil
+}
+
+// printNextCursor tells how to get the next page of a listing, if there is one.
+// It is written to stderr so that stdout holds only the listing.
+func printNextCursor(next string) {
+	if next != "" {
+		fmt.Fprintf(os.Stderr, "More items: repeat with --cursor %s\n", next)
+	}
+}
+
+func favoriteMark(meta keeperclient.ItemMeta) string {
+	if meta.Favorite {
+		return "★"
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/adettelle/go-keeper/pkg/sharing"
+)
+
+// LinkService creates secret links to items for people without an account, and opens them.
+//
+// A secret link holds a snapshot of the item encrypted with a random link key. The key is only kept
+// in the fragment of the link, so the server stores a ciphertext it can not read. Files are shared with
+// presigned links of the object storage instead: the server decides how long they are valid,
+// and they can be downloaded any number of times meanwhile.
+type LinkService struct {
+	client *keeperclient.Client
+	vault  *VaultService
+}
+
+func NewLinkService(client *keeperclient.Client) *LinkService {
+	return &LinkService{
+		client: client,
+		vault:  NewVaultService(client, nil),
+	}
+}
+
+// Defaults of secret links to items other than files.
+const (
+	DefaultLinkTTL   = time.Hour
+	DefaultLinkViews = 1
+)
+
+// ShareLink creates a link to the item, which can be opened views times until the ttl passes, and prints it.
+// Nil ttl and views take the defaults. Files have no ttl and views of their own and reject them.
+func (ls *LinkService) ShareLink(kind keeperclient.ItemKind, title string, ttl *time.Duration, views *int) error {
+	ctx := context.Background()
+
+	if kind == keeperclient.KindFile {
+		if ttl != nil || views != nil {
+			return fmt.Errorf("--ttl and --views do not apply to files: " +
+				"file links are valid for the lifetime set on the server and can be downloaded any number of times")
+		}
+		link, err := ls.client.CreateFileLink(ctx, title)
+		if err != nil {
+			return err
+		}
+		fmt.Println(link.URL)
+		fmt.Println("Anybody with the link can download the file until", link.ExpiresAt.Local().Format("2006-01-02 15:04:05"))
+		return nil
+	}
+
+	item, err := snapshot(ctx, ls.vault, kind, title)
+	if err != nil {
+		return err
+	}
+	plain, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	key, err := sharing.NewLinkKey()
+	if err != nil {
+		return err
+	}
+	payload, err := sharing.SealLink(key, plain)
+	if err != nil {
+		return err
+	}
+
+	linkTTL, linkViews := DefaultLinkTTL, DefaultLinkViews
+	if ttl != nil {
+		linkTTL = *ttl
+	}
+	if views != nil {
+		linkViews = *views
+	}
+
+	link, err := ls.client.CreateLink(ctx, payload, linkTTL, linkViews)
+	if errors.Is(err, keeperclient.ErrBadRequest) {
+		return fmt.Errorf("the link may live from a minute to a week and be opened 1 to 100 times")
+	}
+	if err != nil {
+		return err
+	}
+	log.Println("Link is created.")
+
+	fmt.Println(ls.client.LinkURL(link.ID, key))
+	fmt.Printf("The link can be opened %d time(s) until %s\n", linkViews, link.ExpiresAt.Local().Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+// parseLink returns the id and the key of a secret link, https://host/s/<id>#<key>.
+func parseLink(rawURL string) (string, string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+	id, ok := strings.CutPrefix(u.Path, "/s/")
+	if !ok || id == "" || strings.Contains(id, "/") || u.Fragment == "" {
+		return "", "", fmt.Errorf("not a secret link: %s", rawURL)
+	}
+	return id, u.Fragment, nil
+}
+
+// OpenLink opens the secret link of the configured server and prints the item. It uses up one of the link views.
+func (ls *LinkService) OpenLink(rawURL string) error {
+	id, key, err := parseLink(rawURL)
+	if err != nil {
+		return err
+	}
+
+	payload, err := ls.client.OpenLink(context.Background(), id)
+	if errors.Is(err, keeperclient.ErrNotFound) {
+		return fmt.Errorf("the link has expired or has already been used")
+	}
+	if err != nil {
+		return err
+	}
+
+	plain, err := sharing.OpenLink(key, payload)
+	if err != nil {
+		return fmt.Errorf("the link is damaged: %w", err)
+	}
+	var item sharedItem
+	if err := json.Unmarshal(plain, &item); err != nil {
+		return err
+	}
+	printSharedItem(&item)
+	return nil
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareLinkFileOptions(t *testing.T) {
+	ls := &LinkService{}
+	ttl := 10 * time.Minute
+	views := 3
+
+	// для файлов срок и число просмотров задаёт сервер, флаги отклоняются до обращения к серверу
+	require.Error(t, ls.ShareLink(keeperclient.KindFile, "contract", &ttl, nil))
+	require.Error(t, ls.ShareLink(keeperclient.KindFile, "contract", nil, &views))
+}
+
+func TestParseLink(t *testing.T) {
+	id, key, err := parseLink("https://keeper.example.com/s/abc_-1#a2V5")
+	require.NoError(t, err)
+	require.Equal(t, "abc_-1", id)
+	require.Equal(t, "a2V5", key)
+
+	for _, link := range []string{
+		"https://keeper.example.com/s/abc",
+		"https://keeper.example.com/web/#a2V5",
+		"https://keeper.example.com/s/#a2V5",
+		"https://keeper.example.com/s/abc/def#a2V5",
+	} {
+		_, _, err := parseLink(link)
+		require.Error(t, err, link)
+	}
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// NoteService is a service for managing secure notes: free-form text such as recovery codes,
+// license keys or instructions.
+type NoteService struct {
+	client *keeperclient.Client
+}
+
+func NewNoteService(client *keeperclient.Client) *NoteService {
+	return &NoteService{
+		client: client,
+	}
+}
+
+// AllNotes retrieves secure notes associated with the user, filtered by the options,
+// and displays them in a tabular format, favorites first. The text is not shown.
+func (ns *NoteService) AllNotes(opts keeperclient.ListOptions) error {
+	notes, next, err := ns.client.Notes(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"", "Title", "Folder", "Tags", "Description"})
+
+	for _, note := range notes {
+		t.AppendRow([]interface{}{favoriteMark(note.ItemMeta), note.Title,
+			note.Folder, strings.Join(note.Tags, ", "), note.Description})
+	}
+	t.Render()
+	printNextCursor(next)
+	return nil
+}
+
+// NoteField returns a single field of the note: text or description.
+func (ns *NoteService) NoteField(title, field string) (string, error) {
+	note, err := ns.client.Note(context.Background(), title)
+	if err != nil {
+		return "", err
+	}
+	value, ok := noteValue(note, field)
+	if !ok {
+		return "", fmt.Errorf("note %q has no field %q", title, field)
+	}
+	return value, nil
+}
+
+// GetNoteByTitle prints the note text as it is stored, so that it can be redirected into a file.
+func (ns *NoteService) GetNoteByTitle(title string) error {
+	text, err := ns.NoteField(title, FieldText)
+	if err != nil {
+		return err
+	}
+	fmt.Print(text)
+	if !strings.HasSuffix(text, "\n") {
+		fmt.Println()
+	}
+	return nil
+}
+
+// GetNoteField prints a single field of the note, see NoteField.
+func (ns *NoteService) GetNoteField(title, field string) error {
+	value, err := ns.NoteField(title, field)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", value)
+	return nil
+}
+
+func (ns *NoteService) AddNote(note keeperclient.NoteToAdd) error {
+	err := ns.client.AddNote(context.Background(), note)
+	if err != nil {
+		return err
+	}
+	log.Println("Note is added.")
+	return nil
+}
+
+// UpdateNote replaces the text (if not nil) and changes the description (if not empty) of the note.
+func (ns *NoteService) UpdateNote(title, description string, text *string) error {
+	note := keeperclient.NoteToUpdate{Text: text}
+	if description != "" {
+		note.Description = &description
+	}
+	err := ns.client.UpdateNote(context.Background(), title, note)
+	if err != nil {
+		return err
+	}
+	log.Println("Note is updated.")
+	return nil
+}
+
+func (ns *NoteService) DeleteNoteByTitle(title string) error {
+	err := ns.client.DeleteNote(context.Background(), title)
+	if err != nil {
+		return err
+	}
+	log.Println("Note is deleted.")
+	return nil
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/adettelle/go-keeper/internal/access"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// OrgService is a service for organizations, their members and shared collections.
+type OrgService struct {
+	client *keeperclient.Client
+}
+
+func NewOrgService(client *keeperclient.Client) *OrgService {
+	return &OrgService{
+		client: client,
+	}
+}
+
+func (ogs *OrgService) CreateOrg(name string) error {
+	if !access.ValidName(name) {
+		return fmt.Errorf("invalid organization name %q: use up to 64 lowercase letters, digits, dots, dashes and underscores", name)
+	}
+	if err := ogs.client.CreateOrg(context.Background(), name); err != nil {
+		return err
+	}
+	log.Println("Organization is created.")
+	return nil
+}
+
+// Orgs displays the organizations of the user with the user roles.
+func (ogs *OrgService) Orgs() error {
+	orgs, err := ogs.client.Orgs(context.Background())
+	if err != nil {
+		return err
+	}
+	if len(orgs) == 0 {
+		fmt.Println("No organizations.")
+		return nil
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Organization", "Role"})
+	for _, o := range orgs {
+		t.AppendRow(table.Row{o.Name, o.Role})
+	}
+	t.Render()
+	return nil
+}
+
+func (ogs *OrgService) DeleteOrg(name string) error {
+	err := ogs.client.DeleteOrg(context.Background(), name)
+	if errors.Is(err, keeperclient.ErrConflict) {
+		return fmt.Errorf("organization %s has collections, delete them first", name)
+	}
+	if err != nil {
+		return err
+	}
+	log.Println("Organization is deleted.")
+	return nil
+}
+
+// Members displays the members of the organization, owners first.
+func (ogs *OrgService) Members(org string) error {
+	members, err := ogs.client.Members(context.Background(), org)
+	if err != nil {
+		return err
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Login", "Name", "Role"})
+	for _, m := range members {
+		t.AppendRow(table.Row{m.Login, m.Name, m.Role})
+	}
+	t.Render()
+	return nil
+}
+
+// SetMember adds the user to the organization or changes the role of the member.
+func (ogs *OrgService) SetMember(org, login, role string) error {
+	if !access.Role(role).Valid() {
+		return fmt.Errorf("unknown role %q, want owner, admin, editor or viewer", role)
+	}
+	err := ogs.client.SetMember(context.Background(), org, login, keeperclient.Role(role))
+	if errors.Is(err, keeperclient.ErrConflict) {
+		return fmt.Errorf("%s is the last owner of %s", login, org)
+	}
+	if err != nil {
+		return err
+	}
+	log.Println("Member is set.")
+	return nil
+}
+
+func (ogs *OrgService) RemoveMember(org, login string) error {
+	err := ogs.client.RemoveMember(context.Background(), org, login)
+	if errors.Is(err, keeperclient.ErrConflict) {
+		return fmt.Errorf("%s is the last owner of %s", login, org)
+	}
+	if err != nil {
+		return err
+	}
+	log.Println("Member is removed.")
+	return nil
+}
+
+func (ogs *OrgService) CreateCollection(org, name string) error {
+	if !access.ValidName(name) {
+		return fmt.Errorf("invalid collection name %q: use up to 64 lowercase letters, digits, dots, dashes and underscores", name)
+	}
+	if err := ogs.client.CreateCollection(context.Background(), org, name); err != nil {
+		return err
+	}
+	log.Println("Collection is created.")
+	return nil
+}
+
+// Collections prints the collections of the organization as references for --collection.
+func (ogs *OrgService) Collections(org string) error {
+	collections, err := ogs.client.Collections(context.Background(), org)
+	if err != nil {
+		return err
+	}
+	if len(collections) == 0 {
+		fmt.Println("No collections.")
+		return nil
+	}
+	for _, name := range collections {
+		fmt.Println(org + "/" + name)
+	}
+	return nil
+}
+
+func (ogs *OrgService) DeleteCollection(org, name string) error {
+	err := ogs.client.DeleteCollection(context.Background(), org, name)
+	if errors.Is(err, keeperclient.ErrConflict) {
+		return fmt.Errorf("collection %s/%s has items, delete them first", org, name)
+	}
+	if err != nil {
+		return err
+	}
+	log.Println("Collection is deleted.")
+	return nil
+}
@@ -3,194 +3,300 @@
 package client
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
+	"slices"
+	"strings"
 
-	"github.com/adettelle/go-keeper/cmd/settings"
-	"github.com/adettelle/go-keeper/internal/localstorage"
-	"github.com/carlmjohnson/requests"
+	"github.com/adettelle/go-keeper/internal/urlmatch"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
 	"github.com/jedib0t/go-pretty/v6/table"
 )
 
 // PasswordService is a service for managing password-related operations.
 type PasswordService struct {
-	transport *http.Transport
-	keyStore  localstorage.IKeyStorage
+	client *keeperclient.Client
 }
 
-func NewPasswordService(transport *http.Transport, keyStore localstorage.IKeyStorage) *PasswordService {
+func NewPasswordService(client *keeperclient.Client) *PasswordService {
 	return &PasswordService{
-		transport: transport,
-		keyStore:  keyStore,
+		client: client,
 	}
 }
 
 func (ps *PasswordService) GetPasswordByTitle(title string) error {
-	jwtToken, err := ps.keyStore.Get()
+	pwd, err := ps.client.Password(context.Background(), title)
 	if err != nil {
 		return err
 	}
+	fmt.Printf("%s\n", pwd)
+	return nil
+}
 
-	var pwd string
-
-	err = requests.
-		URL("/api/user/password/"+title).
-		Host(settings.ServerURL).
-		Scheme("https").
-		Transport(ps.transport).
-		Header("Authorization", string(jwtToken)).
-		Method(http.MethodGet).
-		ToString(&pwd).
-		Fetch(context.Background())
+// Special field names of GetPasswordField besides the custom fields.
+const (
+	FieldPassword = "password"
+	FieldUsername = "username"
+	FieldURL      = "url"
+)
 
+// PasswordField returns a single field of the password entry: the password, the username,
+// the URLs (one per line) or a custom field by name.
+func (ps *PasswordService) PasswordField(title, field string) (string, error) {
+	details, err := ps.client.PasswordDetails(context.Background(), title)
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	// custom fields take precedence, so that a field named e.g. "url" is still reachable
+	if f, ok := details.Field(field); ok {
+		return f.Value, nil
 	}
-	fmt.Printf("%s\n", pwd)
-	return nil
-}
 
-type PasswordToGet struct {
-	// ID          string `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	switch field {
+	case FieldPassword:
+		return details.Password, nil
+	case FieldUsername:
+		return details.Username, nil
+	case FieldURL:
+		var urls []string
+		for _, u := range details.URLs {
+			urls = append(urls, u.URL)
+		}
+		return strings.Join(urls, "\n"), nil
+	}
+	return "", fmt.Errorf("password %q has no field %q", title, field)
 }
 
-// AllPass retrieves all passwords' info (only title and description) associated with the user.
-// It fetches password data from the server and displays it in a tabular format.
-func (ps *PasswordService) AllPass() error {
-	jwtToken, err := ps.keyStore.Get()
+// GetPasswordField prints a single field of the password entry, see PasswordField.
+func (ps *PasswordService) GetPasswordField(title, field string) error {
+	value, err := ps.PasswordField(title, field)
 	if err != nil {
 		return err
 	}
+	if value != "" {
+		fmt.Printf("%s\n", value)
+	}
+	return nil
+}
 
-	var pwds []PasswordToGet
+// ParsePasswordURL parses a URL optionally prefixed with a match rule, e.g. host=https://mail.example.com.
+// Without a prefix the server uses the domain rule.
+func ParsePasswordURL(s string) keeperclient.PasswordURL {
+	rule, rawURL, ok := strings.Cut(s, "=")
+	switch rule {
+	case keeperclient.MatchDomain, keeperclient.MatchHost, keeperclient.MatchExact, keeperclient.MatchNever:
+		if ok {
+			return keeperclient.PasswordURL{URL: rawURL, Match: rule}
+		}
+	}
+	return keeperclient.PasswordURL{URL: s}
+}
 
-	err = requests.
-		URL("/api/user/passwords").
-		Host(settings.ServerURL).
-		Scheme("https").
-		Transport(ps.transport).
-		Header("Authorization", string(jwtToken)).
-		ToJSON(&pwds).
-		Method(http.MethodGet).
-		Fetch(context.Background())
+// DetailsChanges are the changes of the password entry details.
+// Removals are applied before additions, so a field can be replaced in one go.
+type DetailsChanges struct {
+	Username     string // Username is left unchanged if empty.
+	AddURLs      []keeperclient.PasswordURL
+	RemoveURLs   []string
+	SetFields    []keeperclient.PasswordField
+	RemoveFields []string
+	MaxAgeDays   *int // MaxAgeDays is left unchanged if nil.
+}
 
-	if err != nil {
-		return err
-	} else {
-		t := table.NewWriter()
-		t.SetOutputMirror(os.Stdout)
-		t.AppendHeader(table.Row{"Title", "Description"})
+func (dc DetailsChanges) urlsChanged() bool {
+	return len(dc.AddURLs) > 0 || len(dc.RemoveURLs) > 0
+}
 
-		for _, pwd := range pwds {
-			t.AppendRow([]interface{}{pwd.Title, pwd.Description})
+func (dc DetailsChanges) fieldsChanged() bool {
+	return len(dc.SetFields) > 0 || len(dc.RemoveFields) > 0
+}
+
+// mergeURLs removes and adds URLs; adding an existing URL changes its match rule.
+func mergeURLs(urls []keeperclient.PasswordURL, changes DetailsChanges) []keeperclient.PasswordURL {
+	res := []keeperclient.PasswordURL{}
+	for _, u := range urls {
+		if slices.Contains(changes.RemoveURLs, u.URL) ||
+			slices.ContainsFunc(changes.AddURLs, func(a keeperclient.PasswordURL) bool { return a.URL == u.URL }) {
+			continue
 		}
-		t.Render()
+		res = append(res, u)
 	}
-	return nil
+	return append(res, changes.AddURLs...)
 }
 
-type PasswordToUpdate struct {
-	Password    string `json:"pwd,omitempty"`
-	Description string `json:"description,omitempty"`
+// mergeFields removes and sets custom fields; setting an existing field replaces it in place.
+func mergeFields(fields []keeperclient.PasswordField, changes DetailsChanges) []keeperclient.PasswordField {
+	res := []keeperclient.PasswordField{}
+	for _, f := range fields {
+		if slices.Contains(changes.RemoveFields, f.Name) {
+			continue
+		}
+		if i := slices.IndexFunc(changes.SetFields, func(s keeperclient.PasswordField) bool {
+			return s.Name == f.Name
+		}); i >= 0 {
+			f = changes.SetFields[i]
+		}
+		res = append(res, f)
+	}
+	for _, f := range changes.SetFields {
+		if !slices.ContainsFunc(res, func(r keeperclient.PasswordField) bool { return r.Name == f.Name }) {
+			res = append(res, f)
+		}
+	}
+	return res
 }
 
-// UpdateCard updates password itself and description by unique title.
-// It updates only arguments which are provided.
-func (ps *PasswordService) UpdatePassword(title string, args ...string) error {
-	jwtToken, err := ps.keyStore.Get()
+// AllPass retrieves passwords' info (title, description, folder and tags) associated with the user,
+// filtered by the options. It fetches password data from the server and displays it
+// in a tabular format, favorites first.
+func (ps *PasswordService) AllPass(opts keeperclient.ListOptions) error {
+	pwds, next, err := ps.client.Passwords(context.Background(), opts)
 	if err != nil {
 		return err
 	}
 
-	pwd := PasswordToUpdate{
-		Password:    args[0],
-		Description: args[1],
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"", "Title", "Folder", "Tags", "Description"})
+
+	for _, pwd := range pwds {
+		t.AppendRow([]interface{}{favoriteMark(pwd.ItemMeta), pwd.Title,
+			pwd.Folder, strings.Join(pwd.Tags, ", "), pwd.Description})
 	}
+	t.Render()
+	printNextCursor(next)
+	return nil
+}
 
-	err = requests.
-		URL("/api/user/password/update/"+title).
-		Host(settings.ServerURL).
-		Scheme("https").
-		Transport(ps.transport).
-		Header("Authorization", string(jwtToken)).
-		BodyJSON(&pwd).
-		Method(http.MethodPost).
-		Fetch(context.Background())
+// UpdatePassword updates password itself, description and details by unique title.
+// It updates only arguments which are provided. URLs and custom fields are merged
+// with the stored ones on the client, as the server replaces them as a whole.
+func (ps *PasswordService) UpdatePassword(title, password, description string, changes DetailsChanges) error {
+	pwd := keeperclient.PasswordToUpdate{
+		Password:    password,
+		Description: description,
+	}
+	if changes.Username != "" {
+		pwd.Username = &changes.Username
+	}
+	pwd.MaxAgeDays = changes.MaxAgeDays
 
+	if changes.urlsChanged() || changes.fieldsChanged() {
+		details, err := ps.client.PasswordDetails(context.Background(), title)
+		if err != nil {
+			return err
+		}
+		if changes.urlsChanged() {
+			urls := mergeURLs(details.URLs, changes)
+			pwd.URLs = &urls
+		}
+		if changes.fieldsChanged() {
+			fields := mergeFields(details.Fields, changes)
+			pwd.Fields = &fields
+		}
+	}
+
+	err := ps.client.UpdatePassword(context.Background(), title, pwd)
 	if err != nil {
 		return err
-	} else {
-		log.Println("Password info is updated.")
 	}
+	log.Println("Password info is updated.")
 	return nil
 }
 
 func (ps *PasswordService) DeletePasswordByTitle(title string) error {
-	jwtToken, err := ps.keyStore.Get()
+	err := ps.client.DeletePassword(context.Background(), title)
 	if err != nil {
 		return err
 	}
+	log.Println("Password is deleted.")
+	return nil
+}
 
-	err = requests.
-		URL("/api/user/password/"+title).
-		Host(settings.ServerURL).
-		Scheme("https").
-		Transport(ps.transport).
-		Header("Authorization", string(jwtToken)).
-		Method(http.MethodDelete).
-		Fetch(context.Background())
-
+// AddPassword stores a new password entry with its username, URLs and custom fields.
+func (ps *PasswordService) AddPassword(pwd keeperclient.PwdToAdd) error {
+	err := ps.client.AddPassword(context.Background(), pwd)
 	if err != nil {
 		return err
-	} else {
-		log.Println("Password is deleted.")
 	}
+	log.Println("Password is added.")
 	return nil
 }
 
-type PwdToAdd struct {
-	Password    string `json:"pwd" validate:"required,min=1"`
-	Title       string `json:"title" validate:"required,min=1"`
-	Description string `json:"description"`
+// FoundPassword is a password entry matching a site together with its URL that matched.
+type FoundPassword struct {
+	Title    string
+	Username string
+	URL      string
+	Match    string
 }
 
-func (ps *PasswordService) AddPassword(password, title, description string) error {
-	pwdToAdd := PwdToAdd{
-		Password:    password,
-		Title:       title,
-		Description: description,
+// rankMatches checks the entries returned by the server against the site locally, picks the
+// most specific matching URL of every entry and orders the entries from the most specific match.
+func rankMatches(siteURL string, matches []keeperclient.PasswordMatch) ([]FoundPassword, error) {
+	site, err := urlmatch.Parse(siteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []FoundPassword
+	for _, m := range matches {
+		var best *FoundPassword
+		for _, u := range m.URLs {
+			key, err := urlmatch.Parse(u.URL)
+			if err != nil || !urlmatch.Matches(u.Match, key, site) {
+				continue
+			}
+			if best == nil || urlmatch.Specificity(u.Match) > urlmatch.Specificity(best.Match) {
+				best = &FoundPassword{Title: m.Title, Username: m.Username, URL: u.URL, Match: u.Match}
+			}
+		}
+		if best != nil {
+			res = append(res, *best)
+		}
 	}
 
-	err := validate.Struct(pwdToAdd)
+	slices.SortStableFunc(res, func(a, b FoundPassword) int {
+		return cmp.Compare(urlmatch.Specificity(b.Match), urlmatch.Specificity(a.Match))
+	})
+	return res, nil
+}
+
+// Find shows the password entries matching the site URL by registrable domain, host or exact URL,
+// according to the match rule of every entry URL, the most specific matches first.
+// With field set it prints only that field of the best match, for use in scripts;
+// it fails if nothing matches or several entries match equally well.
+func (ps *PasswordService) Find(siteURL, field string) error {
+	matches, err := ps.client.MatchPasswords(context.Background(), siteURL)
 	if err != nil {
-		log.Println("error in validating:", err)
 		return err
 	}
-
-	jwtToken, err := ps.keyStore.Get()
+	found, err := rankMatches(siteURL, matches)
 	if err != nil {
 		return err
 	}
 
-	err = requests.
-		URL("/api/user/password").
-		Host(settings.ServerURL).
-		Scheme("https").
-		Header("Authorization", string(jwtToken)).
-		Transport(ps.transport).
-		BodyJSON(&pwdToAdd).
-		Method(http.MethodPut).
-		Fetch(context.Background())
+	if field != "" {
+		if len(found) == 0 {
+			return fmt.Errorf("no password matches %s", siteURL)
+		}
+		if len(found) > 1 && urlmatch.Specificity(found[0].Match) == urlmatch.Specificity(found[1].Match) {
+			return errors.New("several passwords match equally well, use get-password with the title")
+		}
+		return ps.GetPasswordField(found[0].Title, field)
+	}
 
-	if err != nil {
-		return err
-	} else {
-		log.Println("Password is added.")
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Title", "Username", "URL", "Match"})
+	for _, f := range found {
+		t.AppendRow([]interface{}{f.Title, f.Username, f.URL, f.Match})
 	}
+	t.Render()
 	return nil
 }
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePasswordURL(t *testing.T) {
+	require.Equal(t, keeperclient.PasswordURL{URL: "https://example.com"},
+		ParsePasswordURL("https://example.com"))
+	require.Equal(t, keeperclient.PasswordURL{URL: "https://mail.example.com", Match: keeperclient.MatchHost},
+		ParsePasswordURL("host=https://mail.example.com"))
+	// "=" in the query is not a rule prefix
+	require.Equal(t, keeperclient.PasswordURL{URL: "https://example.com/?a=b"},
+		ParsePasswordURL("https://example.com/?a=b"))
+}
+
+func TestMergeURLs(t *testing.T) {
+	urls := []keeperclient.PasswordURL{
+		{URL: "https://a.com", Match: keeperclient.MatchDomain},
+		{URL: "https://b.com", Match: keeperclient.MatchDomain},
+	}
+	got := mergeURLs(urls, DetailsChanges{
+		AddURLs:    []keeperclient.PasswordURL{{URL: "https://a.com", Match: keeperclient.MatchExact}},
+		RemoveURLs: []string{"https://b.com"},
+	})
+	require.Equal(t, []keeperclient.PasswordURL{{URL: "https://a.com", Match: keeperclient.MatchExact}}, got)
+
+	require.Empty(t, mergeURLs(urls, DetailsChanges{RemoveURLs: []string{"https://a.com", "https://b.com"}}))
+}
+
+func TestMergeFields(t *testing.T) {
+	fields := []keeperclient.PasswordField{
+		{Name: "pin", Type: keeperclient.FieldHidden, Value: "1234"},
+		{Name: "note", Type: keeperclient.FieldText, Value: "old"},
+		{Name: "2fa", Type: keeperclient.FieldBoolean, Value: "false"},
+	}
+	got := mergeFields(fields, DetailsChanges{
+		SetFields: []keeperclient.PasswordField{
+			{Name: "2fa", Type: keeperclient.FieldBoolean, Value: "true"},
+			{Name: "question", Type: keeperclient.FieldText, Value: "pet"},
+		},
+		RemoveFields: []string{"note"},
+	})
+	require.Equal(t, []keeperclient.PasswordField{
+		{Name: "pin", Type: keeperclient.FieldHidden, Value: "1234"},
+		{Name: "2fa", Type: keeperclient.FieldBoolean, Value: "true"},
+		{Name: "question", Type: keeperclient.FieldText, Value: "pet"},
+	}, got)
+}
+
+func TestRankMatches(t *testing.T) {
+	matches := []keeperclient.PasswordMatch{
+		{Title: "example", URLs: []keeperclient.PasswordURL{{URL: "https://example.com", Match: keeperclient.MatchDomain}}},
+		{Title: "mail", Username: "ane", URLs: []keeperclient.PasswordURL{
+			{URL: "https://example.com", Match: keeperclient.MatchDomain},
+			{URL: "https://mail.example.com", Match: keeperclient.MatchHost},
+		}},
+		{Title: "other", URLs: []keeperclient.PasswordURL{{URL: "https://other.com", Match: keeperclient.MatchDomain}}},
+	}
+
+	found, err := rankMatches("https://mail.example.com/inbox", matches)
+	require.NoError(t, err)
+	require.Equal(t, []FoundPassword{
+		{Title: "mail", Username: "ane", URL: "https://mail.example.com", Match: keeperclient.MatchHost},
+		{Title: "example", URL: "https://example.com", Match: keeperclient.MatchDomain},
+	}, found)
+
+	_, err = rankMatches("mail.example.com", matches)
+	require.Error(t, err)
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// bannerTimeout limits the reminders request made after other commands.
+const bannerTimeout = 3 * time.Second
+
+// ReminderService is a service for the reminders of expiring cards and passwords due for rotation.
+type ReminderService struct {
+	client *keeperclient.Client
+}
+
+func NewReminderService(client *keeperclient.Client) *ReminderService {
+	return &ReminderService{
+		client: client,
+	}
+}
+
+// List displays the reminders due within days in a tabular format.
+// If days is negative, the server default window is used.
+func (rs *ReminderService) List(days int) error {
+	reminders, err := rs.client.Reminders(context.Background(), days)
+	if err != nil {
+		return err
+	}
+	if len(reminders) == 0 {
+		fmt.Println("No reminders.")
+		return nil
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Kind", "Title", "Reminder", "Due"})
+	for _, r := range reminders {
+		t.AppendRow(table.Row{r.Kind, r.Title, reminderText(r), r.DueAt.Format(time.DateOnly)})
+	}
+	t.Render()
+	return nil
+}
+
+func reminderText(r keeperclient.Reminder) string {
+	switch {
+	case r.Reason == keeperclient.ReasonCardExpiry && r.Overdue:
+		return "expired"
+	case r.Reason == keeperclient.ReasonCardExpiry:
+		return "expires soon"
+	case r.Overdue:
+		return "change now"
+	default:
+		return "change soon"
+	}
+}
+
+// Banner prints a one line summary of the reminders, if there are any.
+// It is shown after other commands, so errors are ignored.
+func (rs *ReminderService) Banner(w io.Writer) {
+	ctx, cancel := context.WithTimeout(context.Background(), bannerTimeout)
+	defer cancel()
+
+	reminders, err := rs.client.Reminders(ctx, -1)
+	if err != nil {
+		return
+	}
+	if banner := reminderBanner(reminders); banner != "" {
+		fmt.Fprintln(w, banner)
+	}
+}
+
+// reminderBanner summarizes the reminders, e.g.
+// "Reminder: 1 card expired, 2 passwords due for rotation. See: reminders".
+func reminderBanner(reminders []keeperclient.Reminder) string {
+	var expired, expiring, overdue, due int
+	for _, r := range reminders {
+		switch {
+		case r.Reason == keeperclient.ReasonCardExpiry && r.Overdue:
+			expired++
+		case r.Reason == keeperclient.ReasonCardExpiry:
+			expiring++
+		case r.Overdue:
+			overdue++
+		default:
+			due++
+		}
+	}
+
+	var parts []string
+	if expired > 0 {
+		parts = append(parts, plural(expired, "card", "cards")+" expired")
+	}
+	if expiring > 0 {
+		parts = append(parts, plural(expiring, "card", "cards")+" expiring soon")
+	}
+	if overdue > 0 {
+		parts = append(parts, plural(overdue, "password", "passwords")+" overdue for rotation")
+	}
+	if due > 0 {
+		parts = append(parts, plural(due, "password", "passwords")+" due for rotation soon")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "Reminder: " + strings.Join(parts, ", ") + ". See: reminders"
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, one)
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
@@ -0,0 +1,20 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderBanner(t *testing.T) {
+	require.Empty(t, reminderBanner(nil))
+
+	reminders := []keeperclient.Reminder{
+		{Kind: keeperclient.KindCard, Title: "visa", Reason: keeperclient.ReasonCardExpiry, Overdue: true},
+		{Kind: keeperclient.KindPassword, Title: "mail", Reason: keeperclient.ReasonPasswordRotation},
+		{Kind: keeperclient.KindPassword, Title: "bank", Reason: keeperclient.ReasonPasswordRotation},
+	}
+	require.Equal(t, "Reminder: 1 card expired, 2 passwords due for rotation soon. See: reminders",
+		reminderBanner(reminders))
+}
@@ -0,0 +1,236 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/adettelle/go-keeper/internal/secretref"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+)
+
+// Fields of cards, SSH keys and notes available by secret references.
+// Identity documents are referenced by their field names, e.g. number or expiry_date.
+const (
+	FieldNumber      = "number"
+	FieldBrand       = "brand"
+	FieldExpiry      = "expiry"
+	FieldCvc         = "cvc"
+	FieldPin         = "pin"
+	FieldHolder      = "cardholder"
+	FieldAddress     = "address"
+	FieldDescription = "description"
+	FieldPrivateKey  = "private_key"
+	FieldPublicKey   = "public_key"
+	FieldFingerprint = "fingerprint"
+	FieldText        = "text"
+)
+
+// Resolver resolves secret references with the password, card, identity, SSH key and note APIs.
+// Every item is fetched once, so several references to the same item cost one request.
+type Resolver struct {
+	client     *keeperclient.Client
+	passwords  map[string]*keeperclient.PasswordDetails
+	cards      map[string]*keeperclient.CardToGetByTitle
+	identities map[string]*keeperclient.IdentityToGetByTitle
+	sshKeys    map[string]*keeperclient.SSHKeyToGetByTitle
+	notes      map[string]*keeperclient.NoteToGetByTitle
+}
+
+func NewResolver(client *keeperclient.Client) *Resolver {
+	return &Resolver{
+		client:     client,
+		passwords:  map[string]*keeperclient.PasswordDetails{},
+		cards:      map[string]*keeperclient.CardToGetByTitle{},
+		identities: map[string]*keeperclient.IdentityToGetByTitle{},
+		sshKeys:    map[string]*keeperclient.SSHKeyToGetByTitle{},
+		notes:      map[string]*keeperclient.NoteToGetByTitle{},
+	}
+}
+
+// Resolve returns the value of the referenced field. In the short form without a type
+// the title is looked up among passwords first, then among cards.
+func (r *Resolver) Resolve(ctx context.Context, ref secretref.Ref) (string, error) {
+	kind := ref.Kind
+	if kind == "" {
+		_, err := r.password(ctx, ref.Title)
+		switch {
+		case err == nil:
+			kind = secretref.KindPassword
+		case errors.Is(err, keeperclient.ErrNotFound):
+			kind = secretref.KindCard
+		default:
+			return "", fmt.Errorf("%s: %w", ref, err)
+		}
+	}
+
+	var value string
+	var ok bool
+	switch kind {
+	case secretref.KindPassword:
+		details, err := r.password(ctx, ref.Title)
+		if err != nil {
+			return "", refError(ref, err)
+		}
+		value, ok = passwordValue(details, ref.Field)
+	case secretref.KindCard:
+		card, err := r.card(ctx, ref.Title)
+		if err != nil {
+			return "", refError(ref, err)
+		}
+		value, ok = cardValue(card, ref.Field)
+	case secretref.KindIdentity:
+		doc, err := r.identity(ctx, ref.Title)
+		if err != nil {
+			return "", refError(ref, err)
+		}
+		value, ok = identityValue(doc, ref.Field)
+	case secretref.KindSSHKey:
+		key, err := r.sshKey(ctx, ref.Title)
+		if err != nil {
+			return "", refError(ref, err)
+		}
+		value, ok = sshKeyValue(key, ref.Field)
+	case secretref.KindNote:
+		note, err := r.note(ctx, ref.Title)
+		if err != nil {
+			return "", refError(ref, err)
+		}
+		value, ok = noteValue(note, ref.Field)
+	default:
+		return "", fmt.Errorf("%s: unknown type %q", ref, kind)
+	}
+	if !ok {
+		return "", fmt.Errorf("%s: %s %q has no field %q", ref, kind, ref.Title, ref.Field)
+	}
+	return value, nil
+}
+
+func refError(ref secretref.Ref, err error) error {
+	if errors.Is(err, keeperclient.ErrNotFound) {
+		if ref.Kind == "" {
+			return fmt.Errorf("%s: no password or card with title %q", ref, ref.Title)
+		}
+		return fmt.Errorf("%s: no %s with title %q", ref, ref.Kind, ref.Title)
+	}
+	return fmt.Errorf("%s: %w", ref, err)
+}
+
+func (r *Resolver) password(ctx context.Context, title string) (*keeperclient.PasswordDetails, error) {
+	return cached(r.passwords, title, func() (*keeperclient.PasswordDetails, error) {
+		return r.client.PasswordDetails(ctx, title)
+	})
+}
+
+func (r *Resolver) card(ctx context.Context, title string) (*keeperclient.CardToGetByTitle, error) {
+	return cached(r.cards, title, func() (*keeperclient.CardToGetByTitle, error) {
+		return r.client.Card(ctx, title)
+	})
+}
+
+func (r *Resolver) identity(ctx context.Context, title string) (*keeperclient.IdentityToGetByTitle, error) {
+	return cached(r.identities, title, func() (*keeperclient.IdentityToGetByTitle, error) {
+		return r.client.Identity(ctx, title)
+	})
+}
+
+func (r *Resolver) sshKey(ctx context.Context, title string) (*keeperclient.SSHKeyToGetByTitle, error) {
+	return cached(r.sshKeys, title, func() (*keeperclient.SSHKeyToGetByTitle, error) {
+		return r.client.SSHKey(ctx, title)
+	})
+}
+
+func (r *Resolver) note(ctx context.Context, title string) (*keeperclient.NoteToGetByTitle, error) {
+	return cached(r.notes, title, func() (*keeperclient.NoteToGetByTitle, error) {
+		return r.client.Note(ctx, title)
+	})
+}
+
+// cached returns the item from the cache or fetches and caches it. Errors are not cached.
+func cached[T any](cache map[string]*T, title string, fetch func() (*T, error)) (*T, error) {
+	if item, ok := cache[title]; ok {
+		return item, nil
+	}
+	item, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	cache[title] = item
+	return item, nil
+}
+
+// passwordValue returns the field of the password entry. Custom fields take precedence,
+// as in GetPasswordField; url is the first URL of the entry.
+func passwordValue(details *keeperclient.PasswordDetails, field string) (string, bool) {
+	if f, ok := details.Field(field); ok {
+		return f.Value, true
+	}
+	switch field {
+	case FieldPassword:
+		return details.Password, true
+	case FieldUsername:
+		return details.Username, true
+	case FieldURL:
+		if len(details.URLs) == 0 {
+			return "", false
+		}
+		return details.URLs[0].URL, true
+	case FieldDescription:
+		return details.Description, true
+	}
+	return "", false
+}
+
+func cardValue(card *keeperclient.CardToGetByTitle, field string) (string, bool) {
+	switch field {
+	case FieldNumber:
+		return card.Num, true
+	case FieldBrand:
+		return card.Brand, true
+	case FieldExpiry:
+		return card.Expire, true
+	case FieldCvc:
+		return card.Cvc, true
+	case FieldPin:
+		return card.Pin, true
+	case FieldHolder:
+		return card.Holder, true
+	case FieldAddress:
+		return card.Address, true
+	case FieldDescription:
+		return card.Description, true
+	}
+	return "", false
+}
+
+func identityValue(doc *keeperclient.IdentityToGetByTitle, field string) (string, bool) {
+	if field == FieldDescription {
+		return doc.Description, true
+	}
+	value, ok := doc.Fields[field]
+	return value, ok
+}
+
+func sshKeyValue(key *keeperclient.SSHKeyToGetByTitle, field string) (string, bool) {
+	switch field {
+	case FieldPrivateKey:
+		return key.PrivateKey, true
+	case FieldPublicKey:
+		return key.PublicKey, true
+	case FieldFingerprint:
+		return key.Fingerprint, true
+	case FieldDescription:
+		return key.Description, true
+	}
+	return "", false
+}
+
+func noteValue(note *keeperclient.NoteToGetByTitle, field string) (string, bool) {
+	switch field {
+	case FieldText:
+		return note.Text, true
+	case FieldDescription:
+		return note.Description, true
+	}
+	return "", false
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordValue(t *testing.T) {
+	details := &keeperclient.PasswordDetails{
+		Password: "secret",
+		Username: "ane",
+		URLs:     []keeperclient.PasswordURL{{URL: "https://a.com"}, {URL: "https://b.com"}},
+		Fields:   []keeperclient.PasswordField{{Name: "username", Value: "custom"}, {Name: "token", Value: "t0k"}},
+	}
+
+	for field, want := range map[string]string{
+		"password": "secret",
+		"username": "custom", // custom fields take precedence
+		"url":      "https://a.com",
+		"token":    "t0k",
+	} {
+		got, ok := passwordValue(details, field)
+		require.True(t, ok, field)
+		require.Equal(t, want, got, field)
+	}
+
+	_, ok := passwordValue(details, "missing")
+	require.False(t, ok)
+	_, ok = passwordValue(&keeperclient.PasswordDetails{}, "url")
+	require.False(t, ok)
+}
+
+func TestCardValue(t *testing.T) {
+	card := &keeperclient.CardToGetByTitle{Num: "4111111111111111", Expire: "12/30", Cvc: "123", Holder: "ANE"}
+
+	got, ok := cardValue(card, "cvc")
+	require.True(t, ok)
+	require.Equal(t, "123", got)
+	got, ok = cardValue(card, "cardholder")
+	require.True(t, ok)
+	require.Equal(t, "ANE", got)
+
+	_, ok = cardValue(card, "password")
+	require.False(t, ok)
+}
+
+func TestIdentityValue(t *testing.T) {
+	doc := &keeperclient.IdentityToGetByTitle{Description: "mine", Fields: map[string]string{"number": "AB123"}}
+
+	got, ok := identityValue(doc, "number")
+	require.True(t, ok)
+	require.Equal(t, "AB123", got)
+	got, ok = identityValue(doc, "description")
+	require.True(t, ok)
+	require.Equal(t, "mine", got)
+
+	_, ok = identityValue(doc, "expiry_date")
+	require.False(t, ok)
+}
+
+func TestSSHKeyValue(t *testing.T) {
+	key := &keeperclient.SSHKeyToGetByTitle{PrivateKey: "-----BEGIN", PublicKey: "ssh-ed25519 AAAA", Fingerprint: "SHA256:x"}
+
+	got, ok := sshKeyValue(key, "public_key")
+	require.True(t, ok)
+	require.Equal(t, "ssh-ed25519 AAAA", got)
+
+	_, ok = sshKeyValue(key, "password")
+	require.False(t, ok)
+}
+
+func TestNoteValue(t *testing.T) {
+	note := &keeperclient.NoteToGetByTitle{Description: "github", Text: "code-1\ncode-2"}
+
+	got, ok := noteValue(note, "text")
+	require.True(t, ok)
+	require.Equal(t, "code-1\ncode-2", got)
+	got, ok = noteValue(note, "description")
+	require.True(t, ok)
+	require.Equal(t, "github", got)
+
+	_, ok = noteValue(note, "password")
+	require.False(t, ok)
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/adettelle/go-keeper/internal/secretref"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+)
+
+type RunService struct {
+	resolver *Resolver
+}
+
+func NewRunService(client *keeperclient.Client) *RunService {
+	return &RunService{resolver: NewResolver(client)}
+}
+
+// Environ resolves the secret references among the variable values and returns the variables
+// in the NAME=value form. Literal values are kept as is.
+func (rs *RunService) Environ(ctx context.Context, vars []secretref.Var) ([]string, error) {
+	var env []string
+	for _, v := range vars {
+		value := v.Value
+		if secretref.IsRef(value) {
+			ref, err := secretref.Parse(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", v.Name, err)
+			}
+			if value, err = rs.resolver.Resolve(ctx, ref); err != nil {
+				return nil, fmt.Errorf("%s: %w", v.Name, err)
+			}
+		}
+		env = append(env, v.Name+"="+value)
+	}
+	return env, nil
+}
+
+// Run runs the command with the variables added to the current environment and returns its exit code.
+// Secrets are passed to the command only through its environment, nothing is written to disk.
+// Interrupt and terminate signals are forwarded to the command.
+func (rs *RunService) Run(vars []secretref.Var, args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, errors.New("no command to run")
+	}
+
+	env, err := rs.Environ(context.Background(), vars)
+	if err != nil {
+		return 0, err
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	// later values win, so the variables override the inherited ones
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		for sig := range signals {
+			_ = cmd.Process.Signal(sig)
+		}
+	}()
+
+	err = cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if code := exitErr.ExitCode(); code >= 0 {
+			return code, nil
+		}
+		// killed by a signal
+		return 1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
@@ -0,0 +1,19 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adettelle/go-keeper/internal/secretref"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironLiterals(t *testing.T) {
+	rs := &RunService{}
+	env, err := rs.Environ(context.Background(), []secretref.Var{{Name: "A", Value: "1"}, {Name: "B"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"A=1", "B="}, env)
+
+	_, err = rs.Environ(context.Background(), []secretref.Var{{Name: "A", Value: "keeper://broken"}})
+	require.ErrorContains(t, err, "A: invalid reference")
+}
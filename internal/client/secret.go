@@ -0,0 +1,113 @@
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ErrSecretFlag is returned when a secret is passed as a command line flag without forcing it.
+var ErrSecretFlag = errors.New("passing secrets as flags is unsafe: they end up in shell history and ps output; " +
+	"omit the flag to be prompted, use --secret-stdin or --secret-fd, or add --force-secret-flags")
+
+// SecretReader reads secrets (passwords, cvc etc.) from the terminal with echo disabled,
+// or from stdin or a file descriptor for scripts, instead of taking them from command line flags.
+type SecretReader struct {
+	in    *bufio.Reader // in is a non-interactive source of secrets, one per line; nil means terminal prompt.
+	force bool          // force allows secrets passed as flags.
+}
+
+// NewSecretReader creates a SecretReader.
+//
+// Parameters:
+//   - fromStdin: read secrets from stdin, one per line.
+//   - fd: read secrets from the given file descriptor, one per line; a negative value means not set.
+//   - force: allow secrets given as command line flags.
+func NewSecretReader(fromStdin bool, fd int, force bool) (*SecretReader, error) {
+	sr := &SecretReader{force: force}
+
+	switch {
+	case fromStdin && fd >= 0:
+		return nil, errors.New("--secret-stdin and --secret-fd are mutually exclusive")
+	case fromStdin:
+		sr.in = bufio.NewReader(os.Stdin)
+	case fd >= 0:
+		f := os.NewFile(uintptr(fd), fmt.Sprintf("fd%d", fd))
+		if f == nil {
+			return nil, fmt.Errorf("invalid file descriptor %d", fd)
+		}
+		sr.in = bufio.NewReader(f)
+	}
+	return sr, nil
+}
+
+// Read returns the secret named name.
+// If flagValue is not empty, it is used only when flags are forced.
+// Otherwise the secret is read from the configured source or prompted for;
+// with confirm set the prompt asks to type the secret twice.
+func (sr *SecretReader) Read(name, flagValue string, confirm bool) (string, error) {
+	if flagValue != "" {
+		if !sr.force {
+			return "", fmt.Errorf("%s: %w", name, ErrSecretFlag)
+		}
+		return flagValue, nil
+	}
+
+	if sr.in != nil {
+		return sr.readLine(name)
+	}
+
+	secret, err := prompt(fmt.Sprintf("%s: ", name))
+	if err != nil {
+		return "", err
+	}
+	if secret == "" {
+		return "", fmt.Errorf("%s is empty", name)
+	}
+
+	if confirm {
+		again, err := prompt(fmt.Sprintf("Repeat %s: ", name))
+		if err != nil {
+			return "", err
+		}
+		if again != secret {
+			return "", fmt.Errorf("%s values do not match", name)
+		}
+	}
+	return secret, nil
+}
+
+// readLine reads the next secret from the non-interactive source.
+func (sr *SecretReader) readLine(name string) (string, error) {
+	line, err := sr.in.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", fmt.Errorf("error in reading %s: %w", name, err)
+	}
+	secret := strings.TrimRight(line, "\r\n")
+	if secret == "" {
+		return "", fmt.Errorf("%s is empty", name)
+	}
+	return secret, nil
+}
+
+// prompt asks for a secret on the terminal without echoing it.
+// The prompt is written to stderr so that stdout stays clean for redirection.
+func prompt(text string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errors.New("stdin is not a terminal, use --secret-stdin or --secret-fd to pass secrets")
+	}
+
+	fmt.Fprint(os.Stderr, text)
+	secret, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
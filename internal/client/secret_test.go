@@ -0,0 +1,54 @@
+package client
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretReaderRefusesFlag(t *testing.T) {
+	sr, err := NewSecretReader(false, -1, false)
+	require.NoError(t, err)
+
+	_, err = sr.Read("Password", "secret", false)
+	require.ErrorIs(t, err, ErrSecretFlag)
+}
+
+func TestSecretReaderForcedFlag(t *testing.T) {
+	sr, err := NewSecretReader(false, -1, true)
+	require.NoError(t, err)
+
+	secret, err := sr.Read("Password", "secret", true)
+	require.NoError(t, err)
+	require.Equal(t, "secret", secret)
+}
+
+func TestSecretReaderFromFD(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer r.Close()
+
+	_, err = w.WriteString("first\r\nsecond")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	sr, err := NewSecretReader(false, int(r.Fd()), false)
+	require.NoError(t, err)
+
+	secret, err := sr.Read("Password", "", true)
+	require.NoError(t, err)
+	require.Equal(t, "first", secret)
+
+	secret, err = sr.Read("Card cvc", "", true)
+	require.NoError(t, err)
+	require.Equal(t, "second", secret)
+
+	_, err = sr.Read("Pin", "", false)
+	require.Error(t, err)
+}
+
+func TestSecretReaderExclusiveSources(t *testing.T) {
+	_, err := NewSecretReader(true, 3, false)
+	require.Error(t, err)
+}
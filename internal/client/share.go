@@ -0,0 +1,534 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/adettelle/go-keeper/internal/localstorage"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/adettelle/go-keeper/pkg/sharing"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// ShareService shares items with other users end-to-end encrypted: the server keeps only ciphertexts.
+// The private key is sealed with the master password and the account key. The server receives
+// the master password at login, but never the account key, which is kept in the keyring of every
+// device of the user.
+//
+// A shared item is a snapshot of its fields encrypted with a fresh item key on every change of the
+// recipients, so removing a recipient rotates the key and the removed user can not read the new ciphertext.
+// Sharing the item again refreshes the snapshot.
+type ShareService struct {
+	client      *keeperclient.Client
+	vault       *VaultService
+	keys        KeyCache        // keys is nil if the unlocked private key is not cached.
+	accountKeys AccountKeyStore // accountKeys keeps the account key on this device.
+}
+
+// AccountKeyStore keeps the formatted account key on the device.
+type AccountKeyStore interface {
+	Get() (string, error)
+	Set(accountKey string) error
+}
+
+// KeyCache caches the unlocked sharing private key, e.g. in the unlock agent,
+// so that the master password is not asked every time.
+type KeyCache interface {
+	Get() (string, error)
+	Set(key string)
+}
+
+func NewShareService(client *keeperclient.Client) *ShareService {
+	return &ShareService{
+		client: client,
+		vault:  NewVaultService(client, nil),
+	}
+}
+
+// sharedField is a field of a shared item.
+type sharedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Secret bool   `json:"secret,omitempty"`
+}
+
+// sharedItem is the plaintext of a shared item.
+type sharedItem struct {
+	Kind   keeperclient.ItemKind `json:"kind"`
+	Title  string                `json:"title"`
+	Fields []sharedField         `json:"fields"`
+}
+
+// snapshot returns the current fields of the item.
+func snapshot(ctx context.Context, vault *VaultService, kind keeperclient.ItemKind, title string) (*sharedItem, error) {
+	fields, err := vault.Fields(ctx, keeperclient.Item{Kind: kind, Title: title})
+	if err != nil {
+		return nil, err
+	}
+	item := &sharedItem{Kind: kind, Title: title}
+	for _, f := range fields {
+		item.Fields = append(item.Fields, sharedField{Name: f.Name, Value: f.Value, Secret: f.Secret})
+	}
+	return item, nil
+}
+
+// recipientKey is the public key of a recipient.
+type recipientKey struct {
+	Login     string
+	PublicKey sharing.Key
+}
+
+// sealShare encrypts the item with a new item key and wraps the key for every recipient.
+func sealShare(item sharedItem, recipients []recipientKey) (*keeperclient.Share, error) {
+	plain, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	itemKey, err := sharing.NewItemKey()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := sharing.Encrypt(itemKey, plain)
+	if err != nil {
+		return nil, err
+	}
+
+	share := &keeperclient.Share{Kind: item.Kind, Title: item.Title, Payload: payload}
+	for _, rec := range recipients {
+		wrapped, err := sharing.WrapKey(itemKey, rec.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		share.Recipients = append(share.Recipients, keeperclient.ShareRecipient{Login: rec.Login, WrappedKey: wrapped})
+	}
+	return share, nil
+}
+
+// openShare decrypts the item shared with the owner of the keys.
+func openShare(payload *keeperclient.SharedPayload, keys *sharing.KeyPair) (*sharedItem, error) {
+	itemKey, err := sharing.UnwrapKey(payload.WrappedKey, keys)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := sharing.Decrypt(itemKey, payload.Payload)
+	if err != nil {
+		return nil, err
+	}
+	var item sharedItem
+	if err := json.Unmarshal(plain, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// SetKeyCache caches the private key once it is unlocked with the master password.
+func (ss *ShareService) SetKeyCache(keys KeyCache) {
+	ss.keys = keys
+}
+
+// SetAccountKeyStore sets where the account key is kept on this device.
+func (ss *ShareService) SetAccountKeyStore(accountKeys AccountKeyStore) {
+	ss.accountKeys = accountKeys
+}
+
+// accountKey returns the account key kept on this device.
+func (ss *ShareService) accountKey() (sharing.Key, error) {
+	if ss.accountKeys == nil {
+		return sharing.Key{}, localstorage.ErrNoAccountKey
+	}
+	formatted, err := ss.accountKeys.Get()
+	if err != nil {
+		return sharing.Key{}, err
+	}
+	return sharing.ParseAccountKey(formatted)
+}
+
+// keepAccountKey stores the new account key on this device and prints it for the user to write down.
+// The key is printed also if it can not be stored, so that it is not lost.
+func (ss *ShareService) keepAccountKey(accountKey sharing.Key) error {
+	formatted := sharing.FormatAccountKey(accountKey)
+	fmt.Println("Your account key:", formatted)
+	fmt.Println("Write it down and keep it safe. With your master password it opens your private key;" +
+		" the server never receives it. Add it on your other devices with import-account-key.")
+	if ss.accountKeys == nil {
+		return fmt.Errorf("the account key can not be stored on this device")
+	}
+	return ss.accountKeys.Set(formatted)
+}
+
+// InitKeys creates the sharing key pair of the user. The private key is sealed with the master password
+// and a new account key. Keys sealed by older clients with the master password alone are sealed again.
+func (ss *ShareService) InitKeys(masterPassword string) error {
+	ctx := context.Background()
+
+	existing, err := ss.client.Keys(ctx)
+	if err != nil && !errors.Is(err, keeperclient.ErrNotFound) {
+		return err
+	}
+	if err == nil {
+		if !sharing.IsLegacySealed(existing.PrivateKey) {
+			return fmt.Errorf("sharing keys are already created")
+		}
+		priv, err := sharing.OpenLegacyPrivateKey(existing.PrivateKey, masterPassword)
+		if errors.Is(err, sharing.ErrDecrypt) {
+			return fmt.Errorf("wrong master password")
+		}
+		if err != nil {
+			return err
+		}
+		return ss.resealLegacy(ctx, existing.PublicKey, priv, masterPassword)
+	}
+
+	keys, err := sharing.GenerateKeyPair()
+	if err != nil {
+		return err
+	}
+	accountKey, err := sharing.NewAccountKey()
+	if err != nil {
+		return err
+	}
+	sealed, err := sharing.SealPrivateKey(keys.Private, masterPassword, accountKey)
+	if err != nil {
+		return err
+	}
+
+	err = ss.client.AddKeys(ctx, keeperclient.UserKeys{PublicKey: sharing.EncodeKey(keys.Public), PrivateKey: sealed})
+	if errors.Is(err, keeperclient.ErrConflict) {
+		return fmt.Errorf("sharing keys are already created")
+	}
+	if err != nil {
+		return err
+	}
+	log.Println("Sharing keys are created.")
+	fmt.Println("Your key fingerprint:", sharing.Fingerprint(keys.Public))
+	return ss.keepAccountKey(accountKey)
+}
+
+// ImportAccountKey stores the account key written down on another device on this one.
+func (ss *ShareService) ImportAccountKey(formatted string) error {
+	accountKey, err := sharing.ParseAccountKey(formatted)
+	if err != nil {
+		return err
+	}
+	if ss.accountKeys == nil {
+		return fmt.Errorf("the account key can not be stored on this device")
+	}
+	if err := ss.accountKeys.Set(sharing.FormatAccountKey(accountKey)); err != nil {
+		return err
+	}
+	log.Println("Account key is stored.")
+	return nil
+}
+
+// ShowAccountKey prints the account key stored on this device, to be added on another one.
+func (ss *ShareService) ShowAccountKey() error {
+	accountKey, err := ss.accountKey()
+	if errors.Is(err, localstorage.ErrNoAccountKey) {
+		return fmt.Errorf("no account key is stored on this device")
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println(sharing.FormatAccountKey(accountKey))
+	return nil
+}
+
+// resealLegacy seals the private key, sealed by an older client with the master password alone,
+// with the master password and the account key of this device or a new one.
+func (ss *ShareService) resealLegacy(ctx context.Context, publicKey string, priv sharing.Key,
+	masterPassword string) error {
+
+	accountKey, err := ss.accountKey()
+	isNew := errors.Is(err, localstorage.ErrNoAccountKey)
+	if isNew {
+		accountKey, err = sharing.NewAccountKey()
+	}
+	if err != nil {
+		return err
+	}
+
+	sealed, err := sharing.SealPrivateKey(priv, masterPassword, accountKey)
+	if err != nil {
+		return err
+	}
+	if err := ss.client.AddKeys(ctx, keeperclient.UserKeys{PublicKey: publicKey, PrivateKey: sealed}); err != nil {
+		return err
+	}
+	log.Println("Private key was sealed with the master password alone and is sealed with the account key now.")
+	if isNew {
+		return ss.keepAccountKey(accountKey)
+	}
+	return nil
+}
+
+// unlockKeys returns the key pair of the user with the private key taken from the cache or,
+// if it is not cached, opened by the master password, which is asked only then.
+func (ss *ShareService) unlockKeys(ctx context.Context, masterPassword func() (string, error)) (*sharing.KeyPair, error) {
+	userKeys, err := ss.client.Keys(ctx)
+	if errors.Is(err, keeperclient.ErrNotFound) {
+		return nil, fmt.Errorf("you have no sharing keys, create them with init-keys")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := sharing.DecodeKey(userKeys.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if ss.keys != nil {
+		if cached, err := ss.keys.Get(); err == nil {
+			priv, err := sharing.DecodeKey(cached)
+			keys := &sharing.KeyPair{Public: pub, Private: priv}
+			// a key of other keys, e.g. of a deleted account, is not used
+			if err == nil && keys.Matches() {
+				return keys, nil
+			}
+		}
+	}
+
+	var accountKey sharing.Key
+	legacy := sharing.IsLegacySealed(userKeys.PrivateKey)
+	if !legacy {
+		accountKey, err = ss.accountKey()
+		if errors.Is(err, localstorage.ErrNoAccountKey) {
+			return nil, fmt.Errorf("the account key of your sharing keys is not on this device, add it with import-account-key")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	password, err := masterPassword()
+	if err != nil {
+		return nil, err
+	}
+
+	var priv sharing.Key
+	if legacy {
+		priv, err = sharing.OpenLegacyPrivateKey(userKeys.PrivateKey, password)
+	} else {
+		priv, err = sharing.OpenPrivateKey(userKeys.PrivateKey, password, accountKey)
+	}
+	if errors.Is(err, sharing.ErrDecrypt) {
+		return nil, fmt.Errorf("wrong master password or account key")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if legacy {
+		if err := ss.resealLegacy(ctx, userKeys.PublicKey, priv, password); err != nil {
+			return nil, err
+		}
+	}
+	if ss.keys != nil {
+		ss.keys.Set(sharing.EncodeKey(priv))
+	}
+	return &sharing.KeyPair{Public: pub, Private: priv}, nil
+}
+
+// recipients returns the logins the item is shared with now, nil if it is not shared.
+func (ss *ShareService) recipients(ctx context.Context, kind keeperclient.ItemKind, title string) ([]string, error) {
+	shares, err := ss.client.Shares(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range shares {
+		if s.Kind == kind && s.Title == title {
+			return s.Recipients, nil
+		}
+	}
+	return nil, nil
+}
+
+// publicKey returns the public key of the user to encrypt for. Keys whose private key is sealed with
+// the master password alone are refused: the server could open it.
+func publicKey(ctx context.Context, client *keeperclient.Client, login string) (sharing.Key, error) {
+	key, err := client.PublicKey(ctx, login)
+	if errors.Is(err, keeperclient.ErrNotFound) {
+		return sharing.Key{}, fmt.Errorf("%s has no sharing keys, they must run init-keys first", login)
+	}
+	if err != nil {
+		return sharing.Key{}, err
+	}
+	if key.LegacySealed {
+		return sharing.Key{}, fmt.Errorf("the private key of %s is sealed with the master password alone, "+
+			"they must run init-keys to seal it with an account key first", login)
+	}
+	pub, err := sharing.DecodeKey(key.PublicKey)
+	if err != nil {
+		return sharing.Key{}, fmt.Errorf("public key of %s: %w", login, err)
+	}
+	return pub, nil
+}
+
+// put encrypts the current fields of the item with a new item key for the recipients.
+func (ss *ShareService) put(ctx context.Context, kind keeperclient.ItemKind, title string, logins []string) error {
+	recipients := make([]recipientKey, 0, len(logins))
+	for _, login := range logins {
+		pub, err := publicKey(ctx, ss.client, login)
+		if err != nil {
+			return err
+		}
+		recipients = append(recipients, recipientKey{Login: login, PublicKey: pub})
+	}
+
+	item, err := snapshot(ctx, ss.vault, kind, title)
+	if err != nil {
+		return err
+	}
+
+	share, err := sealShare(*item, recipients)
+	if err != nil {
+		return err
+	}
+	if err := ss.client.PutShare(ctx, *share); err != nil {
+		return err
+	}
+
+	for _, rec := range recipients {
+		fmt.Printf("%s\t%s\n", rec.Login, sharing.Fingerprint(rec.PublicKey))
+	}
+	return nil
+}
+
+// Share shares the item with the users in addition to its current recipients.
+// The fingerprints of the recipient keys are printed to be compared with the ones the users see.
+func (ss *ShareService) Share(kind keeperclient.ItemKind, title string, logins []string) error {
+	if kind == keeperclient.KindFile {
+		return fmt.Errorf("files can not be shared")
+	}
+	ctx := context.Background()
+
+	current, err := ss.recipients(ctx, kind, title)
+	if err != nil {
+		return err
+	}
+	for _, login := range logins {
+		if !slices.Contains(current, login) {
+			current = append(current, login)
+		}
+	}
+
+	if err := ss.put(ctx, kind, title, current); err != nil {
+		return err
+	}
+	log.Println("Item is shared.")
+	return nil
+}
+
+// Unshare stops sharing the item with the users. The item is encrypted again with a new item key
+// for the remaining recipients; with none left the share is deleted.
+func (ss *ShareService) Unshare(kind keeperclient.ItemKind, title string, logins []string) error {
+	ctx := context.Background()
+
+	current, err := ss.recipients(ctx, kind, title)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return fmt.Errorf("%s %s is not shared", kind, title)
+	}
+
+	remaining := slices.DeleteFunc(current, func(login string) bool {
+		return len(logins) == 0 || slices.Contains(logins, login)
+	})
+	if len(remaining) == 0 {
+		if err := ss.client.DeleteShare(ctx, kind, title); err != nil {
+			return err
+		}
+		log.Println("Item is not shared anymore.")
+		return nil
+	}
+
+	if err := ss.put(ctx, kind, title, remaining); err != nil {
+		return err
+	}
+	log.Println("Item key is rotated.")
+	return nil
+}
+
+// Shares displays the items shared by the user.
+func (ss *ShareService) Shares() error {
+	shares, err := ss.client.Shares(context.Background())
+	if err != nil {
+		return err
+	}
+	if len(shares) == 0 {
+		fmt.Println("No shared items.")
+		return nil
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Kind", "Title", "Shared with", "Updated"})
+	for _, s := range shares {
+		t.AppendRow(table.Row{s.Kind, s.Title, strings.Join(s.Recipients, ", "), s.UpdatedAt.Format("2006-01-02 15:04")})
+	}
+	t.Render()
+	return nil
+}
+
+// SharedWithMe displays the items other users shared with the user.
+func (ss *ShareService) SharedWithMe() error {
+	items, err := ss.client.SharedWithMe(context.Background())
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		fmt.Println("Nothing is shared with you.")
+		return nil
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Owner", "Kind", "Title", "Updated"})
+	for _, s := range items {
+		t.AppendRow(table.Row{s.Owner, s.Kind, s.Title, s.UpdatedAt.Format("2006-01-02 15:04")})
+	}
+	t.Render()
+	return nil
+}
+
+// GetShared decrypts and displays the item the owner shared with the user.
+// masterPassword is called if the private key is not cached.
+func (ss *ShareService) GetShared(owner string, kind keeperclient.ItemKind, title string,
+	masterPassword func() (string, error)) error {
+	ctx := context.Background()
+
+	payload, err := ss.client.SharedItem(ctx, owner, kind, title)
+	if err != nil {
+		return err
+	}
+	keys, err := ss.unlockKeys(ctx, masterPassword)
+	if err != nil {
+		return err
+	}
+	item, err := openShare(payload, keys)
+	if err != nil {
+		return err
+	}
+	if item.Kind != kind || item.Title != title {
+		return fmt.Errorf("shared item does not match %s %s", kind, title)
+	}
+
+	printSharedItem(item)
+	return nil
+}
+
+func printSharedItem(item *sharedItem) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Field", "Value"})
+	for _, f := range item.Fields {
+		t.AppendRow(table.Row{f.Name, f.Value})
+	}
+	t.Render()
+}
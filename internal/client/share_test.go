@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adettelle/go-keeper/internal/localstorage"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/adettelle/go-keeper/pkg/sharing"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+func TestShareRotation(t *testing.T) {
+	bob, err := sharing.GenerateKeyPair()
+	require.NoError(t, err)
+	eve, err := sharing.GenerateKeyPair()
+	require.NoError(t, err)
+
+	item := sharedItem{Kind: keeperclient.KindPassword, Title: "mail",
+		Fields: []sharedField{{Name: FieldPassword, Value: "s3cret", Secret: true}}}
+
+	share, err := sealShare(item, []recipientKey{{"bob@aaa.com", bob.Public}, {"eve@aaa.com", eve.Public}})
+	require.NoError(t, err)
+	require.Len(t, share.Recipients, 2)
+	require.NotContains(t, share.Payload, "s3cret")
+
+	got, err := openShare(&keeperclient.SharedPayload{Payload: share.Payload, WrappedKey: share.Recipients[1].WrappedKey}, eve)
+	require.NoError(t, err)
+	require.Equal(t, item, *got)
+	oldKey := share.Recipients[1].WrappedKey
+
+	// eve is removed: the key is rotated and her old wrapped key does not open the new ciphertext
+	share, err = sealShare(item, []recipientKey{{"bob@aaa.com", bob.Public}})
+	require.NoError(t, err)
+	_, err = openShare(&keeperclient.SharedPayload{Payload: share.Payload, WrappedKey: oldKey}, eve)
+	require.ErrorIs(t, err, sharing.ErrDecrypt)
+
+	got, err = openShare(&keeperclient.SharedPayload{Payload: share.Payload, WrappedKey: share.Recipients[0].WrappedKey}, bob)
+	require.NoError(t, err)
+	require.Equal(t, item, *got)
+}
+
+// memAccountKeys keeps the account key in memory.
+type memAccountKeys struct {
+	key string
+}
+
+func (m *memAccountKeys) Get() (string, error) {
+	if m.key == "" {
+		return "", localstorage.ErrNoAccountKey
+	}
+	return m.key, nil
+}
+
+func (m *memAccountKeys) Set(key string) error {
+	m.key = key
+	return nil
+}
+
+// keysServer serves the sharing keys of the user and takes the keys sealed again.
+func keysServer(t *testing.T, keys *keeperclient.UserKeys) *keeperclient.Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/user/keys", r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(keys)
+		case http.MethodPut:
+			var put keeperclient.UserKeys
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&put))
+			require.Equal(t, keys.PublicKey, put.PublicKey)
+			*keys = put
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := keeperclient.New(keeperclient.Config{BaseURL: srv.URL, Tokens: keeperclient.StaticToken("Bearer token")})
+	require.NoError(t, err)
+	return c
+}
+
+func TestUnlockKeys(t *testing.T) {
+	pair, err := sharing.GenerateKeyPair()
+	require.NoError(t, err)
+	accountKey, err := sharing.NewAccountKey()
+	require.NoError(t, err)
+	sealed, err := sharing.SealPrivateKey(pair.Private, "master", accountKey)
+	require.NoError(t, err)
+
+	keys := &keeperclient.UserKeys{PublicKey: sharing.EncodeKey(pair.Public), PrivateKey: sealed}
+	ss := NewShareService(keysServer(t, keys))
+	password := func() (string, error) { return "master", nil }
+
+	// одного мастер-пароля, который сервер получает при входе, недостаточно
+	ss.SetAccountKeyStore(&memAccountKeys{})
+	_, err = ss.unlockKeys(context.Background(), password)
+	require.ErrorContains(t, err, "import-account-key")
+
+	ss.SetAccountKeyStore(&memAccountKeys{key: sharing.FormatAccountKey(accountKey)})
+	got, err := ss.unlockKeys(context.Background(), password)
+	require.NoError(t, err)
+	require.Equal(t, pair.Private, got.Private)
+
+	_, err = ss.unlockKeys(context.Background(), func() (string, error) { return "wrong", nil })
+	require.ErrorContains(t, err, "wrong master password")
+}
+
+// legacySealed seals the private key the way older clients did: with the master password alone.
+func legacySealed(t *testing.T, priv sharing.Key, masterPassword string) string {
+	salt := make([]byte, 16)
+	var nonce [24]byte
+	var key [sharing.KeySize]byte
+	copy(key[:], argon2.IDKey([]byte(masterPassword), salt, 3, 64*1024, 4, sharing.KeySize))
+
+	out := secretbox.Seal(append(salt, nonce[:]...), priv[:], &nonce, &key)
+	sealed := "v1$" + base64.StdEncoding.EncodeToString(out)
+	require.True(t, sharing.IsLegacySealed(sealed))
+	return sealed
+}
+
+func TestUnlockLegacyKeys(t *testing.T) {
+	pair, err := sharing.GenerateKeyPair()
+	require.NoError(t, err)
+	legacy := legacySealed(t, pair.Private, "master")
+
+	keys := &keeperclient.UserKeys{PublicKey: sharing.EncodeKey(pair.Public), PrivateKey: legacy}
+	ss := NewShareService(keysServer(t, keys))
+	accountKeys := &memAccountKeys{}
+	ss.SetAccountKeyStore(accountKeys)
+
+	got, err := ss.unlockKeys(context.Background(), func() (string, error) { return "master", nil })
+	require.NoError(t, err)
+	require.Equal(t, pair.Private, got.Private)
+
+	// ключ запечатан заново с новым ключом учётной записи, который сохранён на устройстве
+	require.False(t, sharing.IsLegacySealed(keys.PrivateKey))
+	accountKey, err := sharing.ParseAccountKey(accountKeys.key)
+	require.NoError(t, err)
+	priv, err := sharing.OpenPrivateKey(keys.PrivateKey, "master", accountKey)
+	require.NoError(t, err)
+	require.Equal(t, pair.Private, priv)
+}
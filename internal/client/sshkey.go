@@ -0,0 +1,226 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/adettelle/go-keeper/internal/sshagent"
+	"github.com/adettelle/go-keeper/internal/sshkey"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHKeyService is a service for managing SSH keys and serving them with the ssh agent.
+type SSHKeyService struct {
+	client *keeperclient.Client
+}
+
+func NewSSHKeyService(client *keeperclient.Client) *SSHKeyService {
+	return &SSHKeyService{
+		client: client,
+	}
+}
+
+// AllSSHKeys retrieves SSH keys associated with the user, filtered by the options,
+// and displays them with their fingerprints in a tabular format, favorites first.
+func (ss *SSHKeyService) AllSSHKeys(opts keeperclient.ListOptions) error {
+	keys, next, err := ss.client.SSHKeys(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"", "Title", "Type", "Fingerprint", "Confirm", "Folder", "Tags", "Description"})
+
+	for _, key := range keys {
+		confirm := ""
+		if key.Confirm {
+			confirm = "yes"
+		}
+		t.AppendRow([]interface{}{favoriteMark(key.ItemMeta), key.Title, key.KeyType, key.Fingerprint, confirm,
+			key.Folder, strings.Join(key.Tags, ", "), key.Description})
+	}
+	t.Render()
+	printNextCursor(next)
+	return nil
+}
+
+// GenerateSSHKey generates a new key, stores it and prints its public key for authorized_keys.
+func (ss *SSHKeyService) GenerateSSHKey(key keeperclient.SSHKeyToAdd, keyType sshkey.Type, bits int) error {
+	generated, err := sshkey.Generate(keyType, bits, key.Comment)
+	if err != nil {
+		return err
+	}
+	key.PrivateKey = generated.PrivateKey
+
+	err = ss.client.AddSSHKey(context.Background(), key)
+	if err != nil {
+		return err
+	}
+	log.Println("SSH key is added:", generated.Fingerprint)
+	fmt.Println(generated.PublicKey)
+	return nil
+}
+
+// ImportSSHKey stores the private key from the file. If the key is protected, the passphrase
+// is asked for and the key is stored decrypted. Without a comment the one of the .pub file
+// next to the key is used, if any.
+func (ss *SSHKeyService) ImportSSHKey(key keeperclient.SSHKeyToAdd, path string,
+	passphrase func() (string, error)) error {
+
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if key.Comment == "" {
+		key.Comment = pubKeyComment(path + ".pub")
+	}
+
+	parsed, err := sshkey.Parse(pemBytes, nil, key.Comment)
+	if errors.Is(err, sshkey.ErrPassphraseRequired) {
+		var p string
+		p, err = passphrase()
+		if err != nil {
+			return err
+		}
+		parsed, err = sshkey.Parse(pemBytes, []byte(p), key.Comment)
+	}
+	if err != nil {
+		return err
+	}
+	key.PrivateKey = parsed.PrivateKey
+
+	err = ss.client.AddSSHKey(context.Background(), key)
+	if err != nil {
+		return err
+	}
+	log.Println("SSH key is added:", parsed.Fingerprint)
+	return nil
+}
+
+// pubKeyComment returns the comment of the public key file or an empty string.
+func pubKeyComment(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	_, comment, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return ""
+	}
+	return comment
+}
+
+// GetSSHKey prints the private key by title, or its public key for authorized_keys if public is set.
+func (ss *SSHKeyService) GetSSHKey(title string, public bool) error {
+	key, err := ss.client.SSHKey(context.Background(), title)
+	if err != nil {
+		return err
+	}
+	if public {
+		fmt.Println(key.PublicKey)
+		return nil
+	}
+	fmt.Print(key.PrivateKey)
+	return nil
+}
+
+// UpdateSSHKey changes the description (if not empty) and the confirmation (if not nil) of the key.
+func (ss *SSHKeyService) UpdateSSHKey(title, description string, confirm *bool) error {
+	key := keeperclient.SSHKeyToUpdate{Confirm: confirm}
+	if description != "" {
+		key.Description = &description
+	}
+	err := ss.client.UpdateSSHKey(context.Background(), title, key)
+	if err != nil {
+		return err
+	}
+	log.Println("SSH key is updated.")
+	return nil
+}
+
+func (ss *SSHKeyService) DeleteSSHKeyByTitle(title string) error {
+	err := ss.client.DeleteSSHKey(context.Background(), title)
+	if err != nil {
+		return err
+	}
+	log.Println("SSH key is deleted.")
+	return nil
+}
+
+// ServeAgent serves the SSH keys with the given titles, or all the keys if titles are empty,
+// over the ssh-agent protocol on the Unix socket until interrupted. confirmAll requires
+// confirmation for every key; confirm asks the user.
+func (ss *SSHKeyService) ServeAgent(socket string, titles []string, confirmAll bool,
+	confirm sshagent.ConfirmFunc) error {
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	list, _, err := ss.client.SSHKeys(ctx, keeperclient.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var keys []sshagent.Key
+	for _, item := range list {
+		if len(titles) > 0 && !slices.Contains(titles, item.Title) {
+			continue
+		}
+		key, err := ss.client.SSHKey(ctx, item.Title)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, sshagent.Key{
+			Title:      item.Title,
+			PrivateKey: key.PrivateKey,
+			Confirm:    key.Confirm || confirmAll,
+		})
+	}
+	if len(keys) == 0 {
+		return errors.New("no ssh keys to serve")
+	}
+
+	a, err := sshagent.New(keys, confirm)
+	if err != nil {
+		return err
+	}
+	l, err := sshagent.Listen(socket)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(socket)
+
+	// вывод в формате ssh-agent, чтобы его можно было передать в eval
+	fmt.Printf("SSH_AUTH_SOCK=%s; export SSH_AUTH_SOCK;\n", socket)
+	log.Printf("Serving %d ssh keys, press Ctrl+C to stop.", len(keys))
+	return sshagent.Serve(ctx, l, a)
+}
+
+// TerminalConfirm asks on the controlling terminal to allow the use of the key.
+// It refuses if there is no terminal.
+func TerminalConfirm(title, fingerprint string) bool {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		log.Println("error in opening terminal:", err)
+		return false
+	}
+	defer tty.Close()
+
+	fmt.Fprintf(tty, "Allow use of SSH key %q (%s)? [y/N] ", title, fingerprint)
+	answer, err := bufio.NewReader(tty).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -4,59 +4,34 @@ package client
 
 import (
 	"context"
-	"log"
-	"net/http"
 
-	"github.com/adettelle/go-keeper/cmd/settings"
 	"github.com/adettelle/go-keeper/internal/localstorage"
-	"github.com/carlmjohnson/requests"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
 )
 
 // UserService is a service for managing user-related operations.
 type UserService struct {
-	transport *http.Transport
-	keyStore  localstorage.IKeyStorage
+	client   *keeperclient.Client
+	keyStore localstorage.IKeyStorage
 }
 
-func NewUserService(transport *http.Transport, keyStore localstorage.IKeyStorage) *UserService {
+func NewUserService(client *keeperclient.Client, keyStore localstorage.IKeyStorage) *UserService {
 	return &UserService{
-		transport: transport,
-		keyStore:  keyStore,
+		client:   client,
+		keyStore: keyStore,
 	}
 }
 
-type CustomerToReg struct {
-	Name           string `json:"name" validate:"required,min=1"`
-	Login          string `json:"login" validate:"required,email"`
-	MasterPassword string `json:"masterpassword" validate:"required,min=3"`
-	Authentication bool   `json:"signin"`
-}
-
 // Register registers a new user with the provided credentials and optionally logs in the user.
 func (us *UserService) Register(name, login, masterPassword string, signIn bool) error {
-	customer := CustomerToReg{
+	customer := keeperclient.CustomerToReg{
 		Name:           name,
 		Login:          login,
 		MasterPassword: masterPassword,
 		Authentication: signIn,
 	}
 
-	err := validate.Struct(customer)
-	if err != nil {
-		log.Println("error in validating:", err)
-		return err
-	}
-
-	err = requests.
-		URL("/api/user/register").
-		CheckStatus(http.StatusOK).
-		Host(settings.ServerURL).
-		Scheme("https").
-		Transport(us.transport).
-		Method(http.MethodPost).
-		BodyJSON(&customer).
-		Fetch(context.Background())
-
+	err := us.client.Register(context.Background(), customer)
 	if err != nil {
 		return err
 	}
@@ -70,40 +45,16 @@ func (us *UserService) Register(name, login, masterPassword string, signIn bool)
 	return nil
 }
 
-type CustomerToLogin struct {
-	Login    string `json:"login" validate:"required,email"`
-	Password string `json:"pwd" validate:"required,min=3"`
-}
-
+// LogIn authenticates the user and saves the received jwt token in the key store.
 func (us *UserService) LogIn(login, password string) error {
-	customer := CustomerToLogin{
+	jwtToken, err := us.client.Login(context.Background(), keeperclient.CustomerToLogin{
 		Login:    login,
 		Password: password,
-	}
-
-	err := validate.Struct(customer)
-	if err != nil {
-		log.Println("error in validating:", err)
-		return err
-	}
-
-	headers := http.Header{}
-
-	err = requests.
-		URL("/api/user/login").
-		CopyHeaders(headers).
-		CheckStatus(http.StatusOK).
-		Host(settings.ServerURL).
-		Scheme("https").
-		Transport(us.transport).
-		BodyJSON(&customer).
-		Fetch(context.Background())
-
+	})
 	if err != nil {
 		return err
 	}
 
-	jwtToken := headers.Get("Authorization")
 	err = us.keyStore.Set(jwtToken)
 	if err != nil {
 		return err
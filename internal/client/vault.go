@@ -0,0 +1,241 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/adettelle/go-keeper/internal/tui"
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+)
+
+// VaultService gives the terminal UI access to the items of all kinds.
+type VaultService struct {
+	client *keeperclient.Client
+	clip   *ClipService // clip is nil if no clipboard is available.
+}
+
+func NewVaultService(client *keeperclient.Client, clip *ClipService) *VaultService {
+	return &VaultService{client: client, clip: clip}
+}
+
+var _ tui.Vault = (*VaultService)(nil)
+
+// RunTUI runs the terminal UI. Service logging is muted meanwhile, as it would break the screen.
+func (vs *VaultService) RunTUI() error {
+	out := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(out)
+
+	return tui.Run(vs)
+}
+
+// Items returns the items of all kinds, favorites first.
+func (vs *VaultService) Items(ctx context.Context) ([]keeperclient.Item, error) {
+	items, _, err := vs.client.SearchItems(ctx, keeperclient.ListOptions{})
+	return items, err
+}
+
+// Fields returns the fields of the item; secrets are marked to be masked.
+func (vs *VaultService) Fields(ctx context.Context, item keeperclient.Item) ([]tui.Field, error) {
+	switch item.Kind {
+	case keeperclient.KindPassword:
+		details, err := vs.client.PasswordDetails(ctx, item.Title)
+		if err != nil {
+			return nil, err
+		}
+		fields := []tui.Field{
+			{Name: FieldUsername, Value: details.Username, Editable: true},
+			{Name: FieldPassword, Value: details.Password, Secret: true, Editable: true},
+		}
+		for _, u := range details.URLs {
+			fields = append(fields, tui.Field{Name: FieldURL, Value: u.URL})
+		}
+		for _, f := range details.Fields {
+			fields = append(fields, tui.Field{Name: f.Name, Value: f.Value,
+				Secret: f.Type == keeperclient.FieldHidden, Editable: true})
+		}
+		return append(fields, tui.Field{Name: FieldDescription, Value: details.Description, Editable: true}), nil
+
+	case keeperclient.KindCard:
+		card, err := vs.client.Card(ctx, item.Title)
+		if err != nil {
+			return nil, err
+		}
+		return []tui.Field{
+			{Name: FieldNumber, Value: card.Num, Secret: true, Editable: true},
+			{Name: FieldBrand, Value: card.Brand},
+			{Name: FieldExpiry, Value: card.Expire, Editable: true},
+			{Name: FieldCvc, Value: card.Cvc, Secret: true, Editable: true},
+			{Name: FieldPin, Value: card.Pin, Secret: true, Editable: true},
+			{Name: FieldHolder, Value: card.Holder, Editable: true},
+			{Name: FieldAddress, Value: card.Address, Editable: true},
+			{Name: FieldDescription, Value: card.Description, Editable: true},
+		}, nil
+
+	case keeperclient.KindIdentity:
+		doc, err := vs.client.Identity(ctx, item.Title)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(doc.Fields))
+		for name := range doc.Fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fields := []tui.Field{{Name: "type", Value: string(doc.DocType)}}
+		for _, name := range names {
+			fields = append(fields, tui.Field{Name: name, Value: doc.Fields[name], Editable: true})
+		}
+		return append(fields, tui.Field{Name: FieldDescription, Value: doc.Description, Editable: true}), nil
+
+	case keeperclient.KindSSHKey:
+		key, err := vs.client.SSHKey(ctx, item.Title)
+		if err != nil {
+			return nil, err
+		}
+		return []tui.Field{
+			{Name: FieldPublicKey, Value: key.PublicKey},
+			{Name: FieldFingerprint, Value: key.Fingerprint},
+			{Name: FieldPrivateKey, Value: key.PrivateKey, Secret: true},
+			{Name: FieldDescription, Value: key.Description, Editable: true},
+		}, nil
+
+	case keeperclient.KindNote:
+		note, err := vs.client.Note(ctx, item.Title)
+		if err != nil {
+			return nil, err
+		}
+		return []tui.Field{
+			{Name: FieldText, Value: note.Text, Secret: true, Editable: true},
+			{Name: FieldDescription, Value: note.Description, Editable: true},
+		}, nil
+
+	case keeperclient.KindFile:
+		files, _, err := vs.client.Files(ctx, keeperclient.ListOptions{Search: item.Title})
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if f.Title == item.Title {
+				return []tui.Field{
+					{Name: tui.FieldFileName, Value: f.FileName},
+					{Name: FieldDescription, Value: f.Description, Editable: true},
+				}, nil
+			}
+		}
+		return nil, keeperclient.ErrNotFound
+	}
+	return nil, fmt.Errorf("unknown item kind %q", item.Kind)
+}
+
+// SetField changes an editable field of the item. Custom password fields keep their type.
+func (vs *VaultService) SetField(ctx context.Context, item keeperclient.Item, name, value string) error {
+	switch item.Kind {
+	case keeperclient.KindPassword:
+		var pwd keeperclient.PasswordToUpdate
+		switch name {
+		case FieldPassword:
+			pwd.Password = value
+		case FieldUsername:
+			pwd.Username = &value
+		case FieldDescription:
+			pwd.Description = value
+		default:
+			details, err := vs.client.PasswordDetails(ctx, item.Title)
+			if err != nil {
+				return err
+			}
+			f, ok := details.Field(name)
+			if !ok {
+				return fmt.Errorf("password %q has no field %q", item.Title, name)
+			}
+			f.Value = value
+			fields := mergeFields(details.Fields, DetailsChanges{SetFields: []keeperclient.PasswordField{f}})
+			pwd.Fields = &fields
+		}
+		return vs.client.UpdatePassword(ctx, item.Title, pwd)
+
+	case keeperclient.KindCard:
+		var card keeperclient.CardToUpdate
+		switch name {
+		case FieldNumber:
+			card.Num = value
+		case FieldExpiry:
+			card.Expire = value
+		case FieldCvc:
+			card.Cvc = value
+		case FieldPin:
+			card.Pin = value
+		case FieldHolder:
+			card.Holder = value
+		case FieldAddress:
+			card.Address = value
+		case FieldDescription:
+			card.Description = value
+		default:
+			return fmt.Errorf("card field %q can not be edited", name)
+		}
+		return vs.client.UpdateCard(ctx, item.Title, card)
+
+	case keeperclient.KindIdentity:
+		if name == FieldDescription {
+			return vs.client.UpdateIdentity(ctx, item.Title, keeperclient.IdentityToUpdate{Description: &value})
+		}
+		return vs.client.UpdateIdentity(ctx, item.Title, keeperclient.IdentityToUpdate{
+			Fields: map[string]*string{name: &value},
+		})
+
+	case keeperclient.KindSSHKey:
+		if name != FieldDescription {
+			return fmt.Errorf("ssh key field %q can not be edited", name)
+		}
+		return vs.client.UpdateSSHKey(ctx, item.Title, keeperclient.SSHKeyToUpdate{Description: &value})
+
+	case keeperclient.KindNote:
+		switch name {
+		case FieldText:
+			return vs.client.UpdateNote(ctx, item.Title, keeperclient.NoteToUpdate{Text: &value})
+		case FieldDescription:
+			return vs.client.UpdateNote(ctx, item.Title, keeperclient.NoteToUpdate{Description: &value})
+		}
+		return fmt.Errorf("note field %q can not be edited", name)
+
+	case keeperclient.KindFile:
+		if name != FieldDescription {
+			return fmt.Errorf("file field %q can not be edited", name)
+		}
+		return vs.client.UpdateFile(ctx, item.Title, keeperclient.FileToUpdate{Description: value})
+	}
+	return fmt.Errorf("unknown item kind %q", item.Kind)
+}
+
+// Download saves the file to the path with 0600 permissions. An existing file is not overwritten.
+func (vs *VaultService) Download(ctx context.Context, item keeperclient.Item, path string) error {
+	if item.Kind != keeperclient.KindFile {
+		return errors.New("only files can be downloaded")
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := vs.client.File(ctx, item.Title, f); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+// Copy copies the value to the clipboard, it is cleared after the clipboard timeout.
+func (vs *VaultService) Copy(name, value string) error {
+	if vs.clip == nil {
+		return errors.New("clipboard is not available")
+	}
+	return vs.clip.Copy(name, value)
+}
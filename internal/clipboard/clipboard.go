@@ -0,0 +1,168 @@
+// Package clipboard copies secrets to the system clipboard through pluggable backends
+// (wl-copy, xclip, xsel, pbcopy, clip.exe) and clears them later if the content is unchanged.
+package clipboard
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// ErrNoBackend is returned if no clipboard tool is found.
+var ErrNoBackend = errors.New("no clipboard tool found: install wl-clipboard, xclip or xsel")
+
+// Backend is a clipboard.
+type Backend interface {
+	Name() string
+	Write(text string) error
+	Read() (string, error)
+}
+
+// Command is a backend running external tools: copy gets the text on stdin, paste prints it.
+type Command struct {
+	name  string
+	copy  []string
+	paste []string
+	clear []string // clear empties the clipboard; empty text is copied if nil.
+}
+
+// Backends by name, in the order of detection.
+var commands = []Command{
+	{name: "wl-copy", copy: []string{"wl-copy"}, paste: []string{"wl-paste", "--no-newline"}, clear: []string{"wl-copy", "--clear"}},
+	{name: "xclip", copy: []string{"xclip", "-selection", "clipboard", "-in"}, paste: []string{"xclip", "-selection", "clipboard", "-out"}},
+	{name: "xsel", copy: []string{"xsel", "--clipboard", "--input"}, paste: []string{"xsel", "--clipboard", "--output"}, clear: []string{"xsel", "--clipboard", "--delete"}},
+	{name: "pbcopy", copy: []string{"pbcopy"}, paste: []string{"pbpaste"}},
+	{name: "clip.exe", copy: []string{"clip.exe"}, paste: []string{"powershell.exe", "-NoProfile", "-Command", "Get-Clipboard -Raw"}},
+}
+
+// Names returns the names of the command backends.
+func Names() []string {
+	var names []string
+	for _, c := range commands {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+func (c Command) Name() string {
+	return c.name
+}
+
+func (c Command) Write(text string) error {
+	args := c.copy
+	if text == "" && c.clear != nil {
+		args = c.clear
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	// stdout and stderr are not attached: xclip and wl-copy keep serving the clipboard in the background,
+	// and an attached pipe would make Run wait for them
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", c.name, err)
+	}
+	return nil
+}
+
+func (c Command) Read() (string, error) {
+	var out bytes.Buffer
+	cmd := exec.Command(c.paste[0], c.paste[1:]...)
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s: %w", c.name, err)
+	}
+	text := out.String()
+	if c.name == "clip.exe" {
+		// PowerShell ends the output with CRLF
+		text = strings.TrimSuffix(text, "\r\n")
+	}
+	return text, nil
+}
+
+// available reports whether the tools of the backend are installed and usable in this session.
+func (c Command) available() bool {
+	for _, args := range [][]string{c.copy, c.paste} {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			return false
+		}
+	}
+	switch c.name {
+	case "wl-copy":
+		return os.Getenv("WAYLAND_DISPLAY") != ""
+	case "xclip", "xsel":
+		return os.Getenv("DISPLAY") != ""
+	case "pbcopy":
+		return runtime.GOOS == "darwin"
+	}
+	return true
+}
+
+// New returns the backend by name. With an empty name or "auto" the first backend usable
+// in the session is chosen: wl-copy on Wayland, xclip or xsel on X11, pbcopy on macOS,
+// clip.exe on Windows and WSL.
+func New(name string) (Backend, error) {
+	for _, c := range commands {
+		if name == c.name {
+			if _, err := exec.LookPath(c.copy[0]); err != nil {
+				return nil, fmt.Errorf("clipboard tool %s is not found", c.name)
+			}
+			return c, nil
+		}
+		if (name == "" || name == "auto") && c.available() {
+			return c, nil
+		}
+	}
+	if name == "" || name == "auto" {
+		return nil, ErrNoBackend
+	}
+	return nil, fmt.Errorf("unknown clipboard backend %q", name)
+}
+
+// Memory is an in-process clipboard for tests.
+type Memory struct {
+	mu   sync.Mutex
+	text string
+}
+
+func (m *Memory) Name() string {
+	return "memory"
+}
+
+func (m *Memory) Write(text string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.text = text
+	return nil
+}
+
+func (m *Memory) Read() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.text, nil
+}
+
+// Digest returns the SHA-256 of the text. It identifies the copied secret without keeping it around
+// until the clipboard is cleared.
+func Digest(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// ClearIfUnchanged empties the clipboard if it still holds the text with the digest,
+// so that anything copied by the user since then is kept. It reports whether the clipboard was cleared.
+func ClearIfUnchanged(b Backend, digest string) (bool, error) {
+	text, err := b.Read()
+	if err != nil {
+		return false, err
+	}
+	if Digest(text) != digest {
+		return false, nil
+	}
+	return true, b.Write("")
+}
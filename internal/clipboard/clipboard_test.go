@@ -0,0 +1,40 @@
+package clipboard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClearIfUnchanged(t *testing.T) {
+	board := &Memory{}
+	require.NoError(t, board.Write("s3cret"))
+
+	cleared, err := ClearIfUnchanged(board, Digest("s3cret"))
+	require.NoError(t, err)
+	require.True(t, cleared)
+	text, err := board.Read()
+	require.NoError(t, err)
+	require.Empty(t, text)
+
+	// the user copied something else since then
+	require.NoError(t, board.Write("s3cret"))
+	require.NoError(t, board.Write("hello"))
+	cleared, err = ClearIfUnchanged(board, Digest("s3cret"))
+	require.NoError(t, err)
+	require.False(t, cleared)
+	text, err = board.Read()
+	require.NoError(t, err)
+	require.Equal(t, "hello", text)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("nope")
+	require.ErrorContains(t, err, "unknown clipboard backend")
+
+	t.Setenv("PATH", t.TempDir())
+	_, err = New("auto")
+	require.ErrorIs(t, err, ErrNoBackend)
+	_, err = New("xclip")
+	require.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,204 @@
+// Package credhelper implements the git-credential and docker-credential-* helper protocols
+// on top of a credential store, so that git and docker take credentials from the vault
+// instead of .git-credentials and ~/.docker/config.json.
+package credhelper
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrNotFound is returned by docker get when there are no credentials for the server.
+// The message is the one docker expects from credential helpers.
+var ErrNotFound = errors.New("credentials not found in native keychain")
+
+// Credential is a username and a secret for a server URL.
+type Credential struct {
+	URL      string
+	Username string
+	Password string
+}
+
+// Store keeps the credentials.
+type Store interface {
+	// Get returns the credential for the URL and the username, if not empty; nil if there is none.
+	Get(ctx context.Context, serverURL, username string) (*Credential, error)
+	// Store saves the credential.
+	Store(ctx context.Context, cred Credential) error
+	// Erase removes the credential. An empty password matches any.
+	Erase(ctx context.Context, cred Credential) error
+	// List returns the stored credentials without passwords.
+	List(ctx context.Context) ([]Credential, error)
+}
+
+// Git actions.
+const (
+	GitGet   = "get"
+	GitStore = "store"
+	GitErase = "erase"
+)
+
+// Git serves one git-credential request: it reads the key=value attributes from in and,
+// for get, writes the username and password to out. Unknown actions are ignored, as git requires.
+func Git(ctx context.Context, action string, in io.Reader, out io.Writer, s Store) error {
+	switch action {
+	case GitGet, GitStore, GitErase:
+	default:
+		return nil
+	}
+
+	attrs, err := readGitAttrs(in)
+	if err != nil {
+		return err
+	}
+	serverURL, err := gitURL(attrs)
+	if err != nil {
+		return err
+	}
+	cred := Credential{URL: serverURL, Username: attrs["username"], Password: attrs["password"]}
+
+	switch action {
+	case GitGet:
+		found, err := s.Get(ctx, cred.URL, cred.Username)
+		if err != nil || found == nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "username=%s\npassword=%s\n", found.Username, found.Password)
+		return err
+	case GitStore:
+		if cred.Username == "" || cred.Password == "" {
+			return nil
+		}
+		return s.Store(ctx, cred)
+	case GitErase:
+		return s.Erase(ctx, cred)
+	}
+	return nil
+}
+
+// readGitAttrs reads key=value lines up to an empty line or the end of input.
+func readGitAttrs(in io.Reader) (map[string]string, error) {
+	attrs := map[string]string{}
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid credential attribute %q", line)
+		}
+		attrs[key] = value
+	}
+	return attrs, scanner.Err()
+}
+
+// gitURL builds the server URL from the url attribute or from protocol, host and path.
+func gitURL(attrs map[string]string) (string, error) {
+	if u := attrs["url"]; u != "" {
+		return u, nil
+	}
+	if attrs["protocol"] == "" || attrs["host"] == "" {
+		return "", errors.New("credential request must have protocol and host")
+	}
+	u := attrs["protocol"] + "://" + attrs["host"]
+	if path := attrs["path"]; path != "" {
+		u += "/" + strings.TrimPrefix(path, "/")
+	}
+	return u, nil
+}
+
+// Docker actions.
+const (
+	DockerGet   = "get"
+	DockerStore = "store"
+	DockerErase = "erase"
+	DockerList  = "list"
+)
+
+// dockerCredential is the JSON credential of the docker protocol.
+type dockerCredential struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// Docker serves one docker-credential request. get and erase read the server URL from in,
+// store reads a JSON credential; get and list write JSON to out.
+// get fails with ErrNotFound if there are no credentials for the server.
+func Docker(ctx context.Context, action string, in io.Reader, out io.Writer, s Store) error {
+	switch action {
+	case DockerGet:
+		serverURL, err := readServerURL(in)
+		if err != nil {
+			return err
+		}
+		found, err := s.Get(ctx, dockerURL(serverURL), "")
+		if err != nil {
+			return err
+		}
+		if found == nil {
+			return ErrNotFound
+		}
+		return json.NewEncoder(out).Encode(dockerCredential{
+			ServerURL: serverURL,
+			Username:  found.Username,
+			Secret:    found.Password,
+		})
+
+	case DockerStore:
+		var cred dockerCredential
+		if err := json.NewDecoder(in).Decode(&cred); err != nil {
+			return fmt.Errorf("invalid credential: %w", err)
+		}
+		if cred.ServerURL == "" || cred.Secret == "" {
+			return errors.New("credential must have ServerURL and Secret")
+		}
+		return s.Store(ctx, Credential{URL: dockerURL(cred.ServerURL), Username: cred.Username, Password: cred.Secret})
+
+	case DockerErase:
+		serverURL, err := readServerURL(in)
+		if err != nil {
+			return err
+		}
+		return s.Erase(ctx, Credential{URL: dockerURL(serverURL)})
+
+	case DockerList:
+		creds, err := s.List(ctx)
+		if err != nil {
+			return err
+		}
+		res := map[string]string{}
+		for _, c := range creds {
+			res[c.URL] = c.Username
+		}
+		return json.NewEncoder(out).Encode(res)
+	}
+	return fmt.Errorf("unknown action %q", action)
+}
+
+func readServerURL(in io.Reader) (string, error) {
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return "", err
+	}
+	serverURL := strings.TrimSpace(string(data))
+	if serverURL == "" {
+		return "", errors.New("server URL is empty")
+	}
+	return serverURL, nil
+}
+
+// dockerURL adds the https scheme to registry addresses given without it, e.g. registry.example.com:5000.
+func dockerURL(serverURL string) string {
+	if strings.Contains(serverURL, "://") {
+		return serverURL
+	}
+	return "https://" + serverURL
+}
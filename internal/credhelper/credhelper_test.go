@@ -0,0 +1,110 @@
+package credhelper
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memStore struct {
+	creds []Credential
+}
+
+func (m *memStore) Get(_ context.Context, serverURL, username string) (*Credential, error) {
+	for _, c := range m.creds {
+		if c.URL == serverURL && (username == "" || c.Username == username) {
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memStore) Store(_ context.Context, cred Credential) error {
+	m.creds = append(m.creds, cred)
+	return nil
+}
+
+func (m *memStore) Erase(_ context.Context, cred Credential) error {
+	var res []Credential
+	for _, c := range m.creds {
+		if c.URL == cred.URL && (cred.Password == "" || c.Password == cred.Password) {
+			continue
+		}
+		res = append(res, c)
+	}
+	m.creds = res
+	return nil
+}
+
+func (m *memStore) List(context.Context) ([]Credential, error) {
+	return m.creds, nil
+}
+
+func TestGit(t *testing.T) {
+	ctx := context.Background()
+	store := &memStore{}
+
+	var out bytes.Buffer
+	require.NoError(t, Git(ctx, GitGet, strings.NewReader("protocol=https\nhost=github.com\n\n"), &out, store))
+	require.Empty(t, out.String())
+
+	require.NoError(t, Git(ctx, GitStore,
+		strings.NewReader("protocol=https\nhost=github.com\nusername=ane\npassword=s3cret\n"), &out, store))
+	require.Equal(t, []Credential{{URL: "https://github.com", Username: "ane", Password: "s3cret"}}, store.creds)
+
+	require.NoError(t, Git(ctx, GitGet, strings.NewReader("protocol=https\nhost=github.com\n"), &out, store))
+	require.Equal(t, "username=ane\npassword=s3cret\n", out.String())
+
+	// the rejected password is not the stored one
+	require.NoError(t, Git(ctx, GitErase,
+		strings.NewReader("protocol=https\nhost=github.com\nusername=ane\npassword=old\n"), &out, store))
+	require.Len(t, store.creds, 1)
+	require.NoError(t, Git(ctx, GitErase,
+		strings.NewReader("protocol=https\nhost=github.com\nusername=ane\npassword=s3cret\n"), &out, store))
+	require.Empty(t, store.creds)
+
+	// unknown actions are ignored
+	require.NoError(t, Git(ctx, "capability", strings.NewReader("protocol=https\nhost=github.com\n"), &out, store))
+
+	require.Error(t, Git(ctx, GitGet, strings.NewReader("host=github.com\n"), &out, store))
+	require.Error(t, Git(ctx, GitGet, strings.NewReader("garbage\n"), &out, store))
+}
+
+func TestGitURL(t *testing.T) {
+	u, err := gitURL(map[string]string{"protocol": "https", "host": "example.com:8443", "path": "org/repo.git"})
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com:8443/org/repo.git", u)
+
+	u, err = gitURL(map[string]string{"url": "https://example.com/x"})
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/x", u)
+}
+
+func TestDocker(t *testing.T) {
+	ctx := context.Background()
+	store := &memStore{}
+
+	var out bytes.Buffer
+	err := Docker(ctx, DockerGet, strings.NewReader("registry.example.com\n"), &out, store)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, Docker(ctx, DockerStore,
+		strings.NewReader(`{"ServerURL":"registry.example.com","Username":"ane","Secret":"tok"}`), &out, store))
+	require.Equal(t, []Credential{{URL: "https://registry.example.com", Username: "ane", Password: "tok"}}, store.creds)
+
+	require.NoError(t, Docker(ctx, DockerGet, strings.NewReader("registry.example.com"), &out, store))
+	require.JSONEq(t, `{"ServerURL":"registry.example.com","Username":"ane","Secret":"tok"}`, out.String())
+
+	out.Reset()
+	require.NoError(t, Docker(ctx, DockerList, strings.NewReader(""), &out, store))
+	require.JSONEq(t, `{"https://registry.example.com":"ane"}`, out.String())
+
+	require.NoError(t, Docker(ctx, DockerErase, strings.NewReader("registry.example.com"), &out, store))
+	require.Empty(t, store.creds)
+
+	require.Error(t, Docker(ctx, DockerStore, strings.NewReader(`{"ServerURL":""}`), &out, store))
+	require.Error(t, Docker(ctx, "version", strings.NewReader(""), &out, store))
+}
@@ -0,0 +1,67 @@
+// Package emergency defines emergency access: a user designates trusted contacts, and a contact who
+// requests access gets it once the waiting period set by the user passes without the user rejecting
+// the request. Access is read-only: the contact receives the emergency kit, a snapshot of the vault
+// encrypted on the client of the user with a key wrapped for the public key of the contact.
+package emergency
+
+import (
+	"fmt"
+	"time"
+)
+
+// Status is the state of the access of a trusted contact.
+type Status string
+
+const (
+	// Idle means the contact has not requested access, or the request was rejected.
+	Idle Status = "idle"
+	// Requested means the contact is waiting for the waiting period to pass.
+	Requested Status = "requested"
+	// Granted means the waiting period passed: the contact can open the emergency kit.
+	Granted Status = "granted"
+)
+
+// Event is a recorded step of emergency access. Every event is notified to the other party.
+type Event string
+
+const (
+	EventAdded     Event = "added"     // the user designated the contact
+	EventUpdated   Event = "updated"   // the user renewed the kit or changed the waiting period
+	EventRequested Event = "requested" // the contact requested access
+	EventRejected  Event = "rejected"  // the user rejected the request or revoked the access
+	EventGranted   Event = "granted"   // the waiting period passed
+	EventViewed    Event = "viewed"    // the contact opened the kit
+	EventRemoved   Event = "removed"   // the user removed the contact
+)
+
+// Limits of the waiting period.
+const (
+	MinWait = time.Hour
+	MaxWait = 90 * 24 * time.Hour
+)
+
+// ValidWait checks that the waiting period is within the limits.
+func ValidWait(wait time.Duration) error {
+	if wait < MinWait || wait > MaxWait {
+		return fmt.Errorf("waiting period must be from %s to %s", MinWait, MaxWait)
+	}
+	return nil
+}
+
+// Effective returns the status as of now: a request becomes granted once the waiting period has passed.
+func Effective(status Status, requestedAt *time.Time, wait time.Duration, now time.Time) Status {
+	if status == Requested && requestedAt != nil && !now.Before(requestedAt.Add(wait)) {
+		return Granted
+	}
+	return status
+}
+
+// NotifiesOwner reports whether the event is notified to the user who designated the contact;
+// the other events are notified to the contact.
+func (e Event) NotifiesOwner() bool {
+	switch e {
+	case EventRequested, EventGranted, EventViewed:
+		return true
+	}
+	return false
+}
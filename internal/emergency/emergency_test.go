@@ -0,0 +1,39 @@
+package emergency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffective(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	wait := 48 * time.Hour
+	recent := now.Add(-time.Hour)
+	old := now.Add(-wait)
+
+	tests := []struct {
+		name        string
+		status      Status
+		requestedAt *time.Time
+		want        Status
+	}{
+		{"idle", Idle, nil, Idle},
+		{"waiting", Requested, &recent, Requested},
+		{"waiting period passed", Requested, &old, Granted},
+		{"granted", Granted, &old, Granted},
+		{"rejected", Idle, &old, Idle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Effective(tt.status, tt.requestedAt, wait, now))
+		})
+	}
+}
+
+func TestValidWait(t *testing.T) {
+	require.NoError(t, ValidWait(72*time.Hour))
+	require.Error(t, ValidWait(time.Minute))
+	require.Error(t, ValidWait(365*24*time.Hour))
+}
@@ -0,0 +1,129 @@
+// Package health builds a vault health report: weak, reused and old passwords and cards near expiry.
+// The report is built on the client from decrypted values, the server takes no part in it.
+package health
+
+import (
+	"crypto/sha256"
+	"sort"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/paycard"
+	"github.com/adettelle/go-keeper/internal/strength"
+)
+
+const (
+	DefaultMaxAge       = 365 * 24 * time.Hour // DefaultMaxAge is the age after which a password is reported as old.
+	DefaultExpiryWindow = 60 * 24 * time.Hour  // DefaultExpiryWindow is how early cards are reported before expiry.
+)
+
+// Password is a decrypted password entry.
+type Password struct {
+	Title     string
+	Value     string
+	ChangedAt time.Time // ChangedAt is the last change of the value; zero if unknown.
+}
+
+// Card is a decrypted card entry.
+type Card struct {
+	Title  string
+	Expire string // Expire is the expiry date as MMYY or MM/YY.
+}
+
+// Options configures the checks.
+type Options struct {
+	MaxAge       time.Duration
+	ExpiryWindow time.Duration
+	Now          time.Time
+}
+
+type WeakPassword struct {
+	Title   string
+	Score   int
+	Warning string
+}
+
+type OldPassword struct {
+	Title     string
+	ChangedAt time.Time
+	Age       time.Duration
+}
+
+type ExpiringCard struct {
+	Title     string
+	ExpiresAt time.Time // ExpiresAt is the first moment the card is not valid.
+	Expired   bool
+}
+
+// Report is the result of the checks.
+type Report struct {
+	Weak     []WeakPassword
+	Reused   [][]string // Reused holds groups of titles sharing the same password.
+	Old      []OldPassword
+	Expiring []ExpiringCard
+	Invalid  []string // Invalid holds titles of cards with unparsable expiry date.
+}
+
+// Empty reports whether no problem was found.
+func (r Report) Empty() bool {
+	return len(r.Weak) == 0 && len(r.Reused) == 0 && len(r.Old) == 0 &&
+		len(r.Expiring) == 0 && len(r.Invalid) == 0
+}
+
+// Check runs all checks. Zero options are replaced with the defaults.
+func Check(passwords []Password, cards []Card, opts Options) Report {
+	if opts.MaxAge == 0 {
+		opts.MaxAge = DefaultMaxAge
+	}
+	if opts.ExpiryWindow == 0 {
+		opts.ExpiryWindow = DefaultExpiryWindow
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	var res Report
+	reused := map[[sha256.Size]byte][]string{}
+
+	for _, pwd := range passwords {
+		// the title often names the service, so it is the first thing to guess
+		est := strength.Estimate(pwd.Value, pwd.Title)
+		if est.Weak() {
+			res.Weak = append(res.Weak, WeakPassword{Title: pwd.Title, Score: est.Score, Warning: est.Warning})
+		}
+
+		sum := sha256.Sum256([]byte(pwd.Value))
+		reused[sum] = append(reused[sum], pwd.Title)
+
+		if !pwd.ChangedAt.IsZero() {
+			if age := opts.Now.Sub(pwd.ChangedAt); age > opts.MaxAge {
+				res.Old = append(res.Old, OldPassword{Title: pwd.Title, ChangedAt: pwd.ChangedAt, Age: age})
+			}
+		}
+	}
+
+	for _, titles := range reused {
+		if len(titles) > 1 {
+			sort.Strings(titles)
+			res.Reused = append(res.Reused, titles)
+		}
+	}
+	sort.Slice(res.Reused, func(i, j int) bool { return res.Reused[i][0] < res.Reused[j][0] })
+
+	for _, card := range cards {
+		expiresAt, err := paycard.ParseExpiry(card.Expire)
+		if err != nil {
+			res.Invalid = append(res.Invalid, card.Title)
+			continue
+		}
+		if expiresAt.Sub(opts.Now) <= opts.ExpiryWindow {
+			res.Expiring = append(res.Expiring, ExpiringCard{
+				Title:     card.Title,
+				ExpiresAt: expiresAt,
+				Expired:   !opts.Now.Before(expiresAt),
+			})
+		}
+	}
+	sort.Slice(res.Expiring, func(i, j int) bool { return res.Expiring[i].ExpiresAt.Before(res.Expiring[j].ExpiresAt) })
+
+	return res
+}
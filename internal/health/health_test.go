@@ -0,0 +1,53 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2024, time.November, 15, 0, 0, 0, 0, time.UTC)
+
+	passwords := []Password{
+		{Title: "mail", Value: "password", ChangedAt: now.Add(-24 * time.Hour)},
+		{Title: "bank", Value: "k7#Qz!p2Lm9x-Wf", ChangedAt: now.Add(-400 * 24 * time.Hour)},
+		{Title: "shop", Value: "k7#Qz!p2Lm9x-Wf"},
+		{Title: "github", Value: "github2024"},
+	}
+	cards := []Card{
+		{Title: "visa", Expire: "1124"},
+		{Title: "master", Expire: "12/24"},
+		{Title: "old", Expire: "0124"},
+		{Title: "fresh", Expire: "0530"},
+		{Title: "broken", Expire: "1x24"},
+	}
+
+	report := Check(passwords, cards, Options{Now: now})
+	require.False(t, report.Empty())
+
+	var weak []string
+	for _, w := range report.Weak {
+		weak = append(weak, w.Title)
+		require.NotEmpty(t, w.Warning)
+	}
+	require.Equal(t, []string{"mail", "github"}, weak)
+
+	require.Equal(t, [][]string{{"bank", "shop"}}, report.Reused)
+
+	require.Len(t, report.Old, 1)
+	require.Equal(t, "bank", report.Old[0].Title)
+
+	require.Equal(t, []ExpiringCard{
+		{Title: "old", ExpiresAt: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), Expired: true},
+		{Title: "visa", ExpiresAt: time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC)},
+		{Title: "master", ExpiresAt: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}, report.Expiring)
+	require.Equal(t, []string{"broken"}, report.Invalid)
+}
+
+func TestCheckEmpty(t *testing.T) {
+	report := Check([]Password{{Title: "bank", Value: "k7#Qz!p2Lm9x-Wf"}}, nil, Options{})
+	require.True(t, report.Empty())
+}
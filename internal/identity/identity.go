@@ -0,0 +1,205 @@
+// Package identity describes the schemas of identity documents: passports, driver licenses,
+// national IDs and addresses. Every document type has a fixed set of typed fields
+// (text, dates, country codes, document numbers) which are normalized and validated by the schema.
+package identity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DocType is a type of identity documents.
+type DocType string
+
+const (
+	DocPassport      DocType = "passport"
+	DocDriverLicense DocType = "driver_license"
+	DocNationalID    DocType = "national_id"
+	DocAddress       DocType = "address"
+)
+
+// DocTypes are all the document types in the listing order.
+var DocTypes = []DocType{DocPassport, DocDriverLicense, DocNationalID, DocAddress}
+
+// FieldType is a type of document field values.
+type FieldType string
+
+const (
+	FieldText      FieldType = "text"
+	FieldDate      FieldType = "date"            // FieldDate is a date in the YYYY-MM-DD form.
+	FieldCountry   FieldType = "country"         // FieldCountry is an ISO 3166-1 alpha-2 country code.
+	FieldDocNumber FieldType = "document_number" // FieldDocNumber is a document number: latin letters, digits and dashes.
+)
+
+// DateLayout is the layout of date fields.
+const DateLayout = time.DateOnly
+
+// MaxTextLength is the maximal length of a text field.
+const MaxTextLength = 255
+
+// Tag returns the validator tag checking the normalized values of the field type.
+// The document_number tag is registered with ValidDocNumber.
+func (ft FieldType) Tag() string {
+	switch ft {
+	case FieldDate:
+		return "datetime=" + DateLayout
+	case FieldCountry:
+		return "iso3166_1_alpha2"
+	case FieldDocNumber:
+		return "document_number"
+	default:
+		return fmt.Sprintf("max=%d", MaxTextLength)
+	}
+}
+
+// Normalize returns the canonical form of the value: country codes and document numbers
+// are upper-cased, spaces are removed from document numbers.
+func (ft FieldType) Normalize(value string) string {
+	value = strings.TrimSpace(value)
+	switch ft {
+	case FieldCountry:
+		return strings.ToUpper(value)
+	case FieldDocNumber:
+		return strings.ToUpper(strings.Join(strings.Fields(value), ""))
+	default:
+		return value
+	}
+}
+
+var docNumberRe = regexp.MustCompile(`^[A-Z0-9](?:[A-Z0-9-]{0,30}[A-Z0-9])?$`)
+
+// ValidDocNumber reports whether the normalized document number is valid: up to 32 latin letters,
+// digits and inner dashes.
+func ValidDocNumber(s string) bool {
+	return docNumberRe.MatchString(s)
+}
+
+// Field describes a document field.
+type Field struct {
+	Name     string
+	Type     FieldType
+	Required bool
+}
+
+// Field names shared by several document types.
+const (
+	FieldNumber     = "number"
+	FieldCountryOf  = "country"
+	FieldFullName   = "full_name"
+	FieldBirthDate  = "birth_date"
+	FieldIssueDate  = "issue_date"
+	FieldExpiryDate = "expiry_date"
+	FieldAuthority  = "authority"
+)
+
+// schemas are the fields of the document types in the display order.
+var schemas = map[DocType][]Field{
+	DocPassport: {
+		{Name: FieldNumber, Type: FieldDocNumber, Required: true},
+		{Name: FieldCountryOf, Type: FieldCountry, Required: true},
+		{Name: FieldFullName, Type: FieldText, Required: true},
+		{Name: "nationality", Type: FieldCountry},
+		{Name: FieldBirthDate, Type: FieldDate},
+		{Name: "birth_place", Type: FieldText},
+		{Name: "sex", Type: FieldText},
+		{Name: FieldIssueDate, Type: FieldDate},
+		{Name: FieldExpiryDate, Type: FieldDate},
+		{Name: FieldAuthority, Type: FieldText},
+	},
+	DocDriverLicense: {
+		{Name: FieldNumber, Type: FieldDocNumber, Required: true},
+		{Name: FieldCountryOf, Type: FieldCountry, Required: true},
+		{Name: FieldFullName, Type: FieldText, Required: true},
+		{Name: FieldBirthDate, Type: FieldDate},
+		{Name: "categories", Type: FieldText},
+		{Name: FieldIssueDate, Type: FieldDate},
+		{Name: FieldExpiryDate, Type: FieldDate},
+		{Name: FieldAuthority, Type: FieldText},
+	},
+	DocNationalID: {
+		{Name: FieldNumber, Type: FieldDocNumber, Required: true},
+		{Name: FieldCountryOf, Type: FieldCountry, Required: true},
+		{Name: FieldFullName, Type: FieldText, Required: true},
+		{Name: FieldBirthDate, Type: FieldDate},
+		{Name: FieldIssueDate, Type: FieldDate},
+		{Name: FieldExpiryDate, Type: FieldDate},
+		{Name: FieldAuthority, Type: FieldText},
+	},
+	DocAddress: {
+		{Name: FieldFullName, Type: FieldText},
+		{Name: "street", Type: FieldText, Required: true},
+		{Name: "city", Type: FieldText, Required: true},
+		{Name: "region", Type: FieldText},
+		{Name: "postal_code", Type: FieldText},
+		{Name: FieldCountryOf, Type: FieldCountry, Required: true},
+		{Name: "phone", Type: FieldText},
+	},
+}
+
+// Schema returns the fields of the document type.
+func Schema(t DocType) ([]Field, bool) {
+	fields, ok := schemas[t]
+	return fields, ok
+}
+
+// CheckFunc checks the value by the validator tag, e.g. (*validator.Validate).Var.
+type CheckFunc func(value any, tag string) error
+
+// Validate checks the fields against the schema of the document type with check and returns
+// the normalized fields; empty values are dropped. The issue date must not be before
+// the birth date and the expiry date must be after the issue date.
+func Validate(t DocType, fields map[string]string, check CheckFunc) (map[string]string, error) {
+	schema, ok := Schema(t)
+	if !ok {
+		return nil, fmt.Errorf("unknown document type %q", t)
+	}
+	for name := range fields {
+		if !slices.ContainsFunc(schema, func(f Field) bool { return f.Name == name }) {
+			return nil, fmt.Errorf("unknown %s field %q", t, name)
+		}
+	}
+
+	res := make(map[string]string, len(fields))
+	for _, f := range schema {
+		value := f.Type.Normalize(fields[f.Name])
+		if value == "" {
+			if f.Required {
+				return nil, fmt.Errorf("%s field %q is required", t, f.Name)
+			}
+			continue
+		}
+		if err := check(value, f.Type.Tag()); err != nil {
+			return nil, fmt.Errorf("invalid %s field %q: %w", t, f.Name, err)
+		}
+		res[f.Name] = value
+	}
+
+	if err := checkDateOrder(res, FieldBirthDate, FieldIssueDate, false); err != nil {
+		return nil, err
+	}
+	if err := checkDateOrder(res, FieldIssueDate, FieldExpiryDate, true); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// checkDateOrder checks that the date of the later field is after (or, unless strict, equal to)
+// the date of the earlier one. Missing dates are not checked.
+func checkDateOrder(fields map[string]string, earlier, later string, strict bool) error {
+	if fields[earlier] == "" || fields[later] == "" {
+		return nil
+	}
+	from, err1 := time.Parse(DateLayout, fields[earlier])
+	to, err2 := time.Parse(DateLayout, fields[later])
+	if err := errors.Join(err1, err2); err != nil {
+		return err
+	}
+	if to.Before(from) || (strict && to.Equal(from)) {
+		return fmt.Errorf("%s must be after %s", later, earlier)
+	}
+	return nil
+}
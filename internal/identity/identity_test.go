@@ -0,0 +1,95 @@
+package identity
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/require"
+)
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	_ = v.RegisterValidation("document_number", func(fl validator.FieldLevel) bool {
+		return ValidDocNumber(fl.Field().String())
+	})
+	return v
+}
+
+func TestValidate(t *testing.T) {
+	v := newValidator()
+
+	fields, err := Validate(DocPassport, map[string]string{
+		FieldNumber:     " 45 01 123456 ",
+		FieldCountryOf:  "ru",
+		FieldFullName:   "Ane Doe",
+		"nationality":   "",
+		FieldBirthDate:  "1990-04-01",
+		FieldIssueDate:  "2015-05-20",
+		FieldExpiryDate: "2035-05-20",
+	}, v.Var)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		FieldNumber:     "4501123456",
+		FieldCountryOf:  "RU",
+		FieldFullName:   "Ane Doe",
+		FieldBirthDate:  "1990-04-01",
+		FieldIssueDate:  "2015-05-20",
+		FieldExpiryDate: "2035-05-20",
+	}, fields)
+}
+
+func TestValidateErrors(t *testing.T) {
+	v := newValidator()
+	valid := func() map[string]string {
+		return map[string]string{FieldNumber: "D1234567", FieldCountryOf: "US", FieldFullName: "Ane Doe"}
+	}
+
+	tests := []struct {
+		name    string
+		docType DocType
+		change  func(map[string]string)
+	}{
+		{name: "unknown type", docType: "visa", change: func(map[string]string) {}},
+		{name: "unknown field", docType: DocDriverLicense, change: func(f map[string]string) { f["street"] = "x" }},
+		{name: "required", docType: DocDriverLicense, change: func(f map[string]string) { delete(f, FieldFullName) }},
+		{name: "country", docType: DocDriverLicense, change: func(f map[string]string) { f[FieldCountryOf] = "XX" }},
+		{name: "number", docType: DocDriverLicense, change: func(f map[string]string) { f[FieldNumber] = "D-123/4" }},
+		{name: "date", docType: DocDriverLicense, change: func(f map[string]string) { f[FieldBirthDate] = "01.04.1990" }},
+		{name: "expiry before issue", docType: DocDriverLicense, change: func(f map[string]string) {
+			f[FieldIssueDate], f[FieldExpiryDate] = "2020-01-01", "2019-01-01"
+		}},
+		{name: "issue before birth", docType: DocNationalID, change: func(f map[string]string) {
+			f[FieldBirthDate], f[FieldIssueDate] = "2000-01-01", "1999-01-01"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := valid()
+			tt.change(fields)
+			_, err := Validate(tt.docType, fields, v.Var)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestValidateUsesCheck(t *testing.T) {
+	errRejected := errors.New("rejected")
+	_, err := Validate(DocAddress, map[string]string{"street": "Main st. 1", "city": "Paris", FieldCountryOf: "fr"},
+		func(value any, tag string) error {
+			if value == "FR" && tag == "iso3166_1_alpha2" {
+				return errRejected
+			}
+			return nil
+		})
+	require.ErrorIs(t, err, errRejected)
+}
+
+func TestValidDocNumber(t *testing.T) {
+	for _, s := range []string{"A", "4501123456", "AB-123-C"} {
+		require.True(t, ValidDocNumber(s), s)
+	}
+	for _, s := range []string{"", "-AB", "AB-", "ab12", "AB 12", "A/1"} {
+		require.False(t, ValidDocNumber(s), s)
+	}
+}
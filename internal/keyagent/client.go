@@ -0,0 +1,167 @@
+package keyagent
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/localstorage"
+	"github.com/adettelle/go-keeper/internal/unixsock"
+)
+
+// dialTimeout limits the requests to the agent, so that a hung agent does not block commands.
+const dialTimeout = 2 * time.Second
+
+// Client talks to the agent on the socket.
+type Client struct {
+	Socket string
+}
+
+func NewClient(socket string) *Client {
+	return &Client{
+		Socket: socket,
+	}
+}
+
+func (c *Client) call(req request) (response, error) {
+	conn, err := unixsock.Dial(c.Socket, dialTimeout)
+	if err != nil {
+		return response{}, err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(dialTimeout)); err != nil {
+		return response{}, err
+	}
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return response{}, err
+	}
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return response{}, err
+	}
+	var resp response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return response{}, err
+	}
+	if resp.Error == ErrLocked.Error() {
+		return resp, ErrLocked
+	}
+	if resp.Error != "" {
+		return resp, errors.New(resp.Error)
+	}
+	return resp, nil
+}
+
+// Token returns the cached token of the profile or ErrLocked.
+func (c *Client) Token(profile string) (string, error) {
+	resp, err := c.call(request{Op: opGet, Profile: profile})
+	return resp.Token, err
+}
+
+// SetToken caches the token of the profile; an empty token removes it.
+func (c *Client) SetToken(profile, token string) error {
+	_, err := c.call(request{Op: opSet, Profile: profile, Token: token})
+	return err
+}
+
+// PrivateKey returns the cached unlocked sharing private key of the profile or ErrLocked.
+func (c *Client) PrivateKey(profile string) (string, error) {
+	resp, err := c.call(request{Op: opGetKey, Profile: profile})
+	return resp.Key, err
+}
+
+// SetPrivateKey caches the unlocked sharing private key of the profile; an empty key removes it.
+func (c *Client) SetPrivateKey(profile, key string) error {
+	_, err := c.call(request{Op: opSetKey, Profile: profile, Key: key})
+	return err
+}
+
+// Lock makes the agent forget all the tokens and keys.
+func (c *Client) Lock() error {
+	_, err := c.call(request{Op: opLock})
+	return err
+}
+
+// Status returns the state of the agent.
+func (c *Client) Status() (*Status, error) {
+	resp, err := c.call(request{Op: opStatus})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Status, nil
+}
+
+// Stop locks the agent and makes it exit.
+func (c *Client) Stop() error {
+	_, err := c.call(request{Op: opStop})
+	return err
+}
+
+// Store is a key store asking the agent first. On a miss the token is read from the backend key store
+// (which may prompt for the keyring password) and cached in the agent. If the agent is not running,
+// the backend is used alone.
+type Store struct {
+	Agent   *Client
+	Profile string
+	Backend localstorage.IKeyStorage
+}
+
+var _ localstorage.IKeyStorage = (*Store)(nil)
+
+func NewStore(agent *Client, profile string, backend localstorage.IKeyStorage) *Store {
+	return &Store{
+		Agent:   agent,
+		Profile: profile,
+		Backend: backend,
+	}
+}
+
+// Get returns the token from the agent or, if it is locked or not running, from the backend.
+func (s *Store) Get() (string, error) {
+	token, err := s.Agent.Token(s.Profile)
+	if err == nil {
+		return token, nil
+	}
+
+	token, err = s.Backend.Get()
+	if err != nil {
+		return "", err
+	}
+	_ = s.Agent.SetToken(s.Profile, token) // агент может быть не запущен
+	return token, nil
+}
+
+// Set stores the token in the backend and updates the agent.
+func (s *Store) Set(token string) error {
+	if err := s.Backend.Set(token); err != nil {
+		return err
+	}
+	_ = s.Agent.SetToken(s.Profile, token)
+	return nil
+}
+
+// KeyCache caches the unlocked sharing private key of the profile in the agent.
+// It does nothing if the agent is not running.
+type KeyCache struct {
+	Agent   *Client
+	Profile string
+}
+
+func NewKeyCache(agent *Client, profile string) *KeyCache {
+	return &KeyCache{
+		Agent:   agent,
+		Profile: profile,
+	}
+}
+
+// Get returns the cached key or an error if the agent is locked or not running.
+func (kc *KeyCache) Get() (string, error) {
+	return kc.Agent.PrivateKey(kc.Profile)
+}
+
+// Set caches the key if the agent is running.
+func (kc *KeyCache) Set(key string) {
+	_ = kc.Agent.SetPrivateKey(kc.Profile, key) // агент может быть не запущен
+}
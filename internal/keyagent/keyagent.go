@@ -0,0 +1,250 @@
+// Package keyagent implements the local unlock agent. The agent keeps the tokens read from
+// the unlocked keyring and the sharing private keys unlocked with the master password in memory
+// and hands them out over a Unix socket, so that client commands do not open the keyring or ask
+// for the master password every time. The socket lives in a private directory and only processes
+// of the same user are served, which is checked with the peer credentials of the socket; clients
+// check the agent the same way, see internal/unixsock. Everything is forgotten after an idle timeout.
+package keyagent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/unixsock"
+)
+
+// DefaultIdleTimeout is the time after the last request when the agent forgets the tokens.
+const DefaultIdleTimeout = 15 * time.Minute
+
+// ErrLocked is returned by Client.Token if the agent has no token for the profile.
+var ErrLocked = errors.New("agent is locked")
+
+// Operations of the agent protocol. Requests and responses are JSON objects, one per line.
+const (
+	opGet    = "get"
+	opSet    = "set"
+	opGetKey = "get-key"
+	opSetKey = "set-key"
+	opLock   = "lock"
+	opStatus = "status"
+	opStop   = "stop"
+)
+
+type request struct {
+	Op      string `json:"op"`
+	Profile string `json:"profile,omitempty"`
+	Token   string `json:"token,omitempty"`
+	Key     string `json:"key,omitempty"`
+}
+
+type response struct {
+	Token  string  `json:"token,omitempty"`
+	Key    string  `json:"key,omitempty"`
+	Status *Status `json:"status,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// Status is the state of the agent.
+type Status struct {
+	Profiles []string  `json:"profiles"`       // Profiles have tokens cached; none means the agent is locked.
+	Keys     []string  `json:"keys,omitempty"` // Keys are the profiles with the sharing private key unlocked.
+	LocksAt  time.Time `json:"locks_at"`       // LocksAt is when the agent locks if idle; zero if locked.
+}
+
+// Agent caches the tokens and the unlocked sharing private keys by profile name.
+type Agent struct {
+	idle time.Duration
+
+	mu      sync.Mutex
+	tokens  map[string]string
+	keys    map[string]string
+	timer   *time.Timer
+	locksAt time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// New creates a locked agent forgetting the tokens after the idle timeout.
+func New(idle time.Duration) *Agent {
+	if idle <= 0 {
+		idle = DefaultIdleTimeout
+	}
+	return &Agent{
+		idle:   idle,
+		tokens: map[string]string{},
+		keys:   map[string]string{},
+		stop:   make(chan struct{}),
+	}
+}
+
+// touch postpones the automatic lock. It must be called with mu held.
+func (a *Agent) touch() {
+	if a.timer != nil {
+		a.timer.Stop()
+	}
+	a.locksAt = time.Now().Add(a.idle)
+	a.timer = time.AfterFunc(a.idle, a.expire)
+}
+
+// expire locks the agent once the idle deadline has passed. A timer stopped
+// too late by touch may still fire, so the deadline is re-checked under mu.
+func (a *Agent) expire() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.locksAt.IsZero() || time.Now().Before(a.locksAt) {
+		return
+	}
+	log.Println("Agent is locked after idle timeout.")
+	a.lock()
+}
+
+// Lock forgets all the tokens and keys.
+func (a *Agent) Lock() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.lock()
+}
+
+// lock forgets all the tokens and keys. It must be called with mu held.
+func (a *Agent) lock() {
+	if a.timer != nil {
+		a.timer.Stop()
+		a.timer = nil
+	}
+	clear(a.tokens)
+	clear(a.keys)
+	a.locksAt = time.Time{}
+}
+
+func (a *Agent) handle(req request) response {
+	switch req.Op {
+	case opGet:
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		token, ok := a.tokens[req.Profile]
+		if !ok {
+			return response{Error: ErrLocked.Error()}
+		}
+		a.touch()
+		return response{Token: token}
+	case opSet:
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if req.Token == "" {
+			delete(a.tokens, req.Profile)
+		} else {
+			a.tokens[req.Profile] = req.Token
+		}
+		a.touch()
+		return response{}
+	case opGetKey:
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		key, ok := a.keys[req.Profile]
+		if !ok {
+			return response{Error: ErrLocked.Error()}
+		}
+		a.touch()
+		return response{Key: key}
+	case opSetKey:
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if req.Key == "" {
+			delete(a.keys, req.Profile)
+		} else {
+			a.keys[req.Profile] = req.Key
+		}
+		a.touch()
+		return response{}
+	case opLock:
+		a.Lock()
+		return response{}
+	case opStatus:
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		status := &Status{Profiles: []string{}}
+		for profile := range a.tokens {
+			status.Profiles = append(status.Profiles, profile)
+		}
+		for profile := range a.keys {
+			status.Keys = append(status.Keys, profile)
+		}
+		if len(a.tokens) > 0 || len(a.keys) > 0 {
+			status.LocksAt = a.locksAt
+		}
+		return response{Status: status}
+	case opStop:
+		a.Lock()
+		a.stopOnce.Do(func() { close(a.stop) })
+		return response{}
+	default:
+		return response{Error: fmt.Sprintf("unknown operation %q", req.Op)}
+	}
+}
+
+// Listen creates the Unix socket accessible only by the user, in a private directory.
+// A stale socket file is removed.
+func Listen(path string) (net.Listener, error) {
+	return unixsock.Listen(path)
+}
+
+// Serve serves the agent on the listener until the context is done or the agent is stopped.
+// Connections of other users are refused. The listener is closed on return.
+func (a *Agent) Serve(ctx context.Context, l net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-a.stop:
+			cancel()
+		}
+		l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				a.Lock()
+				return nil
+			}
+			return err
+		}
+		go a.serveConn(conn)
+	}
+}
+
+func (a *Agent) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	if err := unixsock.CheckPeer(conn); err != nil {
+		log.Println("agent connection is refused:", err)
+		return
+	}
+
+	scanner := bufio.NewScanner(conn)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req request
+		resp := response{}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp.Error = "malformed request"
+		} else {
+			resp = a.handle(req)
+		}
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package keyagent
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// start serves a new agent on a socket in a temporary directory and returns its client.
+func start(t *testing.T, idle time.Duration) *Client {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "agent", "agent.sock")
+	l, err := Listen(socket)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- New(idle).Serve(ctx, l) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+	return NewClient(socket)
+}
+
+func TestAgent(t *testing.T) {
+	client := start(t, time.Minute)
+
+	_, err := client.Token("default")
+	require.ErrorIs(t, err, ErrLocked)
+
+	require.NoError(t, client.SetToken("default", "Bearer abc"))
+	token, err := client.Token("default")
+	require.NoError(t, err)
+	require.Equal(t, "Bearer abc", token)
+	_, err = client.Token("work")
+	require.ErrorIs(t, err, ErrLocked)
+
+	status, err := client.Status()
+	require.NoError(t, err)
+	require.Equal(t, []string{"default"}, status.Profiles)
+	require.WithinDuration(t, time.Now().Add(time.Minute), status.LocksAt, 5*time.Second)
+
+	require.NoError(t, client.Lock())
+	_, err = client.Token("default")
+	require.ErrorIs(t, err, ErrLocked)
+	status, err = client.Status()
+	require.NoError(t, err)
+	require.Empty(t, status.Profiles)
+	require.True(t, status.LocksAt.IsZero())
+}
+
+func TestAgentPrivateKey(t *testing.T) {
+	client := start(t, time.Minute)
+	cache := NewKeyCache(client, "default")
+
+	_, err := cache.Get()
+	require.ErrorIs(t, err, ErrLocked)
+
+	cache.Set("unlocked")
+	key, err := cache.Get()
+	require.NoError(t, err)
+	require.Equal(t, "unlocked", key)
+
+	status, err := client.Status()
+	require.NoError(t, err)
+	require.Equal(t, []string{"default"}, status.Keys)
+	require.False(t, status.LocksAt.IsZero())
+
+	// ключ забывается вместе с токенами
+	require.NoError(t, client.Lock())
+	_, err = cache.Get()
+	require.ErrorIs(t, err, ErrLocked)
+}
+
+func TestAgentIdleTimeout(t *testing.T) {
+	client := start(t, 50*time.Millisecond)
+
+	require.NoError(t, client.SetToken("default", "Bearer abc"))
+	// status does not postpone the lock, unlike reading the token
+	require.Eventually(t, func() bool {
+		status, err := client.Status()
+		return err == nil && len(status.Profiles) == 0
+	}, 2*time.Second, 10*time.Millisecond)
+	_, err := client.Token("default")
+	require.ErrorIs(t, err, ErrLocked)
+}
+
+func TestAgentStaleIdleTimer(t *testing.T) {
+	a := New(time.Hour)
+	a.mu.Lock()
+	a.tokens["default"] = "Bearer abc"
+	a.touch()
+	a.mu.Unlock()
+
+	// a timer superseded by a later request fires before its deadline
+	a.expire()
+	a.mu.Lock()
+	require.Equal(t, "Bearer abc", a.tokens["default"])
+	a.mu.Unlock()
+
+	a.mu.Lock()
+	a.locksAt = time.Now().Add(-time.Second)
+	a.mu.Unlock()
+	a.expire()
+	a.mu.Lock()
+	require.Empty(t, a.tokens)
+	require.Nil(t, a.timer)
+	a.mu.Unlock()
+}
+
+func TestAgentStop(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "agent", "agent.sock")
+	l, err := Listen(socket)
+	require.NoError(t, err)
+
+	done := make(chan error)
+	go func() { done <- New(time.Minute).Serve(context.Background(), l) }()
+
+	require.NoError(t, NewClient(socket).Stop())
+	require.NoError(t, <-done)
+}
+
+type memStore struct {
+	token string
+	gets  int
+}
+
+func (m *memStore) Set(token string) error {
+	m.token = token
+	return nil
+}
+
+func (m *memStore) Get() (string, error) {
+	m.gets++
+	if m.token == "" {
+		return "", errors.New("no token")
+	}
+	return m.token, nil
+}
+
+func TestStore(t *testing.T) {
+	backend := &memStore{token: "Bearer abc"}
+	store := NewStore(start(t, time.Minute), "default", backend)
+
+	// the first read unlocks the agent, the next ones do not open the backend
+	for range 3 {
+		token, err := store.Get()
+		require.NoError(t, err)
+		require.Equal(t, "Bearer abc", token)
+	}
+	require.Equal(t, 1, backend.gets)
+
+	require.NoError(t, store.Set("Bearer new"))
+	token, err := store.Get()
+	require.NoError(t, err)
+	require.Equal(t, "Bearer new", token)
+	require.Equal(t, 1, backend.gets)
+}
+
+func TestStoreWithoutAgent(t *testing.T) {
+	backend := &memStore{token: "Bearer abc"}
+	store := NewStore(NewClient(filepath.Join(t.TempDir(), "none.sock")), "default", backend)
+
+	token, err := store.Get()
+	require.NoError(t, err)
+	require.Equal(t, "Bearer abc", token)
+	require.NoError(t, store.Set("Bearer new"))
+	require.Equal(t, "Bearer new", backend.token)
+}
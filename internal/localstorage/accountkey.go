@@ -0,0 +1,54 @@
+package localstorage
+
+import (
+	"errors"
+
+	"github.com/99designs/keyring"
+)
+
+// ErrNoAccountKey is returned when no account key is stored for the profile.
+var ErrNoAccountKey = errors.New("no account key is stored on this device")
+
+// AccountKeyStore keeps the account key of the sharing keys of a client profile in the keyring.
+// The account key never leaves the devices of the user: with the master password it opens the private key.
+type AccountKeyStore struct {
+	Config *keyring.Config
+	Key    string // Key is the keyring item key of the account key, one per client profile.
+}
+
+// NewAccountKeyStore creates an AccountKeyStore for the client profile.
+func NewAccountKeyStore(config *keyring.Config, profile string) *AccountKeyStore {
+	return &AccountKeyStore{
+		Config: config,
+		Key:    profile + ".account-key",
+	}
+}
+
+// Set stores the formatted account key in the keyring.
+func (as *AccountKeyStore) Set(accountKey string) error {
+	ring, err := keyring.Open(*as.Config)
+	if err != nil {
+		return err
+	}
+	return ring.Set(keyring.Item{
+		Key:  as.Key,
+		Data: []byte(accountKey),
+	})
+}
+
+// Get returns the formatted account key. It returns ErrNoAccountKey if none is stored.
+func (as *AccountKeyStore) Get() (string, error) {
+	ring, err := keyring.Open(*as.Config)
+	if err != nil {
+		return "", err
+	}
+
+	i, err := ring.Get(as.Key)
+	if errors.Is(err, keyring.ErrKeyNotFound) {
+		return "", ErrNoAccountKey
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(i.Data), nil
+}
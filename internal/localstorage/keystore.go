@@ -3,12 +3,17 @@
 package localstorage
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/99designs/keyring"
 )
 
 const (
 	service = "gokeeper" // service defines the name of the keyring service used for storing the token.
-	user    = "default"  // user defines the default keyring item key under which the token is stored.
+	// LegacyKey is the keyring item key of the token before client profiles. The default profile
+	// is named the same, so tokens stored by older clients keep working after the upgrade.
+	LegacyKey = "default"
 )
 
 // IKeyStorage defines an interface for managing JWT token storage.
@@ -20,11 +25,18 @@ type IKeyStorage interface {
 
 type KeyStore struct {
 	Config *keyring.Config
+	Key    string // Key is the keyring item key under which the token is stored, one per client profile.
 }
 
-func NewKeyStore(config *keyring.Config) *KeyStore {
+// NewKeyStore creates a KeyStore that keeps the token under the given item key.
+// An empty key means LegacyKey.
+func NewKeyStore(config *keyring.Config, key string) *KeyStore {
+	if key == "" {
+		key = LegacyKey
+	}
 	return &KeyStore{
 		Config: config,
+		Key:    key,
 	}
 }
 
@@ -42,7 +54,7 @@ func (ks *KeyStore) Set(jwtToken string) error {
 	}
 
 	err = ring.Set(keyring.Item{
-		Key:  user,
+		Key:  ks.Key,
 		Data: []byte(jwtToken),
 	})
 	if err != nil {
@@ -62,7 +74,10 @@ func (ks *KeyStore) Get() (string, error) {
 		return "", err
 	}
 
-	i, err := ring.Get(user)
+	i, err := ring.Get(ks.Key)
+	if errors.Is(err, keyring.ErrKeyNotFound) {
+		return "", fmt.Errorf("no token is stored for profile %q, log in first: %w", ks.Key, err)
+	}
 	if err != nil {
 		return "", err
 	}
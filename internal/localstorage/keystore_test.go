@@ -0,0 +1,58 @@
+package localstorage
+
+import (
+	"testing"
+
+	"github.com/99designs/keyring"
+	"github.com/adettelle/go-keeper/internal/client/config"
+	"github.com/stretchr/testify/require"
+)
+
+func fileKeyring(t *testing.T) *keyring.Config {
+	return &keyring.Config{
+		ServiceName:      service,
+		AllowedBackends:  []keyring.BackendType{keyring.FileBackend},
+		FileDir:          t.TempDir(),
+		FilePasswordFunc: keyring.FixedStringPrompt("secret"),
+	}
+}
+
+// Токен, сохранённый клиентом до появления профилей, читается профилем по умолчанию.
+func TestDefaultProfileReadsLegacyToken(t *testing.T) {
+	cfg := fileKeyring(t)
+
+	ring, err := keyring.Open(*cfg)
+	require.NoError(t, err)
+	require.NoError(t, ring.Set(keyring.Item{Key: LegacyKey, Data: []byte("old-token")}))
+
+	token, err := NewKeyStore(cfg, config.DefaultProfile).Get()
+	require.NoError(t, err)
+	require.Equal(t, "old-token", token)
+
+	_, err = NewKeyStore(cfg, "work").Get()
+	require.ErrorIs(t, err, keyring.ErrKeyNotFound)
+	require.ErrorContains(t, err, `profile "work"`)
+}
+
+// Ключ учётной записи хранится отдельно от токена того же профиля.
+func TestAccountKeyStore(t *testing.T) {
+	cfg := fileKeyring(t)
+
+	store := NewAccountKeyStore(cfg, config.DefaultProfile)
+	_, err := store.Get()
+	require.ErrorIs(t, err, ErrNoAccountKey)
+
+	require.NoError(t, NewKeyStore(cfg, config.DefaultProfile).Set("token"))
+	require.NoError(t, store.Set("GK2-ABCDEF"))
+
+	key, err := store.Get()
+	require.NoError(t, err)
+	require.Equal(t, "GK2-ABCDEF", key)
+
+	token, err := NewKeyStore(cfg, config.DefaultProfile).Get()
+	require.NoError(t, err)
+	require.Equal(t, "token", token)
+
+	_, err = NewAccountKeyStore(cfg, "work").Get()
+	require.ErrorIs(t, err, ErrNoAccountKey)
+}
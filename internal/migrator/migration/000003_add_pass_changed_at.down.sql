@@ -0,0 +1 @@
+alter table pass drop column changed_at;
@@ -0,0 +1 @@
+alter table pass add column changed_at timestamp not null default now();
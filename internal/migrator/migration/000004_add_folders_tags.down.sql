@@ -0,0 +1,11 @@
+alter table pass drop column folder;
+alter table pass drop column tags;
+alter table pass drop column favorite;
+
+alter table card drop column folder;
+alter table card drop column tags;
+alter table card drop column favorite;
+
+alter table bfile drop column folder;
+alter table bfile drop column tags;
+alter table bfile drop column favorite;
@@ -0,0 +1,11 @@
+alter table pass add column folder varchar(255) not null default '';
+alter table pass add column tags varchar(255)[] not null default '{}';
+alter table pass add column favorite boolean not null default false;
+
+alter table card add column folder varchar(255) not null default '';
+alter table card add column tags varchar(255)[] not null default '{}';
+alter table card add column favorite boolean not null default false;
+
+alter table bfile add column folder varchar(255) not null default '';
+alter table bfile add column tags varchar(255)[] not null default '{}';
+alter table bfile add column favorite boolean not null default false;
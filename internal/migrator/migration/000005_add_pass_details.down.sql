@@ -0,0 +1,5 @@
+drop table pass_field;
+
+drop table pass_url;
+
+alter table pass drop column username;
@@ -0,0 +1,19 @@
+alter table pass add column username varchar(1000) not null default '';
+
+create table pass_url
+    (id serial primary key,
+    pass_id integer not null,
+    url varchar(8000) not null,
+    match_rule varchar(16) not null default 'domain',
+    position integer not null,
+    foreign key (pass_id) references pass (id) on delete cascade);
+
+create table pass_field
+    (id serial primary key,
+    pass_id integer not null,
+    name varchar(255) not null,
+    field_type varchar(16) not null,
+    value varchar(8000) not null,
+    position integer not null,
+    foreign key (pass_id) references pass (id) on delete cascade,
+    unique (pass_id, name));
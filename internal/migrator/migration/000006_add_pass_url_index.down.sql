@@ -0,0 +1,7 @@
+drop index if exists pass_url_url_index;
+drop index if exists pass_url_host_index;
+drop index if exists pass_url_domain_index;
+
+alter table pass_url drop column url_index;
+alter table pass_url drop column host_index;
+alter table pass_url drop column domain_index;
@@ -0,0 +1,7 @@
+alter table pass_url add column domain_index varchar(64) not null default '';
+alter table pass_url add column host_index varchar(64) not null default '';
+alter table pass_url add column url_index varchar(64) not null default '';
+
+create index pass_url_domain_index on pass_url (domain_index);
+create index pass_url_host_index on pass_url (host_index);
+create index pass_url_url_index on pass_url (url_index);
@@ -0,0 +1,3 @@
+alter table card drop column pin;
+alter table card drop column address;
+alter table card drop column cardholder;
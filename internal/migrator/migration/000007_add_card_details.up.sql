@@ -0,0 +1,3 @@
+alter table card add column cardholder varchar(1000) not null default '';
+alter table card add column address varchar(4000) not null default '';
+alter table card add column pin varchar(255) not null default '';
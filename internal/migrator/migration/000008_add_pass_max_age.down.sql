@@ -0,0 +1 @@
+alter table pass drop column max_age_days;
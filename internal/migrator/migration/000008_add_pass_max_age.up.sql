@@ -0,0 +1 @@
+alter table pass add column max_age_days integer not null default 0;
@@ -0,0 +1,12 @@
+create table identity
+    (id serial primary key,
+    doc_type varchar(32) not null,
+    title varchar(255) not null,
+    description varchar(1000) not null default '',
+    fields text not null,
+    folder varchar(255) not null default '',
+    tags varchar(255)[] not null default '{}',
+    favorite boolean not null default false,
+    customer_id integer,
+    foreign key (customer_id) references customer (id),
+    unique (title, customer_id));
@@ -0,0 +1,15 @@
+create table ssh_key
+    (id serial primary key,
+    title varchar(255) not null,
+    description varchar(1000) not null default '',
+    key_type varchar(64) not null,
+    public_key text not null,
+    fingerprint varchar(128) not null,
+    private_key text not null,
+    confirm boolean not null default false,
+    folder varchar(255) not null default '',
+    tags varchar(255)[] not null default '{}',
+    favorite boolean not null default false,
+    customer_id integer,
+    foreign key (customer_id) references customer (id),
+    unique (title, customer_id));
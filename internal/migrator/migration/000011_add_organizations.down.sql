@@ -0,0 +1,3 @@
+drop table collection;
+drop table org_member;
+drop table organization;
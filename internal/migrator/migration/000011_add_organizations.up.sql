@@ -0,0 +1,22 @@
+create table organization
+    (id serial primary key,
+    name varchar(64) not null unique);
+
+create table org_member
+    (id serial primary key,
+    org_id integer not null,
+    customer_id integer not null,
+    role varchar(16) not null,
+    foreign key (org_id) references organization (id) on delete cascade,
+    foreign key (customer_id) references customer (id),
+    unique (org_id, customer_id));
+
+-- the items of a collection belong to its vault customer, which can not log in
+create table collection
+    (id serial primary key,
+    org_id integer not null,
+    name varchar(64) not null,
+    vault_customer_id integer not null unique,
+    foreign key (org_id) references organization (id),
+    foreign key (vault_customer_id) references customer (id),
+    unique (org_id, name));
@@ -0,0 +1,3 @@
+drop table share_recipient;
+drop table item_share;
+drop table user_key;
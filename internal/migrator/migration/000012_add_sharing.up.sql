@@ -0,0 +1,26 @@
+-- the private key is sealed on the client with the vault key derived from the master password
+create table user_key
+    (customer_id integer primary key,
+    public_key varchar(64) not null,
+    private_key text not null,
+    foreign key (customer_id) references customer (id));
+
+-- the payload is encrypted on the client with the item key, which is wrapped for every recipient
+create table item_share
+    (id serial primary key,
+    owner_id integer not null,
+    kind varchar(16) not null,
+    title varchar(255) not null,
+    payload text not null,
+    updated_at timestamp not null default now(),
+    foreign key (owner_id) references customer (id),
+    unique (owner_id, kind, title));
+
+create table share_recipient
+    (id serial primary key,
+    share_id integer not null,
+    customer_id integer not null,
+    wrapped_key text not null,
+    foreign key (share_id) references item_share (id) on delete cascade,
+    foreign key (customer_id) references customer (id),
+    unique (share_id, customer_id));
@@ -0,0 +1 @@
+drop table secret_link;
@@ -0,0 +1,9 @@
+-- the payload is encrypted on the client, the key is only in the fragment of the link
+create table secret_link
+    (id varchar(32) primary key,
+    owner_id integer not null,
+    payload text not null,
+    views_left integer not null,
+    expires_at timestamp not null,
+    created_at timestamp not null default now(),
+    foreign key (owner_id) references customer (id));
@@ -0,0 +1,2 @@
+drop table emergency_event;
+drop table trusted_contact;
@@ -0,0 +1,27 @@
+-- the kit is the vault of the owner encrypted on the client, its key is wrapped for the contact
+create table trusted_contact
+    (id serial primary key,
+    owner_id integer not null,
+    contact_id integer not null,
+    wait_seconds integer not null,
+    payload text not null,
+    wrapped_key text not null,
+    status varchar(16) not null default 'idle',
+    requested_at timestamp,
+    updated_at timestamp not null default now(),
+    foreign key (owner_id) references customer (id),
+    foreign key (contact_id) references customer (id),
+    unique (owner_id, contact_id));
+
+-- every step of emergency access; notify_id is the party to notify, notified is set once it is shown
+create table emergency_event
+    (id serial primary key,
+    owner_id integer not null,
+    contact_id integer not null,
+    event varchar(16) not null,
+    notify_id integer not null,
+    notified boolean not null default false,
+    created_at timestamp not null default now(),
+    foreign key (owner_id) references customer (id),
+    foreign key (contact_id) references customer (id),
+    foreign key (notify_id) references customer (id));
@@ -0,0 +1 @@
+drop table note;
@@ -0,0 +1,11 @@
+create table note
+    (id serial primary key,
+    title varchar(255) not null,
+    description varchar(1000) not null default '',
+    body text not null,
+    folder varchar(255) not null default '',
+    tags varchar(255)[] not null default '{}',
+    favorite boolean not null default false,
+    customer_id integer,
+    foreign key (customer_id) references customer (id),
+    unique (title, customer_id));
@@ -0,0 +1,213 @@
+// Package passgen provides functionality for generating random passwords and diceware passphrases.
+// All randomness comes from crypto/rand. Generated values are reported together with their entropy.
+package passgen
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	_ "embed"
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+)
+
+const (
+	LowerChars     = "abcdefghijklmnopqrstuvwxyz"
+	UpperChars     = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	DigitChars     = "0123456789"
+	SymbolChars    = "!@#$%^&*()-_=+[]{};:,.<>/?~"
+	AmbiguousChars = "0Oo1lI|`'\";:,."
+
+	MinLength = 4   // MinLength is the minimal length of a generated password.
+	MaxLength = 256 // MaxLength is the maximal length of a generated password.
+	MaxWords  = 64  // MaxWords is the maximal number of words in a passphrase.
+)
+
+// wordList is the EFF large diceware word list (7776 words),
+// https://www.eff.org/deeplinks/2016/07/new-wordlists-random-passphrases,
+// distributed under CC BY 3.0 US.
+//
+//go:embed eff_large_wordlist.txt
+var wordList []byte
+
+// words holds the parsed word list.
+var words = parseWordList(wordList)
+
+func parseWordList(data []byte) []string {
+	res := make([]string, 0, 7776)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		// each line is "<dice rolls>\t<word>"
+		_, word, ok := strings.Cut(scanner.Text(), "\t")
+		if ok && word != "" {
+			res = append(res, word)
+		}
+	}
+	return res
+}
+
+// Policy describes which characters a generated password consists of.
+// Every enabled character class is present in the password at least once.
+type Policy struct {
+	Length           int
+	Lower            bool
+	Upper            bool
+	Digits           bool
+	Symbols          bool
+	ExcludeAmbiguous bool // ExcludeAmbiguous removes characters that are easy to confuse, like 0/O and 1/l.
+}
+
+// DefaultPolicy is a 20 characters password of all character classes.
+var DefaultPolicy = Policy{Length: 20, Lower: true, Upper: true, Digits: true, Symbols: true}
+
+// classes returns the alphabets of the enabled character classes.
+func (p Policy) classes() []string {
+	var res []string
+	add := func(enabled bool, chars string) {
+		if !enabled {
+			return
+		}
+		if p.ExcludeAmbiguous {
+			chars = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(AmbiguousChars, r) {
+					return -1
+				}
+				return r
+			}, chars)
+		}
+		res = append(res, chars)
+	}
+	add(p.Lower, LowerChars)
+	add(p.Upper, UpperChars)
+	add(p.Digits, DigitChars)
+	add(p.Symbols, SymbolChars)
+	return res
+}
+
+// Validate checks that a password can be generated with the policy.
+func (p Policy) Validate() error {
+	if p.Length < MinLength || p.Length > MaxLength {
+		return errors.New("password length must be between 4 and 256")
+	}
+	classes := p.classes()
+	if len(classes) == 0 {
+		return errors.New("at least one character class must be enabled")
+	}
+	if len(classes) > p.Length {
+		return errors.New("password is too short for the enabled character classes")
+	}
+	return nil
+}
+
+// Entropy returns the entropy of a password generated with the policy in bits.
+// Requiring every class to be present reduces it slightly, so the value is an upper bound.
+func (p Policy) Entropy() float64 {
+	alphabet := strings.Join(p.classes(), "")
+	if alphabet == "" {
+		return 0
+	}
+	return float64(p.Length) * math.Log2(float64(len(alphabet)))
+}
+
+// Generate returns a random password built according to the policy.
+func Generate(p Policy) (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+
+	classes := p.classes()
+	alphabet := strings.Join(classes, "")
+
+	res := make([]byte, 0, p.Length)
+	// one character of every class, so that each of them is present
+	for _, class := range classes {
+		c, err := randomChar(class)
+		if err != nil {
+			return "", err
+		}
+		res = append(res, c)
+	}
+	for len(res) < p.Length {
+		c, err := randomChar(alphabet)
+		if err != nil {
+			return "", err
+		}
+		res = append(res, c)
+	}
+
+	// shuffle so that the guaranteed characters are not always in front (Fisher-Yates)
+	for i := len(res) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return "", err
+		}
+		res[i], res[j] = res[j], res[i]
+	}
+	return string(res), nil
+}
+
+// PassphrasePolicy describes a diceware passphrase.
+type PassphrasePolicy struct {
+	Words      int
+	Separator  string
+	Capitalize bool // Capitalize makes the first letter of every word upper case.
+}
+
+// Validate checks that a passphrase can be generated with the policy.
+func (p PassphrasePolicy) Validate() error {
+	if p.Words < 1 || p.Words > MaxWords {
+		return errors.New("number of words must be between 1 and 64")
+	}
+	return nil
+}
+
+// Entropy returns the entropy of a passphrase generated with the policy in bits.
+func (p PassphrasePolicy) Entropy() float64 {
+	return float64(p.Words) * math.Log2(float64(len(words)))
+}
+
+// GeneratePassphrase returns a passphrase of random words from the EFF large word list.
+func GeneratePassphrase(p PassphrasePolicy) (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+
+	res := make([]string, 0, p.Words)
+	for range p.Words {
+		i, err := randomInt(len(words))
+		if err != nil {
+			return "", err
+		}
+		word := words[i]
+		if p.Capitalize {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		res = append(res, word)
+	}
+	return strings.Join(res, p.Separator), nil
+}
+
+// randomChar returns a uniformly chosen character of chars.
+func randomChar(chars string) (byte, error) {
+	i, err := randomInt(len(chars))
+	if err != nil {
+		return 0, err
+	}
+	return chars[i], nil
+}
+
+// randomInt returns a uniform random number in [0, n).
+func randomInt(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
+
+// Words returns the diceware word list. The returned slice must not be modified.
+func Words() []string {
+	return words
+}
@@ -0,0 +1,67 @@
+package passgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWordList(t *testing.T) {
+	require.Len(t, words, 7776)
+	require.Equal(t, "abacus", words[0])
+	require.Equal(t, "zoom", words[len(words)-1])
+}
+
+func TestGenerate(t *testing.T) {
+	pwd, err := Generate(DefaultPolicy)
+	require.NoError(t, err)
+	require.Len(t, pwd, DefaultPolicy.Length)
+
+	require.True(t, strings.ContainsAny(pwd, LowerChars))
+	require.True(t, strings.ContainsAny(pwd, UpperChars))
+	require.True(t, strings.ContainsAny(pwd, DigitChars))
+	require.True(t, strings.ContainsAny(pwd, SymbolChars))
+}
+
+func TestGenerateExcludeAmbiguous(t *testing.T) {
+	p := Policy{Length: 200, Lower: true, Upper: true, Digits: true, Symbols: true, ExcludeAmbiguous: true}
+
+	pwd, err := Generate(p)
+	require.NoError(t, err)
+	require.False(t, strings.ContainsAny(pwd, AmbiguousChars))
+}
+
+func TestGenerateDigitsOnly(t *testing.T) {
+	pwd, err := Generate(Policy{Length: 6, Digits: true})
+	require.NoError(t, err)
+	require.Len(t, pwd, 6)
+	require.Empty(t, strings.Trim(pwd, DigitChars))
+}
+
+func TestGenerateInvalidPolicy(t *testing.T) {
+	_, err := Generate(Policy{Length: 20})
+	require.Error(t, err)
+
+	_, err = Generate(Policy{Length: 2, Lower: true})
+	require.Error(t, err)
+}
+
+func TestEntropy(t *testing.T) {
+	require.InDelta(t, 8*3.3219, Policy{Length: 8, Digits: true}.Entropy(), 0.001)
+	require.InDelta(t, 77.549, PassphrasePolicy{Words: 6}.Entropy(), 0.001)
+}
+
+func TestGeneratePassphrase(t *testing.T) {
+	phrase, err := GeneratePassphrase(PassphrasePolicy{Words: 5, Separator: "-", Capitalize: true})
+	require.NoError(t, err)
+
+	parts := strings.Split(phrase, "-")
+	require.Len(t, parts, 5)
+	for _, part := range parts {
+		require.Equal(t, strings.ToUpper(part[:1]), part[:1])
+	}
+
+	_, err = GeneratePassphrase(PassphrasePolicy{Words: 0})
+	require.Error(t, err)
+}
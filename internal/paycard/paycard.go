@@ -0,0 +1,202 @@
+// Package paycard validates payment card data: numbers by length and the Luhn checksum,
+// brands by IIN (issuer identification number) ranges and MM/YY expiry dates.
+package paycard
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Brand is a payment card network.
+type Brand string
+
+const (
+	BrandUnknown    Brand = ""
+	BrandVisa       Brand = "visa"
+	BrandMastercard Brand = "mastercard"
+	BrandAmex       Brand = "amex"
+	BrandDiscover   Brand = "discover"
+	BrandJCB        Brand = "jcb"
+	BrandDiners     Brand = "diners"
+	BrandUnionPay   Brand = "unionpay"
+	BrandMaestro    Brand = "maestro"
+	BrandMir        Brand = "mir"
+)
+
+const (
+	MinLength = 12 // MinLength is the minimal length of a card number of an unknown brand.
+	MaxLength = 19 // MaxLength is the maximal length of a card number (ISO/IEC 7812).
+)
+
+// iinRange is a range of card number prefixes [from, to] of the given number of digits.
+type iinRange struct {
+	digits   int
+	from, to int
+	brand    Brand
+	lengths  []int
+}
+
+// iinRanges are checked in order, so narrower ranges go before wider ones.
+var iinRanges = []iinRange{
+	{digits: 2, from: 34, to: 34, brand: BrandAmex, lengths: []int{15}},
+	{digits: 2, from: 37, to: 37, brand: BrandAmex, lengths: []int{15}},
+	{digits: 4, from: 2200, to: 2204, brand: BrandMir, lengths: []int{16, 17, 18, 19}},
+	{digits: 4, from: 2221, to: 2720, brand: BrandMastercard, lengths: []int{16}},
+	{digits: 2, from: 51, to: 55, brand: BrandMastercard, lengths: []int{16}},
+	{digits: 4, from: 3528, to: 3589, brand: BrandJCB, lengths: []int{16, 17, 18, 19}},
+	{digits: 3, from: 300, to: 305, brand: BrandDiners, lengths: []int{14, 15, 16, 17, 18, 19}},
+	{digits: 2, from: 36, to: 36, brand: BrandDiners, lengths: []int{14, 15, 16, 17, 18, 19}},
+	{digits: 2, from: 38, to: 39, brand: BrandDiners, lengths: []int{14, 15, 16, 17, 18, 19}},
+	{digits: 4, from: 6011, to: 6011, brand: BrandDiscover, lengths: []int{16, 17, 18, 19}},
+	{digits: 6, from: 622126, to: 622925, brand: BrandDiscover, lengths: []int{16, 17, 18, 19}},
+	{digits: 3, from: 644, to: 649, brand: BrandDiscover, lengths: []int{16, 17, 18, 19}},
+	{digits: 2, from: 65, to: 65, brand: BrandDiscover, lengths: []int{16, 17, 18, 19}},
+	{digits: 2, from: 62, to: 62, brand: BrandUnionPay, lengths: []int{16, 17, 18, 19}},
+	{digits: 1, from: 4, to: 4, brand: BrandVisa, lengths: []int{13, 16, 19}},
+	{digits: 2, from: 50, to: 50, brand: BrandMaestro, lengths: []int{12, 13, 14, 15, 16, 17, 18, 19}},
+	{digits: 2, from: 56, to: 69, brand: BrandMaestro, lengths: []int{12, 13, 14, 15, 16, 17, 18, 19}},
+}
+
+// Normalize removes spaces and dashes people put between digit groups.
+func Normalize(num string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(num))
+}
+
+func digitsOnly(num string) bool {
+	for _, r := range num {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return num != ""
+}
+
+// Detect returns the brand of the normalized card number by its IIN, BrandUnknown if it is not recognized.
+func Detect(num string) Brand {
+	if r, ok := lookup(num); ok {
+		return r.brand
+	}
+	return BrandUnknown
+}
+
+func lookup(num string) (iinRange, bool) {
+	if !digitsOnly(num) {
+		return iinRange{}, false
+	}
+	for _, r := range iinRanges {
+		if len(num) < r.digits {
+			continue
+		}
+		prefix, _ := strconv.Atoi(num[:r.digits])
+		if prefix >= r.from && prefix <= r.to {
+			return r, true
+		}
+	}
+	return iinRange{}, false
+}
+
+// Validate checks the normalized card number: digits only, a length allowed for its brand
+// (MinLength to MaxLength for unknown brands) and the Luhn checksum.
+func Validate(num string) error {
+	if !digitsOnly(num) {
+		return errors.New("card number must consist of digits")
+	}
+	if r, ok := lookup(num); ok {
+		if !slices.Contains(r.lengths, len(num)) {
+			return fmt.Errorf("%s card number must be %s digits long", r.brand, joinLengths(r.lengths))
+		}
+	} else if len(num) < MinLength || len(num) > MaxLength {
+		return fmt.Errorf("card number must be %d to %d digits long", MinLength, MaxLength)
+	}
+	if !Luhn(num) {
+		return errors.New("card number checksum is invalid")
+	}
+	return nil
+}
+
+func joinLengths(lengths []int) string {
+	if len(lengths) == 1 {
+		return strconv.Itoa(lengths[0])
+	}
+	return fmt.Sprintf("%d to %d", lengths[0], lengths[len(lengths)-1])
+}
+
+// Luhn reports whether the digits pass the Luhn (mod 10) checksum.
+func Luhn(num string) bool {
+	sum := 0
+	double := false
+	for i := len(num) - 1; i >= 0; i-- {
+		d := int(num[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// CVCLength returns the length of the security code of the brand: 4 digits for Amex, 3 for the others.
+func CVCLength(brand Brand) int {
+	if brand == BrandAmex {
+		return 4
+	}
+	return 3
+}
+
+// Mask hides all but the last four digits of the card number, e.g. **** 4242.
+func Mask(num string) string {
+	if len(num) < 4 {
+		return "****"
+	}
+	return "**** " + num[len(num)-4:]
+}
+
+// ParseExpiry parses a card expiry date given as MMYY or MM/YY.
+// A card is valid through the end of the month, so the first day of the next month is returned.
+func ParseExpiry(s string) (time.Time, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "/", "")
+	if len(s) != 4 {
+		return time.Time{}, fmt.Errorf("invalid expiry date %q, expected MMYY or MM/YY", s)
+	}
+	month, err := strconv.Atoi(s[:2])
+	if err != nil || month < 1 || month > 12 {
+		return time.Time{}, fmt.Errorf("invalid expiry month in %q", s)
+	}
+	year, err := strconv.Atoi(s[2:])
+	if err != nil || year < 0 {
+		return time.Time{}, fmt.Errorf("invalid expiry year in %q", s)
+	}
+	return time.Date(2000+year, time.Month(month)+1, 1, 0, 0, 0, 0, time.UTC), nil
+}
+
+// ValidateExpiry parses the expiry date and rejects the dates that passed by now.
+// The canonical MM/YY form of the date is returned.
+func ValidateExpiry(s string, now time.Time) (string, error) {
+	expiresAt, err := ParseExpiry(s)
+	if err != nil {
+		return "", err
+	}
+	if !now.Before(expiresAt) {
+		return "", fmt.Errorf("card expired in %s", FormatExpiry(expiresAt))
+	}
+	return FormatExpiry(expiresAt), nil
+}
+
+// FormatExpiry formats the time returned by ParseExpiry as MM/YY.
+func FormatExpiry(expiresAt time.Time) string {
+	last := expiresAt.AddDate(0, 0, -1)
+	return fmt.Sprintf("%02d/%02d", int(last.Month()), last.Year()%100)
+}
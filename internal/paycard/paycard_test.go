@@ -0,0 +1,82 @@
+package paycard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		num  string
+		want Brand
+	}{
+		{num: "4242424242424242", want: BrandVisa},
+		{num: "5555555555554444", want: BrandMastercard},
+		{num: "2223003122003222", want: BrandMastercard},
+		{num: "378282246310005", want: BrandAmex},
+		{num: "6011111111111117", want: BrandDiscover},
+		{num: "3530111333300000", want: BrandJCB},
+		{num: "36227206271667", want: BrandDiners},
+		{num: "6200000000000005", want: BrandUnionPay},
+		{num: "2200000000000004", want: BrandMir},
+		{num: "6759649826438453", want: BrandMaestro},
+		{num: "9999999999999995", want: BrandUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.num, func(t *testing.T) {
+			require.Equal(t, tt.want, Detect(tt.num))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, num := range []string{
+		"4242424242424242",    // visa, 16
+		"378282246310005",     // amex, 15
+		"4222222222222",       // visa, 13
+		"6250941006528599",    // unionpay, 16
+		"4000000000000000006", // visa, 19
+	} {
+		require.NoError(t, Validate(num), num)
+	}
+
+	for _, num := range []string{
+		"",
+		"4242 4242 4242 4242", // not normalized
+		"4242424242424241",    // checksum
+		"37828224631000",      // amex must be 15 digits
+		"42424242424",         // too short
+	} {
+		require.Error(t, Validate(num), num)
+	}
+}
+
+func TestNormalizeAndMask(t *testing.T) {
+	require.Equal(t, "4242424242424242", Normalize(" 4242 4242-4242 4242 "))
+	require.Equal(t, "**** 4242", Mask("4242424242424242"))
+	require.Equal(t, "****", Mask("42"))
+}
+
+func TestParseExpiry(t *testing.T) {
+	exp, err := ParseExpiry("12/29")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC), exp)
+
+	for _, s := range []string{"", "1329", "0029", "12-29", "12295"} {
+		_, err := ParseExpiry(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestValidateExpiry(t *testing.T) {
+	now := time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+	exp, err := ValidateExpiry("0325", now)
+	require.NoError(t, err)
+	require.Equal(t, "03/25", exp)
+
+	_, err = ValidateExpiry("02/25", now)
+	require.Error(t, err)
+}
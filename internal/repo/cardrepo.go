@@ -35,14 +35,25 @@ func NewCardRepo(db *sql.DB) *CardRepo {
 	}
 }
 
-// AddCard adds a new card to the database for an authenticated user.
-func (cr *CardRepo) AddCard(
-	ctx context.Context, cardNum, expire, cvc, title, description string, login string) error {
+// Card is a card to add; all the values but the title and description are encrypted.
+type Card struct {
+	Num         string
+	Expire      string
+	Cvc         string
+	Holder      string
+	Address     string
+	Pin         string
+	Title       string
+	Description string
+}
 
-	sqlSt := `insert into card (num, expires_at, cvc, title, description, customer_id) 
-		values ($1, $2, $3, $4, $5, (select id from customer where login = $6));`
+// AddCard adds a new card to the database for an authenticated user.
+func (cr *CardRepo) AddCard(ctx context.Context, card Card, login string) error {
+	sqlSt := `insert into card (num, expires_at, cvc, cardholder, address, pin, title, description, customer_id) 
+		values ($1, $2, $3, $4, $5, $6, $7, $8, (select id from customer where login = $9));`
 
-	_, err := cr.DB.ExecContext(ctx, sqlSt, cardNum, expire, cvc, title, description, login)
+	_, err := cr.DB.ExecContext(ctx, sqlSt, card.Num, card.Expire, card.Cvc, card.Holder, card.Address,
+		card.Pin, card.Title, card.Description, login)
 	if err != nil {
 		log.Println("error in adding card:", err)
 		return err
@@ -73,42 +84,51 @@ func (cr *CardRepo) AddCard(
 	}
 */
 type CardToGet struct {
+	ID          int
 	Num         string
 	Title       string
 	Description string
+	ItemMeta
 }
 
-// GetAllCards retrieves all cards info (number, title and description) associated with a user login.
-// Only the last four digits of the card number are shown.
-func (cr *CardRepo) GetAllCards(ctx context.Context, login string) ([]CardToGet, error) {
+// GetAllCards retrieves a page of cards info (encrypted number, title, description, folder, tags
+// and favorite mark) associated with a user login. Favorites go first. The returned cursor is not nil if there are more cards.
+func (cr *CardRepo) GetAllCards(ctx context.Context, login string, filter ItemFilter,
+	page Page) ([]CardToGet, *ItemCursor, error) {
+
 	cards := make([]CardToGet, 0)
 
-	sqlSt := `select num, title, description from card  
+	where, args := filter.where("card", []any{login})
+	after, order, args := page.clauses("card", false, args)
+	sqlSt := `select card.id, num, title, description, folder, tags, favorite from card  
 		inner join customer c on c.id = card.customer_id
-		where c.login = $1
-		order by title;`
+		where c.login = $1` + where + after + order + `;`
 
-	rows, err := cr.DB.QueryContext(ctx, sqlSt, login)
+	rows, err := cr.DB.QueryContext(ctx, sqlSt, args...)
 	if err != nil || rows.Err() != nil {
 		log.Println("error in getting cards:", err)
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
 	// пробегаем по всем записям
 	for rows.Next() {
 		var card CardToGet
-		err := rows.Scan(&card.Num, &card.Title, &card.Description)
+		err := rows.Scan(&card.ID, &card.Num, &card.Title, &card.Description,
+			&card.Folder, tagsScanner(&card.Tags), &card.Favorite)
 		if err != nil {
 			log.Println("error: ", err)
-			return nil, err
+			return nil, nil, err
 		}
-		modifiedNum := "************" + card.Num[12:]
-		card.Num = modifiedNum
 		cards = append(cards, card)
 	}
 
-	return cards, nil
+	next := page.next(len(cards), func() ItemCursor {
+		cards = cards[:page.Limit]
+		last := cards[len(cards)-1]
+		return ItemCursor{Favorite: last.Favorite, Key: page.cursorKey(last.ID, last.Title)}
+	})
+	return cards, next, nil
 }
 
 // DeleteCardByTitle deletes a card based on its title and the user's login.
@@ -116,11 +136,24 @@ func (cr *CardRepo) DeleteCardByTitle(ctx context.Context, cardTitle, login stri
 	sqlSt := `delete from card 
 		where title = $1 and customer_id = (select id from customer c where c.login = $2);`
 
-	_, err := cr.DB.ExecContext(ctx, sqlSt, cardTitle, login)
+	tx, err := cr.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	_, err = tx.ExecContext(ctx, sqlSt, cardTitle, login)
 	if err != nil {
 		log.Println("error in deleting card:", err)
 		return err
 	}
+	if err := deleteItemShare(ctx, tx, login, KindCard, cardTitle); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Println("error in deleting card:", err)
+		return err
+	}
 
 	log.Println("Card is deleted.")
 	return nil
@@ -130,6 +163,9 @@ type CardGetByTitle struct {
 	Num         string
 	Expire      string
 	Cvc         string
+	Holder      string
+	Address     string
+	Pin         string
 	Title       string
 	Description string
 }
@@ -137,7 +173,7 @@ type CardGetByTitle struct {
 func (cr *CardRepo) GetCardByTitle(ctx context.Context,
 	cardTitle, login string) (*CardGetByTitle, error) {
 
-	sqlSt := `select num, expires_at, cvc, title, description from card
+	sqlSt := `select num, expires_at, cvc, cardholder, address, pin, title, description from card
 		inner join customer c on c.id = card.customer_id 
 		where card.title = $1 and c.login = $2;`
 
@@ -145,7 +181,8 @@ func (cr *CardRepo) GetCardByTitle(ctx context.Context,
 
 	var card CardGetByTitle
 
-	err := row.Scan(&card.Num, &card.Expire, &card.Cvc, &card.Title, &card.Description)
+	err := row.Scan(&card.Num, &card.Expire, &card.Cvc, &card.Holder, &card.Address, &card.Pin,
+		&card.Title, &card.Description)
 	if err != nil {
 		log.Println("error in scan: ", err)
 		if err == sql.ErrNoRows { // считаем, что это не ошибка, просто не нашли пользователя
@@ -156,25 +193,22 @@ func (cr *CardRepo) GetCardByTitle(ctx context.Context,
 	return &card, nil
 }
 
-// UpdateCard updates card values (num, expires_at, cvc, description) by card title.
+// CardUpdate holds the card values to change; nil fields remain unchanged.
+type CardUpdate struct {
+	Num         *string `db:"num" goqu:"omitnil"`
+	Expire      *string `db:"expires_at" goqu:"omitnil"`
+	Cvc         *string `db:"cvc" goqu:"omitnil"`
+	Holder      *string `db:"cardholder" goqu:"omitnil"`
+	Address     *string `db:"address" goqu:"omitnil"`
+	Pin         *string `db:"pin" goqu:"omitnil"`
+	Description *string `db:"description" goqu:"omitnil"`
+}
+
+// UpdateCard updates card values (num, expires_at, cvc, cardholder, address, pin, description) by card title.
 // если в json не передать поле, то оно не измениться
 // если передать пустую строку "" - то поле станет пустым
-func (pr *CardRepo) UpdateCard(ctx context.Context,
-	title string, num *string, expiresAt *string, cvc *string, description *string, userID int) error {
-
-	type card struct {
-		Num         *string `db:"num" goqu:"omitnil"`         // omitempty
-		Expire      *string `db:"expires_at" goqu:"omitnil"`  //omitempty
-		Cvc         *string `db:"cvc" goqu:"omitnil"`         //omitempty
-		Description *string `db:"description" goqu:"omitnil"` //omitempty
-	}
-
-	sqlSt, args, _ := goqu.Update("card").Set(card{
-		Num:         num,
-		Expire:      expiresAt,
-		Cvc:         cvc,
-		Description: description,
-	}).Where(goqu.C("title").Eq(title)).Where(goqu.C("customer_id").Eq(userID)).ToSQL()
+func (pr *CardRepo) UpdateCard(ctx context.Context, title string, upd CardUpdate, userID int) error {
+	sqlSt, args, _ := goqu.Update("card").Set(upd).Where(goqu.C("title").Eq(title)).Where(goqu.C("customer_id").Eq(userID)).ToSQL()
 
 	_, err := pr.DB.ExecContext(ctx, sqlSt, args...)
 	if err != nil {
@@ -0,0 +1,346 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/emergency"
+)
+
+// EmergencyRepo keeps trusted contacts, their emergency kits and the record of every step of emergency access.
+// Each step is recorded as an event, which is notified to the other party once.
+type EmergencyRepo struct {
+	DB *sql.DB
+}
+
+func NewEmergencyRepo(db *sql.DB) *EmergencyRepo {
+	return &EmergencyRepo{
+		DB: db,
+	}
+}
+
+// TrustedContact is a contact designated by the owner with the state of its access.
+type TrustedContact struct {
+	Owner       string
+	Contact     string
+	Wait        time.Duration
+	Status      emergency.Status
+	RequestedAt *time.Time
+	UpdatedAt   time.Time
+}
+
+// EmergencyKit is the vault of the owner encrypted with the emergency key, and the key wrapped for the contact.
+type EmergencyKit struct {
+	Payload    string
+	WrappedKey string
+}
+
+// EmergencyEvent is a recorded step of emergency access.
+type EmergencyEvent struct {
+	Owner     string
+	Contact   string
+	Event     emergency.Event
+	CreatedAt time.Time
+}
+
+// addEvent records the step in the transaction and notifies the other party.
+func addEvent(ctx context.Context, tx *sql.Tx, owner string, contact string, event emergency.Event) error {
+	notify := contact
+	if event.NotifiesOwner() {
+		notify = owner
+	}
+
+	sqlSt := `insert into emergency_event (owner_id, contact_id, event, notify_id)
+		values ((select id from customer where login = $1), (select id from customer where login = $2), $3,
+		(select id from customer where login = $4));`
+
+	_, err := tx.ExecContext(ctx, sqlSt, owner, contact, event, notify)
+	if err != nil {
+		log.Println("error in adding emergency event:", err)
+		return err
+	}
+	log.Printf("Emergency access of %s to %s: %s.", contact, owner, event)
+	return nil
+}
+
+// transition runs the update of the trusted contact and records the event if the update has found the contact.
+func (er *EmergencyRepo) transition(ctx context.Context, owner string, contact string, event emergency.Event,
+	sqlSt string, args ...any) (bool, error) {
+
+	tx, err := er.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	res, err := tx.ExecContext(ctx, sqlSt, append([]any{owner, contact}, args...)...)
+	if err != nil {
+		log.Println("error in updating trusted contact:", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return false, nil
+	}
+
+	if err := addEvent(ctx, tx, owner, contact, event); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Println("error in updating trusted contact:", err)
+		return false, err
+	}
+	return true, nil
+}
+
+const trustedContactColumns = `select o.login, c.login, t.wait_seconds, t.status, t.requested_at, t.updated_at
+	from trusted_contact t
+	inner join customer o on o.id = t.owner_id
+	inner join customer c on c.id = t.contact_id`
+
+func scanTrustedContacts(rows *sql.Rows) ([]TrustedContact, error) {
+	res := make([]TrustedContact, 0)
+	for rows.Next() {
+		var tc TrustedContact
+		var waitSeconds int
+		if err := rows.Scan(&tc.Owner, &tc.Contact, &waitSeconds, &tc.Status, &tc.RequestedAt, &tc.UpdatedAt); err != nil {
+			log.Println("error:", err)
+			return nil, err
+		}
+		tc.Wait = time.Duration(waitSeconds) * time.Second
+		res = append(res, tc)
+	}
+	return res, rows.Err()
+}
+
+// GetContact returns the trusted contact of the owner, nil if the owner has not designated the user.
+func (er *EmergencyRepo) GetContact(ctx context.Context, owner string, contact string) (*TrustedContact, error) {
+	rows, err := er.DB.QueryContext(ctx, trustedContactColumns+` where o.login = $1 and c.login = $2;`, owner, contact)
+	if err != nil {
+		log.Println("error in getting trusted contact:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	contacts, err := scanTrustedContacts(rows)
+	if err != nil || len(contacts) == 0 {
+		return nil, err
+	}
+	return &contacts[0], nil
+}
+
+// GetContacts returns the trusted contacts designated by the owner.
+func (er *EmergencyRepo) GetContacts(ctx context.Context, owner string) ([]TrustedContact, error) {
+	rows, err := er.DB.QueryContext(ctx, trustedContactColumns+` where o.login = $1 order by c.login;`, owner)
+	if err != nil {
+		log.Println("error in getting trusted contacts:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTrustedContacts(rows)
+}
+
+// GetGrantors returns the users who designated the contact.
+func (er *EmergencyRepo) GetGrantors(ctx context.Context, contact string) ([]TrustedContact, error) {
+	rows, err := er.DB.QueryContext(ctx, trustedContactColumns+` where c.login = $1 order by o.login;`, contact)
+	if err != nil {
+		log.Println("error in getting grantors:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTrustedContacts(rows)
+}
+
+// PutContact designates the contact or renews the kit and the waiting period of a designated one.
+// The state of a pending request is kept. It returns true if the contact is new.
+func (er *EmergencyRepo) PutContact(ctx context.Context, owner string, contact string, wait time.Duration,
+	kit EmergencyKit) (bool, error) {
+
+	tx, err := er.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	sqlSt := `update trusted_contact set wait_seconds = $3, payload = $4, wrapped_key = $5, updated_at = now()
+		where owner_id = (select id from customer where login = $1)
+		and contact_id = (select id from customer where login = $2);`
+	res, err := tx.ExecContext(ctx, sqlSt, owner, contact, int(wait.Seconds()), kit.Payload, kit.WrappedKey)
+	if err != nil {
+		log.Println("error in updating trusted contact:", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	event := emergency.EventUpdated
+	if n == 0 {
+		event = emergency.EventAdded
+		sqlSt = `insert into trusted_contact (owner_id, contact_id, wait_seconds, payload, wrapped_key)
+			values ((select id from customer where login = $1), (select id from customer where login = $2), $3, $4, $5);`
+		_, err = tx.ExecContext(ctx, sqlSt, owner, contact, int(wait.Seconds()), kit.Payload, kit.WrappedKey)
+		if err != nil {
+			log.Println("error in adding trusted contact:", err)
+			return false, err
+		}
+	}
+
+	if err := addEvent(ctx, tx, owner, contact, event); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Println("error in adding trusted contact:", err)
+		return false, err
+	}
+	return event == emergency.EventAdded, nil
+}
+
+// DeleteContact removes the trusted contact with its kit. The recorded events are kept.
+// It returns false if there is no such contact.
+func (er *EmergencyRepo) DeleteContact(ctx context.Context, owner string, contact string) (bool, error) {
+	sqlSt := `delete from trusted_contact
+		where owner_id = (select id from customer where login = $1)
+		and contact_id = (select id from customer where login = $2);`
+	return er.transition(ctx, owner, contact, emergency.EventRemoved, sqlSt)
+}
+
+// RequestAccess starts the waiting period. It returns false if there is no such contact or access
+// is already requested or granted.
+func (er *EmergencyRepo) RequestAccess(ctx context.Context, owner string, contact string) (bool, error) {
+	sqlSt := `update trusted_contact set status = $3, requested_at = now()
+		where owner_id = (select id from customer where login = $1)
+		and contact_id = (select id from customer where login = $2)
+		and status = $4;`
+	return er.transition(ctx, owner, contact, emergency.EventRequested, sqlSt, emergency.Requested, emergency.Idle)
+}
+
+// RejectAccess rejects the request during its waiting period. It returns false if there is
+// no such contact, no request or the waiting period has passed.
+func (er *EmergencyRepo) RejectAccess(ctx context.Context, owner string, contact string) (bool, error) {
+	sqlSt := `update trusted_contact set status = $3, requested_at = null
+		where owner_id = (select id from customer where login = $1)
+		and contact_id = (select id from customer where login = $2)
+		and status = $4 and requested_at + wait_seconds * interval '1 second' > now();`
+	return er.transition(ctx, owner, contact, emergency.EventRejected, sqlSt, emergency.Idle, emergency.Requested)
+}
+
+// GrantDue grants the requests whose waiting period has passed, where the user is the owner or the contact,
+// and records each grant once, notifying the owner. It returns how many requests are granted.
+func (er *EmergencyRepo) GrantDue(ctx context.Context, login string) (int, error) {
+	sqlSt := `with g as (update trusted_contact set status = $2
+			where status = $3 and requested_at + wait_seconds * interval '1 second' <= now()
+			and (select id from customer where login = $1) in (owner_id, contact_id)
+			returning owner_id, contact_id)
+		insert into emergency_event (owner_id, contact_id, event, notify_id)
+		select owner_id, contact_id, $4, owner_id from g;`
+
+	res, err := er.DB.ExecContext(ctx, sqlSt, login, emergency.Granted, emergency.Requested, emergency.EventGranted)
+	if err != nil {
+		log.Println("error in granting emergency access:", err)
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 {
+		log.Printf("Emergency access granted by %s: %d.", login, n)
+	}
+	return int(n), nil
+}
+
+// TakeKit returns the emergency kit to the contact and records the view. It returns nil if access is not granted.
+func (er *EmergencyRepo) TakeKit(ctx context.Context, owner string, contact string) (*EmergencyKit, error) {
+	tx, err := er.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	sqlSt := `select payload, wrapped_key from trusted_contact
+		where owner_id = (select id from customer where login = $1)
+		and contact_id = (select id from customer where login = $2)
+		and status = $3;`
+
+	var kit EmergencyKit
+	err = tx.QueryRowContext(ctx, sqlSt, owner, contact, emergency.Granted).Scan(&kit.Payload, &kit.WrappedKey)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Println("error in scan:", err)
+		return nil, err
+	}
+
+	if err := addEvent(ctx, tx, owner, contact, emergency.EventViewed); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Println("error in taking emergency kit:", err)
+		return nil, err
+	}
+	return &kit, nil
+}
+
+const emergencyEventColumns = `select o.login, c.login, e.event, e.created_at
+	from emergency_event e
+	inner join customer o on o.id = e.owner_id
+	inner join customer c on c.id = e.contact_id`
+
+func scanEmergencyEvents(rows *sql.Rows) ([]EmergencyEvent, error) {
+	res := make([]EmergencyEvent, 0)
+	for rows.Next() {
+		var e EmergencyEvent
+		if err := rows.Scan(&e.Owner, &e.Contact, &e.Event, &e.CreatedAt); err != nil {
+			log.Println("error:", err)
+			return nil, err
+		}
+		res = append(res, e)
+	}
+	return res, rows.Err()
+}
+
+// GetEvents returns the latest steps of emergency access where the user is the owner or the contact, newest first.
+func (er *EmergencyRepo) GetEvents(ctx context.Context, login string, limit int) ([]EmergencyEvent, error) {
+	sqlSt := emergencyEventColumns + ` where o.login = $1 or c.login = $1
+		order by e.created_at desc, e.id desc limit $2;`
+
+	rows, err := er.DB.QueryContext(ctx, sqlSt, login, limit)
+	if err != nil {
+		log.Println("error in getting emergency events:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEmergencyEvents(rows)
+}
+
+// TakeNotifications returns the events the user has not been notified of yet, oldest first,
+// and marks them as notified.
+func (er *EmergencyRepo) TakeNotifications(ctx context.Context, login string) ([]EmergencyEvent, error) {
+	sqlSt := `with e as (update emergency_event set notified = true
+			where notify_id = (select id from customer where login = $1) and not notified
+			returning id, owner_id, contact_id, event, created_at)
+		select o.login, c.login, e.event, e.created_at from e
+		inner join customer o on o.id = e.owner_id
+		inner join customer c on c.id = e.contact_id
+		order by e.created_at, e.id;`
+
+	rows, err := er.DB.QueryContext(ctx, sqlSt, login)
+	if err != nil {
+		log.Println("error in getting notifications:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEmergencyEvents(rows)
+}
@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/adettelle/go-keeper/internal/emergency"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrantDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	// открытие доступа и событие для владельца записываются одним запросом
+	mock.ExpectExec(`with g as \(update trusted_contact set status = \$2 .*returning owner_id, contact_id\)
+		insert into emergency_event \(owner_id, contact_id, event, notify_id\)
+		select owner_id, contact_id, \$4, owner_id from g`).
+		WithArgs("bob@aaa.com", emergency.Granted, emergency.Requested, emergency.EventGranted).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	er := NewEmergencyRepo(db)
+	n, err := er.GrantDue(context.Background(), "bob@aaa.com")
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRejectAccessOnlyDuringWait(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	// срок ожидания истёк: запрос не отклоняется и событие не записывается
+	mock.ExpectBegin()
+	mock.ExpectExec(`update trusted_contact set status = \$3, requested_at = null .*and status = \$4 and requested_at \+ wait_seconds \* interval '1 second' > now\(\)`).
+		WithArgs("ane@aaa.com", "bob@aaa.com", emergency.Idle, emergency.Requested).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	er := NewEmergencyRepo(db)
+	rejected, err := er.RejectAccess(context.Background(), "ane@aaa.com", "bob@aaa.com")
+	require.NoError(t, err)
+	require.False(t, rejected)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
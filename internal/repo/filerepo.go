@@ -81,41 +81,76 @@ func (fr *FileRepo) GetFileCoudIDByTitle(ctx context.Context, title, login strin
 	return *fileCloudID, nil
 }
 
+// FileObject is the object of a file in the storage.
+type FileObject struct {
+	CloudID  string
+	FileName string
+}
+
+// GetFileObject returns the storage object of the file, nil if the user has no file with the title.
+func (fr *FileRepo) GetFileObject(ctx context.Context, title, login string) (*FileObject, error) {
+	sqlSt := `select cloud_id, file_name from bfile
+		inner join customer c on c.id = bfile.customer_id
+		where title = $1 and c.login = $2;`
+
+	var res FileObject
+	err := fr.DB.QueryRowContext(ctx, sqlSt, title, login).Scan(&res.CloudID, &res.FileName)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Println("error in scan:", err)
+		return nil, err
+	}
+	return &res, nil
+}
+
 type FileToGet struct {
-	ID          string
+	ID          int
 	FileName    string
 	Title       string
 	Description string
+	ItemMeta
 }
 
-// GetAllFiles получает список файлов по имени (name) пользователя
-func (fr *FileRepo) GetAllFiles(ctx context.Context, login string) ([]FileToGet, error) {
+// GetAllFiles получает страницу списка файлов по имени (name) пользователя, избранные идут первыми.
+// Курсор не nil, если есть следующая страница.
+func (fr *FileRepo) GetAllFiles(ctx context.Context, login string, filter ItemFilter,
+	page Page) ([]FileToGet, *ItemCursor, error) {
+
 	files := make([]FileToGet, 0)
 
-	sqlSt := `select title, file_name,  description from bfile  
+	where, args := filter.where("bfile", []any{login})
+	after, order, args := page.clauses("bfile", false, args)
+	sqlSt := `select bfile.id, title, file_name, description, folder, tags, favorite from bfile  
 		inner join customer c on c.id = bfile.customer_id 
-		where c.login = $1
-		order by title;`
+		where c.login = $1` + where + after + order + `;`
 
-	rows, err := fr.DB.QueryContext(ctx, sqlSt, login)
+	rows, err := fr.DB.QueryContext(ctx, sqlSt, args...)
 	if err != nil || rows.Err() != nil {
 		log.Println("error in getting files:", err)
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
 	// пробегаем по всем записям
 	for rows.Next() {
 		var file FileToGet
-		err := rows.Scan(&file.Title, &file.FileName, &file.Description)
+		err := rows.Scan(&file.ID, &file.Title, &file.FileName, &file.Description,
+			&file.Folder, tagsScanner(&file.Tags), &file.Favorite)
 		if err != nil {
 			log.Println("error: ", err)
-			return nil, err
+			return nil, nil, err
 		}
 		files = append(files, file)
 	}
 
-	return files, nil
+	next := page.next(len(files), func() ItemCursor {
+		files = files[:page.Limit]
+		last := files[len(files)-1]
+		return ItemCursor{Favorite: last.Favorite, Key: page.cursorKey(last.ID, last.Title)}
+	})
+	return files, next, nil
 }
 
 func (fr *FileRepo) DeleteFileByTitle(ctx context.Context, title string, login string) error {
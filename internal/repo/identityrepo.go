@@ -0,0 +1,175 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+type IdentityRepo struct {
+	DB *sql.DB
+}
+
+func NewIdentityRepo(db *sql.DB) *IdentityRepo {
+	return &IdentityRepo{
+		DB: db,
+	}
+}
+
+// Identity is an identity document; the fields are an encrypted JSON object.
+type Identity struct {
+	DocType     string
+	Title       string
+	Description string
+	Fields      string
+}
+
+// AddIdentity adds a new identity document to the database for an authenticated user.
+func (ir *IdentityRepo) AddIdentity(ctx context.Context, identity Identity, login string) error {
+	sqlSt := `insert into identity (doc_type, title, description, fields, customer_id) 
+		values ($1, $2, $3, $4, (select id from customer where login = $5));`
+
+	_, err := ir.DB.ExecContext(ctx, sqlSt, identity.DocType, identity.Title, identity.Description,
+		identity.Fields, login)
+	if err != nil {
+		log.Println("error in adding identity:", err)
+		return err
+	}
+	log.Println("Identity is added.")
+	return nil
+}
+
+type IdentityToGet struct {
+	ID          int
+	DocType     string
+	Title       string
+	Description string
+	ItemMeta
+}
+
+// GetAllIdentities retrieves a page of identity documents info (type, title, description, folder, tags
+// and favorite mark) associated with a user login. Favorites go first. The returned cursor is not nil if there are more documents.
+func (ir *IdentityRepo) GetAllIdentities(ctx context.Context, login string, filter ItemFilter,
+	page Page) ([]IdentityToGet, *ItemCursor, error) {
+
+	identities := make([]IdentityToGet, 0)
+
+	where, args := filter.where("identity", []any{login})
+	after, order, args := page.clauses("identity", false, args)
+	sqlSt := `select identity.id, doc_type, title, description, folder, tags, favorite from identity  
+		inner join customer c on c.id = identity.customer_id
+		where c.login = $1` + where + after + order + `;`
+
+	rows, err := ir.DB.QueryContext(ctx, sqlSt, args...)
+	if err != nil || rows.Err() != nil {
+		log.Println("error in getting identities:", err)
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var identity IdentityToGet
+		err := rows.Scan(&identity.ID, &identity.DocType, &identity.Title, &identity.Description,
+			&identity.Folder, tagsScanner(&identity.Tags), &identity.Favorite)
+		if err != nil {
+			log.Println("error: ", err)
+			return nil, nil, err
+		}
+		identities = append(identities, identity)
+	}
+
+	next := page.next(len(identities), func() ItemCursor {
+		identities = identities[:page.Limit]
+		last := identities[len(identities)-1]
+		return ItemCursor{Favorite: last.Favorite, Key: page.cursorKey(last.ID, last.Title)}
+	})
+	return identities, next, nil
+}
+
+// GetIdentityByTitle returns the identity document with encrypted fields or nil if it is not found.
+func (ir *IdentityRepo) GetIdentityByTitle(ctx context.Context, title, login string) (*Identity, error) {
+	sqlSt := `select doc_type, title, description, fields from identity
+		inner join customer c on c.id = identity.customer_id 
+		where identity.title = $1 and c.login = $2;`
+
+	row := ir.DB.QueryRowContext(ctx, sqlSt, title, login)
+
+	var identity Identity
+
+	err := row.Scan(&identity.DocType, &identity.Title, &identity.Description, &identity.Fields)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Println("error in scan: ", err)
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// IdentityUpdate holds the identity values to change; nil fields remain unchanged.
+// Fields replace all the previous fields.
+type IdentityUpdate struct {
+	Fields      *string `db:"fields" goqu:"omitnil"`
+	Description *string `db:"description" goqu:"omitnil"`
+}
+
+// UpdateIdentity updates the identity document fields and description by title.
+// It returns false if the user has no such document.
+func (ir *IdentityRepo) UpdateIdentity(ctx context.Context, title string, upd IdentityUpdate,
+	login string) (bool, error) {
+
+	if upd.Fields == nil && upd.Description == nil {
+		return true, nil
+	}
+	customerID := goqu.From("customer").Select("id").Where(goqu.C("login").Eq(login))
+	sqlSt, args, _ := goqu.Update("identity").Set(upd).
+		Where(goqu.C("title").Eq(title), goqu.C("customer_id").Eq(customerID)).ToSQL()
+
+	res, err := ir.DB.ExecContext(ctx, sqlSt, args...)
+	if err != nil {
+		log.Println("error in changing identity:", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// DeleteIdentityByTitle deletes the identity document by title.
+// It returns false if the user has no such document.
+func (ir *IdentityRepo) DeleteIdentityByTitle(ctx context.Context, title, login string) (bool, error) {
+	sqlSt := `delete from identity 
+		where title = $1 and customer_id = (select id from customer c where c.login = $2);`
+
+	tx, err := ir.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	res, err := tx.ExecContext(ctx, sqlSt, title, login)
+	if err != nil {
+		log.Println("error in deleting identity:", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return false, nil
+	}
+	if err := deleteItemShare(ctx, tx, login, KindIdentity, title); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Println("error in deleting identity:", err)
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,343 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ItemKind is a type of vault items.
+type ItemKind string
+
+const (
+	KindPassword ItemKind = "password"
+	KindCard     ItemKind = "card"
+	KindFile     ItemKind = "file"
+	KindIdentity ItemKind = "identity"
+	KindSSHKey   ItemKind = "sshkey"
+	KindNote     ItemKind = "note"
+)
+
+// ItemKinds are all the item kinds in the listing order.
+var ItemKinds = []ItemKind{KindPassword, KindCard, KindFile, KindIdentity, KindSSHKey, KindNote}
+
+// itemTables maps item kinds to their tables.
+var itemTables = map[ItemKind]string{
+	KindPassword: "pass",
+	KindCard:     "card",
+	KindFile:     "bfile",
+	KindIdentity: "identity",
+	KindSSHKey:   "ssh_key",
+	KindNote:     "note",
+}
+
+// ItemMeta is the organizing information common for all vault items.
+type ItemMeta struct {
+	Folder   string   // Folder is a slash separated path, e.g. "work/prod"; empty for the root.
+	Tags     []string // Tags are free-form labels.
+	Favorite bool     // Favorite items are listed first.
+}
+
+// ItemMetaUpdate holds the item meta fields to change; nil fields remain unchanged.
+type ItemMetaUpdate struct {
+	Folder   *string
+	Tags     *[]string
+	Favorite *bool
+}
+
+// ItemFilter restricts item listings. Zero value matches all the items.
+type ItemFilter struct {
+	Folder   string   // Folder matches items of the folder and all its subfolders.
+	Tags     []string // Tags matches items having all of the tags.
+	Favorite bool     // Favorite matches only favorite items.
+	Search   string   // Search matches items with the substring in the title, description or a tag, ignoring case.
+}
+
+// where returns the filter conditions over the table (prefixed with " and ")
+// with placeholders numbered after the given args, and the args extended with the filter values.
+func (f ItemFilter) where(table string, args []any) (string, []any) {
+	var sb strings.Builder
+	if f.Folder != "" {
+		args = append(args, f.Folder, escapeLike(f.Folder)+"/%")
+		fmt.Fprintf(&sb, " and (%[1]s.folder = $%[2]d or %[1]s.folder like $%[3]d)", table, len(args)-1, len(args))
+	}
+	if len(f.Tags) > 0 {
+		args = append(args, f.Tags)
+		fmt.Fprintf(&sb, " and %s.tags @> $%d::varchar[]", table, len(args))
+	}
+	if f.Favorite {
+		sb.WriteString(" and " + table + ".favorite")
+	}
+	if f.Search != "" {
+		args = append(args, "%"+escapeLike(f.Search)+"%")
+		fmt.Fprintf(&sb, " and (%[1]s.title ilike $%[2]d or coalesce(%[1]s.description, '') ilike $%[2]d"+
+			" or exists (select 1 from unnest(%[1]s.tags) tag where tag ilike $%[2]d))", table, len(args))
+	}
+	return sb.String(), args
+}
+
+// ItemSort is a listing order. Favorites always go first.
+type ItemSort string
+
+const (
+	SortTitle       ItemSort = "title"
+	SortTitleDesc   ItemSort = "-title"
+	SortCreated     ItemSort = "created"
+	SortCreatedDesc ItemSort = "-created"
+)
+
+// ItemSorts are all the supported listing orders.
+var ItemSorts = []ItemSort{SortTitle, SortTitleDesc, SortCreated, SortCreatedDesc}
+
+// ItemCursor is the position of the last item of a page, the next page starts after it.
+type ItemCursor struct {
+	Favorite bool     `json:"f"`
+	Key      string   `json:"k"`           // Key is the value of the sort column: the title or the id.
+	Kind     ItemKind `json:"t,omitempty"` // Kind breaks ties between items of different kinds.
+}
+
+// Page is a part of a listing.
+type Page struct {
+	Sort  ItemSort    // Sort is the order of items, by title if empty.
+	Limit int         // Limit is the maximal number of items, zero means no limit.
+	After *ItemCursor // After is the position to continue from, nil for the first page.
+}
+
+// clauses returns the cursor condition (prefixed with " and ") over the table, the order
+// and limit clauses and the args extended with the cursor values.
+// withKind adds the kind column as the last sort key for listings spanning item kinds.
+func (p Page) clauses(table string, withKind bool, args []any) (string, string, []any) {
+	column, dir, cmp := table+".title", "asc", ">"
+	switch p.Sort {
+	case SortTitleDesc:
+		dir, cmp = "desc", "<"
+	case SortCreated:
+		column = table + ".id"
+	case SortCreatedDesc:
+		column, dir, cmp = table+".id", "desc", "<"
+	}
+
+	order := fmt.Sprintf(" order by %s.favorite desc, %s %s", table, column, dir)
+	if withKind {
+		order += ", " + table + ".kind"
+	}
+	if p.Limit > 0 {
+		// one more item tells if there is a next page
+		order += fmt.Sprintf(" limit %d", p.Limit+1)
+	}
+
+	if p.After == nil {
+		return "", order, args
+	}
+
+	var key any = p.After.Key
+	if p.byCreation() {
+		key, _ = strconv.Atoi(p.After.Key) // validated by ValidCursor
+	}
+	args = append(args, p.After.Favorite, key)
+	fav, keyArg := len(args)-1, len(args)
+	after := fmt.Sprintf("%s %s $%d", column, cmp, keyArg)
+	if withKind {
+		args = append(args, string(p.After.Kind))
+		after = fmt.Sprintf("(%s or (%s = $%d and %s.kind > $%d))", after, column, keyArg, table, len(args))
+	}
+	where := fmt.Sprintf(" and (%[1]s.favorite < $%[2]d or (%[1]s.favorite = $%[2]d and %[3]s))", table, fav, after)
+	return where, order, args
+}
+
+func (p Page) byCreation() bool {
+	return p.Sort == SortCreated || p.Sort == SortCreatedDesc
+}
+
+// ValidCursor checks that the cursor fits the page order.
+func (p Page) ValidCursor() bool {
+	if p.After == nil || !p.byCreation() {
+		return true
+	}
+	_, err := strconv.Atoi(p.After.Key)
+	return err == nil
+}
+
+// cursorKey returns the cursor key of an item for the page order.
+func (p Page) cursorKey(id int, title string) string {
+	if p.byCreation() {
+		return strconv.Itoa(id)
+	}
+	return title
+}
+
+// next returns the cursor of the last item if the page is full and there are more items.
+// n is the number of fetched items, which is one more than the limit in that case.
+func (p Page) next(n int, last func() ItemCursor) *ItemCursor {
+	if p.Limit <= 0 || n <= p.Limit {
+		return nil
+	}
+	c := last()
+	return &c
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// tagsScanner returns a scanner of a varchar array column into tags.
+func tagsScanner(tags *[]string) sql.Scanner {
+	return pgtype.NewMap().SQLScanner(tags)
+}
+
+type ItemRepo struct {
+	DB *sql.DB
+}
+
+func NewItemRepo(db *sql.DB) *ItemRepo {
+	return &ItemRepo{
+		DB: db,
+	}
+}
+
+// UpdateItemMeta moves, retags or (un)favorites an item by its kind and title.
+// It returns false if the user has no such item.
+func (ir *ItemRepo) UpdateItemMeta(ctx context.Context,
+	kind ItemKind, title string, upd ItemMetaUpdate, login string) (bool, error) {
+
+	table, ok := itemTables[kind]
+	if !ok {
+		return false, fmt.Errorf("unknown item kind %q", kind)
+	}
+
+	var (
+		sets []string
+		args []any
+	)
+	set := func(column string, value any) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if upd.Folder != nil {
+		set("folder", *upd.Folder)
+	}
+	if upd.Tags != nil {
+		set("tags", *upd.Tags)
+	}
+	if upd.Favorite != nil {
+		set("favorite", *upd.Favorite)
+	}
+	if len(sets) == 0 {
+		return true, nil
+	}
+
+	args = append(args, title, login)
+	sqlSt := fmt.Sprintf(`update %s set %s 
+		where title = $%d and customer_id = (select id from customer c where c.login = $%d);`,
+		table, strings.Join(sets, ", "), len(args)-1, len(args))
+
+	res, err := ir.DB.ExecContext(ctx, sqlSt, args...)
+	if err != nil {
+		log.Println("error in changing item meta:", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// GetAllFolders returns all non-empty folders of the user items, sorted.
+func (ir *ItemRepo) GetAllFolders(ctx context.Context, login string) ([]string, error) {
+	folders := make([]string, 0)
+
+	sqlSt := `select folder from pass where customer_id = (select id from customer c where c.login = $1)
+		union select folder from card where customer_id = (select id from customer c where c.login = $1)
+		union select folder from bfile where customer_id = (select id from customer c where c.login = $1)
+		union select folder from identity where customer_id = (select id from customer c where c.login = $1)
+		union select folder from ssh_key where customer_id = (select id from customer c where c.login = $1)
+		union select folder from note where customer_id = (select id from customer c where c.login = $1)
+		order by folder;`
+
+	rows, err := ir.DB.QueryContext(ctx, sqlSt, login)
+	if err != nil || rows.Err() != nil {
+		log.Println("error in getting folders:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var folder string
+		if err := rows.Scan(&folder); err != nil {
+			log.Println("error:", err)
+			return nil, err
+		}
+		if folder != "" {
+			folders = append(folders, folder)
+		}
+	}
+
+	return folders, nil
+}
+
+// Item is a vault item of any kind in the search results.
+type Item struct {
+	ID          int
+	Kind        ItemKind
+	Title       string
+	Description string
+	ItemMeta
+}
+
+// SearchItems retrieves a page of items of all kinds matching the filter for the specified user.
+// Favorites go first. The returned cursor is not nil if there are more items.
+func (ir *ItemRepo) SearchItems(ctx context.Context, login string, filter ItemFilter,
+	page Page) ([]Item, *ItemCursor, error) {
+
+	items := make([]Item, 0)
+
+	where, args := filter.where("items", []any{login})
+	after, order, args := page.clauses("items", true, args)
+	sqlSt := `select items.id, items.kind, items.title, items.description, 
+			items.folder, items.tags, items.favorite from (
+		select id, 'password' as kind, title, description, folder, tags, favorite, customer_id from pass
+		union all
+		select id, 'card', title, coalesce(description, ''), folder, tags, favorite, customer_id from card
+		union all
+		select id, 'file', title, coalesce(description, ''), folder, tags, favorite, customer_id from bfile
+		union all
+		select id, 'identity', title, description, folder, tags, favorite, customer_id from identity
+		union all
+		select id, 'sshkey', title, description, folder, tags, favorite, customer_id from ssh_key
+		union all
+		select id, 'note', title, description, folder, tags, favorite, customer_id from note
+	) items 
+		inner join customer c on c.id = items.customer_id 
+		where c.login = $1` + where + after + order + `;`
+
+	rows, err := ir.DB.QueryContext(ctx, sqlSt, args...)
+	if err != nil || rows.Err() != nil {
+		log.Println("error in searching items:", err)
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item Item
+		err := rows.Scan(&item.ID, &item.Kind, &item.Title, &item.Description,
+			&item.Folder, tagsScanner(&item.Tags), &item.Favorite)
+		if err != nil {
+			log.Println("error:", err)
+			return nil, nil, err
+		}
+		items = append(items, item)
+	}
+
+	next := page.next(len(items), func() ItemCursor {
+		items = items[:page.Limit]
+		last := items[len(items)-1]
+		return ItemCursor{Favorite: last.Favorite, Key: page.cursorKey(last.ID, last.Title), Kind: last.Kind}
+	})
+	return items, next, nil
+}
@@ -0,0 +1,56 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemFilterWhere(t *testing.T) {
+	where, args := ItemFilter{}.where("pass", []any{"Ane"})
+	require.Empty(t, where)
+	require.Equal(t, []any{"Ane"}, args)
+
+	where, args = ItemFilter{Folder: "work_1", Tags: []string{"prod"}, Favorite: true}.where("pass", []any{"Ane"})
+	require.Equal(t, " and (pass.folder = $2 or pass.folder like $3)"+
+		" and pass.tags @> $4::varchar[] and pass.favorite", where)
+	require.Equal(t, []any{"Ane", "work_1", `work\_1/%`, []string{"prod"}}, args)
+}
+
+func TestItemFilterWhereSearch(t *testing.T) {
+	where, args := ItemFilter{Search: "50%"}.where("card", []any{"Ane"})
+	require.Equal(t, " and (card.title ilike $2 or coalesce(card.description, '') ilike $2"+
+		" or exists (select 1 from unnest(card.tags) tag where tag ilike $2))", where)
+	require.Equal(t, []any{"Ane", `%50\%%`}, args)
+}
+
+func TestPageClauses(t *testing.T) {
+	where, order, args := Page{}.clauses("pass", false, []any{"Ane"})
+	require.Empty(t, where)
+	require.Equal(t, " order by pass.favorite desc, pass.title asc", order)
+	require.Equal(t, []any{"Ane"}, args)
+
+	page := Page{Sort: SortTitleDesc, Limit: 10, After: &ItemCursor{Favorite: true, Key: "vk"}}
+	where, order, args = page.clauses("pass", false, []any{"Ane"})
+	require.Equal(t, " and (pass.favorite < $2 or (pass.favorite = $2 and pass.title < $3))", where)
+	require.Equal(t, " order by pass.favorite desc, pass.title desc limit 11", order)
+	require.Equal(t, []any{"Ane", true, "vk"}, args)
+
+	page = Page{Sort: SortCreated, Limit: 5, After: &ItemCursor{Key: "42", Kind: KindCard}}
+	where, order, args = page.clauses("items", true, []any{"Ane"})
+	require.Equal(t, " and (items.favorite < $2 or (items.favorite = $2 and"+
+		" (items.id > $3 or (items.id = $3 and items.kind > $4))))", where)
+	require.Equal(t, " order by items.favorite desc, items.id asc, items.kind limit 6", order)
+	require.Equal(t, []any{"Ane", false, 42, "card"}, args)
+}
+
+func TestPageNext(t *testing.T) {
+	last := func() ItemCursor { return ItemCursor{Key: "last"} }
+
+	require.Nil(t, Page{}.next(100, last))
+	require.Nil(t, Page{Limit: 10}.next(10, last))
+	require.Equal(t, &ItemCursor{Key: "last"}, Page{Limit: 10}.next(11, last))
+
+	require.True(t, Page{Sort: SortCreated, After: &ItemCursor{Key: "42"}}.ValidCursor())
+	require.False(t, Page{Sort: SortCreated, After: &ItemCursor{Key: "vk"}}.ValidCursor())
+}
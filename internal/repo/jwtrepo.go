@@ -74,3 +74,16 @@ func (jr *JwtRepo) TokenIsValid(ctx context.Context, token string) (bool, error)
 
 	return isValid, nil
 }
+
+// InvalidateToken sets the token as invalid, e.g. when the web vault session is logged out.
+func (jr *JwtRepo) InvalidateToken(ctx context.Context, token string) error {
+	sqlSt := `update jwttoken set is_valid = false where token = $1;`
+
+	_, err := jr.DB.ExecContext(ctx, sqlSt, token)
+	if err != nil {
+		log.Println("error in invalidating token:", err)
+		return err
+	}
+	log.Println("Token is invalidated.")
+	return nil
+}
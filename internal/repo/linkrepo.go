@@ -0,0 +1,81 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+// LinkRepo keeps secret links: secrets encrypted on the client that anybody with the link can read
+// a limited number of times until the link expires.
+type LinkRepo struct {
+	DB *sql.DB
+}
+
+func NewLinkRepo(db *sql.DB) *LinkRepo {
+	return &LinkRepo{
+		DB: db,
+	}
+}
+
+// AddLink stores the encrypted secret under the link id. Expired links are removed meanwhile.
+func (lr *LinkRepo) AddLink(ctx context.Context, login string, id string, payload string, views int,
+	expiresAt time.Time) error {
+
+	_, err := lr.DB.ExecContext(ctx, `delete from secret_link where expires_at <= now();`)
+	if err != nil {
+		log.Println("error in deleting expired links:", err)
+		return err
+	}
+
+	sqlSt := `insert into secret_link (id, owner_id, payload, views_left, expires_at)
+		values ($1, (select id from customer where login = $2), $3, $4, $5);`
+
+	_, err = lr.DB.ExecContext(ctx, sqlSt, id, login, payload, views, expiresAt)
+	if err != nil {
+		log.Println("error in adding link:", err)
+		return err
+	}
+	log.Println("Link is added.")
+	return nil
+}
+
+// TakeLink returns the encrypted secret of the link and counts the view. The link is burnt with the last view.
+// It returns false if there is no such link or it has expired.
+func (lr *LinkRepo) TakeLink(ctx context.Context, id string) (string, bool, error) {
+	tx, err := lr.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", false, err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	sqlSt := `update secret_link set views_left = views_left - 1
+		where id = $1 and views_left > 0 and expires_at > now()
+		returning payload, views_left;`
+
+	var payload string
+	var viewsLeft int
+	err = tx.QueryRowContext(ctx, sqlSt, id).Scan(&payload, &viewsLeft)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		log.Println("error in taking link:", err)
+		return "", false, err
+	}
+
+	if viewsLeft == 0 {
+		if _, err := tx.ExecContext(ctx, `delete from secret_link where id = $1;`, id); err != nil {
+			log.Println("error in deleting link:", err)
+			return "", false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("error in taking link:", err)
+		return "", false, err
+	}
+	log.Println("Link is viewed.")
+	return payload, true, nil
+}
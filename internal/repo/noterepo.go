@@ -0,0 +1,170 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+type NoteRepo struct {
+	DB *sql.DB
+}
+
+func NewNoteRepo(db *sql.DB) *NoteRepo {
+	return &NoteRepo{
+		DB: db,
+	}
+}
+
+// Note is a secure note; the text is encrypted.
+type Note struct {
+	Title       string
+	Description string
+	Text        string
+}
+
+// AddNote adds a new secure note to the database for an authenticated user.
+func (nr *NoteRepo) AddNote(ctx context.Context, note Note, login string) error {
+	sqlSt := `insert into note (title, description, body, customer_id) 
+		values ($1, $2, $3, (select id from customer where login = $4));`
+
+	_, err := nr.DB.ExecContext(ctx, sqlSt, note.Title, note.Description, note.Text, login)
+	if err != nil {
+		log.Println("error in adding note:", err)
+		return err
+	}
+	log.Println("Note is added.")
+	return nil
+}
+
+type NoteToGet struct {
+	ID          int
+	Title       string
+	Description string
+	ItemMeta
+}
+
+// GetAllNotes retrieves a page of secure notes info (title, description, folder, tags and favorite mark)
+// associated with a user login, without their text. Favorites go first.
+// The returned cursor is not nil if there are more notes.
+func (nr *NoteRepo) GetAllNotes(ctx context.Context, login string, filter ItemFilter,
+	page Page) ([]NoteToGet, *ItemCursor, error) {
+
+	notes := make([]NoteToGet, 0)
+
+	where, args := filter.where("note", []any{login})
+	after, order, args := page.clauses("note", false, args)
+	sqlSt := `select note.id, title, description, folder, tags, favorite from note  
+		inner join customer c on c.id = note.customer_id
+		where c.login = $1` + where + after + order + `;`
+
+	rows, err := nr.DB.QueryContext(ctx, sqlSt, args...)
+	if err != nil || rows.Err() != nil {
+		log.Println("error in getting notes:", err)
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var note NoteToGet
+		err := rows.Scan(&note.ID, &note.Title, &note.Description,
+			&note.Folder, tagsScanner(&note.Tags), &note.Favorite)
+		if err != nil {
+			log.Println("error: ", err)
+			return nil, nil, err
+		}
+		notes = append(notes, note)
+	}
+
+	next := page.next(len(notes), func() ItemCursor {
+		notes = notes[:page.Limit]
+		last := notes[len(notes)-1]
+		return ItemCursor{Favorite: last.Favorite, Key: page.cursorKey(last.ID, last.Title)}
+	})
+	return notes, next, nil
+}
+
+// GetNoteByTitle returns the secure note with encrypted text or nil if it is not found.
+func (nr *NoteRepo) GetNoteByTitle(ctx context.Context, title, login string) (*Note, error) {
+	sqlSt := `select title, description, body from note
+		inner join customer c on c.id = note.customer_id 
+		where note.title = $1 and c.login = $2;`
+
+	row := nr.DB.QueryRowContext(ctx, sqlSt, title, login)
+
+	var note Note
+
+	err := row.Scan(&note.Title, &note.Description, &note.Text)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Println("error in scan: ", err)
+		return nil, err
+	}
+	return &note, nil
+}
+
+// NoteUpdate holds the note values to change; nil fields remain unchanged.
+type NoteUpdate struct {
+	Text        *string `db:"body" goqu:"omitnil"`
+	Description *string `db:"description" goqu:"omitnil"`
+}
+
+// UpdateNote updates the note text and description by title.
+// It returns false if the user has no such note.
+func (nr *NoteRepo) UpdateNote(ctx context.Context, title string, upd NoteUpdate, login string) (bool, error) {
+	if upd.Text == nil && upd.Description == nil {
+		return true, nil
+	}
+	customerID := goqu.From("customer").Select("id").Where(goqu.C("login").Eq(login))
+	sqlSt, args, _ := goqu.Update("note").Set(upd).
+		Where(goqu.C("title").Eq(title), goqu.C("customer_id").Eq(customerID)).ToSQL()
+
+	res, err := nr.DB.ExecContext(ctx, sqlSt, args...)
+	if err != nil {
+		log.Println("error in changing note:", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// DeleteNoteByTitle deletes the secure note by title.
+// It returns false if the user has no such note.
+func (nr *NoteRepo) DeleteNoteByTitle(ctx context.Context, title, login string) (bool, error) {
+	sqlSt := `delete from note 
+		where title = $1 and customer_id = (select id from customer c where c.login = $2);`
+
+	tx, err := nr.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	res, err := tx.ExecContext(ctx, sqlSt, title, login)
+	if err != nil {
+		log.Println("error in deleting note:", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return false, nil
+	}
+	if err := deleteItemShare(ctx, tx, login, KindNote, title); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Println("error in deleting note:", err)
+		return false, err
+	}
+	return true, nil
+}
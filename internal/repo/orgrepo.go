@@ -0,0 +1,380 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/adettelle/go-keeper/internal/access"
+)
+
+// OrgRepo keeps organizations, their members with roles and shared collections.
+//
+// The items of a collection belong to its vault customer: a customer row without a master password,
+// so it can not log in. Handlers of authorized members work with the items as the vault customer,
+// and the item repositories need no changes.
+type OrgRepo struct {
+	DB *sql.DB
+}
+
+func NewOrgRepo(db *sql.DB) *OrgRepo {
+	return &OrgRepo{
+		DB: db,
+	}
+}
+
+// OrgMembership is an organization of the user with the role of the user in it.
+type OrgMembership struct {
+	Org  string
+	Role access.Role
+}
+
+// OrgMember is a member of an organization.
+type OrgMember struct {
+	Login string
+	Name  string
+	Role  access.Role
+}
+
+// CollectionAccess is a collection the user is a member of: the vault customer to work as and the role.
+type CollectionAccess struct {
+	VaultID    int
+	VaultLogin string
+	Role       access.Role
+}
+
+// vaultLogin is the login of the vault customer of the collection. It is not an email,
+// so nobody can register with it.
+func vaultLogin(org, collection string) string {
+	return fmt.Sprintf("collection:%s/%s", org, collection)
+}
+
+func (or *OrgRepo) OrgExists(ctx context.Context, org string) (bool, error) {
+	sqlSt := `select count(*) > 0 from organization where name = $1;`
+
+	var exists bool
+	err := or.DB.QueryRowContext(ctx, sqlSt, org).Scan(&exists)
+	if err != nil {
+		log.Println("error in scan:", err)
+		return false, err
+	}
+	return exists, nil
+}
+
+// CreateOrg adds the organization with the user as its owner.
+func (or *OrgRepo) CreateOrg(ctx context.Context, org string, login string) error {
+	tx, err := or.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	var orgID int
+	err = tx.QueryRowContext(ctx, `insert into organization (name) values ($1) returning id;`, org).Scan(&orgID)
+	if err != nil {
+		log.Println("error in adding organization:", err)
+		return err
+	}
+
+	sqlSt := `insert into org_member (org_id, customer_id, role)
+		values ($1, (select id from customer where login = $2), $3);`
+	_, err = tx.ExecContext(ctx, sqlSt, orgID, login, access.Owner)
+	if err != nil {
+		log.Println("error in adding organization owner:", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("error in adding organization:", err)
+		return err
+	}
+	log.Println("Organization is added.")
+	return nil
+}
+
+// DeleteOrg removes the organization with its members. The collections must be deleted before.
+func (or *OrgRepo) DeleteOrg(ctx context.Context, org string) error {
+	sqlSt := `delete from organization where name = $1;`
+
+	_, err := or.DB.ExecContext(ctx, sqlSt, org)
+	if err != nil {
+		log.Println("error in deleting organization:", err)
+		return err
+	}
+	log.Println("Organization is deleted.")
+	return nil
+}
+
+// GetOrgs returns the organizations of the user with the user roles.
+func (or *OrgRepo) GetOrgs(ctx context.Context, login string) ([]OrgMembership, error) {
+	sqlSt := `select o.name, m.role from org_member m
+		inner join organization o on o.id = m.org_id
+		inner join customer c on c.id = m.customer_id
+		where c.login = $1 order by o.name;`
+
+	rows, err := or.DB.QueryContext(ctx, sqlSt, login)
+	if err != nil {
+		log.Println("error in getting organizations:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]OrgMembership, 0)
+	for rows.Next() {
+		var m OrgMembership
+		if err := rows.Scan(&m.Org, &m.Role); err != nil {
+			log.Println("error:", err)
+			return nil, err
+		}
+		res = append(res, m)
+	}
+	return res, rows.Err()
+}
+
+// GetRole returns the role of the user in the organization, empty if the user is not a member.
+func (or *OrgRepo) GetRole(ctx context.Context, org string, login string) (access.Role, error) {
+	sqlSt := `select m.role from org_member m
+		inner join organization o on o.id = m.org_id
+		inner join customer c on c.id = m.customer_id
+		where o.name = $1 and c.login = $2;`
+
+	var role access.Role
+	err := or.DB.QueryRowContext(ctx, sqlSt, org, login).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		log.Println("error in scan:", err)
+		return "", err
+	}
+	return role, nil
+}
+
+// GetMembers returns the members of the organization, owners first.
+func (or *OrgRepo) GetMembers(ctx context.Context, org string) ([]OrgMember, error) {
+	sqlSt := `select c.login, c.name, m.role from org_member m
+		inner join organization o on o.id = m.org_id
+		inner join customer c on c.id = m.customer_id
+		where o.name = $1
+		order by case m.role when 'owner' then 0 when 'admin' then 1 when 'editor' then 2 else 3 end, c.login;`
+
+	rows, err := or.DB.QueryContext(ctx, sqlSt, org)
+	if err != nil {
+		log.Println("error in getting members:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]OrgMember, 0)
+	for rows.Next() {
+		var m OrgMember
+		if err := rows.Scan(&m.Login, &m.Name, &m.Role); err != nil {
+			log.Println("error:", err)
+			return nil, err
+		}
+		res = append(res, m)
+	}
+	return res, rows.Err()
+}
+
+// SetMember adds the user to the organization with the role or changes the role of a member.
+// It returns false if there is no user with the login.
+func (or *OrgRepo) SetMember(ctx context.Context, org string, login string, role access.Role) (bool, error) {
+	sqlSt := `insert into org_member (org_id, customer_id, role)
+		select o.id, c.id, $3 from organization o, customer c
+		where o.name = $1 and c.login = $2
+		and c.id not in (select vault_customer_id from collection)
+		on conflict (org_id, customer_id) do update set role = excluded.role;`
+
+	res, err := or.DB.ExecContext(ctx, sqlSt, org, login, role)
+	if err != nil {
+		log.Println("error in setting member:", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return false, nil
+	}
+	log.Println("Member is set.")
+	return true, nil
+}
+
+// DeleteMember removes the user from the organization.
+func (or *OrgRepo) DeleteMember(ctx context.Context, org string, login string) error {
+	sqlSt := `delete from org_member
+		where org_id = (select id from organization where name = $1)
+		and customer_id = (select id from customer where login = $2);`
+
+	_, err := or.DB.ExecContext(ctx, sqlSt, org, login)
+	if err != nil {
+		log.Println("error in deleting member:", err)
+		return err
+	}
+	log.Println("Member is deleted.")
+	return nil
+}
+
+// CountOwners returns the number of owners of the organization.
+func (or *OrgRepo) CountOwners(ctx context.Context, org string) (int, error) {
+	sqlSt := `select count(*) from org_member m
+		inner join organization o on o.id = m.org_id
+		where o.name = $1 and m.role = $2;`
+
+	var n int
+	err := or.DB.QueryRowContext(ctx, sqlSt, org, access.Owner).Scan(&n)
+	if err != nil {
+		log.Println("error in scan:", err)
+		return 0, err
+	}
+	return n, nil
+}
+
+func (or *OrgRepo) CollectionExists(ctx context.Context, org string, collection string) (bool, error) {
+	sqlSt := `select count(*) > 0 from collection col
+		inner join organization o on o.id = col.org_id
+		where o.name = $1 and col.name = $2;`
+
+	var exists bool
+	err := or.DB.QueryRowContext(ctx, sqlSt, org, collection).Scan(&exists)
+	if err != nil {
+		log.Println("error in scan:", err)
+		return false, err
+	}
+	return exists, nil
+}
+
+// CreateCollection adds the collection with its vault customer.
+func (or *OrgRepo) CreateCollection(ctx context.Context, org string, collection string) error {
+	tx, err := or.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	// the empty master password never matches a password hash, so the vault can not log in
+	sqlSt := `insert into customer (name, login, masterpassword) values ($1, $2, '') returning id;`
+
+	var vaultID int
+	err = tx.QueryRowContext(ctx, sqlSt, collection, vaultLogin(org, collection)).Scan(&vaultID)
+	if err != nil {
+		log.Println("error in adding collection vault:", err)
+		return err
+	}
+
+	sqlSt = `insert into collection (org_id, name, vault_customer_id)
+		values ((select id from organization where name = $1), $2, $3);`
+	_, err = tx.ExecContext(ctx, sqlSt, org, collection, vaultID)
+	if err != nil {
+		log.Println("error in adding collection:", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("error in adding collection:", err)
+		return err
+	}
+	log.Println("Collection is added.")
+	return nil
+}
+
+// GetCollections returns the names of the collections of the organization.
+func (or *OrgRepo) GetCollections(ctx context.Context, org string) ([]string, error) {
+	sqlSt := `select col.name from collection col
+		inner join organization o on o.id = col.org_id
+		where o.name = $1 order by col.name;`
+
+	rows, err := or.DB.QueryContext(ctx, sqlSt, org)
+	if err != nil {
+		log.Println("error in getting collections:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			log.Println("error:", err)
+			return nil, err
+		}
+		res = append(res, name)
+	}
+	return res, rows.Err()
+}
+
+// DeleteCollection removes the collection with its vault customer if the collection has no items.
+// It returns false if there are items left.
+func (or *OrgRepo) DeleteCollection(ctx context.Context, org string, collection string) (bool, error) {
+	tx, err := or.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	var vaultID int
+	sqlSt := `delete from collection col using organization o
+		where o.id = col.org_id and o.name = $1 and col.name = $2 returning col.vault_customer_id;`
+	err = tx.QueryRowContext(ctx, sqlSt, org, collection).Scan(&vaultID)
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+	if err != nil {
+		log.Println("error in deleting collection:", err)
+		return false, err
+	}
+
+	var empty bool
+	sqlSt = `select not exists (select 1 from pass where customer_id = $1)
+		and not exists (select 1 from card where customer_id = $1)
+		and not exists (select 1 from bfile where customer_id = $1)
+		and not exists (select 1 from identity where customer_id = $1)
+		and not exists (select 1 from ssh_key where customer_id = $1)
+		and not exists (select 1 from note where customer_id = $1);`
+	if err := tx.QueryRowContext(ctx, sqlSt, vaultID).Scan(&empty); err != nil {
+		log.Println("error in scan:", err)
+		return false, err
+	}
+	if !empty {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `delete from customer where id = $1;`, vaultID); err != nil {
+		log.Println("error in deleting collection vault:", err)
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("error in deleting collection:", err)
+		return false, err
+	}
+	log.Println("Collection is deleted.")
+	return true, nil
+}
+
+// GetCollectionAccess returns the vault of the collection and the role of the user in its organization.
+// It returns nil if there is no such collection or the user is not a member of the organization.
+func (or *OrgRepo) GetCollectionAccess(ctx context.Context, org string, collection string,
+	login string) (*CollectionAccess, error) {
+
+	sqlSt := `select v.id, v.login, m.role from collection col
+		inner join organization o on o.id = col.org_id
+		inner join customer v on v.id = col.vault_customer_id
+		inner join org_member m on m.org_id = o.id
+		inner join customer c on c.id = m.customer_id
+		where o.name = $1 and col.name = $2 and c.login = $3;`
+
+	var res CollectionAccess
+	err := or.DB.QueryRowContext(ctx, sqlSt, org, collection, login).Scan(&res.VaultID, &res.VaultLogin, &res.Role)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Println("error in scan:", err)
+		return nil, err
+	}
+	return &res, nil
+}
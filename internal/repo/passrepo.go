@@ -13,6 +13,7 @@ import (
 	"context"
 	"database/sql"
 	"log"
+	"time"
 
 	"github.com/doug-martin/goqu/v9"
 )
@@ -27,46 +28,252 @@ func NewPasswordRepo(db *sql.DB) *PasswordRepo {
 	}
 }
 
-func (pr *PasswordRepo) CreatePassword(
-	ctx context.Context, password, title, description string, login string) error {
+// PasswordURL is a site address of a password entry.
+type PasswordURL struct {
+	URL   string   // URL is encrypted.
+	Match string   // Match is the rule of matching the URL against a site: domain, host, exact or never.
+	Index URLIndex // Index is only written, it is not read back.
+}
+
+// URLIndex is a blind index of a URL: keyed hashes of its registrable domain, host and normalized form.
+type URLIndex struct {
+	Domain string
+	Host   string
+	URL    string
+}
+
+// PasswordField is a custom field of a password entry.
+type PasswordField struct {
+	Name  string
+	Type  string // Type is text, hidden or boolean.
+	Value string // Value is encrypted.
+}
+
+// PasswordDetails are the structured parts of a password entry besides the password itself.
+type PasswordDetails struct {
+	Username   string // Username is encrypted, empty if not set.
+	URLs       []PasswordURL
+	Fields     []PasswordField
+	MaxAgeDays int // MaxAgeDays is the period of the password rotation reminder, 0 means no reminder.
+}
+
+// PasswordDetailsUpdate holds the details to change; nil fields remain unchanged.
+// URLs and Fields replace all the previous values.
+type PasswordDetailsUpdate struct {
+	Username   *string
+	URLs       *[]PasswordURL
+	Fields     *[]PasswordField
+	MaxAgeDays *int
+}
+
+// CreatePassword adds the password entry with its URLs and custom fields in one transaction.
+// The entry is put into the folder and tagged by the same insert.
+func (pr *PasswordRepo) CreatePassword(ctx context.Context,
+	password, title, description string, details PasswordDetails, meta ItemMeta, login string) error {
+
+	tx, err := pr.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
 
 	// do not need to check if the user has a password with this title,
 	// because there is a unique (title, customer_id) in table
-	sqlSt := `insert into pass (pwd, title, description, customer_id) 
-		values ($1, $2, $3, (select id from customer where login = $4));`
+	sqlSt := `insert into pass (pwd, title, description, username, max_age_days, folder, tags, customer_id) 
+		values ($1, $2, $3, $4, $5, $6, $7, (select id from customer where login = $8)) returning id;`
 
-	_, err := pr.DB.ExecContext(ctx, sqlSt, password, title, description, login)
+	tags := meta.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	var passID int
+	err = tx.QueryRowContext(ctx, sqlSt, password, title, description, details.Username,
+		details.MaxAgeDays, meta.Folder, tags, login).Scan(&passID)
 	if err != nil {
 		log.Println("error in adding password:", err)
 		return err
 	}
+
+	if err := insertPasswordURLs(ctx, tx, passID, details.URLs); err != nil {
+		return err
+	}
+	if err := insertPasswordFields(ctx, tx, passID, details.Fields); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("error in adding password:", err)
+		return err
+	}
 	log.Println("Password is added.")
 	return nil
 }
 
-// UpdatePassword updates the password and description by title.
+func insertPasswordURLs(ctx context.Context, tx *sql.Tx, passID int, urls []PasswordURL) error {
+	sqlSt := `insert into pass_url (pass_id, url, match_rule, position, domain_index, host_index, url_index) 
+		values ($1, $2, $3, $4, $5, $6, $7);`
+	for i, u := range urls {
+		if _, err := tx.ExecContext(ctx, sqlSt, passID, u.URL, u.Match, i,
+			u.Index.Domain, u.Index.Host, u.Index.URL); err != nil {
+			log.Println("error in adding password url:", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// BackfillURLIndexes fills in the blind indexes of the URLs stored before URLs were indexed,
+// so that they can be looked up too. index builds the blind index of an encrypted URL; URLs it
+// fails on are left unindexed. It returns the number of URLs indexed.
+func (pr *PasswordRepo) BackfillURLIndexes(ctx context.Context,
+	index func(encryptedURL string) (URLIndex, error)) (int, error) {
+
+	rows, err := pr.DB.QueryContext(ctx, `select id, url from pass_url where url_index = '';`)
+	if err != nil {
+		log.Println("error in getting unindexed password urls:", err)
+		return 0, err
+	}
+	defer rows.Close()
+
+	type storedURL struct {
+		id  int
+		url string
+	}
+	var urls []storedURL
+	for rows.Next() {
+		var u storedURL
+		if err := rows.Scan(&u.id, &u.url); err != nil {
+			log.Println("error:", err)
+			return 0, err
+		}
+		urls = append(urls, u)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(urls) == 0 {
+		return 0, nil
+	}
+
+	tx, err := pr.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	sqlSt := `update pass_url set domain_index = $2, host_index = $3, url_index = $4 where id = $1;`
+	n := 0
+	for _, u := range urls {
+		idx, err := index(u.url)
+		if err != nil {
+			log.Printf("error in indexing password url %d: %v", u.id, err)
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, sqlSt, u.id, idx.Domain, idx.Host, idx.URL); err != nil {
+			log.Println("error in indexing password url:", err)
+			return 0, err
+		}
+		n++
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("error in indexing password urls:", err)
+		return 0, err
+	}
+	return n, nil
+}
+
+func insertPasswordFields(ctx context.Context, tx *sql.Tx, passID int, fields []PasswordField) error {
+	sqlSt := `insert into pass_field (pass_id, name, field_type, value, position) values ($1, $2, $3, $4, $5);`
+	for i, f := range fields {
+		if _, err := tx.ExecContext(ctx, sqlSt, passID, f.Name, f.Type, f.Value, i); err != nil {
+			log.Println("error in adding password field:", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdatePassword updates the password, description, username, URLs and custom fields by title.
 // Fields not provided in json remain unchanged.
 // Passing an empty string sets the field to empty.
-func (pr *PasswordRepo) UpdatePassword(ctx context.Context,
-	title string, password *string, description *string, userID int) error {
+// Changing the password itself also updates its changed_at time.
+// It returns false if the user has no password with the title.
+func (pr *PasswordRepo) UpdatePassword(ctx context.Context, title string, password *string,
+	description *string, details PasswordDetailsUpdate, userID int) (bool, error) {
 
 	type pwd struct {
-		Password    *string `db:"pwd" goqu:"omitnil"`
-		Description *string `db:"description" goqu:"omitnil"`
+		Password    *string    `db:"pwd" goqu:"omitnil"`
+		Description *string    `db:"description" goqu:"omitnil"`
+		Username    *string    `db:"username" goqu:"omitnil"`
+		MaxAgeDays  *int       `db:"max_age_days" goqu:"omitnil"`
+		ChangedAt   *time.Time `db:"changed_at" goqu:"omitnil"`
 	}
 
-	sqlSt, args, _ := goqu.Update("pass").Set(pwd{
-		Password:    password,
-		Description: description,
-	}).Where(goqu.C("title").Eq(title)).Where(goqu.C("customer_id").Eq(userID)).ToSQL()
+	var changedAt *time.Time
+	if password != nil {
+		now := time.Now()
+		changedAt = &now
+	}
 
-	_, err := pr.DB.ExecContext(ctx, sqlSt, args...)
+	tx, err := pr.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	var passID int
+	err = tx.QueryRowContext(ctx, `select id from pass where title = $1 and customer_id = $2 for update;`,
+		title, userID).Scan(&passID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
 	if err != nil {
 		log.Println("error in changing password info:", err)
-		return err
+		return false, err
+	}
+
+	if password != nil || description != nil || details.Username != nil || details.MaxAgeDays != nil {
+		sqlSt, args, _ := goqu.Update("pass").Set(pwd{
+			Password:    password,
+			Description: description,
+			Username:    details.Username,
+			MaxAgeDays:  details.MaxAgeDays,
+			ChangedAt:   changedAt,
+		}).Where(goqu.C("id").Eq(passID)).ToSQL()
+
+		if _, err := tx.ExecContext(ctx, sqlSt, args...); err != nil {
+			log.Println("error in changing password info:", err)
+			return false, err
+		}
+	}
+
+	if details.URLs != nil {
+		if _, err := tx.ExecContext(ctx, `delete from pass_url where pass_id = $1;`, passID); err != nil {
+			log.Println("error in changing password urls:", err)
+			return false, err
+		}
+		if err := insertPasswordURLs(ctx, tx, passID, *details.URLs); err != nil {
+			return false, err
+		}
+	}
+	if details.Fields != nil {
+		if _, err := tx.ExecContext(ctx, `delete from pass_field where pass_id = $1;`, passID); err != nil {
+			log.Println("error in changing password fields:", err)
+			return false, err
+		}
+		if err := insertPasswordFields(ctx, tx, passID, *details.Fields); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("error in changing password info:", err)
+		return false, err
 	}
 	log.Println("Password info is changed.")
-	return nil
+	return true, nil
 }
 
 // DeletePassword removes a password entry by title and by user login.
@@ -74,11 +281,24 @@ func (pr *PasswordRepo) DeletePassword(ctx context.Context, title string, login
 	sqlSt := `delete from pass  
 		where title = $1 and pass.customer_id = (select id from customer c where c.login = $2);`
 
-	_, err := pr.DB.ExecContext(ctx, sqlSt, title, login)
+	tx, err := pr.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	_, err = tx.ExecContext(ctx, sqlSt, title, login)
 	if err != nil {
 		log.Println("error in deleting password:", err)
 		return err
 	}
+	if err := deleteItemShare(ctx, tx, login, KindPassword, title); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Println("error in deleting password:", err)
+		return err
+	}
 
 	log.Println("Password is deleted.")
 	return nil
@@ -105,37 +325,174 @@ func (pr *PasswordRepo) GetPasswordByTitle(
 	return *pwd, nil
 }
 
+// PasswordEntry is a password with all its details; secret values are encrypted.
+type PasswordEntry struct {
+	Password    string
+	Title       string
+	Description string
+	ChangedAt   time.Time
+	PasswordDetails
+}
+
+// GetPasswordDetails returns the password entry with its username, URLs and custom fields by title.
+// It returns nil if the user has no password with the title.
+func (pr *PasswordRepo) GetPasswordDetails(ctx context.Context, title string, login string) (*PasswordEntry, error) {
+	sqlSt := `select pass.id, pwd, title, description, changed_at, username, max_age_days from pass 
+		inner join customer c on c.id = pass.customer_id 
+		where title = $1 and c.login = $2;`
+
+	var (
+		passID int
+		entry  PasswordEntry
+	)
+	err := pr.DB.QueryRowContext(ctx, sqlSt, title, login).Scan(&passID, &entry.Password, &entry.Title,
+		&entry.Description, &entry.ChangedAt, &entry.Username, &entry.MaxAgeDays)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Println("error in scan:", err)
+		return nil, err
+	}
+
+	if err := pr.loadDetails(ctx, passID, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// loadDetails reads the URLs and custom fields of the password entry.
+func (pr *PasswordRepo) loadDetails(ctx context.Context, passID int, entry *PasswordEntry) error {
+	rows, err := pr.DB.QueryContext(ctx,
+		`select url, match_rule from pass_url where pass_id = $1 order by position;`, passID)
+	if err != nil {
+		log.Println("error in getting password urls:", err)
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var u PasswordURL
+		if err := rows.Scan(&u.URL, &u.Match); err != nil {
+			log.Println("error:", err)
+			return err
+		}
+		entry.URLs = append(entry.URLs, u)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = pr.DB.QueryContext(ctx,
+		`select name, field_type, value from pass_field where pass_id = $1 order by position;`, passID)
+	if err != nil {
+		log.Println("error in getting password fields:", err)
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var f PasswordField
+		if err := rows.Scan(&f.Name, &f.Type, &f.Value); err != nil {
+			log.Println("error:", err)
+			return err
+		}
+		entry.Fields = append(entry.Fields, f)
+	}
+	return rows.Err()
+}
+
+// MatchPasswords returns the password entries of the user having a URL that matches
+// the blind index according to the URL match rule, ordered by title.
+func (pr *PasswordRepo) MatchPasswords(ctx context.Context, login string, idx URLIndex) ([]PasswordEntry, error) {
+	sqlSt := `select pass.id, pwd, title, description, changed_at, username, max_age_days from pass 
+		inner join customer c on c.id = pass.customer_id 
+		where c.login = $1 and exists (
+			select 1 from pass_url u where u.pass_id = pass.id and (
+				(u.match_rule = 'domain' and u.domain_index = $2) or
+				(u.match_rule = 'host' and u.host_index = $3) or
+				(u.match_rule = 'exact' and u.url_index = $4)))
+		order by title;`
+
+	rows, err := pr.DB.QueryContext(ctx, sqlSt, login, idx.Domain, idx.Host, idx.URL)
+	if err != nil {
+		log.Println("error in matching passwords:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		ids     []int
+		entries []PasswordEntry
+	)
+	for rows.Next() {
+		var (
+			passID int
+			entry  PasswordEntry
+		)
+		if err := rows.Scan(&passID, &entry.Password, &entry.Title, &entry.Description,
+			&entry.ChangedAt, &entry.Username, &entry.MaxAgeDays); err != nil {
+			log.Println("error:", err)
+			return nil, err
+		}
+		ids = append(ids, passID)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		if err := pr.loadDetails(ctx, ids[i], &entries[i]); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
 type Password struct {
+	ID          int
 	Title       string
 	Description string
+	ChangedAt   time.Time // ChangedAt is the time the password itself was last set.
+	ItemMeta
 }
 
-// GetAllPasswords retrieves a list of all passwords info (title and description) for the specified user.
-func (pr *PasswordRepo) GetAllPasswords(ctx context.Context, login string) ([]Password, error) {
+// GetAllPasswords retrieves a page of passwords info (title, description,
+// time of the last password change, folder, tags and favorite mark) for the specified user.
+// Favorites go first. The returned cursor is not nil if there are more passwords.
+func (pr *PasswordRepo) GetAllPasswords(ctx context.Context, login string, filter ItemFilter,
+	page Page) ([]Password, *ItemCursor, error) {
+
 	pwds := make([]Password, 0)
 
-	sqlSt := `select title, description from pass 
+	where, args := filter.where("pass", []any{login})
+	after, order, args := page.clauses("pass", false, args)
+	sqlSt := `select pass.id, title, description, changed_at, folder, tags, favorite from pass 
 		inner join customer c on c.id = pass.customer_id 
-		where c.login = $1
-		order by pass.title;`
+		where c.login = $1` + where + after + order + `;`
 
-	rows, err := pr.DB.QueryContext(ctx, sqlSt, login)
+	rows, err := pr.DB.QueryContext(ctx, sqlSt, args...)
 	if err != nil || rows.Err() != nil {
 		log.Println("error in getting passwords:", err)
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
 	// пробегаем по всем записям
 	for rows.Next() {
 		var pwd Password
-		err := rows.Scan(&pwd.Title, &pwd.Description)
+		err := rows.Scan(&pwd.ID, &pwd.Title, &pwd.Description, &pwd.ChangedAt,
+			&pwd.Folder, tagsScanner(&pwd.Tags), &pwd.Favorite)
 		if err != nil {
 			log.Println("error:", err)
-			return nil, err
+			return nil, nil, err
 		}
 		pwds = append(pwds, pwd)
 	}
 
-	return pwds, nil
+	next := page.next(len(pwds), func() ItemCursor {
+		pwds = pwds[:page.Limit]
+		last := pwds[len(pwds)-1]
+		return ItemCursor{Favorite: last.Favorite, Key: page.cursorKey(last.ID, last.Title)}
+	})
+	return pwds, next, nil
 }
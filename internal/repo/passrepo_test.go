@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillURLIndexes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`select id, url from pass_url where url_index = ''`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url"}).
+			AddRow(1, "enc-mail").
+			AddRow(2, "broken"))
+	mock.ExpectBegin()
+	mock.ExpectExec(`update pass_url set domain_index = \$2, host_index = \$3, url_index = \$4 where id = \$1`).
+		WithArgs(1, "d-mail", "h-mail", "u-mail").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	index := func(encryptedURL string) (URLIndex, error) {
+		if encryptedURL == "broken" {
+			return URLIndex{}, errors.New("can not decrypt")
+		}
+		return URLIndex{Domain: "d-mail", Host: "h-mail", URL: "u-mail"}, nil
+	}
+
+	pr := NewPasswordRepo(db)
+	n, err := pr.BackfillURLIndexes(context.Background(), index)
+	require.NoError(t, err)
+	// адрес, который не удалось расшифровать, остаётся без индекса
+	require.Equal(t, 1, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// повторный запуск ничего не делает
+	mock.ExpectQuery(`select id, url from pass_url where url_index = ''`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url"}))
+	n, err = pr.BackfillURLIndexes(context.Background(), index)
+	require.NoError(t, err)
+	require.Zero(t, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,84 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+type ReminderRepo struct {
+	DB *sql.DB
+}
+
+func NewReminderRepo(db *sql.DB) *ReminderRepo {
+	return &ReminderRepo{
+		DB: db,
+	}
+}
+
+// CardExpiry is the encrypted expiry date of a card.
+type CardExpiry struct {
+	Title  string
+	Expire string
+}
+
+// GetCardExpiries returns the encrypted expiry dates of all the user cards.
+// They are encrypted, so the dates are compared by the caller.
+func (rr *ReminderRepo) GetCardExpiries(ctx context.Context, login string) ([]CardExpiry, error) {
+	sqlSt := `select title, expires_at from card 
+		inner join customer c on c.id = card.customer_id 
+		where c.login = $1 order by title;`
+
+	rows, err := rr.DB.QueryContext(ctx, sqlSt, login)
+	if err != nil {
+		log.Println("error in getting card expiries:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []CardExpiry
+	for rows.Next() {
+		var card CardExpiry
+		if err := rows.Scan(&card.Title, &card.Expire); err != nil {
+			log.Println("error:", err)
+			return nil, err
+		}
+		res = append(res, card)
+	}
+	return res, rows.Err()
+}
+
+// PasswordRotation is a password due for rotation.
+type PasswordRotation struct {
+	Title string
+	DueAt time.Time // DueAt is the time the password reaches its max age.
+}
+
+// GetPasswordsDue returns the user passwords with a max age set which reach it before the given time,
+// the earliest first.
+func (rr *ReminderRepo) GetPasswordsDue(ctx context.Context, login string, before time.Time) ([]PasswordRotation, error) {
+	sqlSt := `select title, changed_at + max_age_days * interval '1 day' as due_at from pass 
+		inner join customer c on c.id = pass.customer_id 
+		where c.login = $1 and max_age_days > 0 
+			and changed_at + max_age_days * interval '1 day' <= $2
+		order by due_at, title;`
+
+	rows, err := rr.DB.QueryContext(ctx, sqlSt, login, before)
+	if err != nil {
+		log.Println("error in getting passwords due:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []PasswordRotation
+	for rows.Next() {
+		var pwd PasswordRotation
+		if err := rows.Scan(&pwd.Title, &pwd.DueAt); err != nil {
+			log.Println("error:", err)
+			return nil, err
+		}
+		res = append(res, pwd)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,263 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+// ShareRepo keeps the sharing keys of users and the items shared between them.
+//
+// Everything here is encrypted on the client: the private keys with the vault keys of their owners,
+// the shared items with their item keys and the item keys with the public keys of the recipients.
+type ShareRepo struct {
+	DB *sql.DB
+}
+
+func NewShareRepo(db *sql.DB) *ShareRepo {
+	return &ShareRepo{
+		DB: db,
+	}
+}
+
+// UserKeys are the public key and the sealed private key of a user.
+type UserKeys struct {
+	PublicKey  string
+	PrivateKey string
+}
+
+// ShareRecipient is a user an item is shared with and the item key wrapped for the user.
+type ShareRecipient struct {
+	Login      string
+	WrappedKey string
+}
+
+// OwnedShare is an item shared by the user with the logins of its recipients.
+type OwnedShare struct {
+	Kind       ItemKind
+	Title      string
+	Recipients []string
+	UpdatedAt  time.Time
+}
+
+// SharedItem is an item shared with the user.
+type SharedItem struct {
+	Owner     string
+	Kind      ItemKind
+	Title     string
+	UpdatedAt time.Time
+}
+
+// SharedPayload is the encrypted item and the item key wrapped for the recipient.
+type SharedPayload struct {
+	Payload    string
+	WrappedKey string
+	UpdatedAt  time.Time
+}
+
+// GetKeys returns the sharing keys of the user, nil if the user has none.
+func (sr *ShareRepo) GetKeys(ctx context.Context, login string) (*UserKeys, error) {
+	sqlSt := `select public_key, private_key from user_key
+		where customer_id = (select id from customer where login = $1);`
+
+	var keys UserKeys
+	err := sr.DB.QueryRowContext(ctx, sqlSt, login).Scan(&keys.PublicKey, &keys.PrivateKey)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Println("error in scan:", err)
+		return nil, err
+	}
+	return &keys, nil
+}
+
+func (sr *ShareRepo) AddKeys(ctx context.Context, login string, keys UserKeys) error {
+	sqlSt := `insert into user_key (customer_id, public_key, private_key)
+		values ((select id from customer where login = $1), $2, $3);`
+
+	_, err := sr.DB.ExecContext(ctx, sqlSt, login, keys.PublicKey, keys.PrivateKey)
+	if err != nil {
+		log.Println("error in adding keys:", err)
+		return err
+	}
+	log.Println("Keys are added.")
+	return nil
+}
+
+// UpdatePrivateKey replaces the sealed private key of the user, sealed again for the same public key.
+func (sr *ShareRepo) UpdatePrivateKey(ctx context.Context, login string, privateKey string) error {
+	sqlSt := `update user_key set private_key = $2
+		where customer_id = (select id from customer where login = $1);`
+
+	_, err := sr.DB.ExecContext(ctx, sqlSt, login, privateKey)
+	if err != nil {
+		log.Println("error in updating private key:", err)
+		return err
+	}
+	log.Println("Private key is sealed again.")
+	return nil
+}
+
+// PutShare stores the encrypted item and replaces its recipients. The recipients must have
+// sharing keys. Every call comes with a new item key, so recipients left out can not read the item anymore.
+func (sr *ShareRepo) PutShare(ctx context.Context, owner string, kind ItemKind, title string,
+	payload string, recipients []ShareRecipient) error {
+
+	tx, err := sr.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	sqlSt := `insert into item_share (owner_id, kind, title, payload)
+		values ((select id from customer where login = $1), $2, $3, $4)
+		on conflict (owner_id, kind, title) do update set payload = excluded.payload, updated_at = now()
+		returning id;`
+
+	var shareID int
+	err = tx.QueryRowContext(ctx, sqlSt, owner, kind, title, payload).Scan(&shareID)
+	if err != nil {
+		log.Println("error in adding share:", err)
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `delete from share_recipient where share_id = $1;`, shareID); err != nil {
+		log.Println("error in deleting recipients:", err)
+		return err
+	}
+
+	sqlSt = `insert into share_recipient (share_id, customer_id, wrapped_key)
+		values ($1, (select id from customer where login = $2), $3);`
+	for _, rec := range recipients {
+		if _, err := tx.ExecContext(ctx, sqlSt, shareID, rec.Login, rec.WrappedKey); err != nil {
+			log.Println("error in adding recipient:", err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("error in adding share:", err)
+		return err
+	}
+	log.Println("Share is updated.")
+	return nil
+}
+
+// GetShares returns the items shared by the user with their recipients.
+func (sr *ShareRepo) GetShares(ctx context.Context, owner string) ([]OwnedShare, error) {
+	sqlSt := `select s.kind, s.title, s.updated_at, coalesce(c.login, '') from item_share s
+		left join share_recipient r on r.share_id = s.id
+		left join customer c on c.id = r.customer_id
+		where s.owner_id = (select id from customer where login = $1)
+		order by s.kind, s.title, c.login;`
+
+	rows, err := sr.DB.QueryContext(ctx, sqlSt, owner)
+	if err != nil {
+		log.Println("error in getting shares:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]OwnedShare, 0)
+	for rows.Next() {
+		var s OwnedShare
+		var recipient string
+		if err := rows.Scan(&s.Kind, &s.Title, &s.UpdatedAt, &recipient); err != nil {
+			log.Println("error:", err)
+			return nil, err
+		}
+		if n := len(res); n == 0 || res[n-1].Kind != s.Kind || res[n-1].Title != s.Title {
+			s.Recipients = make([]string, 0)
+			res = append(res, s)
+		}
+		if recipient != "" {
+			last := &res[len(res)-1]
+			last.Recipients = append(last.Recipients, recipient)
+		}
+	}
+	return res, rows.Err()
+}
+
+// DeleteShare stops sharing the item. It returns false if the item is not shared.
+func (sr *ShareRepo) DeleteShare(ctx context.Context, owner string, kind ItemKind, title string) (bool, error) {
+	sqlSt := `delete from item_share
+		where owner_id = (select id from customer where login = $1) and kind = $2 and title = $3;`
+
+	res, err := sr.DB.ExecContext(ctx, sqlSt, owner, kind, title)
+	if err != nil {
+		log.Println("error in deleting share:", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return false, nil
+	}
+	log.Println("Share is deleted.")
+	return true, nil
+}
+
+// deleteItemShare stops sharing the item deleted in tx, so that the share does not outlive the item.
+func deleteItemShare(ctx context.Context, tx *sql.Tx, login string, kind ItemKind, title string) error {
+	sqlSt := `delete from item_share
+		where owner_id = (select id from customer where login = $1) and kind = $2 and title = $3;`
+
+	if _, err := tx.ExecContext(ctx, sqlSt, login, kind, title); err != nil {
+		log.Println("error in deleting share:", err)
+		return err
+	}
+	return nil
+}
+
+// GetSharedWithMe returns the items shared with the user.
+func (sr *ShareRepo) GetSharedWithMe(ctx context.Context, login string) ([]SharedItem, error) {
+	sqlSt := `select o.login, s.kind, s.title, s.updated_at from share_recipient r
+		inner join item_share s on s.id = r.share_id
+		inner join customer o on o.id = s.owner_id
+		where r.customer_id = (select id from customer where login = $1)
+		order by o.login, s.kind, s.title;`
+
+	rows, err := sr.DB.QueryContext(ctx, sqlSt, login)
+	if err != nil {
+		log.Println("error in getting shared items:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]SharedItem, 0)
+	for rows.Next() {
+		var s SharedItem
+		if err := rows.Scan(&s.Owner, &s.Kind, &s.Title, &s.UpdatedAt); err != nil {
+			log.Println("error:", err)
+			return nil, err
+		}
+		res = append(res, s)
+	}
+	return res, rows.Err()
+}
+
+// GetSharedItem returns the item shared with the user, nil if there is no such item or it is not shared with the user.
+func (sr *ShareRepo) GetSharedItem(ctx context.Context, login string, owner string, kind ItemKind,
+	title string) (*SharedPayload, error) {
+
+	sqlSt := `select s.payload, r.wrapped_key, s.updated_at from share_recipient r
+		inner join item_share s on s.id = r.share_id
+		where r.customer_id = (select id from customer where login = $1)
+		and s.owner_id = (select id from customer where login = $2)
+		and s.kind = $3 and s.title = $4;`
+
+	var res SharedPayload
+	err := sr.DB.QueryRowContext(ctx, sqlSt, login, owner, kind, title).Scan(&res.Payload, &res.WrappedKey, &res.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Println("error in scan:", err)
+		return nil, err
+	}
+	return &res, nil
+}
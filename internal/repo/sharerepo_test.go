@@ -0,0 +1,75 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteItemDeletesShare(t *testing.T) {
+	tests := []struct {
+		name   string
+		table  string
+		kind   ItemKind
+		delete func(db *sql.DB) error
+	}{
+		{
+			name:  "password",
+			table: "pass",
+			kind:  KindPassword,
+			delete: func(db *sql.DB) error {
+				return NewPasswordRepo(db).DeletePassword(context.Background(), "mail", "alice")
+			},
+		},
+		{
+			name:  "card",
+			table: "card",
+			kind:  KindCard,
+			delete: func(db *sql.DB) error {
+				return NewCardRepo(db).DeleteCardByTitle(context.Background(), "mail", "alice")
+			},
+		},
+		{
+			name:  "identity",
+			table: "identity",
+			kind:  KindIdentity,
+			delete: func(db *sql.DB) error {
+				_, err := NewIdentityRepo(db).DeleteIdentityByTitle(context.Background(), "mail", "alice")
+				return err
+			},
+		},
+		{
+			name:  "ssh key",
+			table: "ssh_key",
+			kind:  KindSSHKey,
+			delete: func(db *sql.DB) error {
+				_, err := NewSSHKeyRepo(db).DeleteSSHKeyByTitle(context.Background(), "mail", "alice")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			// запись и её общий доступ удаляются в одной транзакции
+			mock.ExpectBegin()
+			mock.ExpectExec(`delete from `+tt.table+` `).
+				WithArgs("mail", "alice").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(`delete from item_share`).
+				WithArgs("alice", tt.kind, "mail").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			require.NoError(t, tt.delete(db))
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+type SSHKeyRepo struct {
+	DB *sql.DB
+}
+
+func NewSSHKeyRepo(db *sql.DB) *SSHKeyRepo {
+	return &SSHKeyRepo{
+		DB: db,
+	}
+}
+
+// SSHKey is an SSH key pair. The public key and the fingerprint are stored in clear,
+// the private key is encrypted.
+type SSHKey struct {
+	Title       string
+	Description string
+	KeyType     string
+	PublicKey   string
+	Fingerprint string
+	PrivateKey  string
+	Confirm     bool // Confirm asks the agent to confirm every use of the key.
+}
+
+// AddSSHKey adds a new SSH key to the database for an authenticated user.
+func (sr *SSHKeyRepo) AddSSHKey(ctx context.Context, key SSHKey, login string) error {
+	sqlSt := `insert into ssh_key (title, description, key_type, public_key, fingerprint, private_key, 
+		confirm, customer_id) 
+		values ($1, $2, $3, $4, $5, $6, $7, (select id from customer where login = $8));`
+
+	_, err := sr.DB.ExecContext(ctx, sqlSt, key.Title, key.Description, key.KeyType, key.PublicKey,
+		key.Fingerprint, key.PrivateKey, key.Confirm, login)
+	if err != nil {
+		log.Println("error in adding ssh key:", err)
+		return err
+	}
+	log.Println("SSH key is added.")
+	return nil
+}
+
+type SSHKeyToGet struct {
+	ID          int
+	Title       string
+	Description string
+	KeyType     string
+	PublicKey   string
+	Fingerprint string
+	Confirm     bool
+	ItemMeta
+}
+
+// GetAllSSHKeys retrieves a page of SSH keys without private keys associated with a user login.
+// Favorites go first. The returned cursor is not nil if there are more keys.
+func (sr *SSHKeyRepo) GetAllSSHKeys(ctx context.Context, login string, filter ItemFilter,
+	page Page) ([]SSHKeyToGet, *ItemCursor, error) {
+
+	keys := make([]SSHKeyToGet, 0)
+
+	where, args := filter.where("ssh_key", []any{login})
+	after, order, args := page.clauses("ssh_key", false, args)
+	sqlSt := `select ssh_key.id, title, description, key_type, public_key, fingerprint, confirm, 
+			folder, tags, favorite from ssh_key  
+		inner join customer c on c.id = ssh_key.customer_id
+		where c.login = $1` + where + after + order + `;`
+
+	rows, err := sr.DB.QueryContext(ctx, sqlSt, args...)
+	if err != nil || rows.Err() != nil {
+		log.Println("error in getting ssh keys:", err)
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key SSHKeyToGet
+		err := rows.Scan(&key.ID, &key.Title, &key.Description, &key.KeyType, &key.PublicKey,
+			&key.Fingerprint, &key.Confirm, &key.Folder, tagsScanner(&key.Tags), &key.Favorite)
+		if err != nil {
+			log.Println("error: ", err)
+			return nil, nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	next := page.next(len(keys), func() ItemCursor {
+		keys = keys[:page.Limit]
+		last := keys[len(keys)-1]
+		return ItemCursor{Favorite: last.Favorite, Key: page.cursorKey(last.ID, last.Title)}
+	})
+	return keys, next, nil
+}
+
+// GetSSHKeyByTitle returns the SSH key with the encrypted private key or nil if it is not found.
+func (sr *SSHKeyRepo) GetSSHKeyByTitle(ctx context.Context, title, login string) (*SSHKey, error) {
+	sqlSt := `select title, description, key_type, public_key, fingerprint, private_key, confirm from ssh_key
+		inner join customer c on c.id = ssh_key.customer_id 
+		where ssh_key.title = $1 and c.login = $2;`
+
+	row := sr.DB.QueryRowContext(ctx, sqlSt, title, login)
+
+	var key SSHKey
+
+	err := row.Scan(&key.Title, &key.Description, &key.KeyType, &key.PublicKey, &key.Fingerprint,
+		&key.PrivateKey, &key.Confirm)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Println("error in scan: ", err)
+		return nil, err
+	}
+	return &key, nil
+}
+
+// SSHKeyUpdate holds the SSH key values to change; nil fields remain unchanged.
+// The key itself can not be changed, a new key is added instead.
+type SSHKeyUpdate struct {
+	Description *string `db:"description" goqu:"omitnil"`
+	Confirm     *bool   `db:"confirm" goqu:"omitnil"`
+}
+
+// UpdateSSHKey updates the SSH key description and confirmation by title.
+// It returns false if the user has no such key.
+func (sr *SSHKeyRepo) UpdateSSHKey(ctx context.Context, title string, upd SSHKeyUpdate,
+	login string) (bool, error) {
+
+	if upd.Description == nil && upd.Confirm == nil {
+		return true, nil
+	}
+	customerID := goqu.From("customer").Select("id").Where(goqu.C("login").Eq(login))
+	sqlSt, args, _ := goqu.Update("ssh_key").Set(upd).
+		Where(goqu.C("title").Eq(title), goqu.C("customer_id").Eq(customerID)).ToSQL()
+
+	res, err := sr.DB.ExecContext(ctx, sqlSt, args...)
+	if err != nil {
+		log.Println("error in changing ssh key:", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// DeleteSSHKeyByTitle deletes the SSH key by title.
+// It returns false if the user has no such key.
+func (sr *SSHKeyRepo) DeleteSSHKeyByTitle(ctx context.Context, title, login string) (bool, error) {
+	sqlSt := `delete from ssh_key 
+		where title = $1 and customer_id = (select id from customer c where c.login = $2);`
+
+	tx, err := sr.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	res, err := tx.ExecContext(ctx, sqlSt, title, login)
+	if err != nil {
+		log.Println("error in deleting ssh key:", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return false, nil
+	}
+	if err := deleteItemShare(ctx, tx, login, KindSSHKey, title); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Println("error in deleting ssh key:", err)
+		return false, err
+	}
+	return true, nil
+}
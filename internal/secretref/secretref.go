@@ -0,0 +1,141 @@
+// Package secretref parses references to vault secrets, e.g. keeper://password/prod-db/password,
+// env files whose values may be such references and renders templates with them.
+package secretref
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Scheme is the prefix of a secret reference.
+const Scheme = "keeper://"
+
+// Kinds of referenced items.
+const (
+	KindPassword = "password"
+	KindCard     = "card"
+	KindIdentity = "identity"
+	KindSSHKey   = "sshkey"
+	KindNote     = "note"
+)
+
+// Ref is a reference to a field of a vault item.
+type Ref struct {
+	Kind  string // Kind is empty in the short form: the title is looked up among passwords, then cards.
+	Title string
+	Field string
+}
+
+// IsRef reports whether the value is a secret reference rather than a literal.
+func IsRef(s string) bool {
+	return strings.HasPrefix(s, Scheme)
+}
+
+// Parse parses a reference of the form keeper://<type>/<title>/<field>, where type is
+// password, card, identity, sshkey or note, or of the short form keeper://<title>/<field>.
+// Segments are percent-decoded, so a title with a slash is written as e.g. keeper://password/a%2Fb/password.
+func Parse(s string) (Ref, error) {
+	rest, ok := strings.CutPrefix(s, Scheme)
+	if !ok {
+		return Ref{}, fmt.Errorf("invalid reference %q: must start with %s", s, Scheme)
+	}
+
+	parts := strings.Split(rest, "/")
+	var ref Ref
+	switch len(parts) {
+	case 2:
+	case 3:
+		ref.Kind, parts = parts[0], parts[1:]
+		switch ref.Kind {
+		case KindPassword, KindCard, KindIdentity, KindSSHKey, KindNote:
+		default:
+			return Ref{}, fmt.Errorf("invalid reference %q: unknown type %q", s, ref.Kind)
+		}
+	default:
+		return Ref{}, fmt.Errorf("invalid reference %q: must be %s<type>/<title>/<field>", s, Scheme)
+	}
+
+	var err error
+	if ref.Title, err = url.PathUnescape(parts[0]); err != nil {
+		return Ref{}, fmt.Errorf("invalid reference %q: %w", s, err)
+	}
+	if ref.Field, err = url.PathUnescape(parts[1]); err != nil {
+		return Ref{}, fmt.Errorf("invalid reference %q: %w", s, err)
+	}
+	if ref.Title == "" || ref.Field == "" {
+		return Ref{}, fmt.Errorf("invalid reference %q: title and field must not be empty", s)
+	}
+	return ref, nil
+}
+
+// String returns the reference in the keeper:// form.
+func (r Ref) String() string {
+	s := Scheme
+	if r.Kind != "" {
+		s += r.Kind + "/"
+	}
+	return s + url.PathEscape(r.Title) + "/" + url.PathEscape(r.Field)
+}
+
+// Var is an environment variable whose value is a literal or a secret reference.
+type Var struct {
+	Name  string
+	Value string
+}
+
+var varName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ParseVar parses NAME=value.
+func ParseVar(s string) (Var, error) {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return Var{}, fmt.Errorf("invalid variable %q: must be NAME=value", s)
+	}
+	if !varName.MatchString(name) {
+		return Var{}, fmt.Errorf("invalid variable name %q", name)
+	}
+	return Var{Name: name, Value: value}, nil
+}
+
+// ParseEnvFile parses the env file: one NAME=value per line, with an optional "export " prefix.
+// Blank lines and lines starting with # are skipped. A value may be enclosed in single or double quotes.
+func ParseEnvFile(r io.Reader) ([]Var, error) {
+	var vars []Var
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		v, err := ParseVar(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		if v.Value, err = unquote(strings.TrimSpace(v.Value)); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		vars = append(vars, v)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+func unquote(s string) (string, error) {
+	if len(s) == 0 || (s[0] != '"' && s[0] != '\'') {
+		return s, nil
+	}
+	if len(s) < 2 || s[len(s)-1] != s[0] {
+		return "", errors.New("unterminated quote")
+	}
+	return s[1 : len(s)-1], nil
+}
@@ -0,0 +1,78 @@
+package secretref
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	ref, err := Parse("keeper://prod-db/password")
+	require.NoError(t, err)
+	require.Equal(t, Ref{Title: "prod-db", Field: "password"}, ref)
+	require.Equal(t, "keeper://prod-db/password", ref.String())
+
+	ref, err = Parse("keeper://card/visa/cvc")
+	require.NoError(t, err)
+	require.Equal(t, Ref{Kind: KindCard, Title: "visa", Field: "cvc"}, ref)
+	require.Equal(t, "keeper://card/visa/cvc", ref.String())
+
+	ref, err = Parse("keeper://note/recovery/text")
+	require.NoError(t, err)
+	require.Equal(t, Ref{Kind: KindNote, Title: "recovery", Field: "text"}, ref)
+
+	ref, err = Parse("keeper://work%2Fmail/api%20key")
+	require.NoError(t, err)
+	require.Equal(t, Ref{Title: "work/mail", Field: "api key"}, ref)
+	require.Equal(t, "keeper://work%2Fmail/api%20key", ref.String())
+
+	for _, s := range []string{
+		"prod-db/password",
+		"keeper://prod-db",
+		"keeper://prod-db/",
+		"keeper:///password",
+		"keeper://a/b/c/d",
+		"keeper://file/report/content",
+		"keeper://card//cvc",
+		"keeper://a%zz/password",
+	} {
+		_, err := Parse(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestParseVar(t *testing.T) {
+	v, err := ParseVar("DB_PASS=keeper://prod-db/password")
+	require.NoError(t, err)
+	require.Equal(t, Var{Name: "DB_PASS", Value: "keeper://prod-db/password"}, v)
+
+	v, err = ParseVar("EMPTY=")
+	require.NoError(t, err)
+	require.Equal(t, Var{Name: "EMPTY"}, v)
+
+	_, err = ParseVar("DB_PASS")
+	require.Error(t, err)
+	_, err = ParseVar("1DB=x")
+	require.Error(t, err)
+}
+
+func TestParseEnvFile(t *testing.T) {
+	vars, err := ParseEnvFile(strings.NewReader(`
+# database
+DB_HOST=db.example.com
+export DB_PASS=keeper://prod-db/password
+DB_USER="keeper://prod-db/username"
+GREETING='a # b'
+`))
+	require.NoError(t, err)
+	require.Equal(t, []Var{
+		{Name: "DB_HOST", Value: "db.example.com"},
+		{Name: "DB_PASS", Value: "keeper://prod-db/password"},
+		{Name: "DB_USER", Value: "keeper://prod-db/username"},
+		{Name: "GREETING", Value: "a # b"},
+	}, vars)
+
+	_, err = ParseEnvFile(strings.NewReader("A=1\nB=\"x\n"))
+	require.ErrorContains(t, err, "line 2")
+}
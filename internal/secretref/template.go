@@ -0,0 +1,56 @@
+package secretref
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"text/template"
+)
+
+// ResolveFunc returns the value of the referenced field.
+type ResolveFunc func(Ref) (string, error)
+
+// placeholder matches {{ keeper://... }} with optional trim markers.
+var placeholder = regexp.MustCompile(`\{\{(-?)\s*(keeper://[^\s{}]+)\s*(-?)\}\}`)
+
+// Render renders the Go text/template with secret references. A reference is written either
+// as a placeholder {{ keeper://password/prod-db/password }} or as a call of the secret function,
+// e.g. {{ secret "keeper://password/prod-db/password" | printf "%q" }}.
+// Rendering fails on the first reference that can not be resolved, so partial output is never returned.
+func Render(name, text string, resolve ResolveFunc) ([]byte, error) {
+	text = placeholder.ReplaceAllStringFunc(text, func(s string) string {
+		m := placeholder.FindStringSubmatch(s)
+		left, ref, right := "{{", m[2], "}}"
+		if m[1] != "" {
+			left = "{{- "
+		}
+		if m[3] != "" {
+			right = " -}}"
+		}
+		return left + " secret " + strconv.Quote(ref) + " " + right
+	})
+
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(template.FuncMap{
+		"secret": func(s string) (string, error) {
+			ref, err := Parse(s)
+			if err != nil {
+				return "", err
+			}
+			value, err := resolve(ref)
+			if err != nil {
+				return "", fmt.Errorf("unresolved reference %s: %w", ref, err)
+			}
+			return value, nil
+		},
+	}).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,43 @@
+package secretref
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	secrets := map[string]string{
+		"keeper://password/prod-db/password": `p"ss`,
+		"keeper://password/prod-db/username": "app",
+		"keeper://card/visa/cvc":             "123",
+	}
+	resolve := func(ref Ref) (string, error) {
+		if v, ok := secrets[ref.String()]; ok {
+			return v, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	out, err := Render("config", `db:
+  user: {{ keeper://password/prod-db/username }}
+  password: {{ secret "keeper://password/prod-db/password" | printf "%q" }}
+  cvc: [ {{- keeper://card/visa/cvc -}} ]
+`, resolve)
+	require.NoError(t, err)
+	require.Equal(t, `db:
+  user: app
+  password: "p\"ss"
+  cvc: [123]
+`, string(out))
+
+	_, err = Render("config", `a: {{ keeper://password/missing/password }}`, resolve)
+	require.ErrorContains(t, err, "unresolved reference keeper://password/missing/password")
+
+	_, err = Render("config", `a: {{ keeper://nope }}`, resolve)
+	require.ErrorContains(t, err, "invalid reference")
+
+	_, err = Render("config", `a: {{ .Missing }`, resolve)
+	require.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/adettelle/go-keeper/internal/breach"
+)
+
+// BreachHandlers serve k-anonymity range queries over the local breached passwords corpus.
+// Only a hash prefix is sent by the client, so the server never learns which password is checked.
+type BreachHandlers struct {
+	Corpus IBreachCorpus
+}
+
+func NewBreachHandlers(corpus IBreachCorpus) *BreachHandlers {
+	return &BreachHandlers{
+		Corpus: corpus,
+	}
+}
+
+type IBreachCorpus interface {
+	Range(prefix string) ([]breach.Entry, error)
+}
+
+// BreachRange returns "SUFFIX:COUNT" lines of all hashes starting with the prefix.
+func (bh *BreachHandlers) BreachRange(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	prefix := pathValue(r, "prefix")
+	if !breach.ValidPrefix(prefix) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	entries, err := bh.Corpus.Range(prefix)
+	if err != nil {
+		log.Println("error in breach range lookup: ", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	if err := breach.WriteRange(w, entries); err != nil {
+		log.Println("error in writing breach range: ", err)
+	}
+}
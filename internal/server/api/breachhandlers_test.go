@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adettelle/go-keeper/internal/breach"
+	"github.com/adettelle/go-keeper/mocks"
+	"github.com/carlmjohnson/requests"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// ------- Хендлер: GET /api/breach/range/{prefix}
+func TestBreachRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	corpus := mocks.NewMockIBreachCorpus(ctrl)
+	h := &BreachHandlers{Corpus: corpus}
+
+	corpus.EXPECT().Range("5BAA6").Return([]breach.Entry{
+		{Suffix: "1E4C9B93F3F0682250B6CF8331B7EE68FD8", Count: 9659365},
+		{Suffix: "1E56B40C0FB9F63A6E1C2D1D8F2C6E5A5B0", Count: 2},
+	}, nil)
+
+	request, err := requests.
+		URL("/api/breach/range/5BAA6").
+		Method(http.MethodGet).
+		Request(context.Background())
+	require.NoError(t, err)
+	request.SetPathValue("prefix", "5BAA6")
+
+	response := httptest.NewRecorder()
+	h.BreachRange(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, "1E4C9B93F3F0682250B6CF8331B7EE68FD8:9659365\r\n1E56B40C0FB9F63A6E1C2D1D8F2C6E5A5B0:2\r\n",
+		response.Body.String())
+}
+
+// ------- Хендлер: GET /api/breach/range/{prefix}
+func TestBreachRangeInvalidPrefix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	h := &BreachHandlers{Corpus: mocks.NewMockIBreachCorpus(ctrl)}
+
+	for _, prefix := range []string{"5BAA", "5BAA61", "ZZZZZ"} {
+		request, err := requests.
+			URL("/api/breach/range/" + prefix).
+			Method(http.MethodGet).
+			Request(context.Background())
+		require.NoError(t, err)
+		request.SetPathValue("prefix", prefix)
+
+		response := httptest.NewRecorder()
+		h.BreachRange(response, request)
+
+		require.Equal(t, http.StatusBadRequest, response.Code, prefix)
+	}
+}
@@ -8,8 +8,12 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/adettelle/go-keeper/internal/access"
 	"github.com/adettelle/go-keeper/internal/encryption"
+	"github.com/adettelle/go-keeper/internal/identity"
+	"github.com/adettelle/go-keeper/internal/paycard"
 	"github.com/adettelle/go-keeper/internal/repo"
 	"github.com/adettelle/go-keeper/internal/server/config"
 	"github.com/go-playground/validator/v10"
@@ -30,21 +34,53 @@ func NewCardHandlers(cardRepo ICardRepo, signKey []byte, cfg *config.Config) *Ca
 }
 
 // use a single instance of Validate, it caches struct info
-var validate *validator.Validate = validator.New(validator.WithRequiredStructEnabled())
+var validate *validator.Validate = newValidator()
+
+// newValidator creates the validator with the card_number (brand length and Luhn checksum),
+// card_expiry (MMYY or MM/YY, not in the past), document_number (identity documents)
+// and org_name (organizations and collections) tags.
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	_ = v.RegisterValidation("card_number", func(fl validator.FieldLevel) bool {
+		return paycard.Validate(fl.Field().String()) == nil
+	})
+	_ = v.RegisterValidation("card_expiry", func(fl validator.FieldLevel) bool {
+		_, err := paycard.ValidateExpiry(fl.Field().String(), time.Now())
+		return err == nil
+	})
+	_ = v.RegisterValidation("document_number", func(fl validator.FieldLevel) bool {
+		return identity.ValidDocNumber(fl.Field().String())
+	})
+	_ = v.RegisterValidation("org_name", func(fl validator.FieldLevel) bool {
+		return access.ValidName(fl.Field().String())
+	})
+	return v
+}
+
+// validateCVC checks that the card security code length fits the card brand.
+func validateCVC(num, cvc string) error {
+	if want := paycard.CVCLength(paycard.Detect(num)); len(cvc) != want {
+		return fmt.Errorf("cvc must be %d digits long", want)
+	}
+	return nil
+}
 
 type ICardRepo interface {
-	AddCard(ctx context.Context, cardNum, expire, cvc, title, description string, login string) error
-	GetAllCards(ctx context.Context, login string) ([]repo.CardToGet, error)
+	AddCard(ctx context.Context, card repo.Card, login string) error
+	GetAllCards(ctx context.Context, login string, filter repo.ItemFilter,
+		page repo.Page) ([]repo.CardToGet, *repo.ItemCursor, error)
 	GetCardByTitle(ctx context.Context, cardTitle, login string) (*repo.CardGetByTitle, error)
-	UpdateCard(ctx context.Context, title string, cardNum *string, expire *string,
-		cvc *string, description *string, userID int) error
+	UpdateCard(ctx context.Context, title string, upd repo.CardUpdate, userID int) error
 	DeleteCardByTitle(ctx context.Context, cardTitle, login string) error
 }
 
 type cardCreateRequestDTO struct {
-	Num         string `json:"num" validate:"required,credit_card,len=16"`
-	Expire      string `json:"expires_at" validate:"required,numeric,len=4"`
-	Cvc         string `json:"cvc" validate:"required,numeric,len=3"`
+	Num         string `json:"num" validate:"required,card_number"`
+	Expire      string `json:"expires_at" validate:"required,card_expiry"`
+	Cvc         string `json:"cvc" validate:"required,numeric,min=3,max=4"`
+	Holder      string `json:"cardholder" validate:"max=255"`
+	Address     string `json:"address" validate:"max=1000"`
+	Pin         string `json:"pin" validate:"omitempty,numeric,min=4,max=12"`
 	Title       string `json:"title" validate:"required,min=1"`
 	Description string `json:"description"`
 }
@@ -74,34 +110,40 @@ func (ch *CardHandlers) CardAdd(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	card.Num = paycard.Normalize(card.Num)
 	err = validate.Struct(card)
+	if err == nil {
+		err = validateCVC(card.Num, card.Cvc)
+	}
 	if err != nil {
 		log.Println("error in validating:", err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
-
-	encryptedNum, err := encryption.AESEncrypt(card.Num, ch.SignKey)
-	if err != nil {
-		log.Println("error in encrypting card number:", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-	encryptedExpire, err := encryption.AESEncrypt(card.Expire, ch.SignKey)
-	if err != nil {
-		log.Println("error in encrypting card expire date:", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-	encryptedCvc, err := encryption.AESEncrypt(card.Cvc, ch.SignKey)
-	if err != nil {
-		log.Println("error in encrypting card cvc:", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+	card.Expire, _ = paycard.ValidateExpiry(card.Expire, time.Now()) // канонический вид MM/YY
+
+	toAdd := repo.Card{Title: card.Title, Description: card.Description}
+	for _, v := range []struct {
+		name      string
+		plain     string
+		encrypted *string
+	}{
+		{"number", card.Num, &toAdd.Num},
+		{"expire date", card.Expire, &toAdd.Expire},
+		{"cvc", card.Cvc, &toAdd.Cvc},
+		{"cardholder", card.Holder, &toAdd.Holder},
+		{"address", card.Address, &toAdd.Address},
+		{"pin", card.Pin, &toAdd.Pin},
+	} {
+		*v.encrypted, err = encryption.AESEncrypt(v.plain, ch.SignKey)
+		if err != nil {
+			log.Printf("error in encrypting card %s: %v", v.name, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
 	}
 
-	err = ch.CardRepo.AddCard(context.Background(), encryptedNum, encryptedExpire, encryptedCvc,
-		card.Title, card.Description, userLogin)
+	err = ch.CardRepo.AddCard(context.Background(), toAdd, userLogin)
 
 	if err != nil {
 		log.Println("error in adding card:", err)
@@ -113,25 +155,35 @@ func (ch *CardHandlers) CardAdd(w http.ResponseWriter, r *http.Request) {
 }
 
 type cardGetRequestDTO struct {
-	Num         string `json:"num"`
+	Num         string `json:"num"` // Num is masked, only the last four digits are shown.
+	Brand       string `json:"brand"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
+	ItemMetaDTO
 }
 
-func NewCardDTO(card repo.CardToGet) *cardGetRequestDTO {
+// NewCardDTO creates the list DTO of the card with a decrypted card number.
+func NewCardDTO(card repo.CardToGet, num string) *cardGetRequestDTO {
 	return &cardGetRequestDTO{
-		Num:         card.Num,
+		Num:         paycard.Mask(num),
+		Brand:       string(paycard.Detect(num)),
 		Title:       card.Title,
 		Description: card.Description,
+		ItemMetaDTO: NewItemMetaDTO(card.ItemMeta),
 	}
 }
 
-func NewCardListDTO(cards []repo.CardToGet) []*cardGetRequestDTO {
+// newCardListDTO decrypts the card numbers to mask them and detect the brands.
+func (ch *CardHandlers) newCardListDTO(cards []repo.CardToGet) ([]*cardGetRequestDTO, error) {
 	res := []*cardGetRequestDTO{}
 	for _, card := range cards {
-		res = append(res, NewCardDTO(card))
+		num, err := encryption.AESDecrypt(card.Num, ch.SignKey)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, NewCardDTO(card, num))
 	}
-	return res
+	return res, nil
 }
 
 func (ch *CardHandlers) AllCards(w http.ResponseWriter, r *http.Request) {
@@ -144,19 +196,44 @@ func (ch *CardHandlers) AllCards(w http.ResponseWriter, r *http.Request) {
 
 	userLogin := r.Header.Get("x-user")
 
-	cards, err := ch.CardRepo.GetAllCards(context.Background(), userLogin)
+	filter, err := parseItemFilter(r)
+	if err != nil {
+		log.Println("error in parsing filter:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		log.Println("error in parsing page:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	cards, next, err := ch.CardRepo.GetAllCards(context.Background(), userLogin, filter, page)
 	if err != nil {
 		log.Println("error in getting card by login:", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	resp, err := json.Marshal(NewCardListDTO(cards))
+	list, err := ch.newCardListDTO(cards)
+	if err != nil {
+		log.Println("error in decrypting card number:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := json.Marshal(list)
 	if err != nil {
 		log.Println("error in marshalling json:", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	if err := setNextCursor(w, next); err != nil {
+		log.Println("error in marshalling cursor:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
 
 	_, err = w.Write(resp)
 	if err != nil {
@@ -168,8 +245,12 @@ func (ch *CardHandlers) AllCards(w http.ResponseWriter, r *http.Request) {
 
 type cardGetByTitleRequestDTO struct {
 	Num         string `json:"num"`
+	Brand       string `json:"brand"`
 	Expire      string `json:"expires_at"`
 	Cvc         string `json:"cvc"`
+	Holder      string `json:"cardholder"`
+	Address     string `json:"address"`
+	Pin         string `json:"pin"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
 }
@@ -177,8 +258,12 @@ type cardGetByTitleRequestDTO struct {
 func NewCardGetByTitleDTO(card repo.CardGetByTitle) *cardGetByTitleRequestDTO {
 	return &cardGetByTitleRequestDTO{
 		Num:         card.Num,
+		Brand:       string(paycard.Detect(card.Num)),
 		Expire:      card.Expire,
 		Cvc:         card.Cvc,
+		Holder:      card.Holder,
+		Address:     card.Address,
+		Pin:         card.Pin,
 		Title:       card.Title,
 		Description: card.Description,
 	}
@@ -194,7 +279,7 @@ func (ch *CardHandlers) CardGetByTitle(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	userLogin := r.Header.Get("x-user")
-	title := r.PathValue("title")
+	title := pathValue(r, "title")
 
 	card, err := ch.CardRepo.GetCardByTitle(context.Background(), title, userLogin)
 	if err != nil {
@@ -207,27 +292,24 @@ func (ch *CardHandlers) CardGetByTitle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	decryptedNum, err := encryption.AESDecrypt(card.Num, ch.SignKey)
-	if err != nil {
-		log.Println("error in decrypting card number:", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-	decryptedExpire, err := encryption.AESDecrypt(card.Expire, ch.SignKey)
-	if err != nil {
-		log.Println("error in decrypting card expires date:", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-	decryptedCvc, err := encryption.AESDecrypt(card.Cvc, ch.SignKey)
-	if err != nil {
-		log.Println("error in decrypting card cvc:", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+	for _, v := range []struct {
+		name  string
+		value *string
+	}{
+		{"number", &card.Num},
+		{"expires date", &card.Expire},
+		{"cvc", &card.Cvc},
+		{"cardholder", &card.Holder},
+		{"address", &card.Address},
+		{"pin", &card.Pin},
+	} {
+		*v.value, err = encryption.AESDecrypt(*v.value, ch.SignKey)
+		if err != nil {
+			log.Printf("error in decrypting card %s: %v", v.name, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
 	}
-	card.Num = decryptedNum
-	card.Expire = decryptedExpire
-	card.Cvc = decryptedCvc
 
 	resp, err := json.Marshal(NewCardGetByTitleDTO(*card))
 	if err != nil {
@@ -245,9 +327,12 @@ func (ch *CardHandlers) CardGetByTitle(w http.ResponseWriter, r *http.Request) {
 }
 
 type cardUpdateRequestDTO struct {
-	Num         *string `json:"num,omitempty" validate:"omitnil,omitempty,credit_card,len=16"`   // validate:"omitnil,omitempty,credit_card,len=16"
-	Expire      *string `json:"expires_at,omitempty" validate:"omitempty,omitnil,numeric,len=4"` //  validate:"omitempty,omitnil,numeric,len=4"
-	Cvc         *string `json:"cvc,omitempty" validate:"omitempty,omitnil,numeric,len=3"`        //  validate:"omitempty,omitnil,numeric,len=3"
+	Num         *string `json:"num,omitempty" validate:"omitnil,card_number"`
+	Expire      *string `json:"expires_at,omitempty" validate:"omitnil,card_expiry"`
+	Cvc         *string `json:"cvc,omitempty" validate:"omitnil,numeric,min=3,max=4"`
+	Holder      *string `json:"cardholder,omitempty" validate:"omitnil,max=255"`
+	Address     *string `json:"address,omitempty" validate:"omitnil,max=1000"`
+	Pin         *string `json:"pin,omitempty" validate:"omitnil,omitempty,numeric,min=4,max=12"`
 	Description *string `json:"description,omitempty"`
 }
 
@@ -266,7 +351,7 @@ func (ch *CardHandlers) CardUpdate(w http.ResponseWriter, r *http.Request) {
 
 	var buf bytes.Buffer
 	var card cardUpdateRequestDTO
-	title := r.PathValue("title")
+	title := pathValue(r, "title")
 
 	// читаем тело запроса
 	_, err = buf.ReadFrom(r.Body)
@@ -282,45 +367,51 @@ func (ch *CardHandlers) CardUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fmt.Println("!!!!!!!!!!!", string(buf.Bytes()))
-	// return
+	if card.Num != nil {
+		num := paycard.Normalize(*card.Num)
+		card.Num = &num
+	}
 	err = validate.Struct(card)
+	if err == nil && card.Num != nil && card.Cvc != nil {
+		// длину cvc можно проверить только вместе с номером карты
+		err = validateCVC(*card.Num, *card.Cvc)
+	}
 	if err != nil {
 		log.Println("error in validating:", err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
-	var encryptedNum, encryptedExpire, encryptedCvc *string
-	if card.Num != nil {
-		res, err := encryption.AESEncrypt(*card.Num, ch.SignKey)
-		if err != nil {
-			log.Println("error in encrypting card number:", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-		encryptedNum = &res
-	}
 	if card.Expire != nil {
-		res, err := encryption.AESEncrypt(*card.Expire, ch.SignKey)
-		if err != nil {
-			log.Println("error in encrypting card expires date:", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			return
+		expire, _ := paycard.ValidateExpiry(*card.Expire, time.Now()) // канонический вид MM/YY
+		card.Expire = &expire
+	}
+
+	upd := repo.CardUpdate{Description: card.Description}
+	for _, v := range []struct {
+		name      string
+		plain     *string
+		encrypted **string
+	}{
+		{"number", card.Num, &upd.Num},
+		{"expires date", card.Expire, &upd.Expire},
+		{"cvc", card.Cvc, &upd.Cvc},
+		{"cardholder", card.Holder, &upd.Holder},
+		{"address", card.Address, &upd.Address},
+		{"pin", card.Pin, &upd.Pin},
+	} {
+		if v.plain == nil {
+			continue
 		}
-		encryptedExpire = &res
-	}
-	if card.Cvc != nil {
-		res, err := encryption.AESEncrypt(*card.Cvc, ch.SignKey)
+		res, err := encryption.AESEncrypt(*v.plain, ch.SignKey)
 		if err != nil {
-			log.Println("error in encrypting card cvc:", err)
+			log.Printf("error in encrypting card %s: %v", v.name, err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
-		encryptedCvc = &res
+		*v.encrypted = &res
 	}
 
-	err = ch.CardRepo.UpdateCard(context.Background(), title, encryptedNum, encryptedExpire,
-		encryptedCvc, card.Description, custID) // card.Num, card.Expire, card.Cvc
+	err = ch.CardRepo.UpdateCard(context.Background(), title, upd, custID)
 	if err != nil {
 		log.Println("error in updating card:", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -335,7 +426,7 @@ func (ch *CardHandlers) CardDeleteByTitle(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	cardTitle := r.PathValue("title")
+	cardTitle := pathValue(r, "title")
 	userLogin := r.Header.Get("x-user")
 
 	err := ch.CardRepo.DeleteCardByTitle(context.Background(), cardTitle, userLogin)
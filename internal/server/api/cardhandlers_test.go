@@ -44,12 +44,13 @@ func TestCardAdd(t *testing.T) {
 	}
 	encryptedNum, err := encryption.AESEncrypt(card.Num, h.SignKey)
 	require.NoError(t, err)
-	encryptedExpire, err := encryption.AESEncrypt(card.Expire, h.SignKey)
+	encryptedExpire, err := encryption.AESEncrypt("01/30", h.SignKey) // срок хранится в виде MM/YY
 	require.NoError(t, err)
 	encryptedCvc, err := encryption.AESEncrypt(card.Cvc, h.SignKey)
 	require.NoError(t, err)
-	cardRepo.EXPECT().AddCard(gomock.Any(), encryptedNum, encryptedExpire, encryptedCvc,
-		card.Title, card.Description, login).Return(nil)
+	wantCard := repo.Card{Num: encryptedNum, Expire: encryptedExpire, Cvc: encryptedCvc,
+		Title: card.Title, Description: card.Description}
+	cardRepo.EXPECT().AddCard(gomock.Any(), wantCard, login).Return(nil)
 
 	request, err := requests.
 		URL("/api/user/card").
@@ -93,13 +94,14 @@ func TestCardAddFailure(t *testing.T) {
 	}
 	encryptedNum, err := encryption.AESEncrypt(card.Num, h.SignKey)
 	require.NoError(t, err)
-	encryptedExpire, err := encryption.AESEncrypt(card.Expire, h.SignKey)
+	encryptedExpire, err := encryption.AESEncrypt("01/30", h.SignKey) // срок хранится в виде MM/YY
 	require.NoError(t, err)
 	encryptedCvc, err := encryption.AESEncrypt(card.Cvc, h.SignKey)
 	require.NoError(t, err)
+	wantCard := repo.Card{Num: encryptedNum, Expire: encryptedExpire, Cvc: encryptedCvc,
+		Title: card.Title, Description: card.Description}
 
-	cardRepo.EXPECT().AddCard(gomock.Any(), encryptedNum, encryptedExpire, encryptedCvc,
-		card.Title, card.Description, login).Return(fmt.Errorf("error in DB"))
+	cardRepo.EXPECT().AddCard(gomock.Any(), wantCard, login).Return(fmt.Errorf("error in DB"))
 
 	request, err := requests.
 		URL("/api/user/card").
@@ -134,19 +136,26 @@ func TestAllCards(t *testing.T) {
 	login := "Ane"
 	userID := 123
 
+	nums := []string{"6011000990139424", "3530111333300000"}
+	encryptedNums := make([]string, len(nums))
+	for i, num := range nums {
+		encrypted, err := encryption.AESEncrypt(num, h.SignKey)
+		require.NoError(t, err)
+		encryptedNums[i] = encrypted
+	}
 	cards := []repo.CardToGet{
 		{
-			Num:         "6011000990139424",
+			Num:         encryptedNums[0],
 			Title:       "bank1",
 			Description: "description1",
 		},
 		{
-			Num:         "3530111333300000",
+			Num:         encryptedNums[1],
 			Title:       "banc2",
 			Description: "description2",
 		},
 	}
-	cardRepo.EXPECT().GetAllCards(gomock.Any(), login).Return(cards, nil)
+	cardRepo.EXPECT().GetAllCards(gomock.Any(), login, repo.ItemFilter{}, repo.Page{}).Return(cards, nil, nil)
 
 	request, err := requests.
 		URL("/api/user/cards").
@@ -168,9 +177,12 @@ func TestAllCards(t *testing.T) {
 	resBody, err := io.ReadAll(response.Body)
 	require.NoError(t, err)
 
-	expectedBody, err := json.Marshal(NewCardListDTO(cards))
+	expectedBody, err := json.Marshal([]*cardGetRequestDTO{NewCardDTO(cards[0], nums[0]), NewCardDTO(cards[1], nums[1])})
 	require.NoError(t, err)
 	assert.JSONEq(t, string(expectedBody), string(resBody))
+	// номера карт выводятся только в маскированном виде
+	assert.Contains(t, string(resBody), `"num":"**** 9424","brand":"discover"`)
+	assert.NotContains(t, string(resBody), nums[0])
 }
 
 // ------- Хендлер: GET /api/user/cards
@@ -189,7 +201,7 @@ func TestAllCardsFailure(t *testing.T) {
 	login := "Ane"
 	userID := 123
 
-	cardRepo.EXPECT().GetAllCards(gomock.Any(), login).Return(nil, fmt.Errorf("DB error"))
+	cardRepo.EXPECT().GetAllCards(gomock.Any(), login, repo.ItemFilter{}, repo.Page{}).Return(nil, nil, fmt.Errorf("DB error"))
 
 	request, err := requests.
 		URL("/api/user/cards").
@@ -379,13 +391,14 @@ func TestCardUpdate(t *testing.T) {
 	}
 	encryptedNum, err := encryption.AESEncrypt(*card.Num, h.SignKey)
 	require.NoError(t, err)
-	encryptedExpire, err := encryption.AESEncrypt(*card.Expire, h.SignKey)
+	encryptedExpire, err := encryption.AESEncrypt("01/41", h.SignKey)
 	require.NoError(t, err)
 	encryptedCvc, err := encryption.AESEncrypt(*card.Cvc, h.SignKey)
 	require.NoError(t, err)
+	wantUpdate := repo.CardUpdate{Num: &encryptedNum, Expire: &encryptedExpire, Cvc: &encryptedCvc,
+		Description: card.Description}
 
-	cardRepo.EXPECT().UpdateCard(gomock.Any(), title, &encryptedNum, &encryptedExpire,
-		&encryptedCvc, card.Description, userID).Return(nil)
+	cardRepo.EXPECT().UpdateCard(gomock.Any(), title, wantUpdate, userID).Return(nil)
 
 	request, err := requests.
 		URL("/api/user/card/update/"+title).
@@ -434,13 +447,14 @@ func TestCardUpdateFailure(t *testing.T) {
 	}
 	encryptedNum, err := encryption.AESEncrypt(*card.Num, h.SignKey)
 	require.NoError(t, err)
-	encryptedExpire, err := encryption.AESEncrypt(*card.Expire, h.SignKey)
+	encryptedExpire, err := encryption.AESEncrypt("01/41", h.SignKey)
 	require.NoError(t, err)
 	encryptedCvc, err := encryption.AESEncrypt(*card.Cvc, h.SignKey)
 	require.NoError(t, err)
+	wantUpdate := repo.CardUpdate{Num: &encryptedNum, Expire: &encryptedExpire, Cvc: &encryptedCvc,
+		Description: card.Description}
 
-	cardRepo.EXPECT().UpdateCard(gomock.Any(), title, &encryptedNum, &encryptedExpire,
-		&encryptedCvc, card.Description, userID).Return(fmt.Errorf("DB error"))
+	cardRepo.EXPECT().UpdateCard(gomock.Any(), title, wantUpdate, userID).Return(fmt.Errorf("DB error"))
 
 	request, err := requests.
 		URL("/api/user/card/update/"+title).
@@ -529,3 +543,53 @@ func TestCardDeleteFailure(t *testing.T) {
 	h.CardDeleteByTitle(response, request)
 	require.Equal(t, wantHTTPStatus, response.Code)
 }
+
+// ------- Хендлер: PUT /api/user/card (проверка номера, срока и cvc)
+func TestCardAddValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		num        string
+		expire     string
+		cvc        string
+		pin        string
+		wantStatus int
+	}{
+		{name: "amex 15 digits", num: "3782 822463 10005", expire: "12/40", cvc: "1234", wantStatus: http.StatusAccepted},
+		{name: "visa 19 digits", num: "4000000000000000006", expire: "1240", cvc: "123", wantStatus: http.StatusAccepted},
+		{name: "with pin", num: "4242424242424242", expire: "12/40", cvc: "123", pin: "1234", wantStatus: http.StatusAccepted},
+		{name: "amex with 3 digit cvc", num: "378282246310005", expire: "12/40", cvc: "123", wantStatus: http.StatusBadRequest},
+		{name: "bad checksum", num: "4242424242424241", expire: "12/40", cvc: "123", wantStatus: http.StatusBadRequest},
+		{name: "expired", num: "4242424242424242", expire: "01/20", cvc: "123", wantStatus: http.StatusBadRequest},
+		{name: "bad month", num: "4242424242424242", expire: "13/40", cvc: "123", wantStatus: http.StatusBadRequest},
+		{name: "short pin", num: "4242424242424242", expire: "12/40", cvc: "123", pin: "12", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			cardRepo := mocks.NewMockICardRepo(ctrl)
+			h := &CardHandlers{
+				CardRepo: cardRepo,
+				SignKey:  []byte("my_super_secret_key"),
+			}
+			if tt.wantStatus == http.StatusAccepted {
+				cardRepo.EXPECT().AddCard(gomock.Any(), gomock.Any(), "Ane").Return(nil)
+			}
+
+			card := cardCreateRequestDTO{Num: tt.num, Expire: tt.expire, Cvc: tt.cvc, Pin: tt.pin, Title: "bank"}
+
+			request, err := requests.
+				URL("/api/user/card").
+				Method(http.MethodPut).
+				BodyJSON(&card).
+				Header("x-user", "Ane").
+				Request(context.Background())
+			require.NoError(t, err)
+
+			response := httptest.NewRecorder()
+			h.CardAdd(response, request)
+
+			require.Equal(t, tt.wantStatus, response.Code)
+		})
+	}
+}
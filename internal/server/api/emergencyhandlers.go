@@ -0,0 +1,383 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/emergency"
+	"github.com/adettelle/go-keeper/internal/repo"
+)
+
+type IEmergencyRepo interface {
+	GetContact(ctx context.Context, owner string, contact string) (*repo.TrustedContact, error)
+	GetContacts(ctx context.Context, owner string) ([]repo.TrustedContact, error)
+	GetGrantors(ctx context.Context, contact string) ([]repo.TrustedContact, error)
+	PutContact(ctx context.Context, owner string, contact string, wait time.Duration, kit repo.EmergencyKit) (bool, error)
+	DeleteContact(ctx context.Context, owner string, contact string) (bool, error)
+	RequestAccess(ctx context.Context, owner string, contact string) (bool, error)
+	RejectAccess(ctx context.Context, owner string, contact string) (bool, error)
+	GrantDue(ctx context.Context, login string) (int, error)
+	TakeKit(ctx context.Context, owner string, contact string) (*repo.EmergencyKit, error)
+	GetEvents(ctx context.Context, login string, limit int) ([]repo.EmergencyEvent, error)
+	TakeNotifications(ctx context.Context, login string) ([]repo.EmergencyEvent, error)
+}
+
+// emergencyEventsLimit is how many of the latest events the history shows.
+const emergencyEventsLimit = 100
+
+// EmergencyHandlers manage trusted contacts and their emergency access to the vault of the user.
+// The server enforces the waiting period: the emergency kit, encrypted on the client of the owner,
+// is only given to the contact once access is granted. Every step is recorded and notified.
+type EmergencyHandlers struct {
+	EmergencyRepo IEmergencyRepo
+	ShareRepo     IShareRepo
+}
+
+func NewEmergencyHandlers(emergencyRepo IEmergencyRepo, shareRepo IShareRepo) *EmergencyHandlers {
+	return &EmergencyHandlers{
+		EmergencyRepo: emergencyRepo,
+		ShareRepo:     shareRepo,
+	}
+}
+
+type contactPutRequestDTO struct {
+	Login       string `json:"login" validate:"required"`
+	WaitSeconds int    `json:"wait_seconds" validate:"required"`
+	Payload     string `json:"payload" validate:"required"`
+	WrappedKey  string `json:"wrapped_key" validate:"required"`
+}
+
+// ContactPut designates the trusted contact or renews its kit and waiting period.
+// The contact must have sharing keys: the kit key is wrapped for its public key.
+func (eh *EmergencyHandlers) ContactPut(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	userLogin := r.Header.Get("x-user")
+
+	var contact contactPutRequestDTO
+	if !readJSON(w, r, &contact) {
+		return
+	}
+	wait := time.Duration(contact.WaitSeconds) * time.Second
+	if err := emergency.ValidWait(wait); err != nil {
+		log.Println("error in validating:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if contact.Login == userLogin {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	keys, err := eh.ShareRepo.GetKeys(context.Background(), contact.Login)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if keys == nil {
+		log.Printf("%s has no sharing keys", contact.Login)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	created, err := eh.EmergencyRepo.PutContact(context.Background(), userLogin, contact.Login, wait,
+		repo.EmergencyKit{Payload: contact.Payload, WrappedKey: contact.WrappedKey})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if created {
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+type trustedContactResponseDTO struct {
+	Owner       string           `json:"owner"`
+	Contact     string           `json:"contact"`
+	WaitSeconds int              `json:"wait_seconds"`
+	Status      emergency.Status `json:"status"`
+	RequestedAt *time.Time       `json:"requested_at,omitempty"`
+	GrantAt     *time.Time       `json:"grant_at,omitempty"` // GrantAt is when a pending request is granted.
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// grantDue records the grants of the requests of the user whose waiting period has passed, so that
+// a grant is recorded and notified once it takes effect, whoever of the two parties comes first.
+// It writes the error status and returns false if it fails.
+func (eh *EmergencyHandlers) grantDue(w http.ResponseWriter, login string) bool {
+	if _, err := eh.EmergencyRepo.GrantDue(context.Background(), login); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+func newTrustedContactListDTO(contacts []repo.TrustedContact, now time.Time) []trustedContactResponseDTO {
+	res := make([]trustedContactResponseDTO, 0, len(contacts))
+	for _, tc := range contacts {
+		dto := trustedContactResponseDTO{
+			Owner:       tc.Owner,
+			Contact:     tc.Contact,
+			WaitSeconds: int(tc.Wait.Seconds()),
+			Status:      emergency.Effective(tc.Status, tc.RequestedAt, tc.Wait, now),
+			RequestedAt: tc.RequestedAt,
+			UpdatedAt:   tc.UpdatedAt,
+		}
+		if tc.RequestedAt != nil {
+			grantAt := tc.RequestedAt.Add(tc.Wait)
+			dto.GrantAt = &grantAt
+		}
+		res = append(res, dto)
+	}
+	return res
+}
+
+// AllContacts lists the trusted contacts of the user with the state of their access.
+func (eh *EmergencyHandlers) AllContacts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if !eh.grantDue(w, r.Header.Get("x-user")) {
+		return
+	}
+
+	contacts, err := eh.EmergencyRepo.GetContacts(context.Background(), r.Header.Get("x-user"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, newTrustedContactListDTO(contacts, time.Now()))
+}
+
+// ContactDelete removes the trusted contact with its kit.
+func (eh *EmergencyHandlers) ContactDelete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	found, err := eh.EmergencyRepo.DeleteContact(context.Background(), r.Header.Get("x-user"), pathValue(r, "login"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// AccessReject rejects the pending request of the contact during the waiting period. Once the waiting
+// period has passed the access is granted and recorded first, and can not be rejected anymore:
+// the owner removes the contact to end it.
+func (eh *EmergencyHandlers) AccessReject(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	userLogin := r.Header.Get("x-user")
+	login := pathValue(r, "login")
+
+	if !eh.grantDue(w, userLogin) {
+		return
+	}
+
+	contact, err := eh.EmergencyRepo.GetContact(context.Background(), userLogin, login)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if contact == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if contact.Status != emergency.Requested {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	rejected, err := eh.EmergencyRepo.RejectAccess(context.Background(), userLogin, login)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !rejected {
+		// the waiting period has passed meanwhile
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// AllGrantors lists the users who designated the user as their trusted contact.
+func (eh *EmergencyHandlers) AllGrantors(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if !eh.grantDue(w, r.Header.Get("x-user")) {
+		return
+	}
+
+	grantors, err := eh.EmergencyRepo.GetGrantors(context.Background(), r.Header.Get("x-user"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, newTrustedContactListDTO(grantors, time.Now()))
+}
+
+// AccessRequest starts the waiting period for the user as a trusted contact of the owner.
+func (eh *EmergencyHandlers) AccessRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	userLogin := r.Header.Get("x-user")
+	owner := pathValue(r, "owner")
+
+	contact, err := eh.EmergencyRepo.GetContact(context.Background(), owner, userLogin)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if contact == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if contact.Status != emergency.Idle {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	_, err = eh.EmergencyRepo.RequestAccess(context.Background(), owner, userLogin)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+type emergencyKitResponseDTO struct {
+	Payload    string `json:"payload"`
+	WrappedKey string `json:"wrapped_key"`
+}
+
+// KitGet gives the emergency kit of the owner to the user once the access is granted.
+// It is forbidden while the waiting period lasts or without a request.
+func (eh *EmergencyHandlers) KitGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	userLogin := r.Header.Get("x-user")
+	owner := pathValue(r, "owner")
+
+	if !eh.grantDue(w, userLogin) {
+		return
+	}
+
+	contact, err := eh.EmergencyRepo.GetContact(context.Background(), owner, userLogin)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if contact == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if emergency.Effective(contact.Status, contact.RequestedAt, contact.Wait, time.Now()) != emergency.Granted {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	kit, err := eh.EmergencyRepo.TakeKit(context.Background(), owner, userLogin)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if kit == nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	writeJSON(w, emergencyKitResponseDTO{Payload: kit.Payload, WrappedKey: kit.WrappedKey})
+}
+
+type emergencyEventResponseDTO struct {
+	Owner     string          `json:"owner"`
+	Contact   string          `json:"contact"`
+	Event     emergency.Event `json:"event"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func newEmergencyEventListDTO(events []repo.EmergencyEvent) []emergencyEventResponseDTO {
+	res := make([]emergencyEventResponseDTO, 0, len(events))
+	for _, e := range events {
+		res = append(res, emergencyEventResponseDTO{Owner: e.Owner, Contact: e.Contact, Event: e.Event, CreatedAt: e.CreatedAt})
+	}
+	return res
+}
+
+// Events lists the latest steps of emergency access of the user as an owner or as a contact, newest first.
+func (eh *EmergencyHandlers) Events(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if !eh.grantDue(w, r.Header.Get("x-user")) {
+		return
+	}
+
+	events, err := eh.EmergencyRepo.GetEvents(context.Background(), r.Header.Get("x-user"), emergencyEventsLimit)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, newEmergencyEventListDTO(events))
+}
+
+// Notifications returns the steps of emergency access the user has not been notified of yet.
+// Each one is returned once, so it is a POST.
+func (eh *EmergencyHandlers) Notifications(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if !eh.grantDue(w, r.Header.Get("x-user")) {
+		return
+	}
+
+	events, err := eh.EmergencyRepo.TakeNotifications(context.Background(), r.Header.Get("x-user"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, newEmergencyEventListDTO(events))
+}
@@ -0,0 +1,276 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/emergency"
+	"github.com/adettelle/go-keeper/internal/repo"
+	"github.com/adettelle/go-keeper/mocks"
+	"github.com/carlmjohnson/requests"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// --------------------------------------- emergency access ---------------------------------------
+// ------- Хендлер: PUT /api/user/emergency/contact
+func TestContactPut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	emergencyRepo := mocks.NewMockIEmergencyRepo(ctrl)
+	shareRepo := mocks.NewMockIShareRepo(ctrl)
+	h := &EmergencyHandlers{EmergencyRepo: emergencyRepo, ShareRepo: shareRepo}
+
+	kit := repo.EmergencyKit{Payload: "ciphertext", WrappedKey: "wrapped"}
+	shareRepo.EXPECT().GetKeys(gomock.Any(), "bob@aaa.com").
+		Return(&repo.UserKeys{PublicKey: "pub", PrivateKey: "sealed"}, nil).Times(2)
+	gomock.InOrder(
+		emergencyRepo.EXPECT().PutContact(gomock.Any(), "ane@aaa.com", "bob@aaa.com", 72*time.Hour, kit).Return(true, nil),
+		emergencyRepo.EXPECT().PutContact(gomock.Any(), "ane@aaa.com", "bob@aaa.com", 72*time.Hour, kit).Return(false, nil),
+	)
+	shareRepo.EXPECT().GetKeys(gomock.Any(), "eve@aaa.com").Return(nil, nil)
+
+	newRequest := func(login string, wait time.Duration) *http.Request {
+		request, err := requests.
+			URL("/api/user/emergency/contact").
+			Method(http.MethodPut).
+			Header("x-user", "ane@aaa.com").
+			BodyJSON(&contactPutRequestDTO{Login: login, WaitSeconds: int(wait.Seconds()),
+				Payload: "ciphertext", WrappedKey: "wrapped"}).
+			Request(context.Background())
+		require.NoError(t, err)
+		return request
+	}
+
+	response := httptest.NewRecorder()
+	h.ContactPut(response, newRequest("bob@aaa.com", 72*time.Hour))
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	response = httptest.NewRecorder()
+	h.ContactPut(response, newRequest("bob@aaa.com", 72*time.Hour))
+	require.Equal(t, http.StatusOK, response.Code)
+
+	// контакт без ключей не может получить набор
+	response = httptest.NewRecorder()
+	h.ContactPut(response, newRequest("eve@aaa.com", 72*time.Hour))
+	require.Equal(t, http.StatusNotFound, response.Code)
+
+	// срок ожидания ограничен, себя назначить нельзя
+	for _, request := range []*http.Request{
+		newRequest("bob@aaa.com", time.Minute),
+		newRequest("bob@aaa.com", 365*24*time.Hour),
+		newRequest("ane@aaa.com", 72*time.Hour),
+	} {
+		response = httptest.NewRecorder()
+		h.ContactPut(response, request)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+	}
+}
+
+// ------- Хендлер: GET /api/user/emergency/contacts
+func TestAllContacts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	emergencyRepo := mocks.NewMockIEmergencyRepo(ctrl)
+	h := &EmergencyHandlers{EmergencyRepo: emergencyRepo}
+
+	requestedAt := time.Now().Add(-2 * time.Hour).UTC()
+	gomock.InOrder(
+		// истёкшие запросы открываются до выдачи списка
+		emergencyRepo.EXPECT().GrantDue(gomock.Any(), "ane@aaa.com").Return(0, nil),
+		emergencyRepo.EXPECT().GetContacts(gomock.Any(), "ane@aaa.com").Return([]repo.TrustedContact{
+			{Owner: "ane@aaa.com", Contact: "bob@aaa.com", Wait: time.Hour, Status: emergency.Requested, RequestedAt: &requestedAt},
+			{Owner: "ane@aaa.com", Contact: "tom@aaa.com", Wait: time.Hour, Status: emergency.Idle},
+		}, nil),
+	)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/user/emergency/contacts", nil)
+	request.Header.Set("x-user", "ane@aaa.com")
+	response := httptest.NewRecorder()
+	h.AllContacts(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var contacts []trustedContactResponseDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &contacts))
+	require.Len(t, contacts, 2)
+	// срок ожидания истёк — доступ уже считается открытым
+	require.Equal(t, emergency.Granted, contacts[0].Status)
+	require.Equal(t, 3600, contacts[0].WaitSeconds)
+	require.NotNil(t, contacts[0].GrantAt)
+	require.True(t, requestedAt.Add(time.Hour).Equal(*contacts[0].GrantAt))
+	require.Equal(t, emergency.Idle, contacts[1].Status)
+	require.Nil(t, contacts[1].GrantAt)
+}
+
+// ------- Хендлер: POST /api/user/emergency/contact/{login}/reject
+func TestAccessReject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	emergencyRepo := mocks.NewMockIEmergencyRepo(ctrl)
+	h := &EmergencyHandlers{EmergencyRepo: emergencyRepo}
+
+	requestedAt := time.Now()
+	passed := time.Now().Add(-2 * time.Hour)
+	// истёкшие запросы сначала открываются
+	emergencyRepo.EXPECT().GrantDue(gomock.Any(), "ane@aaa.com").Return(0, nil).Times(4)
+	gomock.InOrder(
+		emergencyRepo.EXPECT().GetContact(gomock.Any(), "ane@aaa.com", "bob@aaa.com").
+			Return(&repo.TrustedContact{Status: emergency.Requested, RequestedAt: &requestedAt, Wait: time.Hour}, nil),
+		emergencyRepo.EXPECT().RejectAccess(gomock.Any(), "ane@aaa.com", "bob@aaa.com").Return(true, nil),
+		// срок ожидания истёк, доступ уже открыт
+		emergencyRepo.EXPECT().GetContact(gomock.Any(), "ane@aaa.com", "bob@aaa.com").
+			Return(&repo.TrustedContact{Status: emergency.Granted, RequestedAt: &passed, Wait: time.Hour}, nil),
+	)
+	emergencyRepo.EXPECT().GetContact(gomock.Any(), "ane@aaa.com", "tom@aaa.com").
+		Return(&repo.TrustedContact{Status: emergency.Idle, Wait: time.Hour}, nil)
+	emergencyRepo.EXPECT().GetContact(gomock.Any(), "ane@aaa.com", "eve@aaa.com").Return(nil, nil)
+
+	newRequest := func(login string) *http.Request {
+		request := httptest.NewRequest(http.MethodPost, "/api/user/emergency/contact/"+login+"/reject", nil)
+		request.Header.Set("x-user", "ane@aaa.com")
+		request.SetPathValue("login", login)
+		return request
+	}
+
+	response := httptest.NewRecorder()
+	h.AccessReject(response, newRequest("bob@aaa.com"))
+	require.Equal(t, http.StatusOK, response.Code)
+
+	response = httptest.NewRecorder()
+	h.AccessReject(response, newRequest("bob@aaa.com"))
+	require.Equal(t, http.StatusConflict, response.Code)
+
+	// нечего отклонять
+	response = httptest.NewRecorder()
+	h.AccessReject(response, newRequest("tom@aaa.com"))
+	require.Equal(t, http.StatusConflict, response.Code)
+
+	response = httptest.NewRecorder()
+	h.AccessReject(response, newRequest("eve@aaa.com"))
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
+
+// ------- Хендлер: POST /api/user/emergency/grantor/{owner}/request
+func TestAccessRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	emergencyRepo := mocks.NewMockIEmergencyRepo(ctrl)
+	h := &EmergencyHandlers{EmergencyRepo: emergencyRepo}
+
+	requestedAt := time.Now()
+	gomock.InOrder(
+		emergencyRepo.EXPECT().GetContact(gomock.Any(), "ane@aaa.com", "bob@aaa.com").
+			Return(&repo.TrustedContact{Status: emergency.Idle, Wait: time.Hour}, nil),
+		emergencyRepo.EXPECT().RequestAccess(gomock.Any(), "ane@aaa.com", "bob@aaa.com").Return(true, nil),
+		emergencyRepo.EXPECT().GetContact(gomock.Any(), "ane@aaa.com", "bob@aaa.com").
+			Return(&repo.TrustedContact{Status: emergency.Requested, RequestedAt: &requestedAt, Wait: time.Hour}, nil),
+	)
+	emergencyRepo.EXPECT().GetContact(gomock.Any(), "tom@aaa.com", "bob@aaa.com").Return(nil, nil)
+
+	newRequest := func(owner string) *http.Request {
+		request := httptest.NewRequest(http.MethodPost, "/api/user/emergency/grantor/"+owner+"/request", nil)
+		request.Header.Set("x-user", "bob@aaa.com")
+		request.SetPathValue("owner", owner)
+		return request
+	}
+
+	response := httptest.NewRecorder()
+	h.AccessRequest(response, newRequest("ane@aaa.com"))
+	require.Equal(t, http.StatusOK, response.Code)
+
+	// повторный запрос не сбрасывает срок ожидания
+	response = httptest.NewRecorder()
+	h.AccessRequest(response, newRequest("ane@aaa.com"))
+	require.Equal(t, http.StatusConflict, response.Code)
+
+	// пользователь не назначил bob доверенным контактом
+	response = httptest.NewRecorder()
+	h.AccessRequest(response, newRequest("tom@aaa.com"))
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
+
+// ------- Хендлер: GET /api/user/emergency/grantor/{owner}/kit
+func TestKitGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	emergencyRepo := mocks.NewMockIEmergencyRepo(ctrl)
+	h := &EmergencyHandlers{EmergencyRepo: emergencyRepo}
+
+	pending := time.Now().Add(-30 * time.Minute)
+	passed := time.Now().Add(-2 * time.Hour)
+	gomock.InOrder(
+		// срок ожидания ещё не истёк
+		emergencyRepo.EXPECT().GrantDue(gomock.Any(), "bob@aaa.com").Return(0, nil),
+		emergencyRepo.EXPECT().GetContact(gomock.Any(), "ane@aaa.com", "bob@aaa.com").
+			Return(&repo.TrustedContact{Status: emergency.Requested, RequestedAt: &pending, Wait: time.Hour}, nil),
+		// срок истёк — доступ открывается и просмотр записывается
+		emergencyRepo.EXPECT().GrantDue(gomock.Any(), "bob@aaa.com").Return(1, nil),
+		emergencyRepo.EXPECT().GetContact(gomock.Any(), "ane@aaa.com", "bob@aaa.com").
+			Return(&repo.TrustedContact{Status: emergency.Granted, RequestedAt: &passed, Wait: time.Hour}, nil),
+		emergencyRepo.EXPECT().TakeKit(gomock.Any(), "ane@aaa.com", "bob@aaa.com").
+			Return(&repo.EmergencyKit{Payload: "ciphertext", WrappedKey: "wrapped"}, nil),
+		// без запроса доступа нет
+		emergencyRepo.EXPECT().GrantDue(gomock.Any(), "bob@aaa.com").Return(0, nil),
+		emergencyRepo.EXPECT().GetContact(gomock.Any(), "ane@aaa.com", "bob@aaa.com").
+			Return(&repo.TrustedContact{Status: emergency.Idle, Wait: time.Hour}, nil),
+		emergencyRepo.EXPECT().GrantDue(gomock.Any(), "bob@aaa.com").Return(0, nil),
+		emergencyRepo.EXPECT().GetContact(gomock.Any(), "tom@aaa.com", "bob@aaa.com").Return(nil, nil),
+	)
+
+	newRequest := func(owner string) *http.Request {
+		request := httptest.NewRequest(http.MethodGet, "/api/user/emergency/grantor/"+owner+"/kit", nil)
+		request.Header.Set("x-user", "bob@aaa.com")
+		request.SetPathValue("owner", owner)
+		return request
+	}
+
+	response := httptest.NewRecorder()
+	h.KitGet(response, newRequest("ane@aaa.com"))
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	response = httptest.NewRecorder()
+	h.KitGet(response, newRequest("ane@aaa.com"))
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, "no-store", response.Header().Get("Cache-Control"))
+
+	var kit emergencyKitResponseDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &kit))
+	require.Equal(t, emergencyKitResponseDTO{Payload: "ciphertext", WrappedKey: "wrapped"}, kit)
+
+	response = httptest.NewRecorder()
+	h.KitGet(response, newRequest("ane@aaa.com"))
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	response = httptest.NewRecorder()
+	h.KitGet(response, newRequest("tom@aaa.com"))
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
+
+// ------- Хендлер: POST /api/user/notifications
+func TestNotifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	emergencyRepo := mocks.NewMockIEmergencyRepo(ctrl)
+	h := &EmergencyHandlers{EmergencyRepo: emergencyRepo}
+
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	gomock.InOrder(
+		// владелец узнаёт об открытии доступа, даже если контакт ещё не забрал набор
+		emergencyRepo.EXPECT().GrantDue(gomock.Any(), "ane@aaa.com").Return(1, nil),
+		emergencyRepo.EXPECT().TakeNotifications(gomock.Any(), "ane@aaa.com").Return([]repo.EmergencyEvent{
+			{Owner: "ane@aaa.com", Contact: "bob@aaa.com", Event: emergency.EventRequested, CreatedAt: createdAt},
+			{Owner: "ane@aaa.com", Contact: "bob@aaa.com", Event: emergency.EventGranted, CreatedAt: createdAt},
+		}, nil),
+	)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/user/notifications", nil)
+	request.Header.Set("x-user", "ane@aaa.com")
+	response := httptest.NewRecorder()
+	h.Notifications(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var events []emergencyEventResponseDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &events))
+	require.Equal(t, []emergencyEventResponseDTO{
+		{Owner: "ane@aaa.com", Contact: "bob@aaa.com", Event: emergency.EventRequested, CreatedAt: createdAt},
+		{Owner: "ane@aaa.com", Contact: "bob@aaa.com", Event: emergency.EventGranted, CreatedAt: createdAt},
+	}, events)
+}
@@ -36,7 +36,8 @@ func NewFileHandlers(fileRepo IFileRepo, minioService IMinioService,
 type IFileRepo interface {
 	AddFile(ctx context.Context, fileName, title, description, cloudID string, login string) error
 	GetFileCoudIDByTitle(ctx context.Context, fileID, login string) (string, error)
-	GetAllFiles(ctx context.Context, login string) ([]repo.FileToGet, error)
+	GetAllFiles(ctx context.Context, login string, filter repo.ItemFilter,
+		page repo.Page) ([]repo.FileToGet, *repo.ItemCursor, error)
 	UpdateFile(ctx context.Context, title string, fileName *string, description *string, userID int) error
 	DeleteFileByTitle(ctx context.Context, title string, login string) error
 	FileExists(ctx context.Context, title string, custID string) (bool, error)
@@ -114,7 +115,7 @@ func (fh *FileHandlers) FileGetByTitle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userLogin := r.Header.Get("x-user")
-	title := r.PathValue("title")
+	title := pathValue(r, "title")
 
 	fileCLoudID, err := fh.FileRepo.GetFileCoudIDByTitle(context.Background(), title, userLogin)
 	if err != nil {
@@ -150,6 +151,7 @@ type fileGetRequestDTO struct {
 	FileName    string `json:"file_name"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
+	ItemMetaDTO
 }
 
 func NewFileDTO(file repo.FileToGet) *fileGetRequestDTO {
@@ -157,6 +159,7 @@ func NewFileDTO(file repo.FileToGet) *fileGetRequestDTO {
 		FileName:    file.FileName,
 		Title:       file.Title,
 		Description: file.Description,
+		ItemMetaDTO: NewItemMetaDTO(file.ItemMeta),
 	}
 }
 
@@ -178,7 +181,20 @@ func (fh *FileHandlers) AllFiles(w http.ResponseWriter, r *http.Request) {
 
 	userLogin := r.Header.Get("x-user")
 
-	files, err := fh.FileRepo.GetAllFiles(context.Background(), userLogin)
+	filter, err := parseItemFilter(r)
+	if err != nil {
+		log.Println("error in parsing filter:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		log.Println("error in parsing page:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	files, next, err := fh.FileRepo.GetAllFiles(context.Background(), userLogin, filter, page)
 	if err != nil {
 		log.Println("error in getting files: ", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -191,6 +207,11 @@ func (fh *FileHandlers) AllFiles(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	if err := setNextCursor(w, next); err != nil {
+		log.Println("error in marshalling cursor:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
 
 	_, err = w.Write(resp)
 	if err != nil {
@@ -220,7 +241,7 @@ func (fh *FileHandlers) FileUpdate(w http.ResponseWriter, r *http.Request) {
 
 	var buf bytes.Buffer
 	var file fileUpdateRequestDTO
-	title := r.PathValue("title")
+	title := pathValue(r, "title")
 
 	// читаем тело запроса
 	_, err = buf.ReadFrom(r.Body)
@@ -251,7 +272,7 @@ func (fh *FileHandlers) FileDeleteByTitle(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	title := r.PathValue("title")
+	title := pathValue(r, "title")
 	userLogin := r.Header.Get("x-user")
 
 	err := fh.FileRepo.DeleteFileByTitle(context.Background(), title, userLogin)
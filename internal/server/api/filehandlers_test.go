@@ -180,7 +180,7 @@ func TestAllFiles(t *testing.T) {
 			Description: "description2",
 		},
 	}
-	fileRepo.EXPECT().GetAllFiles(gomock.Any(), login).Return(files, nil)
+	fileRepo.EXPECT().GetAllFiles(gomock.Any(), login, repo.ItemFilter{}, repo.Page{}).Return(files, nil, nil)
 
 	request, err := requests.
 		URL("/api/user/files").
@@ -205,11 +205,13 @@ func TestAllFiles(t *testing.T) {
 			FileName:    "./file1.png",
 			Title:       "png1",
 			Description: "description1",
+			ItemMetaDTO: ItemMetaDTO{Tags: []string{}},
 		},
 		{
 			FileName:    "./file2.png",
 			Title:       "png2",
 			Description: "description2",
+			ItemMetaDTO: ItemMetaDTO{Tags: []string{}},
 		},
 	})
 	require.NoError(t, err)
@@ -232,7 +234,7 @@ func TestAllFilesFailure(t *testing.T) {
 	login := "Ane"
 	userID := 123
 
-	fileRepo.EXPECT().GetAllFiles(gomock.Any(), login).Return(nil, fmt.Errorf("DB error"))
+	fileRepo.EXPECT().GetAllFiles(gomock.Any(), login, repo.ItemFilter{}, repo.Page{}).Return(nil, nil, fmt.Errorf("DB error"))
 
 	request, err := requests.
 		URL("/api/user/files").
@@ -9,6 +9,8 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/adettelle/go-keeper/internal/jwt"
 	"github.com/adettelle/go-keeper/internal/repo"
@@ -28,6 +30,7 @@ type IJwtRepo interface {
 type IMinioService interface {
 	GetObject(fileCLoudID string) (io.Reader, error)
 	Upload(fileCloudID string, reader io.Reader) error
+	PresignedGetObject(fileCloudID string, fileName string) (string, time.Time, error)
 }
 
 type CustomerHandlers struct {
@@ -160,3 +163,17 @@ func (ch *CustomerHandlers) RegisterCustomer(w http.ResponseWriter, r *http.Requ
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// pathValue returns the named path segment. A segment with an escaped "/" is routed by the raw
+// path and left escaped, so it is unescaped here.
+func pathValue(r *http.Request, name string) string {
+	value := r.PathValue(name)
+	if r.URL.RawPath == "" {
+		return value
+	}
+	unescaped, err := url.PathUnescape(value)
+	if err != nil {
+		return value
+	}
+	return unescaped
+}
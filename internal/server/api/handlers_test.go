@@ -13,6 +13,7 @@ import (
 	"github.com/adettelle/go-keeper/internal/jwt"
 	"github.com/adettelle/go-keeper/mocks"
 	"github.com/carlmjohnson/requests"
+	"github.com/go-chi/chi/v5"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
@@ -175,3 +176,19 @@ func TestLoginFailure(t *testing.T) {
 
 	require.Equal(t, http.StatusUnauthorized, response.Code)
 }
+
+// ------- pathValue: заголовок с "/" и пробелом
+func TestPathValueEscaped(t *testing.T) {
+	var titles []string
+	r := chi.NewRouter()
+	r.Get("/api/user/password/{title}", func(w http.ResponseWriter, r *http.Request) {
+		titles = append(titles, pathValue(r, "title"))
+	})
+
+	for _, path := range []string{"/api/user/password/work%2Fvk%20mail", "/api/user/password/vk%20mail"} {
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, response.Code)
+	}
+	require.Equal(t, []string{"work/vk mail", "vk mail"}, titles)
+}
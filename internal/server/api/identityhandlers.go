@@ -0,0 +1,372 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/adettelle/go-keeper/internal/encryption"
+	"github.com/adettelle/go-keeper/internal/identity"
+	"github.com/adettelle/go-keeper/internal/repo"
+)
+
+// IdentityHandlers manage identity documents: passports, driver licenses, national IDs and addresses.
+// The document fields are validated by the schema of the document type and stored encrypted.
+type IdentityHandlers struct {
+	IdentityRepo IIdentityRepo
+	SignKey      []byte
+}
+
+func NewIdentityHandlers(identityRepo IIdentityRepo, signKey []byte) *IdentityHandlers {
+	return &IdentityHandlers{
+		IdentityRepo: identityRepo,
+		SignKey:      signKey,
+	}
+}
+
+type IIdentityRepo interface {
+	AddIdentity(ctx context.Context, identity repo.Identity, login string) error
+	GetAllIdentities(ctx context.Context, login string, filter repo.ItemFilter,
+		page repo.Page) ([]repo.IdentityToGet, *repo.ItemCursor, error)
+	GetIdentityByTitle(ctx context.Context, title, login string) (*repo.Identity, error)
+	UpdateIdentity(ctx context.Context, title string, upd repo.IdentityUpdate, login string) (bool, error)
+	DeleteIdentityByTitle(ctx context.Context, title, login string) (bool, error)
+}
+
+// encryptFields encrypts the document fields as a JSON object.
+func (ih *IdentityHandlers) encryptFields(fields map[string]string) (string, error) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return encryption.AESEncrypt(string(data), ih.SignKey)
+}
+
+func (ih *IdentityHandlers) decryptFields(encrypted string) (map[string]string, error) {
+	data, err := encryption.AESDecrypt(encrypted, ih.SignKey)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]string{}
+	if data == "" {
+		return fields, nil
+	}
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+type identityCreateRequestDTO struct {
+	DocType     string            `json:"doc_type" validate:"required"`
+	Title       string            `json:"title" validate:"required,min=1"`
+	Description string            `json:"description" validate:"max=1000"`
+	Fields      map[string]string `json:"fields"`
+}
+
+func (ih *IdentityHandlers) IdentityAdd(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	userLogin := r.Header.Get("x-user")
+
+	var buf bytes.Buffer
+	var doc identityCreateRequestDTO
+
+	// читаем тело запроса
+	_, err := buf.ReadFrom(r.Body)
+	if err != nil {
+		log.Println("error in reading body:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		log.Println("error in unmarshalling json:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = validate.Struct(doc)
+	if err != nil {
+		log.Println("error in validating:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	fields, err := identity.Validate(identity.DocType(doc.DocType), doc.Fields, validate.Var)
+	if err != nil {
+		log.Println("error in validating:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	encrypted, err := ih.encryptFields(fields)
+	if err != nil {
+		log.Println("error in encrypting identity fields:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = ih.IdentityRepo.AddIdentity(context.Background(), repo.Identity{
+		DocType:     doc.DocType,
+		Title:       doc.Title,
+		Description: doc.Description,
+		Fields:      encrypted,
+	}, userLogin)
+	if err != nil {
+		log.Println("error in adding identity:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+type identityGetRequestDTO struct {
+	DocType     string `json:"doc_type"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ItemMetaDTO
+}
+
+func NewIdentityDTO(doc repo.IdentityToGet) *identityGetRequestDTO {
+	return &identityGetRequestDTO{
+		DocType:     doc.DocType,
+		Title:       doc.Title,
+		Description: doc.Description,
+		ItemMetaDTO: NewItemMetaDTO(doc.ItemMeta),
+	}
+}
+
+// AllIdentities lists the identity documents without their fields.
+func (ih *IdentityHandlers) AllIdentities(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	userLogin := r.Header.Get("x-user")
+
+	filter, err := parseItemFilter(r)
+	if err != nil {
+		log.Println("error in parsing filter:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		log.Println("error in parsing page:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	docs, next, err := ih.IdentityRepo.GetAllIdentities(context.Background(), userLogin, filter, page)
+	if err != nil {
+		log.Println("error in getting identities:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	list := []*identityGetRequestDTO{}
+	for _, doc := range docs {
+		list = append(list, NewIdentityDTO(doc))
+	}
+
+	resp, err := json.Marshal(list)
+	if err != nil {
+		log.Println("error in marshalling json:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err := setNextCursor(w, next); err != nil {
+		log.Println("error in marshalling cursor:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, err = w.Write(resp)
+	if err != nil {
+		log.Println("error in writing resp:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+type identityGetByTitleRequestDTO struct {
+	DocType     string            `json:"doc_type"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Fields      map[string]string `json:"fields"`
+}
+
+// IdentityGetByTitle shows the identity document with its decrypted fields by title.
+func (ih *IdentityHandlers) IdentityGetByTitle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	userLogin := r.Header.Get("x-user")
+	title := pathValue(r, "title")
+
+	doc, err := ih.IdentityRepo.GetIdentityByTitle(context.Background(), title, userLogin)
+	if err != nil {
+		log.Println("error in getting identity:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if doc == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	fields, err := ih.decryptFields(doc.Fields)
+	if err != nil {
+		log.Println("error in decrypting identity fields:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := json.Marshal(identityGetByTitleRequestDTO{
+		DocType:     doc.DocType,
+		Title:       doc.Title,
+		Description: doc.Description,
+		Fields:      fields,
+	})
+	if err != nil {
+		log.Println("error in marshalling json:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, err = w.Write(resp)
+	if err != nil {
+		log.Println("error in writing resp:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// identityUpdateRequestDTO changes the description and the fields of the document.
+// Fields with a value are set, fields with null are removed, the others remain unchanged.
+type identityUpdateRequestDTO struct {
+	Description *string            `json:"description,omitempty" validate:"omitnil,max=1000"`
+	Fields      map[string]*string `json:"fields,omitempty"`
+}
+
+func (ih *IdentityHandlers) IdentityUpdate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	userLogin := r.Header.Get("x-user")
+	title := pathValue(r, "title")
+
+	var buf bytes.Buffer
+	var doc identityUpdateRequestDTO
+
+	// читаем тело запроса
+	_, err := buf.ReadFrom(r.Body)
+	if err != nil {
+		log.Println("error in reading body:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		log.Println("error in unmarshalling json:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = validate.Struct(doc)
+	if err != nil {
+		log.Println("error in validating:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	upd := repo.IdentityUpdate{Description: doc.Description}
+	if len(doc.Fields) > 0 {
+		// поля проверяются по схеме целиком, поэтому изменения накладываются на текущие значения
+		current, err := ih.IdentityRepo.GetIdentityByTitle(context.Background(), title, userLogin)
+		if err != nil {
+			log.Println("error in getting identity:", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if current == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fields, err := ih.decryptFields(current.Fields)
+		if err != nil {
+			log.Println("error in decrypting identity fields:", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		for name, value := range doc.Fields {
+			if value == nil {
+				delete(fields, name)
+			} else {
+				fields[name] = *value
+			}
+		}
+
+		fields, err = identity.Validate(identity.DocType(current.DocType), fields, validate.Var)
+		if err != nil {
+			log.Println("error in validating:", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		encrypted, err := ih.encryptFields(fields)
+		if err != nil {
+			log.Println("error in encrypting identity fields:", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		upd.Fields = &encrypted
+	}
+
+	found, err := ih.IdentityRepo.UpdateIdentity(context.Background(), title, upd, userLogin)
+	if err != nil {
+		log.Println("error in updating identity:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (ih *IdentityHandlers) IdentityDeleteByTitle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	title := pathValue(r, "title")
+	userLogin := r.Header.Get("x-user")
+
+	found, err := ih.IdentityRepo.DeleteIdentityByTitle(context.Background(), title, userLogin)
+	if err != nil {
+		log.Println("error in deleting identity:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+}
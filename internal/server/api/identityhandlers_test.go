@@ -0,0 +1,189 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adettelle/go-keeper/internal/repo"
+	"github.com/adettelle/go-keeper/mocks"
+	"github.com/carlmjohnson/requests"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// ------- Хендлер: PUT /api/user/identity
+func TestIdentityAdd(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	identityRepo := mocks.NewMockIIdentityRepo(ctrl)
+	h := &IdentityHandlers{IdentityRepo: identityRepo, SignKey: []byte("1234567812345678")}
+
+	var added repo.Identity
+	identityRepo.EXPECT().AddIdentity(gomock.Any(), gomock.Any(), "Ane").
+		DoAndReturn(func(_ context.Context, doc repo.Identity, _ string) error {
+			added = doc
+			return nil
+		})
+
+	request, err := requests.
+		URL("/api/user/identity").
+		Method(http.MethodPut).
+		BodyJSON(map[string]any{
+			"doc_type": "passport",
+			"title":    "my passport",
+			"fields": map[string]string{
+				"number": "45 01 123456", "country": "ru", "full_name": "Ane Doe", "expiry_date": "2035-05-20",
+			},
+		}).
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	h.IdentityAdd(response, request)
+
+	require.Equal(t, http.StatusAccepted, response.Code)
+	require.Equal(t, "passport", added.DocType)
+	require.Equal(t, "my passport", added.Title)
+	require.NotContains(t, added.Fields, "Ane Doe")
+
+	fields, err := h.decryptFields(added.Fields)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"number": "4501123456", "country": "RU", "full_name": "Ane Doe", "expiry_date": "2035-05-20",
+	}, fields)
+}
+
+// ------- Хендлер: PUT /api/user/identity
+func TestIdentityAddValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	h := &IdentityHandlers{IdentityRepo: mocks.NewMockIIdentityRepo(ctrl), SignKey: []byte("1234567812345678")}
+
+	for _, body := range []map[string]any{
+		{"doc_type": "visa", "title": "t", "fields": map[string]string{"number": "1"}},
+		{"doc_type": "address", "title": "t", "fields": map[string]string{"city": "Paris", "country": "FR"}},
+		{"doc_type": "national_id", "title": "t", "fields": map[string]string{
+			"number": "1", "country": "FR", "full_name": "Ane Doe", "birth_date": "31.12.1990"}},
+		{"doc_type": "address", "fields": map[string]string{"street": "Main st. 1", "city": "Paris", "country": "FR"}},
+	} {
+		request, err := requests.
+			URL("/api/user/identity").
+			Method(http.MethodPut).
+			BodyJSON(body).
+			Header("x-user", "Ane").
+			Request(context.Background())
+		require.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		h.IdentityAdd(response, request)
+
+		require.Equal(t, http.StatusBadRequest, response.Code, body)
+	}
+}
+
+// ------- Хендлер: GET /api/user/identity/{title}
+func TestIdentityGetByTitle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	identityRepo := mocks.NewMockIIdentityRepo(ctrl)
+	h := &IdentityHandlers{IdentityRepo: identityRepo, SignKey: []byte("1234567812345678")}
+
+	encrypted, err := h.encryptFields(map[string]string{"street": "Main st. 1", "city": "Paris", "country": "FR"})
+	require.NoError(t, err)
+	identityRepo.EXPECT().GetIdentityByTitle(gomock.Any(), "home", "Ane").Return(&repo.Identity{
+		DocType: "address", Title: "home", Fields: encrypted,
+	}, nil)
+	identityRepo.EXPECT().GetIdentityByTitle(gomock.Any(), "unknown", "Ane").Return(nil, nil)
+
+	request, err := requests.
+		URL("/api/user/identity/home").
+		Method(http.MethodGet).
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+	request.SetPathValue("title", "home")
+
+	response := httptest.NewRecorder()
+	h.IdentityGetByTitle(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+	var res identityGetByTitleRequestDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &res))
+	require.Equal(t, identityGetByTitleRequestDTO{
+		DocType: "address", Title: "home",
+		Fields: map[string]string{"street": "Main st. 1", "city": "Paris", "country": "FR"},
+	}, res)
+
+	request.SetPathValue("title", "unknown")
+	response = httptest.NewRecorder()
+	h.IdentityGetByTitle(response, request)
+
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
+
+// ------- Хендлер: POST /api/user/identity/update/{title}
+func TestIdentityUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	identityRepo := mocks.NewMockIIdentityRepo(ctrl)
+	h := &IdentityHandlers{IdentityRepo: identityRepo, SignKey: []byte("1234567812345678")}
+
+	encrypted, err := h.encryptFields(map[string]string{
+		"street": "Main st. 1", "city": "Paris", "country": "FR", "phone": "+33 1 00 00 00 00",
+	})
+	require.NoError(t, err)
+	identityRepo.EXPECT().GetIdentityByTitle(gomock.Any(), "home", "Ane").Return(&repo.Identity{
+		DocType: "address", Title: "home", Fields: encrypted,
+	}, nil)
+
+	want, err := h.encryptFields(map[string]string{"street": "Main st. 2", "city": "Paris", "country": "FR"})
+	require.NoError(t, err)
+	identityRepo.EXPECT().UpdateIdentity(gomock.Any(), "home", repo.IdentityUpdate{Fields: &want}, "Ane").
+		Return(true, nil)
+
+	request, err := requests.
+		URL("/api/user/identity/update/home").
+		Method(http.MethodPost).
+		BodyJSON(map[string]any{"fields": map[string]any{"street": "Main st. 2", "phone": nil}}).
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+	request.SetPathValue("title", "home")
+
+	response := httptest.NewRecorder()
+	h.IdentityUpdate(response, request)
+
+	require.Equal(t, http.StatusAccepted, response.Code)
+}
+
+// ------- Хендлер: POST /api/user/identity/update/{title}
+func TestIdentityUpdateRequiredField(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	identityRepo := mocks.NewMockIIdentityRepo(ctrl)
+	h := &IdentityHandlers{IdentityRepo: identityRepo, SignKey: []byte("1234567812345678")}
+
+	encrypted, err := h.encryptFields(map[string]string{"street": "Main st. 1", "city": "Paris", "country": "FR"})
+	require.NoError(t, err)
+	identityRepo.EXPECT().GetIdentityByTitle(gomock.Any(), "home", "Ane").Return(&repo.Identity{
+		DocType: "address", Title: "home", Fields: encrypted,
+	}, nil)
+
+	request, err := requests.
+		URL("/api/user/identity/update/home").
+		Method(http.MethodPost).
+		BodyJSON(map[string]any{"fields": map[string]any{"city": nil}}).
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+	request.SetPathValue("title", "home")
+
+	response := httptest.NewRecorder()
+	h.IdentityUpdate(response, request)
+
+	require.Equal(t, http.StatusBadRequest, response.Code)
+}
@@ -0,0 +1,370 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/adettelle/go-keeper/internal/repo"
+)
+
+const (
+	maxFolderLength = 255
+	maxTagLength    = 64
+	maxTags         = 32
+	maxSearchLength = 255
+	maxPageLimit    = 1000
+
+	// nextCursorHeader holds the cursor of the next page when a listing has more items.
+	nextCursorHeader = "X-Next-Cursor"
+)
+
+type ItemHandlers struct {
+	ItemRepo IItemRepo
+}
+
+func NewItemHandlers(itemRepo IItemRepo) *ItemHandlers {
+	return &ItemHandlers{
+		ItemRepo: itemRepo,
+	}
+}
+
+type IItemRepo interface {
+	UpdateItemMeta(ctx context.Context, kind repo.ItemKind, title string, upd repo.ItemMetaUpdate,
+		login string) (bool, error)
+	GetAllFolders(ctx context.Context, login string) ([]string, error)
+	SearchItems(ctx context.Context, login string, filter repo.ItemFilter, page repo.Page) ([]repo.Item,
+		*repo.ItemCursor, error)
+}
+
+// ItemMetaDTO is the folder, tags and favorite mark of an item in listings.
+type ItemMetaDTO struct {
+	Folder   string   `json:"folder"`
+	Tags     []string `json:"tags"`
+	Favorite bool     `json:"favorite"`
+}
+
+func NewItemMetaDTO(meta repo.ItemMeta) ItemMetaDTO {
+	tags := meta.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return ItemMetaDTO{
+		Folder:   meta.Folder,
+		Tags:     tags,
+		Favorite: meta.Favorite,
+	}
+}
+
+// normalizeFolder trims surrounding slashes and spaces of the folder path segments
+// and checks that no segment is empty: " work / prod/" becomes "work/prod".
+func normalizeFolder(folder string) (string, error) {
+	folder = strings.Trim(strings.TrimSpace(folder), "/")
+	if folder == "" {
+		return "", nil
+	}
+	segments := strings.Split(folder, "/")
+	for i, segment := range segments {
+		segment = strings.TrimSpace(segment)
+		if segment == "" {
+			return "", fmt.Errorf("folder %q has an empty segment", folder)
+		}
+		segments[i] = segment
+	}
+	folder = strings.Join(segments, "/")
+	if utf8.RuneCountInString(folder) > maxFolderLength {
+		return "", fmt.Errorf("folder is longer than %d characters", maxFolderLength)
+	}
+	return folder, nil
+}
+
+// normalizeTags trims the tags and removes duplicates keeping the order.
+func normalizeTags(tags []string) ([]string, error) {
+	res := make([]string, 0, len(tags))
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			return nil, errors.New("tag is empty")
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, fmt.Errorf("tag %q is longer than %d characters", tag, maxTagLength)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			res = append(res, tag)
+		}
+	}
+	if len(res) > maxTags {
+		return nil, fmt.Errorf("more than %d tags", maxTags)
+	}
+	return res, nil
+}
+
+// parseItemFilter reads the listing filter from the query: ?folder=work&tag=prod&tag=db&favorite=true&q=mail.
+func parseItemFilter(r *http.Request) (repo.ItemFilter, error) {
+	query := r.URL.Query()
+
+	folder, err := normalizeFolder(query.Get("folder"))
+	if err != nil {
+		return repo.ItemFilter{}, err
+	}
+
+	var tags []string
+	if len(query["tag"]) > 0 {
+		tags, err = normalizeTags(query["tag"])
+		if err != nil {
+			return repo.ItemFilter{}, err
+		}
+	}
+
+	var favorite bool
+	if s := query.Get("favorite"); s != "" {
+		favorite, err = strconv.ParseBool(s)
+		if err != nil {
+			return repo.ItemFilter{}, fmt.Errorf("invalid favorite value %q", s)
+		}
+	}
+
+	search := strings.TrimSpace(query.Get("q"))
+	if utf8.RuneCountInString(search) > maxSearchLength {
+		return repo.ItemFilter{}, fmt.Errorf("search is longer than %d characters", maxSearchLength)
+	}
+
+	return repo.ItemFilter{Folder: folder, Tags: tags, Favorite: favorite, Search: search}, nil
+}
+
+// parsePage reads the listing page from the query: ?sort=-title&limit=50&cursor=...
+// Without limit all the items are listed.
+func parsePage(r *http.Request) (repo.Page, error) {
+	query := r.URL.Query()
+	var page repo.Page
+
+	if s := query.Get("sort"); s != "" {
+		page.Sort = repo.ItemSort(s)
+		if !slices.Contains(repo.ItemSorts, page.Sort) {
+			return repo.Page{}, fmt.Errorf("invalid sort %q", s)
+		}
+	}
+
+	if s := query.Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return repo.Page{}, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		page.Limit = limit
+	}
+
+	if s := query.Get("cursor"); s != "" {
+		data, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			return repo.Page{}, fmt.Errorf("invalid cursor: %w", err)
+		}
+		var cursor repo.ItemCursor
+		if err := json.Unmarshal(data, &cursor); err != nil {
+			return repo.Page{}, fmt.Errorf("invalid cursor: %w", err)
+		}
+		page.After = &cursor
+		if !page.ValidCursor() {
+			return repo.Page{}, errors.New("cursor does not match the sort")
+		}
+	}
+
+	return page, nil
+}
+
+// setNextCursor passes the cursor of the next page in the response header, if there is one.
+func setNextCursor(w http.ResponseWriter, next *repo.ItemCursor) error {
+	if next == nil {
+		return nil
+	}
+	data, err := json.Marshal(next)
+	if err != nil {
+		return err
+	}
+	w.Header().Set(nextCursorHeader, base64.RawURLEncoding.EncodeToString(data))
+	return nil
+}
+
+type itemMetaUpdateRequestDTO struct {
+	Folder   *string   `json:"folder,omitempty"`
+	Tags     *[]string `json:"tags,omitempty"`
+	Favorite *bool     `json:"favorite,omitempty"`
+}
+
+// ItemMetaUpdate returns a handler which moves an item of the kind to another folder,
+// replaces its tags or marks it as favorite. Fields missing in json remain unchanged.
+func (ih *ItemHandlers) ItemMetaUpdate(kind repo.ItemKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		userLogin := r.Header.Get("x-user")
+		title := pathValue(r, "title")
+
+		var buf bytes.Buffer
+		var meta itemMetaUpdateRequestDTO
+
+		// читаем тело запроса
+		_, err := buf.ReadFrom(r.Body)
+		if err != nil {
+			log.Println("error in reading body:", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if err := json.Unmarshal(buf.Bytes(), &meta); err != nil {
+			log.Println("error in unmarshalling json:", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		upd := repo.ItemMetaUpdate{Favorite: meta.Favorite}
+		if meta.Folder != nil {
+			folder, err := normalizeFolder(*meta.Folder)
+			if err != nil {
+				log.Println("error in validating:", err)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			upd.Folder = &folder
+		}
+		if meta.Tags != nil {
+			tags, err := normalizeTags(*meta.Tags)
+			if err != nil {
+				log.Println("error in validating:", err)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			upd.Tags = &tags
+		}
+
+		found, err := ih.ItemRepo.UpdateItemMeta(context.Background(), kind, title, upd, userLogin)
+		if err != nil {
+			log.Println("error in updating item meta:", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// AllFolders returns all folders used by the user items.
+func (ih *ItemHandlers) AllFolders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	userLogin := r.Header.Get("x-user")
+
+	folders, err := ih.ItemRepo.GetAllFolders(context.Background(), userLogin)
+	if err != nil {
+		log.Println("error in getting folders:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := json.Marshal(folders)
+	if err != nil {
+		log.Println("error in marshalling json:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, err = w.Write(resp)
+	if err != nil {
+		log.Println("error in writing resp:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+type ItemResponseDTO struct {
+	Kind        repo.ItemKind `json:"kind"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	ItemMetaDTO
+}
+
+func NewItemListResponseDTO(items []repo.Item) []*ItemResponseDTO {
+	res := []*ItemResponseDTO{}
+	for _, item := range items {
+		res = append(res, &ItemResponseDTO{
+			Kind:        item.Kind,
+			Title:       item.Title,
+			Description: item.Description,
+			ItemMetaDTO: NewItemMetaDTO(item.ItemMeta),
+		})
+	}
+	return res
+}
+
+// SearchItems lists items of all kinds matching the filter, with the same query parameters
+// as the listings of a single kind.
+func (ih *ItemHandlers) SearchItems(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	userLogin := r.Header.Get("x-user")
+
+	filter, err := parseItemFilter(r)
+	if err != nil {
+		log.Println("error in parsing filter:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		log.Println("error in parsing page:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	items, next, err := ih.ItemRepo.SearchItems(context.Background(), userLogin, filter, page)
+	if err != nil {
+		log.Println("error in searching items:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := json.Marshal(NewItemListResponseDTO(items))
+	if err != nil {
+		log.Println("error in marshalling json:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err := setNextCursor(w, next); err != nil {
+		log.Println("error in marshalling cursor:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, err = w.Write(resp)
+	if err != nil {
+		log.Println("error in writing resp:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
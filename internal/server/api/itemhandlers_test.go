@@ -0,0 +1,170 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adettelle/go-keeper/internal/repo"
+	"github.com/adettelle/go-keeper/mocks"
+	"github.com/carlmjohnson/requests"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ------- Хендлер: POST /api/user/card/meta/{title}
+func TestItemMetaUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	itemRepo := mocks.NewMockIItemRepo(ctrl)
+	h := &ItemHandlers{ItemRepo: itemRepo}
+
+	login := "Ane"
+	folder := "work/prod"
+	tags := []string{"prod", "db"}
+	itemRepo.EXPECT().UpdateItemMeta(gomock.Any(), repo.KindCard, "bank1",
+		repo.ItemMetaUpdate{Folder: &folder, Tags: &tags}, login).Return(true, nil)
+
+	request, err := requests.
+		URL("/api/user/card/meta/bank1").
+		Method(http.MethodPost).
+		BodyJSON(map[string]any{"folder": " work/ prod ", "tags": []string{"prod", " db", "prod"}}).
+		Header("x-user", login).
+		Request(context.Background())
+	require.NoError(t, err)
+	request.SetPathValue("title", "bank1")
+
+	response := httptest.NewRecorder()
+	h.ItemMetaUpdate(repo.KindCard)(response, request)
+
+	require.Equal(t, http.StatusAccepted, response.Code)
+}
+
+// ------- Хендлер: POST /api/user/password/meta/{title}
+func TestItemMetaUpdateNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	itemRepo := mocks.NewMockIItemRepo(ctrl)
+	h := &ItemHandlers{ItemRepo: itemRepo}
+
+	favorite := true
+	itemRepo.EXPECT().UpdateItemMeta(gomock.Any(), repo.KindPassword, "unknown",
+		repo.ItemMetaUpdate{Favorite: &favorite}, "Ane").Return(false, nil)
+
+	request, err := requests.
+		URL("/api/user/password/meta/unknown").
+		Method(http.MethodPost).
+		BodyJSON(map[string]any{"favorite": true}).
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+	request.SetPathValue("title", "unknown")
+
+	response := httptest.NewRecorder()
+	h.ItemMetaUpdate(repo.KindPassword)(response, request)
+
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
+
+// ------- Хендлер: POST /api/user/file/meta/{title}
+func TestItemMetaUpdateBadTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	h := &ItemHandlers{ItemRepo: mocks.NewMockIItemRepo(ctrl)}
+
+	request, err := requests.
+		URL("/api/user/file/meta/png1").
+		Method(http.MethodPost).
+		BodyJSON(map[string]any{"tags": []string{""}}).
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+	request.SetPathValue("title", "png1")
+
+	response := httptest.NewRecorder()
+	h.ItemMetaUpdate(repo.KindFile)(response, request)
+
+	require.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+// ------- Хендлер: GET /api/user/folders
+func TestAllFolders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	itemRepo := mocks.NewMockIItemRepo(ctrl)
+	h := &ItemHandlers{ItemRepo: itemRepo}
+
+	itemRepo.EXPECT().GetAllFolders(gomock.Any(), "Ane").Return([]string{"home", "work", "work/prod"}, nil)
+
+	request, err := requests.
+		URL("/api/user/folders").
+		Method(http.MethodGet).
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	h.AllFolders(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `["home","work","work/prod"]`, response.Body.String())
+}
+
+func TestNormalizeFolder(t *testing.T) {
+	for in, want := range map[string]string{"": "", "/": "", "work": "work", " /work / prod/ ": "work/prod"} {
+		got, err := normalizeFolder(in)
+		require.NoError(t, err)
+		require.Equal(t, want, got, in)
+	}
+
+	_, err := normalizeFolder("work//prod")
+	require.Error(t, err)
+}
+
+// ------- Хендлер: GET /api/user/items/search
+func TestSearchItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	itemRepo := mocks.NewMockIItemRepo(ctrl)
+	h := &ItemHandlers{ItemRepo: itemRepo}
+
+	items := []repo.Item{
+		{Kind: repo.KindCard, Title: "bank1", Description: "salary"},
+		{Kind: repo.KindPassword, Title: "bank2", Description: "online bank", ItemMeta: repo.ItemMeta{Tags: []string{"bank"}}},
+	}
+	itemRepo.EXPECT().SearchItems(gomock.Any(), "Ane", repo.ItemFilter{Search: "bank"}, repo.Page{}).
+		Return(items, nil, nil)
+
+	request, err := requests.
+		URL("/api/user/items/search").
+		Param("q", "bank").
+		Method(http.MethodGet).
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	h.SearchItems(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Empty(t, response.Header().Get(nextCursorHeader))
+	assert.JSONEq(t, `[
+		{"kind":"card","title":"bank1","description":"salary","folder":"","tags":[],"favorite":false},
+		{"kind":"password","title":"bank2","description":"online bank","folder":"","tags":["bank"],"favorite":false}
+	]`, response.Body.String())
+}
+
+func TestParsePageInvalid(t *testing.T) {
+	for _, query := range []string{"sort=name", "limit=0", "limit=1001", "limit=x", "cursor=!!!",
+		"cursor=bm90IGpzb24", "sort=created&cursor=eyJmIjpmYWxzZSwiayI6InZrIn0"} {
+		request, err := requests.
+			URL("/api/user/items/search?" + query).
+			Request(context.Background())
+		require.NoError(t, err)
+
+		_, err = parsePage(request)
+		require.Error(t, err, query)
+	}
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"io/fs"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/repo"
+)
+
+type ILinkRepo interface {
+	AddLink(ctx context.Context, login string, id string, payload string, views int, expiresAt time.Time) error
+	TakeLink(ctx context.Context, id string) (string, bool, error)
+}
+
+type ILinkFileRepo interface {
+	GetFileObject(ctx context.Context, title, login string) (*repo.FileObject, error)
+}
+
+// LinkHandlers create secret links and serve them to anybody who has the link.
+//
+// A secret link holds a secret encrypted on the client; the key is only in the fragment of the link,
+// so the server can not read it. The link works for a limited number of views until it expires.
+// Files are shared with presigned links of the object storage instead, valid for the lifetime
+// of the minio service.
+type LinkHandlers struct {
+	LinkRepo     ILinkRepo
+	FileRepo     ILinkFileRepo
+	MinioService IMinioService
+	Static       fs.FS
+}
+
+func NewLinkHandlers(linkRepo ILinkRepo, fileRepo ILinkFileRepo, minioService IMinioService,
+	static fs.FS) *LinkHandlers {
+
+	return &LinkHandlers{
+		LinkRepo:     linkRepo,
+		FileRepo:     fileRepo,
+		MinioService: minioService,
+		Static:       static,
+	}
+}
+
+// newLinkID returns a random unguessable link id.
+func newLinkID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+type linkCreateRequestDTO struct {
+	Payload string `json:"payload" validate:"required,max=65536"`
+	TTL     int    `json:"ttl_seconds" validate:"required,min=60,max=604800"`
+	Views   int    `json:"views" validate:"required,min=1,max=100"`
+}
+
+type linkResponseDTO struct {
+	ID        string    `json:"id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// LinkCreate stores the encrypted secret and returns the id of its link.
+func (lh *LinkHandlers) LinkCreate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var link linkCreateRequestDTO
+	if !readJSON(w, r, &link) {
+		return
+	}
+
+	id, err := newLinkID()
+	if err != nil {
+		log.Println("error in generating link id:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	expiresAt := time.Now().Add(time.Duration(link.TTL) * time.Second).UTC()
+
+	err = lh.LinkRepo.AddLink(context.Background(), r.Header.Get("x-user"), id, link.Payload, link.Views, expiresAt)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, linkResponseDTO{ID: id, ExpiresAt: expiresAt})
+}
+
+type linkPayloadResponseDTO struct {
+	Payload string `json:"payload"`
+}
+
+// LinkOpen is the public endpoint of secret links: it returns the encrypted secret and counts the view,
+// so the link burns after the last one. It is a POST, so that link previews and caches do not use up views.
+func (lh *LinkHandlers) LinkOpen(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	payload, found, err := lh.LinkRepo.TakeLink(context.Background(), pathValue(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	writeJSON(w, linkPayloadResponseDTO{Payload: payload})
+}
+
+type fileLinkRequestDTO struct {
+	Title string `json:"title" validate:"required"`
+}
+
+type fileLinkResponseDTO struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// FileLinkCreate returns a presigned link to download the file from the object storage.
+func (lh *LinkHandlers) FileLinkCreate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var file fileLinkRequestDTO
+	if !readJSON(w, r, &file) {
+		return
+	}
+
+	obj, err := lh.FileRepo.GetFileObject(context.Background(), file.Title, r.Header.Get("x-user"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if obj == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	url, expiresAt, err := lh.MinioService.PresignedGetObject(obj.CloudID, obj.FileName)
+	if err != nil {
+		log.Println("error in presigning file link:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, fileLinkResponseDTO{URL: url, ExpiresAt: expiresAt.UTC()})
+}
+
+// Page serves the secret link page, which asks the server for the secret and decrypts it in the browser.
+func (lh *LinkHandlers) Page(w http.ResponseWriter, r *http.Request) {
+	SecurityHeaders(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFileFS(w, r, lh.Static, "link.html")
+	})).ServeHTTP(w, r)
+}
+
+// Assets serves the script and the style of the secret link page under /s/.
+func (lh *LinkHandlers) Assets() http.Handler {
+	return SecurityHeaders(http.StripPrefix("/s/", http.FileServerFS(lh.Static)))
+}
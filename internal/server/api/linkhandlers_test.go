@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/repo"
+	"github.com/adettelle/go-keeper/mocks"
+	"github.com/carlmjohnson/requests"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// --------------------------------------- secret links ---------------------------------------
+// ------- Хендлер: POST /api/user/link
+func TestLinkCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	linkRepo := mocks.NewMockILinkRepo(ctrl)
+	h := &LinkHandlers{LinkRepo: linkRepo}
+
+	var gotID string
+	linkRepo.EXPECT().AddLink(gomock.Any(), "ane@aaa.com", gomock.Any(), "ciphertext", 1, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, id string, _ string, _ int, expiresAt time.Time) error {
+			gotID = id
+			require.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+			return nil
+		})
+
+	newRequest := func(link linkCreateRequestDTO) *http.Request {
+		request, err := requests.
+			URL("/api/user/link").
+			Method(http.MethodPost).
+			Header("x-user", "ane@aaa.com").
+			BodyJSON(&link).
+			Request(context.Background())
+		require.NoError(t, err)
+		return request
+	}
+
+	response := httptest.NewRecorder()
+	h.LinkCreate(response, newRequest(linkCreateRequestDTO{Payload: "ciphertext", TTL: 3600, Views: 1}))
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var link linkResponseDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &link))
+	require.Equal(t, gotID, link.ID)
+	require.Len(t, link.ID, 22)
+
+	// срок жизни и число просмотров ограничены
+	for _, dto := range []linkCreateRequestDTO{
+		{Payload: "ciphertext", TTL: 30 * 24 * 3600, Views: 1},
+		{Payload: "ciphertext", TTL: 3600, Views: 0},
+		{Payload: "ciphertext", TTL: 3600, Views: 1000},
+	} {
+		response = httptest.NewRecorder()
+		h.LinkCreate(response, newRequest(dto))
+		require.Equal(t, http.StatusBadRequest, response.Code)
+	}
+}
+
+// ------- Хендлер: POST /api/link/{id}
+func TestLinkOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	linkRepo := mocks.NewMockILinkRepo(ctrl)
+	h := &LinkHandlers{LinkRepo: linkRepo}
+
+	gomock.InOrder(
+		linkRepo.EXPECT().TakeLink(gomock.Any(), "abc").Return("ciphertext", true, nil),
+		linkRepo.EXPECT().TakeLink(gomock.Any(), "abc").Return("", false, nil),
+	)
+
+	newRequest := func() *http.Request {
+		request := httptest.NewRequest(http.MethodPost, "/api/link/abc", nil)
+		request.SetPathValue("id", "abc")
+		return request
+	}
+
+	response := httptest.NewRecorder()
+	h.LinkOpen(response, newRequest())
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, "no-store", response.Header().Get("Cache-Control"))
+
+	var payload linkPayloadResponseDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &payload))
+	require.Equal(t, "ciphertext", payload.Payload)
+
+	// после последнего просмотра ссылка сгорает
+	response = httptest.NewRecorder()
+	h.LinkOpen(response, newRequest())
+	require.Equal(t, http.StatusNotFound, response.Code)
+
+	// GET не расходует просмотры
+	response = httptest.NewRecorder()
+	h.LinkOpen(response, httptest.NewRequest(http.MethodGet, "/api/link/abc", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, response.Code)
+}
+
+// ------- Хендлер: POST /api/user/link/file
+func TestFileLinkCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	fileRepo := mocks.NewMockILinkFileRepo(ctrl)
+	minioService := mocks.NewMockIMinioService(ctrl)
+	h := &LinkHandlers{FileRepo: fileRepo, MinioService: minioService}
+
+	expiresAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fileRepo.EXPECT().GetFileObject(gomock.Any(), "contract", "ane@aaa.com").
+		Return(&repo.FileObject{CloudID: "uuid", FileName: "contract.pdf"}, nil)
+	minioService.EXPECT().PresignedGetObject("uuid", "contract.pdf").
+		Return("https://minio.example.com/test/uuid?X-Amz-Signature=sig", expiresAt, nil)
+	fileRepo.EXPECT().GetFileObject(gomock.Any(), "missing", "ane@aaa.com").Return(nil, nil)
+
+	newRequest := func(title string) *http.Request {
+		request, err := requests.
+			URL("/api/user/link/file").
+			Method(http.MethodPost).
+			Header("x-user", "ane@aaa.com").
+			BodyJSON(&fileLinkRequestDTO{Title: title}).
+			Request(context.Background())
+		require.NoError(t, err)
+		return request
+	}
+
+	response := httptest.NewRecorder()
+	h.FileLinkCreate(response, newRequest("contract"))
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var link fileLinkResponseDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &link))
+	require.Equal(t, "https://minio.example.com/test/uuid?X-Amz-Signature=sig", link.URL)
+	require.Equal(t, expiresAt, link.ExpiresAt)
+
+	response = httptest.NewRecorder()
+	h.FileLinkCreate(response, newRequest("missing"))
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
+
+// ------- Хендлер: GET /s/{id}
+func TestLinkPage(t *testing.T) {
+	h := &LinkHandlers{Static: fstest.MapFS{"link.html": {Data: []byte("<!DOCTYPE html>")}}}
+
+	response := httptest.NewRecorder()
+	h.Page(response, httptest.NewRequest(http.MethodGet, "/s/abc", nil))
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, "<!DOCTYPE html>", response.Body.String())
+	require.Contains(t, response.Header().Get("Content-Security-Policy"), "script-src 'self'")
+	require.Equal(t, "no-referrer", response.Header().Get("Referrer-Policy"))
+}
@@ -0,0 +1,301 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/adettelle/go-keeper/internal/encryption"
+	"github.com/adettelle/go-keeper/internal/repo"
+)
+
+// NoteHandlers manage secure notes: free-form text stored encrypted, e.g. recovery codes or instructions.
+type NoteHandlers struct {
+	NoteRepo INoteRepo
+	SignKey  []byte
+}
+
+func NewNoteHandlers(noteRepo INoteRepo, signKey []byte) *NoteHandlers {
+	return &NoteHandlers{
+		NoteRepo: noteRepo,
+		SignKey:  signKey,
+	}
+}
+
+type INoteRepo interface {
+	AddNote(ctx context.Context, note repo.Note, login string) error
+	GetAllNotes(ctx context.Context, login string, filter repo.ItemFilter,
+		page repo.Page) ([]repo.NoteToGet, *repo.ItemCursor, error)
+	GetNoteByTitle(ctx context.Context, title, login string) (*repo.Note, error)
+	UpdateNote(ctx context.Context, title string, upd repo.NoteUpdate, login string) (bool, error)
+	DeleteNoteByTitle(ctx context.Context, title, login string) (bool, error)
+}
+
+type noteCreateRequestDTO struct {
+	Title       string `json:"title" validate:"required,min=1"`
+	Description string `json:"description" validate:"max=1000"`
+	Text        string `json:"text" validate:"required,max=65536"` // Text is up to 64K characters.
+}
+
+func (nh *NoteHandlers) NoteAdd(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	userLogin := r.Header.Get("x-user")
+
+	var buf bytes.Buffer
+	var note noteCreateRequestDTO
+
+	// читаем тело запроса
+	_, err := buf.ReadFrom(r.Body)
+	if err != nil {
+		log.Println("error in reading body:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &note); err != nil {
+		log.Println("error in unmarshalling json:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = validate.Struct(note)
+	if err != nil {
+		log.Println("error in validating:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	encrypted, err := encryption.AESEncrypt(note.Text, nh.SignKey)
+	if err != nil {
+		log.Println("error in encrypting note:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = nh.NoteRepo.AddNote(context.Background(), repo.Note{
+		Title:       note.Title,
+		Description: note.Description,
+		Text:        encrypted,
+	}, userLogin)
+	if err != nil {
+		log.Println("error in adding note:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+type noteGetRequestDTO struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ItemMetaDTO
+}
+
+func NewNoteDTO(note repo.NoteToGet) *noteGetRequestDTO {
+	return &noteGetRequestDTO{
+		Title:       note.Title,
+		Description: note.Description,
+		ItemMetaDTO: NewItemMetaDTO(note.ItemMeta),
+	}
+}
+
+// AllNotes lists the secure notes without their text.
+func (nh *NoteHandlers) AllNotes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	userLogin := r.Header.Get("x-user")
+
+	filter, err := parseItemFilter(r)
+	if err != nil {
+		log.Println("error in parsing filter:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		log.Println("error in parsing page:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	notes, next, err := nh.NoteRepo.GetAllNotes(context.Background(), userLogin, filter, page)
+	if err != nil {
+		log.Println("error in getting notes:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	list := []*noteGetRequestDTO{}
+	for _, note := range notes {
+		list = append(list, NewNoteDTO(note))
+	}
+
+	resp, err := json.Marshal(list)
+	if err != nil {
+		log.Println("error in marshalling json:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err := setNextCursor(w, next); err != nil {
+		log.Println("error in marshalling cursor:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, err = w.Write(resp)
+	if err != nil {
+		log.Println("error in writing resp:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+type noteGetByTitleRequestDTO struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Text        string `json:"text"`
+}
+
+// NoteGetByTitle shows the secure note with its decrypted text by title.
+func (nh *NoteHandlers) NoteGetByTitle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	userLogin := r.Header.Get("x-user")
+	title := pathValue(r, "title")
+
+	note, err := nh.NoteRepo.GetNoteByTitle(context.Background(), title, userLogin)
+	if err != nil {
+		log.Println("error in getting note:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if note == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	text, err := encryption.AESDecrypt(note.Text, nh.SignKey)
+	if err != nil {
+		log.Println("error in decrypting note:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := json.Marshal(noteGetByTitleRequestDTO{
+		Title:       note.Title,
+		Description: note.Description,
+		Text:        text,
+	})
+	if err != nil {
+		log.Println("error in marshalling json:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, err = w.Write(resp)
+	if err != nil {
+		log.Println("error in writing resp:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+type noteUpdateRequestDTO struct {
+	Text        *string `json:"text,omitempty" validate:"omitnil,min=1,max=65536"`
+	Description *string `json:"description,omitempty" validate:"omitnil,max=1000"`
+}
+
+func (nh *NoteHandlers) NoteUpdate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	userLogin := r.Header.Get("x-user")
+	title := pathValue(r, "title")
+
+	var buf bytes.Buffer
+	var note noteUpdateRequestDTO
+
+	// читаем тело запроса
+	_, err := buf.ReadFrom(r.Body)
+	if err != nil {
+		log.Println("error in reading body:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &note); err != nil {
+		log.Println("error in unmarshalling json:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = validate.Struct(note)
+	if err != nil {
+		log.Println("error in validating:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	upd := repo.NoteUpdate{Description: note.Description}
+	if note.Text != nil {
+		encrypted, err := encryption.AESEncrypt(*note.Text, nh.SignKey)
+		if err != nil {
+			log.Println("error in encrypting note:", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		upd.Text = &encrypted
+	}
+
+	found, err := nh.NoteRepo.UpdateNote(context.Background(), title, upd, userLogin)
+	if err != nil {
+		log.Println("error in updating note:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (nh *NoteHandlers) NoteDeleteByTitle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	title := pathValue(r, "title")
+	userLogin := r.Header.Get("x-user")
+
+	found, err := nh.NoteRepo.DeleteNoteByTitle(context.Background(), title, userLogin)
+	if err != nil {
+		log.Println("error in deleting note:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+}
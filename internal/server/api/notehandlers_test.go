@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adettelle/go-keeper/internal/encryption"
+	"github.com/adettelle/go-keeper/internal/repo"
+	"github.com/adettelle/go-keeper/mocks"
+	"github.com/carlmjohnson/requests"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// ------- Хендлер: PUT /api/user/note
+func TestNoteAdd(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	noteRepo := mocks.NewMockINoteRepo(ctrl)
+	h := &NoteHandlers{NoteRepo: noteRepo, SignKey: []byte("1234567812345678")}
+
+	var added repo.Note
+	noteRepo.EXPECT().AddNote(gomock.Any(), gomock.Any(), "Ane").
+		DoAndReturn(func(_ context.Context, note repo.Note, _ string) error {
+			added = note
+			return nil
+		})
+
+	request, err := requests.
+		URL("/api/user/note").
+		Method(http.MethodPut).
+		BodyJSON(map[string]any{"title": "recovery", "text": "code-1\ncode-2"}).
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	h.NoteAdd(response, request)
+
+	require.Equal(t, http.StatusAccepted, response.Code)
+	require.Equal(t, "recovery", added.Title)
+	// текст хранится зашифрованным
+	require.NotContains(t, added.Text, "code-1")
+	text, err := encryption.AESDecrypt(added.Text, h.SignKey)
+	require.NoError(t, err)
+	require.Equal(t, "code-1\ncode-2", text)
+}
+
+// ------- Хендлер: PUT /api/user/note
+func TestNoteAddValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	h := &NoteHandlers{NoteRepo: mocks.NewMockINoteRepo(ctrl), SignKey: []byte("1234567812345678")}
+
+	for _, body := range []map[string]any{
+		{"title": "recovery"},
+		{"title": "", "text": "code"},
+		{"title": "recovery", "text": strings.Repeat("a", 65537)},
+	} {
+		request, err := requests.
+			URL("/api/user/note").
+			Method(http.MethodPut).
+			BodyJSON(body).
+			Header("x-user", "Ane").
+			Request(context.Background())
+		require.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		h.NoteAdd(response, request)
+
+		require.Equal(t, http.StatusBadRequest, response.Code)
+	}
+}
+
+// ------- Хендлер: GET /api/user/note/{title}
+func TestNoteGetByTitle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	noteRepo := mocks.NewMockINoteRepo(ctrl)
+	h := &NoteHandlers{NoteRepo: noteRepo, SignKey: []byte("1234567812345678")}
+
+	encrypted, err := encryption.AESEncrypt("code-1", h.SignKey)
+	require.NoError(t, err)
+	noteRepo.EXPECT().GetNoteByTitle(gomock.Any(), "recovery", "Ane").Return(&repo.Note{
+		Title: "recovery", Description: "github", Text: encrypted,
+	}, nil)
+	noteRepo.EXPECT().GetNoteByTitle(gomock.Any(), "unknown", "Ane").Return(nil, nil)
+
+	request, err := requests.
+		URL("/api/user/note/recovery").
+		Method(http.MethodGet).
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+	request.SetPathValue("title", "recovery")
+
+	response := httptest.NewRecorder()
+	h.NoteGetByTitle(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+	var res noteGetByTitleRequestDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &res))
+	require.Equal(t, noteGetByTitleRequestDTO{Title: "recovery", Description: "github", Text: "code-1"}, res)
+
+	request.SetPathValue("title", "unknown")
+	response = httptest.NewRecorder()
+	h.NoteGetByTitle(response, request)
+
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
+
+// ------- Хендлер: POST /api/user/note/update/{title}
+func TestNoteUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	noteRepo := mocks.NewMockINoteRepo(ctrl)
+	h := &NoteHandlers{NoteRepo: noteRepo, SignKey: []byte("1234567812345678")}
+
+	var upd repo.NoteUpdate
+	noteRepo.EXPECT().UpdateNote(gomock.Any(), "recovery", gomock.Any(), "Ane").
+		DoAndReturn(func(_ context.Context, _ string, u repo.NoteUpdate, _ string) (bool, error) {
+			upd = u
+			return true, nil
+		})
+	noteRepo.EXPECT().UpdateNote(gomock.Any(), "unknown", gomock.Any(), "Ane").Return(false, nil)
+
+	request, err := requests.
+		URL("/api/user/note/update/recovery").
+		Method(http.MethodPost).
+		BodyJSON(map[string]any{"text": "code-3"}).
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+	request.SetPathValue("title", "recovery")
+
+	response := httptest.NewRecorder()
+	h.NoteUpdate(response, request)
+
+	require.Equal(t, http.StatusAccepted, response.Code)
+	// описание не передано и не меняется
+	require.Nil(t, upd.Description)
+	require.NotNil(t, upd.Text)
+	text, err := encryption.AESDecrypt(*upd.Text, h.SignKey)
+	require.NoError(t, err)
+	require.Equal(t, "code-3", text)
+
+	request, err = requests.
+		URL("/api/user/note/update/unknown").
+		Method(http.MethodPost).
+		BodyJSON(map[string]any{"text": "code-3"}).
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+	request.SetPathValue("title", "unknown")
+
+	response = httptest.NewRecorder()
+	h.NoteUpdate(response, request)
+
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
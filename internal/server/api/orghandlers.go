@@ -0,0 +1,470 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/adettelle/go-keeper/internal/access"
+	"github.com/adettelle/go-keeper/internal/repo"
+)
+
+// CollectionHeader selects the shared collection, "org/collection", a request works with.
+// Without it the request works with the personal vault of the user.
+const CollectionHeader = "X-Collection"
+
+type IOrgRepo interface {
+	OrgExists(ctx context.Context, org string) (bool, error)
+	CreateOrg(ctx context.Context, org string, login string) error
+	DeleteOrg(ctx context.Context, org string) error
+	GetOrgs(ctx context.Context, login string) ([]repo.OrgMembership, error)
+	GetRole(ctx context.Context, org string, login string) (access.Role, error)
+	GetMembers(ctx context.Context, org string) ([]repo.OrgMember, error)
+	SetMember(ctx context.Context, org string, login string, role access.Role) (bool, error)
+	DeleteMember(ctx context.Context, org string, login string) error
+	CountOwners(ctx context.Context, org string) (int, error)
+	CollectionExists(ctx context.Context, org string, collection string) (bool, error)
+	CreateCollection(ctx context.Context, org string, collection string) error
+	GetCollections(ctx context.Context, org string) ([]string, error)
+	DeleteCollection(ctx context.Context, org string, collection string) (bool, error)
+	GetCollectionAccess(ctx context.Context, org string, collection string, login string) (*repo.CollectionAccess, error)
+}
+
+// OrgHandlers manage organizations, their members and shared collections,
+// and authorize access to the items of the collections.
+type OrgHandlers struct {
+	OrgRepo IOrgRepo
+}
+
+func NewOrgHandlers(orgRepo IOrgRepo) *OrgHandlers {
+	return &OrgHandlers{
+		OrgRepo: orgRepo,
+	}
+}
+
+// Authorize checks that the user may do the request with the items of the selected vault.
+// Every user owns the personal vault. For a shared collection the role of the user in the organization
+// must allow the permission; the request then works as the vault customer of the collection,
+// so the item handlers filter by its x-user and x-user-id.
+// The collection is reported as not found to users who are not members.
+func (oh *OrgHandlers) Authorize(perm access.Permission, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ref := r.Header.Get(CollectionHeader)
+		if ref == "" {
+			h(w, r)
+			return
+		}
+
+		org, collection, err := access.ParseCollection(ref)
+		if err != nil {
+			log.Println("error in parsing collection:", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		userLogin := r.Header.Get("x-user")
+		vault, err := oh.OrgRepo.GetCollectionAccess(context.Background(), org, collection, userLogin)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if vault == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if !vault.Role.Allows(perm) {
+			log.Printf("%s as %s is not allowed to change %s", userLogin, vault.Role, ref)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		r.Header.Set("x-user", vault.VaultLogin)
+		r.Header.Set("x-user-id", strconv.Itoa(vault.VaultID))
+		h(w, r)
+	}
+}
+
+// role returns the role of the user in the organization from the path. If the user is not a member,
+// it writes 404 and returns an empty role.
+func (oh *OrgHandlers) role(w http.ResponseWriter, r *http.Request) (string, access.Role) {
+	org := pathValue(r, "org")
+	role, err := oh.OrgRepo.GetRole(context.Background(), org, r.Header.Get("x-user"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return org, ""
+	}
+	if role == "" {
+		w.WriteHeader(http.StatusNotFound)
+	}
+	return org, role
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	resp, err := json.Marshal(v)
+	if err != nil {
+		log.Println("error in marshalling json:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(resp)
+	if err != nil {
+		log.Println("error in writing resp:", err)
+	}
+}
+
+// readJSON reads the request body into v and validates it. It writes the error status and returns false on failure.
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	var buf bytes.Buffer
+
+	_, err := buf.ReadFrom(r.Body)
+	if err != nil {
+		log.Println("error in reading body:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return false
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), v); err != nil {
+		log.Println("error in unmarshalling json:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return false
+	}
+
+	if err := validate.Struct(v); err != nil {
+		log.Println("error in validating:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+type orgCreateRequestDTO struct {
+	Name string `json:"name" validate:"required,org_name"`
+}
+
+// OrgCreate adds the organization with the user as its owner.
+func (oh *OrgHandlers) OrgCreate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	userLogin := r.Header.Get("x-user")
+
+	var org orgCreateRequestDTO
+	if !readJSON(w, r, &org) {
+		return
+	}
+
+	exists, err := oh.OrgRepo.OrgExists(context.Background(), org.Name)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if exists {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	err = oh.OrgRepo.CreateOrg(context.Background(), org.Name, userLogin)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+type orgResponseDTO struct {
+	Name string      `json:"name"`
+	Role access.Role `json:"role"`
+}
+
+// AllOrgs lists the organizations of the user with the user roles.
+func (oh *OrgHandlers) AllOrgs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	orgs, err := oh.OrgRepo.GetOrgs(context.Background(), r.Header.Get("x-user"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	res := make([]orgResponseDTO, 0, len(orgs))
+	for _, o := range orgs {
+		res = append(res, orgResponseDTO{Name: o.Org, Role: o.Role})
+	}
+	writeJSON(w, res)
+}
+
+// OrgDelete removes the organization. Only owners may do it, and only when it has no collections.
+func (oh *OrgHandlers) OrgDelete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	org, role := oh.role(w, r)
+	if role == "" {
+		return
+	}
+	if role != access.Owner {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	collections, err := oh.OrgRepo.GetCollections(context.Background(), org)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(collections) > 0 {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	err = oh.OrgRepo.DeleteOrg(context.Background(), org)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+type memberResponseDTO struct {
+	Login string      `json:"login"`
+	Name  string      `json:"name"`
+	Role  access.Role `json:"role"`
+}
+
+// AllMembers lists the members of the organization to any of its members.
+func (oh *OrgHandlers) AllMembers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	org, role := oh.role(w, r)
+	if role == "" {
+		return
+	}
+
+	members, err := oh.OrgRepo.GetMembers(context.Background(), org)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	res := make([]memberResponseDTO, 0, len(members))
+	for _, m := range members {
+		res = append(res, memberResponseDTO{Login: m.Login, Name: m.Name, Role: m.Role})
+	}
+	writeJSON(w, res)
+}
+
+type memberSetRequestDTO struct {
+	Login string      `json:"login" validate:"required,email"`
+	Role  access.Role `json:"role" validate:"required,oneof=owner admin editor viewer"`
+}
+
+// MemberSet adds the user to the organization or changes the member role. Admins grant editor and viewer
+// roles to members who are not admins or owners; owners grant any role.
+// The last owner can not step down.
+func (oh *OrgHandlers) MemberSet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	org, role := oh.role(w, r)
+	if role == "" {
+		return
+	}
+
+	var member memberSetRequestDTO
+	if !readJSON(w, r, &member) {
+		return
+	}
+
+	current, err := oh.OrgRepo.GetRole(context.Background(), org, member.Login)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !role.CanManage() || !role.CanGrant(member.Role) || (current != "" && !role.CanGrant(current)) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if current == access.Owner && member.Role != access.Owner && !oh.hasOtherOwners(w, org) {
+		return
+	}
+
+	found, err := oh.OrgRepo.SetMember(context.Background(), org, member.Login, member.Role)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// hasOtherOwners checks that the organization keeps an owner when one leaves. It writes 409 if not.
+func (oh *OrgHandlers) hasOtherOwners(w http.ResponseWriter, org string) bool {
+	owners, err := oh.OrgRepo.CountOwners(context.Background(), org)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return false
+	}
+	if owners < 2 {
+		log.Println("the last owner can not leave organization", org)
+		w.WriteHeader(http.StatusConflict)
+		return false
+	}
+	return true
+}
+
+// MemberDelete removes the member from the organization. Members may always leave by themselves;
+// removing others needs the right to grant their role.
+func (oh *OrgHandlers) MemberDelete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	org, role := oh.role(w, r)
+	if role == "" {
+		return
+	}
+
+	login := pathValue(r, "login")
+	current, err := oh.OrgRepo.GetRole(context.Background(), org, login)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if current == "" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	self := login == r.Header.Get("x-user")
+	if !self && (!role.CanManage() || !role.CanGrant(current)) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if current == access.Owner && !oh.hasOtherOwners(w, org) {
+		return
+	}
+
+	err = oh.OrgRepo.DeleteMember(context.Background(), org, login)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+type collectionCreateRequestDTO struct {
+	Name string `json:"name" validate:"required,org_name"`
+}
+
+// CollectionCreate adds a shared collection to the organization. Admins and owners may do it.
+func (oh *OrgHandlers) CollectionCreate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	org, role := oh.role(w, r)
+	if role == "" {
+		return
+	}
+	if !role.CanManage() {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	var collection collectionCreateRequestDTO
+	if !readJSON(w, r, &collection) {
+		return
+	}
+
+	exists, err := oh.OrgRepo.CollectionExists(context.Background(), org, collection.Name)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if exists {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	err = oh.OrgRepo.CreateCollection(context.Background(), org, collection.Name)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+// AllCollections lists the collections of the organization to any of its members.
+func (oh *OrgHandlers) AllCollections(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	org, role := oh.role(w, r)
+	if role == "" {
+		return
+	}
+
+	collections, err := oh.OrgRepo.GetCollections(context.Background(), org)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, collections)
+}
+
+// CollectionDelete removes an empty collection. Admins and owners may do it.
+func (oh *OrgHandlers) CollectionDelete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	org, role := oh.role(w, r)
+	if role == "" {
+		return
+	}
+	if !role.CanManage() {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	deleted, err := oh.OrgRepo.DeleteCollection(context.Background(), org, pathValue(r, "name"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,242 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adettelle/go-keeper/internal/access"
+	"github.com/adettelle/go-keeper/internal/repo"
+	"github.com/adettelle/go-keeper/mocks"
+	"github.com/carlmjohnson/requests"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// --------------------------------------- organizations ---------------------------------------
+// ------- Middleware: authorization of vault requests
+func TestAuthorize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orgRepo := mocks.NewMockIOrgRepo(ctrl)
+	h := &OrgHandlers{OrgRepo: orgRepo}
+
+	var gotUser, gotUserID string
+	next := func(w http.ResponseWriter, r *http.Request) {
+		gotUser, gotUserID = r.Header.Get("x-user"), r.Header.Get("x-user-id")
+		w.WriteHeader(http.StatusOK)
+	}
+
+	vault := &repo.CollectionAccess{VaultID: 42, VaultLogin: "collection:acme/staging"}
+	orgRepo.EXPECT().GetCollectionAccess(gomock.Any(), "acme", "staging", "ane@aaa.com").
+		DoAndReturn(func(context.Context, string, string, string) (*repo.CollectionAccess, error) {
+			return vault, nil
+		}).AnyTimes()
+	orgRepo.EXPECT().GetCollectionAccess(gomock.Any(), "acme", "staging", "stranger@aaa.com").Return(nil, nil)
+
+	tests := []struct {
+		name       string
+		login      string
+		collection string
+		role       access.Role
+		perm       access.Permission
+		wantCode   int
+		wantUser   string
+	}{
+		{"personal vault", "ane@aaa.com", "", "", access.Write, http.StatusOK, "ane@aaa.com"},
+		{"viewer reads", "ane@aaa.com", "acme/staging", access.Viewer, access.Read, http.StatusOK, vault.VaultLogin},
+		{"viewer writes", "ane@aaa.com", "acme/staging", access.Viewer, access.Write, http.StatusForbidden, ""},
+		{"editor writes", "ane@aaa.com", "acme/staging", access.Editor, access.Write, http.StatusOK, vault.VaultLogin},
+		{"not a member", "stranger@aaa.com", "acme/staging", "", access.Read, http.StatusNotFound, ""},
+		{"invalid collection", "ane@aaa.com", "staging", "", access.Read, http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vault.Role = tt.role
+			gotUser, gotUserID = "", ""
+
+			request := httptest.NewRequest(http.MethodGet, "/api/user/passwords", nil)
+			request.Header.Set("x-user", tt.login)
+			request.Header.Set("x-user-id", "7")
+			if tt.collection != "" {
+				request.Header.Set(CollectionHeader, tt.collection)
+			}
+
+			response := httptest.NewRecorder()
+			h.Authorize(tt.perm, next)(response, request)
+			require.Equal(t, tt.wantCode, response.Code)
+			require.Equal(t, tt.wantUser, gotUser)
+			if tt.wantUser == vault.VaultLogin {
+				require.Equal(t, "42", gotUserID)
+			}
+		})
+	}
+}
+
+// ------- Хендлер: PUT /api/user/org
+func TestOrgCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orgRepo := mocks.NewMockIOrgRepo(ctrl)
+	h := &OrgHandlers{OrgRepo: orgRepo}
+
+	orgRepo.EXPECT().OrgExists(gomock.Any(), "acme").Return(false, nil)
+	orgRepo.EXPECT().CreateOrg(gomock.Any(), "acme", "ane@aaa.com").Return(nil)
+
+	request, err := requests.
+		URL("/api/user/org").
+		Method(http.MethodPut).
+		Header("x-user", "ane@aaa.com").
+		BodyJSON(&orgCreateRequestDTO{Name: "acme"}).
+		Request(context.Background())
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	h.OrgCreate(response, request)
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	// имя проверяется
+	request, err = requests.
+		URL("/api/user/org").
+		Method(http.MethodPut).
+		Header("x-user", "ane@aaa.com").
+		BodyJSON(&orgCreateRequestDTO{Name: "Acme Corp"}).
+		Request(context.Background())
+	require.NoError(t, err)
+
+	response = httptest.NewRecorder()
+	h.OrgCreate(response, request)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+// ------- Хендлер: PUT /api/user/org/{org}/member
+func TestMemberSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     access.Role // role is the role of the user making the request.
+		current  access.Role // current is the role of the member before.
+		newRole  access.Role
+		owners   int
+		wantCode int
+	}{
+		{"owner adds admin", access.Owner, "", access.Admin, 1, http.StatusOK},
+		{"admin adds editor", access.Admin, "", access.Editor, 1, http.StatusOK},
+		{"admin adds admin", access.Admin, "", access.Admin, 1, http.StatusForbidden},
+		{"admin demotes owner", access.Admin, access.Owner, access.Viewer, 2, http.StatusForbidden},
+		{"editor adds viewer", access.Editor, "", access.Viewer, 1, http.StatusForbidden},
+		{"last owner steps down", access.Owner, access.Owner, access.Admin, 1, http.StatusConflict},
+		{"one of owners steps down", access.Owner, access.Owner, access.Admin, 2, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			orgRepo := mocks.NewMockIOrgRepo(ctrl)
+			h := &OrgHandlers{OrgRepo: orgRepo}
+
+			orgRepo.EXPECT().GetRole(gomock.Any(), "acme", "ane@aaa.com").Return(tt.role, nil)
+			orgRepo.EXPECT().GetRole(gomock.Any(), "acme", "bob@aaa.com").Return(tt.current, nil)
+			orgRepo.EXPECT().CountOwners(gomock.Any(), "acme").Return(tt.owners, nil).AnyTimes()
+			if tt.wantCode == http.StatusOK {
+				orgRepo.EXPECT().SetMember(gomock.Any(), "acme", "bob@aaa.com", tt.newRole).Return(true, nil)
+			}
+
+			request, err := requests.
+				URL("/api/user/org/acme/member").
+				Method(http.MethodPut).
+				Header("x-user", "ane@aaa.com").
+				BodyJSON(&memberSetRequestDTO{Login: "bob@aaa.com", Role: tt.newRole}).
+				Request(context.Background())
+			require.NoError(t, err)
+			request.SetPathValue("org", "acme")
+
+			response := httptest.NewRecorder()
+			h.MemberSet(response, request)
+			require.Equal(t, tt.wantCode, response.Code)
+		})
+	}
+}
+
+// ------- Хендлер: DELETE /api/user/org/{org}/member/{login}
+func TestMemberDeleteSelf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orgRepo := mocks.NewMockIOrgRepo(ctrl)
+	h := &OrgHandlers{OrgRepo: orgRepo}
+
+	// a viewer may leave by itself
+	orgRepo.EXPECT().GetRole(gomock.Any(), "acme", "ane@aaa.com").Return(access.Viewer, nil).Times(2)
+	orgRepo.EXPECT().DeleteMember(gomock.Any(), "acme", "ane@aaa.com").Return(nil)
+
+	request := httptest.NewRequest(http.MethodDelete, "/api/user/org/acme/member/ane@aaa.com", nil)
+	request.Header.Set("x-user", "ane@aaa.com")
+	request.SetPathValue("org", "acme")
+	request.SetPathValue("login", "ane@aaa.com")
+
+	response := httptest.NewRecorder()
+	h.MemberDelete(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+}
+
+// ------- Хендлер: GET /api/user/org/{org}/members
+func TestAllMembers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orgRepo := mocks.NewMockIOrgRepo(ctrl)
+	h := &OrgHandlers{OrgRepo: orgRepo}
+
+	orgRepo.EXPECT().GetRole(gomock.Any(), "acme", "ane@aaa.com").Return(access.Viewer, nil)
+	orgRepo.EXPECT().GetMembers(gomock.Any(), "acme").Return([]repo.OrgMember{
+		{Login: "bob@aaa.com", Name: "Bob", Role: access.Owner},
+		{Login: "ane@aaa.com", Name: "Ane", Role: access.Viewer},
+	}, nil)
+	orgRepo.EXPECT().GetRole(gomock.Any(), "acme", "stranger@aaa.com").Return(access.Role(""), nil)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/user/org/acme/members", nil)
+	request.Header.Set("x-user", "ane@aaa.com")
+	request.SetPathValue("org", "acme")
+
+	response := httptest.NewRecorder()
+	h.AllMembers(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var members []memberResponseDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &members))
+	require.Equal(t, []memberResponseDTO{
+		{Login: "bob@aaa.com", Name: "Bob", Role: access.Owner},
+		{Login: "ane@aaa.com", Name: "Ane", Role: access.Viewer},
+	}, members)
+
+	// не член организации её не видит
+	request = httptest.NewRequest(http.MethodGet, "/api/user/org/acme/members", nil)
+	request.Header.Set("x-user", "stranger@aaa.com")
+	request.SetPathValue("org", "acme")
+
+	response = httptest.NewRecorder()
+	h.AllMembers(response, request)
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
+
+// ------- Хендлер: DELETE /api/user/org/{org}/collection/{name}
+func TestCollectionDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orgRepo := mocks.NewMockIOrgRepo(ctrl)
+	h := &OrgHandlers{OrgRepo: orgRepo}
+
+	newRequest := func() *http.Request {
+		request := httptest.NewRequest(http.MethodDelete, "/api/user/org/acme/collection/staging", nil)
+		request.Header.Set("x-user", "ane@aaa.com")
+		request.SetPathValue("org", "acme")
+		request.SetPathValue("name", "staging")
+		return request
+	}
+
+	orgRepo.EXPECT().GetRole(gomock.Any(), "acme", "ane@aaa.com").Return(access.Editor, nil)
+	response := httptest.NewRecorder()
+	h.CollectionDelete(response, newRequest())
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	// в коллекции остались записи
+	orgRepo.EXPECT().GetRole(gomock.Any(), "acme", "ane@aaa.com").Return(access.Admin, nil)
+	orgRepo.EXPECT().DeleteCollection(gomock.Any(), "acme", "staging").Return(false, nil)
+	response = httptest.NewRecorder()
+	h.CollectionDelete(response, newRequest())
+	require.Equal(t, http.StatusConflict, response.Code)
+}
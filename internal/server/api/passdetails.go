@@ -0,0 +1,329 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/encryption"
+	"github.com/adettelle/go-keeper/internal/repo"
+	"github.com/adettelle/go-keeper/internal/urlmatch"
+)
+
+// Match rules of password URLs.
+const (
+	MatchDomain = urlmatch.RuleDomain
+	MatchHost   = urlmatch.RuleHost
+	MatchExact  = urlmatch.RuleExact
+	MatchNever  = urlmatch.RuleNever
+)
+
+// Types of password custom fields.
+const (
+	FieldText    = "text"
+	FieldHidden  = "hidden"
+	FieldBoolean = "boolean"
+)
+
+const (
+	maxPasswordURLs   = 32
+	maxPasswordFields = 64
+)
+
+type PasswordURLDTO struct {
+	URL   string `json:"url"`
+	Match string `json:"match,omitempty"`
+}
+
+type PasswordFieldDTO struct {
+	Name  string `json:"name"`
+	Type  string `json:"type,omitempty"`
+	Value string `json:"value"`
+}
+
+// validatePasswordURLs checks the URLs and sets the default match rule.
+func validatePasswordURLs(urls []PasswordURLDTO) error {
+	if len(urls) > maxPasswordURLs {
+		return fmt.Errorf("too many urls, max is %d", maxPasswordURLs)
+	}
+	for i, u := range urls {
+		if _, err := urlmatch.Parse(u.URL); err != nil {
+			return fmt.Errorf("invalid url %q", u.URL)
+		}
+		switch u.Match {
+		case "":
+			urls[i].Match = MatchDomain
+		case MatchDomain, MatchHost, MatchExact, MatchNever:
+		default:
+			return fmt.Errorf("invalid match rule %q", u.Match)
+		}
+	}
+	return nil
+}
+
+// validatePasswordFields checks the custom fields and sets the default type.
+func validatePasswordFields(fields []PasswordFieldDTO) error {
+	if len(fields) > maxPasswordFields {
+		return fmt.Errorf("too many fields, max is %d", maxPasswordFields)
+	}
+	names := map[string]bool{}
+	for i, f := range fields {
+		if f.Name == "" {
+			return errors.New("field name is empty")
+		}
+		if names[f.Name] {
+			return fmt.Errorf("duplicate field %q", f.Name)
+		}
+		names[f.Name] = true
+
+		switch f.Type {
+		case "":
+			fields[i].Type = FieldText
+		case FieldText, FieldHidden:
+		case FieldBoolean:
+			if f.Value != "true" && f.Value != "false" {
+				return fmt.Errorf("boolean field %q must be true or false", f.Name)
+			}
+		default:
+			return fmt.Errorf("invalid field type %q", f.Type)
+		}
+	}
+	return nil
+}
+
+// encryptURLs encrypts the validated URLs and builds their blind indexes.
+func (ph *PassHandlers) encryptURLs(urls []PasswordURLDTO) ([]repo.PasswordURL, error) {
+	var res []repo.PasswordURL
+	for _, u := range urls {
+		encrypted, err := encryption.AESEncrypt(u.URL, ph.SignKey)
+		if err != nil {
+			return nil, err
+		}
+		key, err := urlmatch.Parse(u.URL)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, repo.PasswordURL{URL: encrypted, Match: u.Match, Index: ph.urlIndex(key)})
+	}
+	return res, nil
+}
+
+// urlIndex returns the blind index of the URL key. The index key is derived from the sign key.
+func (ph *PassHandlers) urlIndex(key urlmatch.Key) repo.URLIndex {
+	idx := urlmatch.NewIndex(urlmatch.IndexKey(ph.SignKey), key)
+	return repo.URLIndex{Domain: idx.Domain, Host: idx.Host, URL: idx.URL}
+}
+
+// IndexStoredURL builds the blind index of a stored encrypted URL.
+func (ph *PassHandlers) IndexStoredURL(encryptedURL string) (repo.URLIndex, error) {
+	u, err := encryption.AESDecrypt(encryptedURL, ph.SignKey)
+	if err != nil {
+		return repo.URLIndex{}, err
+	}
+	key, err := urlmatch.Parse(u)
+	if err != nil {
+		return repo.URLIndex{}, err
+	}
+	return ph.urlIndex(key), nil
+}
+
+func (ph *PassHandlers) encryptFields(fields []PasswordFieldDTO) ([]repo.PasswordField, error) {
+	var res []repo.PasswordField
+	for _, f := range fields {
+		encrypted, err := encryption.AESEncrypt(f.Value, ph.SignKey)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, repo.PasswordField{Name: f.Name, Type: f.Type, Value: encrypted})
+	}
+	return res, nil
+}
+
+type PasswordDetailsResponseDTO struct {
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Password    string             `json:"pwd"`
+	Username    string             `json:"username"`
+	ChangedAt   time.Time          `json:"changed_at"`
+	URLs        []PasswordURLDTO   `json:"urls"`
+	Fields      []PasswordFieldDTO `json:"fields"`
+	MaxAgeDays  int                `json:"max_age_days"`
+}
+
+// decryptDetails decrypts the secret values of the password entry.
+func (ph *PassHandlers) decryptDetails(entry *repo.PasswordEntry) (*PasswordDetailsResponseDTO, error) {
+	res := &PasswordDetailsResponseDTO{
+		Title:       entry.Title,
+		Description: entry.Description,
+		ChangedAt:   entry.ChangedAt,
+		MaxAgeDays:  entry.MaxAgeDays,
+		URLs:        []PasswordURLDTO{},
+		Fields:      []PasswordFieldDTO{},
+	}
+
+	var err error
+	if res.Password, err = encryption.AESDecrypt(entry.Password, ph.SignKey); err != nil {
+		return nil, err
+	}
+	if res.Username, err = encryption.AESDecrypt(entry.Username, ph.SignKey); err != nil {
+		return nil, err
+	}
+	for _, u := range entry.URLs {
+		decrypted, err := encryption.AESDecrypt(u.URL, ph.SignKey)
+		if err != nil {
+			return nil, err
+		}
+		res.URLs = append(res.URLs, PasswordURLDTO{URL: decrypted, Match: u.Match})
+	}
+	for _, f := range entry.Fields {
+		decrypted, err := encryption.AESDecrypt(f.Value, ph.SignKey)
+		if err != nil {
+			return nil, err
+		}
+		res.Fields = append(res.Fields, PasswordFieldDTO{Name: f.Name, Type: f.Type, Value: decrypted})
+	}
+	return res, nil
+}
+
+// PasswordDetails returns the password entry by title with its username, URLs and custom fields.
+func (ph *PassHandlers) PasswordDetails(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	userLogin := r.Header.Get("x-user")
+	pwdTitle := pathValue(r, "title")
+
+	entry, err := ph.PwdRepo.GetPasswordDetails(context.Background(), pwdTitle, userLogin)
+	if err != nil {
+		log.Println("error in getting password details:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if entry == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	details, err := ph.decryptDetails(entry)
+	if err != nil {
+		log.Println("error in decrypting password details:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := json.Marshal(details)
+	if err != nil {
+		log.Println("error in marshalling json:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, err = w.Write(resp)
+	if err != nil {
+		log.Println("error in writing resp:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// matchesAny reports whether any of the URLs matches the target according to its match rule.
+func matchesAny(urls []PasswordURLDTO, target urlmatch.Key) bool {
+	for _, u := range urls {
+		stored, err := urlmatch.Parse(u.URL)
+		if err == nil && urlmatch.Matches(u.Match, stored, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutHiddenValues returns the fields with the values of hidden ones cleared.
+func withoutHiddenValues(fields []PasswordFieldDTO) []PasswordFieldDTO {
+	res := make([]PasswordFieldDTO, 0, len(fields))
+	for _, f := range fields {
+		if f.Type == FieldHidden {
+			f.Value = ""
+		}
+		res = append(res, f)
+	}
+	return res
+}
+
+type PasswordMatchResponseDTO struct {
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Username    string             `json:"username"`
+	URLs        []PasswordURLDTO   `json:"urls"`
+	Fields      []PasswordFieldDTO `json:"fields"`
+}
+
+// PasswordsMatch returns the password entries with a URL matching the url query parameter
+// according to the URL match rule. The lookup goes by the blind index, which only narrows the entries down:
+// the decrypted URLs of every entry are checked against the rule before it is returned.
+// Passwords and values of hidden fields are not returned.
+func (ph *PassHandlers) PasswordsMatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	userLogin := r.Header.Get("x-user")
+
+	key, err := urlmatch.Parse(r.URL.Query().Get("url"))
+	if err != nil {
+		log.Println("error in parsing url:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	entries, err := ph.PwdRepo.MatchPasswords(context.Background(), userLogin, ph.urlIndex(key))
+	if err != nil {
+		log.Println("error in matching passwords:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	res := []PasswordMatchResponseDTO{}
+	for i := range entries {
+		details, err := ph.decryptDetails(&entries[i])
+		if err != nil {
+			log.Println("error in decrypting password details:", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !matchesAny(details.URLs, key) {
+			continue
+		}
+		res = append(res, PasswordMatchResponseDTO{
+			Title:       details.Title,
+			Description: details.Description,
+			Username:    details.Username,
+			URLs:        details.URLs,
+			Fields:      withoutHiddenValues(details.Fields),
+		})
+	}
+
+	resp, err := json.Marshal(res)
+	if err != nil {
+		log.Println("error in marshalling json:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, err = w.Write(resp)
+	if err != nil {
+		log.Println("error in writing resp:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/adettelle/go-keeper/internal/encryption"
 	"github.com/adettelle/go-keeper/internal/repo"
@@ -28,22 +29,31 @@ func NewPassHandlers(pwdRepo IPwdRepo, signKey []byte, cfg *config.Config) *Pass
 }
 
 type IPwdRepo interface {
-	GetAllPasswords(ctx context.Context, name string) ([]repo.Password, error)
-	CreatePassword(ctx context.Context, password, title, description string, login string) error
-	UpdatePassword(ctx context.Context, title string, password *string, description *string, userID int) error
+	GetAllPasswords(ctx context.Context, name string, filter repo.ItemFilter,
+		page repo.Page) ([]repo.Password, *repo.ItemCursor, error)
+	CreatePassword(ctx context.Context, password, title, description string,
+		details repo.PasswordDetails, meta repo.ItemMeta, login string) error
+	UpdatePassword(ctx context.Context, title string, password *string, description *string,
+		details repo.PasswordDetailsUpdate, userID int) (bool, error)
 	DeletePassword(ctx context.Context, title string, login string) error
 	GetPasswordByTitle(ctx context.Context, title string, login string) (string, error)
+	GetPasswordDetails(ctx context.Context, title string, login string) (*repo.PasswordEntry, error)
+	MatchPasswords(ctx context.Context, login string, idx repo.URLIndex) ([]repo.PasswordEntry, error)
 }
 
 type PasswordResponseDTO struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	ChangedAt   time.Time `json:"changed_at"`
+	ItemMetaDTO
 }
 
 func NewPwdResponseDTO(pwd repo.Password) *PasswordResponseDTO {
 	return &PasswordResponseDTO{
 		Title:       pwd.Title,
 		Description: pwd.Description,
+		ChangedAt:   pwd.ChangedAt,
+		ItemMetaDTO: NewItemMetaDTO(pwd.ItemMeta),
 	}
 }
 
@@ -66,7 +76,20 @@ func (ph *PassHandlers) AllPasswords(w http.ResponseWriter, r *http.Request) {
 
 	userLogin := r.Header.Get("x-user")
 
-	pwds, err := ph.PwdRepo.GetAllPasswords(context.Background(), userLogin)
+	filter, err := parseItemFilter(r)
+	if err != nil {
+		log.Println("error in parsing filter:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		log.Println("error in parsing page:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	pwds, next, err := ph.PwdRepo.GetAllPasswords(context.Background(), userLogin, filter, page)
 	if err != nil {
 		log.Println("error in getting passwords: ", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -79,6 +102,11 @@ func (ph *PassHandlers) AllPasswords(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	if err := setNextCursor(w, next); err != nil {
+		log.Println("error in marshalling cursor:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
 
 	_, err = w.Write(resp)
 	if err != nil {
@@ -89,9 +117,15 @@ func (ph *PassHandlers) AllPasswords(w http.ResponseWriter, r *http.Request) {
 }
 
 type PwdCreateRequestDTO struct {
-	Password    string `json:"pwd" validate:"required,min=1"`
-	Title       string `json:"title" validate:"required,min=1"`
-	Description string `json:"description"`
+	Password    string             `json:"pwd" validate:"required,min=1"`
+	Title       string             `json:"title" validate:"required,min=1"`
+	Description string             `json:"description"`
+	Username    string             `json:"username"`
+	URLs        []PasswordURLDTO   `json:"urls"`
+	Fields      []PasswordFieldDTO `json:"fields"`
+	MaxAgeDays  int                `json:"max_age_days" validate:"min=0,max=3650"`
+	Folder      string             `json:"folder"`
+	Tags        []string           `json:"tags"`
 }
 
 func (ph *PassHandlers) PasswordCreate(w http.ResponseWriter, r *http.Request) {
@@ -120,6 +154,19 @@ func (ph *PassHandlers) PasswordCreate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err = validate.Struct(pwd)
+	if err == nil {
+		err = validatePasswordURLs(pwd.URLs)
+	}
+	if err == nil {
+		err = validatePasswordFields(pwd.Fields)
+	}
+	var meta repo.ItemMeta
+	if err == nil {
+		meta.Folder, err = normalizeFolder(pwd.Folder)
+	}
+	if err == nil && len(pwd.Tags) > 0 {
+		meta.Tags, err = normalizeTags(pwd.Tags)
+	}
 	if err != nil {
 		log.Println("error in validating:", err)
 		w.WriteHeader(http.StatusBadRequest)
@@ -133,8 +180,22 @@ func (ph *PassHandlers) PasswordCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	details := repo.PasswordDetails{MaxAgeDays: pwd.MaxAgeDays}
+	details.Username, err = encryption.AESEncrypt(pwd.Username, ph.SignKey)
+	if err == nil {
+		details.URLs, err = ph.encryptURLs(pwd.URLs)
+	}
+	if err == nil {
+		details.Fields, err = ph.encryptFields(pwd.Fields)
+	}
+	if err != nil {
+		log.Println("error in encrypting password details:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
 	err = ph.PwdRepo.CreatePassword(
-		context.Background(), encryptedPass, pwd.Title, pwd.Description, userLogin)
+		context.Background(), encryptedPass, pwd.Title, pwd.Description, details, meta, userLogin)
 	if err != nil {
 		log.Println("error in adding password:", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -144,8 +205,12 @@ func (ph *PassHandlers) PasswordCreate(w http.ResponseWriter, r *http.Request) {
 }
 
 type pwdUpdateRequestDTO struct {
-	Password    *string `json:"pwd,omitempty"`
-	Description *string `json:"description,omitempty"` // для ссылки значение по умолчанию - nil
+	Password    *string             `json:"pwd,omitempty"`
+	Description *string             `json:"description,omitempty"` // для ссылки значение по умолчанию - nil
+	Username    *string             `json:"username,omitempty"`
+	URLs        *[]PasswordURLDTO   `json:"urls,omitempty"`   // заменяет все ссылки
+	Fields      *[]PasswordFieldDTO `json:"fields,omitempty"` // заменяет все поля
+	MaxAgeDays  *int                `json:"max_age_days,omitempty" validate:"omitnil,min=0,max=3650"`
 }
 
 func (ph *PassHandlers) PasswordUpdate(w http.ResponseWriter, r *http.Request) {
@@ -163,7 +228,7 @@ func (ph *PassHandlers) PasswordUpdate(w http.ResponseWriter, r *http.Request) {
 
 	var buf bytes.Buffer
 	var pwd pwdUpdateRequestDTO
-	title := r.PathValue("title")
+	title := pathValue(r, "title")
 
 	// читаем тело запроса
 	_, err = buf.ReadFrom(r.Body)
@@ -179,19 +244,78 @@ func (ph *PassHandlers) PasswordUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	encryptedPass, err := encryption.AESEncrypt(*pwd.Password, ph.SignKey)
-	if err != nil {
-		log.Println("error in encrypting password:", err)
-		w.WriteHeader(http.StatusInternalServerError)
+	var encryptedPass *string
+	if pwd.Password != nil {
+		res, err := encryption.AESEncrypt(*pwd.Password, ph.SignKey)
+		if err != nil {
+			log.Println("error in encrypting password:", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		encryptedPass = &res
+	}
+
+	if err := validate.Struct(pwd); err != nil {
+		log.Println("error in validating:", err)
+		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	err = ph.PwdRepo.UpdatePassword(context.Background(), title, &encryptedPass, pwd.Description, custID)
+	details := repo.PasswordDetailsUpdate{MaxAgeDays: pwd.MaxAgeDays}
+	if pwd.URLs != nil {
+		if err := validatePasswordURLs(*pwd.URLs); err != nil {
+			log.Println("error in validating:", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	if pwd.Fields != nil {
+		if err := validatePasswordFields(*pwd.Fields); err != nil {
+			log.Println("error in validating:", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	if pwd.Username != nil {
+		res, err := encryption.AESEncrypt(*pwd.Username, ph.SignKey)
+		if err != nil {
+			log.Println("error in encrypting username:", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		details.Username = &res
+	}
+	if pwd.URLs != nil {
+		res, err := ph.encryptURLs(*pwd.URLs)
+		if err != nil {
+			log.Println("error in encrypting urls:", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		details.URLs = &res
+	}
+	if pwd.Fields != nil {
+		res, err := ph.encryptFields(*pwd.Fields)
+		if err != nil {
+			log.Println("error in encrypting fields:", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		details.Fields = &res
+	}
+
+	found, err := ph.PwdRepo.UpdatePassword(context.Background(), title, encryptedPass, pwd.Description,
+		details, custID)
 	if err != nil {
 		log.Println("error in updating password:", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
 	w.WriteHeader(http.StatusAccepted) // новый пароль принят
 }
 
@@ -201,7 +325,7 @@ func (ph *PassHandlers) PasswordDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pwdTitle := r.PathValue("title")
+	pwdTitle := pathValue(r, "title")
 	userLogin := r.Header.Get("x-user")
 
 	err := ph.PwdRepo.DeletePassword(context.Background(), pwdTitle, userLogin)
@@ -222,7 +346,7 @@ func (ph *PassHandlers) PasswordByTitle(w http.ResponseWriter, r *http.Request)
 	}
 
 	userLogin := r.Header.Get("x-user")
-	pwdTitle := r.PathValue("title")
+	pwdTitle := pathValue(r, "title")
 
 	pwd, err := ph.PwdRepo.GetPasswordByTitle(context.Background(), pwdTitle, userLogin)
 	if err != nil {
@@ -9,9 +9,11 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/adettelle/go-keeper/internal/encryption"
 	"github.com/adettelle/go-keeper/internal/repo"
+	"github.com/adettelle/go-keeper/internal/urlmatch"
 	"github.com/adettelle/go-keeper/mocks"
 	"github.com/carlmjohnson/requests"
 	"github.com/golang/mock/gomock"
@@ -44,7 +46,7 @@ func TestPasswordCreate(t *testing.T) {
 	encryptedPass, err := encryption.AESEncrypt(pwd.Password, h.SignKey)
 	require.NoError(t, err)
 	pwdRepo.EXPECT().CreatePassword(gomock.Any(), encryptedPass,
-		pwd.Title, pwd.Description, login).Return(nil)
+		pwd.Title, pwd.Description, repo.PasswordDetails{}, repo.ItemMeta{}, login).Return(nil)
 
 	request, err := requests.
 		URL("/api/user/password").
@@ -86,7 +88,7 @@ func TestPasswordCreateFailure(t *testing.T) {
 	encryptedPass, err := encryption.AESEncrypt(pwd.Password, h.SignKey)
 	require.NoError(t, err)
 	pwdRepo.EXPECT().CreatePassword(gomock.Any(), encryptedPass,
-		pwd.Title, pwd.Description, login).Return(fmt.Errorf("DB error"))
+		pwd.Title, pwd.Description, repo.PasswordDetails{}, repo.ItemMeta{}, login).Return(fmt.Errorf("DB error"))
 
 	request, err := requests.
 		URL("/api/user/password").
@@ -123,13 +125,15 @@ func TestAllPasswords(t *testing.T) {
 		{
 			Title:       "title1",
 			Description: "description1",
+			ChangedAt:   time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC),
 		},
 		{
 			Title:       "title2",
 			Description: "description2",
+			ChangedAt:   time.Date(2023, 5, 17, 8, 30, 0, 0, time.UTC),
 		},
 	}
-	pwdRepo.EXPECT().GetAllPasswords(gomock.Any(), login).Return(pwds, nil)
+	pwdRepo.EXPECT().GetAllPasswords(gomock.Any(), login, repo.ItemFilter{}, repo.Page{}).Return(pwds, nil, nil)
 
 	request, err := requests.
 		URL("/api/user/passwords").
@@ -156,6 +160,82 @@ func TestAllPasswords(t *testing.T) {
 	assert.JSONEq(t, string(expectedBody), string(resBody))
 }
 
+// ------- Хендлер: GET /api/user/passwords?folder=...&tag=...&favorite=...
+func TestAllPasswordsFiltered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	pwdRepo := mocks.NewMockIPwdRepo(ctrl)
+
+	h := &PassHandlers{
+		PwdRepo: pwdRepo,
+		SignKey: []byte("my_super_secret_key"),
+	}
+
+	login := "Ane"
+
+	pwds := []repo.Password{
+		{
+			Title:    "db",
+			ItemMeta: repo.ItemMeta{Folder: "work/prod", Tags: []string{"prod", "db"}, Favorite: true},
+		},
+	}
+	pwdRepo.EXPECT().GetAllPasswords(gomock.Any(), login,
+		repo.ItemFilter{Folder: "work", Tags: []string{"prod"}, Favorite: true, Search: "db"},
+		repo.Page{Sort: repo.SortTitleDesc, Limit: 1}).
+		Return(pwds, &repo.ItemCursor{Favorite: true, Key: "db"}, nil)
+
+	request, err := requests.
+		URL("/api/user/passwords").
+		Param("folder", "/work/").
+		Param("tag", "prod").
+		Param("favorite", "true").
+		Param("q", " db ").
+		Param("sort", "-title").
+		Param("limit", "1").
+		Method(http.MethodGet).
+		Header("x-user", login).
+		Request(context.Background())
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	h.AllPasswords(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	assert.JSONEq(t, `[{"title":"db","description":"","changed_at":"0001-01-01T00:00:00Z",
+		"folder":"work/prod","tags":["prod","db"],"favorite":true}]`, response.Body.String())
+
+	// the next page starts after the returned cursor
+	cursor := response.Header().Get(nextCursorHeader)
+	require.NotEmpty(t, cursor)
+
+	request.URL.RawQuery = "limit=1&cursor=" + cursor
+	page, err := parsePage(request)
+	require.NoError(t, err)
+	require.Equal(t, repo.Page{Limit: 1, After: &repo.ItemCursor{Favorite: true, Key: "db"}}, page)
+}
+
+// ------- Хендлер: GET /api/user/passwords?folder=...
+func TestAllPasswordsBadFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	h := &PassHandlers{
+		PwdRepo: mocks.NewMockIPwdRepo(ctrl),
+		SignKey: []byte("my_super_secret_key"),
+	}
+
+	request, err := requests.
+		URL("/api/user/passwords").
+		Param("folder", "work//prod").
+		Method(http.MethodGet).
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	h.AllPasswords(response, request)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+}
+
 // ------- Хендлер: GET /api/user/passwords
 func TestAllPasswordsFailure(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -170,7 +250,7 @@ func TestAllPasswordsFailure(t *testing.T) {
 	login := "Ane"
 	userID := 123
 
-	pwdRepo.EXPECT().GetAllPasswords(gomock.Any(), login).Return(nil, fmt.Errorf("DB error"))
+	pwdRepo.EXPECT().GetAllPasswords(gomock.Any(), login, repo.ItemFilter{}, repo.Page{}).Return(nil, nil, fmt.Errorf("DB error"))
 
 	request, err := requests.
 		URL("/api/user/passwords").
@@ -327,7 +407,8 @@ func TestPasswordUpdate(t *testing.T) {
 	}
 	encryptedPass, err := encryption.AESEncrypt(*pwd.Password, h.SignKey)
 	require.NoError(t, err)
-	pwdRepo.EXPECT().UpdatePassword(gomock.Any(), title, &encryptedPass, pwd.Description, userID).Return(nil)
+	pwdRepo.EXPECT().UpdatePassword(gomock.Any(), title, &encryptedPass, pwd.Description,
+		repo.PasswordDetailsUpdate{}, userID).Return(true, nil)
 
 	request, err := requests.
 		URL("/api/user/password/update/"+title).
@@ -349,6 +430,45 @@ func TestPasswordUpdate(t *testing.T) {
 	require.Equal(t, wantHTTPStatus, response.Code)
 }
 
+// ------- Хендлер: Post /api/user/password/update
+func TestPasswordUpdateDescriptionOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	pwdRepo := mocks.NewMockIPwdRepo(ctrl)
+
+	h := &PassHandlers{
+		PwdRepo: pwdRepo,
+		SignKey: []byte("my_super_secret_key"),
+	}
+
+	login := "Ane"
+	userID := 123
+
+	description := "new description"
+	pwd := pwdUpdateRequestDTO{
+		Description: &description,
+	}
+	pwdRepo.EXPECT().UpdatePassword(gomock.Any(), "title1", nil, pwd.Description,
+		repo.PasswordDetailsUpdate{}, userID).Return(true, nil)
+
+	request, err := requests.
+		URL("/api/user/password/update/title1").
+		Method(http.MethodPost).
+		Header("x-user", login).
+		Header("x-user-id", strconv.Itoa(userID)).
+		BodyJSON(&pwd).
+		Request(context.Background())
+	require.NoError(t, err)
+
+	request.SetPathValue("title", "title1")
+
+	response := httptest.NewRecorder()
+
+	h.PasswordUpdate(response, request)
+
+	require.Equal(t, http.StatusAccepted, response.Code)
+}
+
 // ------- Хендлер: Post /api/user/password/update
 func TestPasswordUpdateFailure(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -372,7 +492,7 @@ func TestPasswordUpdateFailure(t *testing.T) {
 	encryptedPass, err := encryption.AESEncrypt(*pwd.Password, h.SignKey)
 	require.NoError(t, err)
 	pwdRepo.EXPECT().UpdatePassword(gomock.Any(), "title1", &encryptedPass,
-		pwd.Description, userID).Return(fmt.Errorf("Update repo fail"))
+		pwd.Description, repo.PasswordDetailsUpdate{}, userID).Return(false, fmt.Errorf("Update repo fail"))
 
 	request, err := requests.
 		URL("/api/user/password/update/title1").
@@ -463,3 +583,318 @@ func TestPasswordDeleteFailure(t *testing.T) {
 
 	require.Equal(t, wantHTTPStatus, response.Code)
 }
+
+// ------- Хендлер: PUT /api/user/password (с логином, ссылками и полями)
+func TestPasswordCreateWithDetails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	pwdRepo := mocks.NewMockIPwdRepo(ctrl)
+
+	h := &PassHandlers{
+		PwdRepo: pwdRepo,
+		SignKey: []byte("my_super_secret_key"),
+	}
+
+	login := "Ane"
+
+	pwd := PwdCreateRequestDTO{
+		Password: "password",
+		Title:    "github",
+		Username: "ane",
+		URLs:     []PasswordURLDTO{{URL: "https://github.com/login"}},
+		Fields: []PasswordFieldDTO{
+			{Name: "recovery", Type: FieldHidden, Value: "code"},
+			{Name: "2fa", Type: FieldBoolean, Value: "true"},
+		},
+		Folder: " work / dev/",
+		Tags:   []string{"git", " git "},
+	}
+
+	encrypt := func(s string) string {
+		res, err := encryption.AESEncrypt(s, h.SignKey)
+		require.NoError(t, err)
+		return res
+	}
+	details := repo.PasswordDetails{
+		Username: encrypt("ane"),
+		URLs: []repo.PasswordURL{{URL: encrypt("https://github.com/login"), Match: MatchDomain,
+			Index: h.urlIndex(mustParseURL(t, "https://github.com/login"))}},
+		Fields: []repo.PasswordField{
+			{Name: "recovery", Type: FieldHidden, Value: encrypt("code")},
+			{Name: "2fa", Type: FieldBoolean, Value: encrypt("true")},
+		},
+	}
+	pwdRepo.EXPECT().CreatePassword(gomock.Any(), encrypt(pwd.Password),
+		pwd.Title, "", details, repo.ItemMeta{Folder: "work/dev", Tags: []string{"git"}}, login).Return(nil)
+
+	request, err := requests.
+		URL("/api/user/password").
+		Method(http.MethodPut).
+		BodyJSON(&pwd).
+		Header("x-user", login).
+		Request(context.Background())
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	h.PasswordCreate(response, request)
+
+	require.Equal(t, http.StatusAccepted, response.Code)
+}
+
+// ------- Хендлер: PUT /api/user/password (неверные ссылки и поля)
+func TestPasswordCreateInvalidDetails(t *testing.T) {
+	tests := []struct {
+		name   string
+		urls   []PasswordURLDTO
+		fields []PasswordFieldDTO
+	}{
+		{name: "url without scheme", urls: []PasswordURLDTO{{URL: "github.com"}}},
+		{name: "unknown match rule", urls: []PasswordURLDTO{{URL: "https://github.com", Match: "path"}}},
+		{name: "empty field name", fields: []PasswordFieldDTO{{Value: "v"}}},
+		{name: "duplicate field", fields: []PasswordFieldDTO{{Name: "a"}, {Name: "a"}}},
+		{name: "unknown field type", fields: []PasswordFieldDTO{{Name: "a", Type: "date"}}},
+		{name: "not a boolean", fields: []PasswordFieldDTO{{Name: "a", Type: FieldBoolean, Value: "yes"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			h := &PassHandlers{
+				PwdRepo: mocks.NewMockIPwdRepo(ctrl),
+				SignKey: []byte("my_super_secret_key"),
+			}
+
+			pwd := PwdCreateRequestDTO{Password: "password", Title: "github", URLs: tt.urls, Fields: tt.fields}
+
+			request, err := requests.
+				URL("/api/user/password").
+				Method(http.MethodPut).
+				BodyJSON(&pwd).
+				Header("x-user", "Ane").
+				Request(context.Background())
+			require.NoError(t, err)
+
+			response := httptest.NewRecorder()
+			h.PasswordCreate(response, request)
+
+			require.Equal(t, http.StatusBadRequest, response.Code)
+		})
+	}
+}
+
+// ------- Хендлер: Post /api/user/password/update (замена ссылок)
+func TestPasswordUpdateURLs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	pwdRepo := mocks.NewMockIPwdRepo(ctrl)
+
+	h := &PassHandlers{
+		PwdRepo: pwdRepo,
+		SignKey: []byte("my_super_secret_key"),
+	}
+
+	userID := 123
+
+	urls := []PasswordURLDTO{{URL: "https://example.com", Match: MatchExact}}
+	pwd := pwdUpdateRequestDTO{URLs: &urls}
+
+	encryptedURL, err := encryption.AESEncrypt("https://example.com", h.SignKey)
+	require.NoError(t, err)
+	wantURLs := []repo.PasswordURL{{URL: encryptedURL, Match: MatchExact,
+		Index: h.urlIndex(mustParseURL(t, "https://example.com"))}}
+	pwdRepo.EXPECT().UpdatePassword(gomock.Any(), "title1", nil, nil,
+		repo.PasswordDetailsUpdate{URLs: &wantURLs}, userID).Return(false, nil)
+
+	request, err := requests.
+		URL("/api/user/password/update/title1").
+		Method(http.MethodPost).
+		Header("x-user-id", strconv.Itoa(userID)).
+		BodyJSON(&pwd).
+		Request(context.Background())
+	require.NoError(t, err)
+
+	request.SetPathValue("title", "title1")
+
+	response := httptest.NewRecorder()
+
+	h.PasswordUpdate(response, request)
+
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
+
+// ------- Хендлер: GET /api/user/password/details/{title}
+func TestPasswordDetails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	pwdRepo := mocks.NewMockIPwdRepo(ctrl)
+
+	h := &PassHandlers{
+		PwdRepo: pwdRepo,
+		SignKey: []byte("my_super_secret_key"),
+	}
+
+	login := "Ane"
+
+	encrypt := func(s string) string {
+		res, err := encryption.AESEncrypt(s, h.SignKey)
+		require.NoError(t, err)
+		return res
+	}
+	changedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	pwdRepo.EXPECT().GetPasswordDetails(gomock.Any(), "github", login).Return(&repo.PasswordEntry{
+		Password:    encrypt("password"),
+		Title:       "github",
+		Description: "work",
+		ChangedAt:   changedAt,
+		PasswordDetails: repo.PasswordDetails{
+			Username: encrypt("ane"),
+			URLs:     []repo.PasswordURL{{URL: encrypt("https://github.com"), Match: MatchHost}},
+			Fields:   []repo.PasswordField{{Name: "pin", Type: FieldHidden, Value: encrypt("1234")}},
+		},
+	}, nil)
+	pwdRepo.EXPECT().GetPasswordDetails(gomock.Any(), "missing", login).Return(nil, nil)
+
+	request, err := requests.
+		URL("/api/user/password/details/github").
+		Header("x-user", login).
+		Request(context.Background())
+	require.NoError(t, err)
+	request.SetPathValue("title", "github")
+
+	response := httptest.NewRecorder()
+	h.PasswordDetails(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var got PasswordDetailsResponseDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &got))
+	assert.Equal(t, PasswordDetailsResponseDTO{
+		Title:       "github",
+		Description: "work",
+		Password:    "password",
+		Username:    "ane",
+		ChangedAt:   changedAt,
+		URLs:        []PasswordURLDTO{{URL: "https://github.com", Match: MatchHost}},
+		Fields:      []PasswordFieldDTO{{Name: "pin", Type: FieldHidden, Value: "1234"}},
+	}, got)
+
+	request.SetPathValue("title", "missing")
+	response = httptest.NewRecorder()
+	h.PasswordDetails(response, request)
+
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func mustParseURL(t *testing.T, rawURL string) urlmatch.Key {
+	t.Helper()
+	key, err := urlmatch.Parse(rawURL)
+	require.NoError(t, err)
+	return key
+}
+
+// ------- Хендлер: GET /api/user/passwords/match?url=...
+func TestPasswordsMatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	pwdRepo := mocks.NewMockIPwdRepo(ctrl)
+
+	h := &PassHandlers{
+		PwdRepo: pwdRepo,
+		SignKey: []byte("my_super_secret_key"),
+	}
+
+	login := "Ane"
+
+	encrypt := func(s string) string {
+		res, err := encryption.AESEncrypt(s, h.SignKey)
+		require.NoError(t, err)
+		return res
+	}
+
+	// the repo is queried by the blind index of the visited url, not the url itself
+	idx := h.urlIndex(mustParseURL(t, "https://mail.example.com/inbox"))
+	require.NotContains(t, idx.Domain, "example")
+	// индекс только сужает выборку: запись с правилом exact для другого адреса не возвращается
+	pwdRepo.EXPECT().MatchPasswords(gomock.Any(), login, idx).Return([]repo.PasswordEntry{{
+		Password: encrypt("password"),
+		Title:    "example",
+		PasswordDetails: repo.PasswordDetails{
+			Username: encrypt("ane"),
+			URLs:     []repo.PasswordURL{{URL: encrypt("https://example.com"), Match: MatchDomain}},
+			Fields: []repo.PasswordField{
+				{Name: "pin", Type: FieldHidden, Value: encrypt("1234")},
+				{Name: "note", Type: FieldText, Value: encrypt("work")},
+			},
+		},
+	}, {
+		Password: encrypt("password"),
+		Title:    "example-admin",
+		PasswordDetails: repo.PasswordDetails{
+			Username: encrypt("admin"),
+			URLs:     []repo.PasswordURL{{URL: encrypt("https://mail.example.com/admin"), Match: MatchExact}},
+		},
+	}}, nil)
+
+	request, err := requests.
+		URL("/api/user/passwords/match").
+		Param("url", "https://mail.example.com/inbox").
+		Header("x-user", login).
+		Request(context.Background())
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	h.PasswordsMatch(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NotContains(t, response.Body.String(), "password")
+	require.NotContains(t, response.Body.String(), "1234")
+
+	var got []PasswordMatchResponseDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &got))
+	assert.Equal(t, []PasswordMatchResponseDTO{{
+		Title:    "example",
+		Username: "ane",
+		URLs:     []PasswordURLDTO{{URL: "https://example.com", Match: MatchDomain}},
+		Fields: []PasswordFieldDTO{
+			{Name: "pin", Type: FieldHidden},
+			{Name: "note", Type: FieldText, Value: "work"},
+		},
+	}}, got)
+}
+
+// ------- Хендлер: GET /api/user/passwords/match?url=...
+func TestPasswordsMatchInvalidURL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	h := &PassHandlers{
+		PwdRepo: mocks.NewMockIPwdRepo(ctrl),
+		SignKey: []byte("my_super_secret_key"),
+	}
+
+	request, err := requests.
+		URL("/api/user/passwords/match").
+		Param("url", "example.com").
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	h.PasswordsMatch(response, request)
+
+	require.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+// Индекс сохранённого адреса совпадает с индексом, который строится при создании записи.
+func TestIndexStoredURL(t *testing.T) {
+	h := &PassHandlers{SignKey: []byte("my_super_secret_key")}
+
+	urls, err := h.encryptURLs([]PasswordURLDTO{{URL: "https://mail.example.com/inbox", Match: MatchDomain}})
+	require.NoError(t, err)
+
+	idx, err := h.IndexStoredURL(urls[0].URL)
+	require.NoError(t, err)
+	require.Equal(t, urls[0].Index, idx)
+
+	_, err = h.IndexStoredURL("not encrypted")
+	require.Error(t, err)
+}
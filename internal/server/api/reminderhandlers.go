@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/encryption"
+	"github.com/adettelle/go-keeper/internal/paycard"
+	"github.com/adettelle/go-keeper/internal/repo"
+	"github.com/adettelle/go-keeper/internal/server/config"
+)
+
+// maxReminderDays limits the days query parameter of the reminders.
+const maxReminderDays = 3650
+
+// Reasons of the reminders.
+const (
+	ReasonCardExpiry       = "card_expiry"
+	ReasonPasswordRotation = "password_rotation"
+)
+
+// ReminderHandlers report cards which expire and passwords which reach their max age soon.
+type ReminderHandlers struct {
+	ReminderRepo IReminderRepo
+	SignKey      []byte
+	Config       *config.Config
+	now          func() time.Time // now is replaced in tests.
+}
+
+func NewReminderHandlers(reminderRepo IReminderRepo, signKey []byte, cfg *config.Config) *ReminderHandlers {
+	return &ReminderHandlers{
+		ReminderRepo: reminderRepo,
+		SignKey:      signKey,
+		Config:       cfg,
+	}
+}
+
+type IReminderRepo interface {
+	GetCardExpiries(ctx context.Context, login string) ([]repo.CardExpiry, error)
+	GetPasswordsDue(ctx context.Context, login string, before time.Time) ([]repo.PasswordRotation, error)
+}
+
+type ReminderResponseDTO struct {
+	Kind    repo.ItemKind `json:"kind"`
+	Title   string        `json:"title"`
+	Reason  string        `json:"reason"`
+	DueAt   time.Time     `json:"due_at"`
+	Overdue bool          `json:"overdue"`
+}
+
+func (rh *ReminderHandlers) clock() time.Time {
+	if rh.now == nil {
+		return time.Now()
+	}
+	return rh.now()
+}
+
+// windowDays returns the days query parameter or the configured default.
+func (rh *ReminderHandlers) windowDays(r *http.Request) (int, bool) {
+	value := r.URL.Query().Get("days")
+	if value == "" {
+		if rh.Config != nil {
+			return rh.Config.ReminderWindowDays, true
+		}
+		return 30, true
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 || days > maxReminderDays {
+		return 0, false
+	}
+	return days, true
+}
+
+// Reminders lists the cards which are expired or expire within the window and the passwords
+// which reached or reach their max age within it, the earliest first.
+func (rh *ReminderHandlers) Reminders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	userLogin := r.Header.Get("x-user")
+
+	days, ok := rh.windowDays(r)
+	if !ok {
+		log.Println("error in parsing days:", r.URL.Query().Get("days"))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	now := rh.clock()
+	before := now.AddDate(0, 0, days)
+
+	res := []ReminderResponseDTO{}
+
+	cards, err := rh.ReminderRepo.GetCardExpiries(context.Background(), userLogin)
+	if err != nil {
+		log.Println("error in getting card expiries:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	for _, card := range cards {
+		expire, err := encryption.AESDecrypt(card.Expire, rh.SignKey)
+		if err != nil {
+			log.Println("error in decrypting card expires date:", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		expiresAt, err := paycard.ParseExpiry(expire)
+		if err != nil {
+			// карты, добавленные до проверки срока, могут хранить некорректную дату
+			log.Printf("card %q has invalid expiry date: %v", card.Title, err)
+			continue
+		}
+		if expiresAt.After(before) {
+			continue
+		}
+		res = append(res, ReminderResponseDTO{
+			Kind:    repo.KindCard,
+			Title:   card.Title,
+			Reason:  ReasonCardExpiry,
+			DueAt:   expiresAt,
+			Overdue: !now.Before(expiresAt),
+		})
+	}
+
+	pwds, err := rh.ReminderRepo.GetPasswordsDue(context.Background(), userLogin, before)
+	if err != nil {
+		log.Println("error in getting passwords due:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	for _, pwd := range pwds {
+		res = append(res, ReminderResponseDTO{
+			Kind:    repo.KindPassword,
+			Title:   pwd.Title,
+			Reason:  ReasonPasswordRotation,
+			DueAt:   pwd.DueAt,
+			Overdue: !now.Before(pwd.DueAt),
+		})
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].DueAt.Before(res[j].DueAt) })
+
+	resp, err := json.Marshal(res)
+	if err != nil {
+		log.Println("error in marshalling json:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, err = w.Write(resp)
+	if err != nil {
+		log.Println("error in writing resp:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
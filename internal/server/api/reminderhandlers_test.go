@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/encryption"
+	"github.com/adettelle/go-keeper/internal/repo"
+	"github.com/adettelle/go-keeper/internal/server/config"
+	"github.com/adettelle/go-keeper/mocks"
+	"github.com/carlmjohnson/requests"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// ------- Хендлер: GET /api/user/reminders
+func TestReminders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	signKey := []byte("1234567812345678")
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+
+	reminderRepo := mocks.NewMockIReminderRepo(ctrl)
+	h := &ReminderHandlers{
+		ReminderRepo: reminderRepo,
+		SignKey:      signKey,
+		Config:       &config.Config{ReminderWindowDays: 30},
+		now:          func() time.Time { return now },
+	}
+
+	encrypt := func(s string) string {
+		res, err := encryption.AESEncrypt(s, signKey)
+		require.NoError(t, err)
+		return res
+	}
+
+	reminderRepo.EXPECT().GetCardExpiries(gomock.Any(), "Ane").Return([]repo.CardExpiry{
+		{Title: "expired", Expire: encrypt("04/24")},
+		{Title: "soon", Expire: encrypt("05/24")},
+		{Title: "later", Expire: encrypt("12/26")},
+		{Title: "broken", Expire: encrypt("13/99")},
+	}, nil)
+	passwordDue := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	reminderRepo.EXPECT().GetPasswordsDue(gomock.Any(), "Ane", now.AddDate(0, 0, 30)).
+		Return([]repo.PasswordRotation{{Title: "mail", DueAt: passwordDue}}, nil)
+
+	request, err := requests.
+		URL("/api/user/reminders").
+		Method(http.MethodGet).
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	h.Reminders(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var res []ReminderResponseDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &res))
+	require.Equal(t, []ReminderResponseDTO{
+		{Kind: repo.KindCard, Title: "expired", Reason: ReasonCardExpiry,
+			DueAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Overdue: true},
+		{Kind: repo.KindCard, Title: "soon", Reason: ReasonCardExpiry,
+			DueAt: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), Overdue: false},
+		{Kind: repo.KindPassword, Title: "mail", Reason: ReasonPasswordRotation,
+			DueAt: passwordDue, Overdue: false},
+	}, res)
+}
+
+// ------- Хендлер: GET /api/user/reminders?days=
+func TestRemindersInvalidDays(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	h := &ReminderHandlers{ReminderRepo: mocks.NewMockIReminderRepo(ctrl)}
+
+	for _, days := range []string{"-1", "abc", "3651"} {
+		request, err := requests.
+			URL("/api/user/reminders").
+			Param("days", days).
+			Method(http.MethodGet).
+			Header("x-user", "Ane").
+			Request(context.Background())
+		require.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		h.Reminders(response, request)
+
+		require.Equal(t, http.StatusBadRequest, response.Code, days)
+	}
+}
@@ -5,44 +5,149 @@ package api
 import (
 	"net/http"
 
+	"github.com/adettelle/go-keeper/internal/access"
+	"github.com/adettelle/go-keeper/internal/repo"
 	"github.com/adettelle/go-keeper/pkg/mware"
 	"github.com/go-chi/chi/v5"
 )
 
 func NewRouter(handlers *CustomerHandlers, cardHandlers *CardHandlers, passHandlers *PassHandlers,
-	fileHandlers *FileHandlers, jwtChecker mware.JwtChecker) chi.Router {
+	fileHandlers *FileHandlers, identityHandlers *IdentityHandlers, sshKeyHandlers *SSHKeyHandlers,
+	noteHandlers *NoteHandlers, itemHandlers *ItemHandlers, reminderHandlers *ReminderHandlers, orgHandlers *OrgHandlers,
+	shareHandlers *ShareHandlers, linkHandlers *LinkHandlers, emergencyHandlers *EmergencyHandlers,
+	breachHandlers *BreachHandlers, webHandlers *WebHandlers, jwtChecker mware.JwtChecker) chi.Router {
 
 	r := chi.NewRouter()
 
+	// The web vault, only when it is turned on. Its cookie sessions are checked before the routes.
+	if webHandlers != nil {
+		r.Use(webHandlers.CookieAuth)
+
+		r.Handle("/web/*", webHandlers.StaticFiles())
+		r.Get("/web", http.RedirectHandler("/web/", http.StatusMovedPermanently).ServeHTTP)
+		r.Post("/web/login", SecurityHeaders(http.HandlerFunc(webHandlers.Login)).ServeHTTP)
+		r.Get("/web/session", SecurityHeaders(http.HandlerFunc(webHandlers.Session)).ServeHTTP)
+		r.Post("/web/logout", SecurityHeaders(http.HandlerFunc(webHandlers.Logout)).ServeHTTP)
+	}
+
 	// withAuth wraps a given HTTP handler with authentication middleware.
 	withAuth := func(h http.HandlerFunc) http.HandlerFunc {
 		return mware.AuthMwr(h, handlers.SignKey, jwtChecker)
 	}
+	// read and write authorize item requests in the personal vault or in the shared collection
+	// selected by the X-Collection header.
+	read := func(h http.HandlerFunc) http.HandlerFunc {
+		return withAuth(orgHandlers.Authorize(access.Read, h))
+	}
+	write := func(h http.HandlerFunc) http.HandlerFunc {
+		return withAuth(orgHandlers.Authorize(access.Write, h))
+	}
 
 	// User authentication routes
 	r.Post("/api/user/register", handlers.RegisterCustomer)
 	r.Post("/api/user/login", handlers.Login)
 
 	// Password management routes
-	r.Put("/api/user/password", withAuth(passHandlers.PasswordCreate))
-	r.Get("/api/user/passwords", withAuth(passHandlers.AllPasswords))
-	r.Get("/api/user/password/{title}", withAuth(passHandlers.PasswordByTitle))
-	r.Post("/api/user/password/update/{title}", withAuth(passHandlers.PasswordUpdate))
-	r.Delete("/api/user/password/{title}", withAuth(passHandlers.PasswordDelete))
+	r.Put("/api/user/password", write(passHandlers.PasswordCreate))
+	r.Get("/api/user/passwords", read(passHandlers.AllPasswords))
+	r.Get("/api/user/passwords/match", read(passHandlers.PasswordsMatch))
+	r.Get("/api/user/password/{title}", read(passHandlers.PasswordByTitle))
+	r.Get("/api/user/password/details/{title}", read(passHandlers.PasswordDetails))
+	r.Post("/api/user/password/update/{title}", write(passHandlers.PasswordUpdate))
+	r.Delete("/api/user/password/{title}", write(passHandlers.PasswordDelete))
 
 	// File management routes
-	r.Put("/api/user/file", withAuth(fileHandlers.FileAdd))
-	r.Get("/api/user/files", withAuth(fileHandlers.AllFiles))
-	r.Get("/api/user/file/{title}", withAuth(fileHandlers.FileGetByTitle))
-	r.Post("/api/user/file/update/{title}", withAuth(fileHandlers.FileUpdate))
-	r.Delete("/api/user/file/{title}", withAuth(fileHandlers.FileDeleteByTitle))
+	r.Put("/api/user/file", write(fileHandlers.FileAdd))
+	r.Get("/api/user/files", read(fileHandlers.AllFiles))
+	r.Get("/api/user/file/{title}", read(fileHandlers.FileGetByTitle))
+	r.Post("/api/user/file/update/{title}", write(fileHandlers.FileUpdate))
+	r.Delete("/api/user/file/{title}", write(fileHandlers.FileDeleteByTitle))
 
 	// Card management routes
-	r.Put("/api/user/card", withAuth(cardHandlers.CardAdd))
-	r.Get("/api/user/cards", withAuth(cardHandlers.AllCards))
-	r.Get("/api/user/card/{title}", withAuth(cardHandlers.CardGetByTitle))
-	r.Post("/api/user/card/update/{title}", withAuth(cardHandlers.CardUpdate))
-	r.Delete("/api/user/card/{title}", withAuth(cardHandlers.CardDeleteByTitle))
+	r.Put("/api/user/card", write(cardHandlers.CardAdd))
+	r.Get("/api/user/cards", read(cardHandlers.AllCards))
+	r.Get("/api/user/card/{title}", read(cardHandlers.CardGetByTitle))
+	r.Post("/api/user/card/update/{title}", write(cardHandlers.CardUpdate))
+	r.Delete("/api/user/card/{title}", write(cardHandlers.CardDeleteByTitle))
+
+	// Identity document management routes
+	r.Put("/api/user/identity", write(identityHandlers.IdentityAdd))
+	r.Get("/api/user/identities", read(identityHandlers.AllIdentities))
+	r.Get("/api/user/identity/{title}", read(identityHandlers.IdentityGetByTitle))
+	r.Post("/api/user/identity/update/{title}", write(identityHandlers.IdentityUpdate))
+	r.Delete("/api/user/identity/{title}", write(identityHandlers.IdentityDeleteByTitle))
+
+	// SSH key management routes
+	r.Put("/api/user/sshkey", write(sshKeyHandlers.SSHKeyAdd))
+	r.Get("/api/user/sshkeys", read(sshKeyHandlers.AllSSHKeys))
+	r.Get("/api/user/sshkey/{title}", read(sshKeyHandlers.SSHKeyGetByTitle))
+	r.Post("/api/user/sshkey/update/{title}", write(sshKeyHandlers.SSHKeyUpdate))
+	r.Delete("/api/user/sshkey/{title}", write(sshKeyHandlers.SSHKeyDeleteByTitle))
+
+	// Secure note management routes
+	r.Put("/api/user/note", write(noteHandlers.NoteAdd))
+	r.Get("/api/user/notes", read(noteHandlers.AllNotes))
+	r.Get("/api/user/note/{title}", read(noteHandlers.NoteGetByTitle))
+	r.Post("/api/user/note/update/{title}", write(noteHandlers.NoteUpdate))
+	r.Delete("/api/user/note/{title}", write(noteHandlers.NoteDeleteByTitle))
+
+	// Folders, tags and favorites of all item kinds
+	r.Get("/api/user/folders", read(itemHandlers.AllFolders))
+	r.Get("/api/user/items/search", read(itemHandlers.SearchItems))
+	r.Post("/api/user/password/meta/{title}", write(itemHandlers.ItemMetaUpdate(repo.KindPassword)))
+	r.Post("/api/user/card/meta/{title}", write(itemHandlers.ItemMetaUpdate(repo.KindCard)))
+	r.Post("/api/user/file/meta/{title}", write(itemHandlers.ItemMetaUpdate(repo.KindFile)))
+	r.Post("/api/user/identity/meta/{title}", write(itemHandlers.ItemMetaUpdate(repo.KindIdentity)))
+	r.Post("/api/user/sshkey/meta/{title}", write(itemHandlers.ItemMetaUpdate(repo.KindSSHKey)))
+	r.Post("/api/user/note/meta/{title}", write(itemHandlers.ItemMetaUpdate(repo.KindNote)))
+
+	// Cards expiring and passwords due for rotation
+	r.Get("/api/user/reminders", read(reminderHandlers.Reminders))
+
+	// Organizations, members and shared collections
+	r.Put("/api/user/org", withAuth(orgHandlers.OrgCreate))
+	r.Get("/api/user/orgs", withAuth(orgHandlers.AllOrgs))
+	r.Delete("/api/user/org/{org}", withAuth(orgHandlers.OrgDelete))
+	r.Get("/api/user/org/{org}/members", withAuth(orgHandlers.AllMembers))
+	r.Put("/api/user/org/{org}/member", withAuth(orgHandlers.MemberSet))
+	r.Delete("/api/user/org/{org}/member/{login}", withAuth(orgHandlers.MemberDelete))
+	r.Put("/api/user/org/{org}/collection", withAuth(orgHandlers.CollectionCreate))
+	r.Get("/api/user/org/{org}/collections", withAuth(orgHandlers.AllCollections))
+	r.Delete("/api/user/org/{org}/collection/{name}", withAuth(orgHandlers.CollectionDelete))
+
+	// Sharing keys and items shared between users, end-to-end encrypted by the clients
+	r.Get("/api/user/keys", withAuth(shareHandlers.KeysGet))
+	r.Put("/api/user/keys", withAuth(shareHandlers.KeysAdd))
+	r.Get("/api/user/keys/{login}", withAuth(shareHandlers.PublicKeyGet))
+	r.Put("/api/user/share", withAuth(shareHandlers.SharePut))
+	r.Get("/api/user/shares", withAuth(shareHandlers.AllShares))
+	r.Delete("/api/user/share/{kind}/{title}", withAuth(shareHandlers.ShareDelete))
+	r.Get("/api/user/shared", withAuth(shareHandlers.SharedWithMe))
+	r.Get("/api/user/shared/{owner}/{kind}/{title}", withAuth(shareHandlers.SharedItemGet))
+
+	// Secret links: created by users, opened by anybody with the link
+	r.Post("/api/user/link", withAuth(linkHandlers.LinkCreate))
+	r.Post("/api/user/link/file", read(linkHandlers.FileLinkCreate))
+	r.Post("/api/link/{id}", linkHandlers.LinkOpen)
+	r.Handle("/s/link.js", linkHandlers.Assets())
+	r.Handle("/s/app.css", linkHandlers.Assets())
+	r.Get("/s/{id}", linkHandlers.Page)
+
+	// Trusted contacts and their emergency access
+	r.Put("/api/user/emergency/contact", withAuth(emergencyHandlers.ContactPut))
+	r.Get("/api/user/emergency/contacts", withAuth(emergencyHandlers.AllContacts))
+	r.Delete("/api/user/emergency/contact/{login}", withAuth(emergencyHandlers.ContactDelete))
+	r.Post("/api/user/emergency/contact/{login}/reject", withAuth(emergencyHandlers.AccessReject))
+	r.Get("/api/user/emergency/grantors", withAuth(emergencyHandlers.AllGrantors))
+	r.Post("/api/user/emergency/grantor/{owner}/request", withAuth(emergencyHandlers.AccessRequest))
+	r.Get("/api/user/emergency/grantor/{owner}/kit", withAuth(emergencyHandlers.KitGet))
+	r.Get("/api/user/emergency/events", withAuth(emergencyHandlers.Events))
+	r.Post("/api/user/notifications", withAuth(emergencyHandlers.Notifications))
+
+	// Breached passwords range queries, only when the corpus is configured
+	if breachHandlers != nil {
+		r.Get("/api/breach/range/{prefix}", withAuth(breachHandlers.BreachRange))
+	}
 
 	return r
 }
@@ -0,0 +1,340 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/repo"
+	"github.com/adettelle/go-keeper/pkg/sharing"
+)
+
+type IShareRepo interface {
+	GetKeys(ctx context.Context, login string) (*repo.UserKeys, error)
+	AddKeys(ctx context.Context, login string, keys repo.UserKeys) error
+	UpdatePrivateKey(ctx context.Context, login string, privateKey string) error
+	PutShare(ctx context.Context, owner string, kind repo.ItemKind, title string,
+		payload string, recipients []repo.ShareRecipient) error
+	GetShares(ctx context.Context, owner string) ([]repo.OwnedShare, error)
+	DeleteShare(ctx context.Context, owner string, kind repo.ItemKind, title string) (bool, error)
+	GetSharedWithMe(ctx context.Context, login string) ([]repo.SharedItem, error)
+	GetSharedItem(ctx context.Context, login string, owner string, kind repo.ItemKind,
+		title string) (*repo.SharedPayload, error)
+}
+
+// ShareHandlers keep the sharing keys of users and the items they share with each other.
+// The server only stores what the clients encrypted: it never sees a private key, an item key
+// or a shared item in the clear.
+type ShareHandlers struct {
+	ShareRepo IShareRepo
+}
+
+func NewShareHandlers(shareRepo IShareRepo) *ShareHandlers {
+	return &ShareHandlers{
+		ShareRepo: shareRepo,
+	}
+}
+
+// shareableKind reports whether items of the kind can be shared. Files are kept in the object storage
+// and are not shared this way.
+func shareableKind(kind repo.ItemKind) bool {
+	switch kind {
+	case repo.KindPassword, repo.KindCard, repo.KindIdentity, repo.KindSSHKey, repo.KindNote:
+		return true
+	}
+	return false
+}
+
+type userKeysDTO struct {
+	PublicKey  string `json:"public_key" validate:"required"`
+	PrivateKey string `json:"private_key" validate:"required"`
+}
+
+// KeysGet returns the public key and the sealed private key of the user.
+func (sh *ShareHandlers) KeysGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	keys, err := sh.ShareRepo.GetKeys(context.Background(), r.Header.Get("x-user"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if keys == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	writeJSON(w, userKeysDTO{PublicKey: keys.PublicKey, PrivateKey: keys.PrivateKey})
+}
+
+// KeysAdd stores the key pair of the user. The keys can not be replaced: the items shared with the user
+// are wrapped for the public key. The private key can be sealed again for the same public key, e.g. when
+// a key sealed with the master password alone is sealed with the account key. Such legacy keys are not
+// accepted anymore: the server knows the master password.
+func (sh *ShareHandlers) KeysAdd(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	userLogin := r.Header.Get("x-user")
+
+	var keys userKeysDTO
+	if !readJSON(w, r, &keys) {
+		return
+	}
+	if _, err := sharing.DecodeKey(keys.PublicKey); err != nil {
+		log.Println("error in validating:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if sharing.IsLegacySealed(keys.PrivateKey) {
+		log.Println("error in validating: private key is sealed with the master password alone")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	existing, err := sh.ShareRepo.GetKeys(context.Background(), userLogin)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if existing != nil && existing.PublicKey != keys.PublicKey {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	if existing != nil {
+		if err := sh.ShareRepo.UpdatePrivateKey(context.Background(), userLogin, keys.PrivateKey); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	err = sh.ShareRepo.AddKeys(context.Background(), userLogin, repo.UserKeys{
+		PublicKey:  keys.PublicKey,
+		PrivateKey: keys.PrivateKey,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+type publicKeyResponseDTO struct {
+	Login     string `json:"login"`
+	PublicKey string `json:"public_key"`
+	// LegacySealed is set while the private key of the user is sealed with the master password alone.
+	LegacySealed bool `json:"legacy_sealed,omitempty"`
+}
+
+// PublicKeyGet returns the public key of another user to share items with.
+func (sh *ShareHandlers) PublicKeyGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	login := pathValue(r, "login")
+	keys, err := sh.ShareRepo.GetKeys(context.Background(), login)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if keys == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	writeJSON(w, publicKeyResponseDTO{
+		Login:        login,
+		PublicKey:    keys.PublicKey,
+		LegacySealed: sharing.IsLegacySealed(keys.PrivateKey),
+	})
+}
+
+type shareRecipientDTO struct {
+	Login      string `json:"login" validate:"required"`
+	WrappedKey string `json:"wrapped_key" validate:"required"`
+}
+
+type sharePutRequestDTO struct {
+	Kind       repo.ItemKind       `json:"kind" validate:"required"`
+	Title      string              `json:"title" validate:"required,min=1,max=255"`
+	Payload    string              `json:"payload" validate:"required"`
+	Recipients []shareRecipientDTO `json:"recipients" validate:"required,min=1,dive"`
+}
+
+// SharePut shares the item, encrypted with a new item key, with the recipients, replacing the previous
+// ciphertext and recipients. Every recipient must have sharing keys.
+func (sh *ShareHandlers) SharePut(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	userLogin := r.Header.Get("x-user")
+
+	var share sharePutRequestDTO
+	if !readJSON(w, r, &share) {
+		return
+	}
+	if !shareableKind(share.Kind) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	recipients := make([]repo.ShareRecipient, 0, len(share.Recipients))
+	seen := make(map[string]bool)
+	for _, rec := range share.Recipients {
+		if rec.Login == userLogin || seen[rec.Login] {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		seen[rec.Login] = true
+
+		keys, err := sh.ShareRepo.GetKeys(context.Background(), rec.Login)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if keys == nil {
+			log.Printf("%s has no sharing keys", rec.Login)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		recipients = append(recipients, repo.ShareRecipient{Login: rec.Login, WrappedKey: rec.WrappedKey})
+	}
+
+	err := sh.ShareRepo.PutShare(context.Background(), userLogin, share.Kind, share.Title, share.Payload, recipients)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+type ownedShareResponseDTO struct {
+	Kind       repo.ItemKind `json:"kind"`
+	Title      string        `json:"title"`
+	Recipients []string      `json:"recipients"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+// AllShares lists the items shared by the user with their recipients.
+func (sh *ShareHandlers) AllShares(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	shares, err := sh.ShareRepo.GetShares(context.Background(), r.Header.Get("x-user"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	res := make([]ownedShareResponseDTO, 0, len(shares))
+	for _, s := range shares {
+		res = append(res, ownedShareResponseDTO{Kind: s.Kind, Title: s.Title, Recipients: s.Recipients, UpdatedAt: s.UpdatedAt})
+	}
+	writeJSON(w, res)
+}
+
+// ShareDelete stops sharing the item with everybody.
+func (sh *ShareHandlers) ShareDelete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	kind := repo.ItemKind(pathValue(r, "kind"))
+	if !shareableKind(kind) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	found, err := sh.ShareRepo.DeleteShare(context.Background(), r.Header.Get("x-user"), kind, pathValue(r, "title"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+type sharedItemResponseDTO struct {
+	Owner     string        `json:"owner"`
+	Kind      repo.ItemKind `json:"kind"`
+	Title     string        `json:"title"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// SharedWithMe lists the items shared with the user.
+func (sh *ShareHandlers) SharedWithMe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	items, err := sh.ShareRepo.GetSharedWithMe(context.Background(), r.Header.Get("x-user"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	res := make([]sharedItemResponseDTO, 0, len(items))
+	for _, s := range items {
+		res = append(res, sharedItemResponseDTO{Owner: s.Owner, Kind: s.Kind, Title: s.Title, UpdatedAt: s.UpdatedAt})
+	}
+	writeJSON(w, res)
+}
+
+type sharedPayloadResponseDTO struct {
+	Payload    string    `json:"payload"`
+	WrappedKey string    `json:"wrapped_key"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// SharedItemGet returns the encrypted item shared with the user and the item key wrapped for the user.
+func (sh *ShareHandlers) SharedItemGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	kind := repo.ItemKind(pathValue(r, "kind"))
+	if !shareableKind(kind) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	item, err := sh.ShareRepo.GetSharedItem(context.Background(), r.Header.Get("x-user"),
+		pathValue(r, "owner"), kind, pathValue(r, "title"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if item == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	writeJSON(w, sharedPayloadResponseDTO{Payload: item.Payload, WrappedKey: item.WrappedKey, UpdatedAt: item.UpdatedAt})
+}
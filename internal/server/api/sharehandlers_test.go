@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adettelle/go-keeper/internal/repo"
+	"github.com/adettelle/go-keeper/mocks"
+	"github.com/adettelle/go-keeper/pkg/sharing"
+	"github.com/carlmjohnson/requests"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// --------------------------------------- sharing ---------------------------------------
+// ------- Хендлер: PUT /api/user/keys
+func TestKeysAdd(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	shareRepo := mocks.NewMockIShareRepo(ctrl)
+	h := &ShareHandlers{ShareRepo: shareRepo}
+
+	keys, err := sharing.GenerateKeyPair()
+	require.NoError(t, err)
+	dto := userKeysDTO{PublicKey: sharing.EncodeKey(keys.Public), PrivateKey: "v2$sealed"}
+
+	newRequest := func(dto userKeysDTO) *http.Request {
+		request, err := requests.
+			URL("/api/user/keys").
+			Method(http.MethodPut).
+			Header("x-user", "ane@aaa.com").
+			BodyJSON(&dto).
+			Request(context.Background())
+		require.NoError(t, err)
+		return request
+	}
+
+	shareRepo.EXPECT().GetKeys(gomock.Any(), "ane@aaa.com").Return(nil, nil)
+	shareRepo.EXPECT().AddKeys(gomock.Any(), "ane@aaa.com",
+		repo.UserKeys{PublicKey: dto.PublicKey, PrivateKey: dto.PrivateKey}).Return(nil)
+	response := httptest.NewRecorder()
+	h.KeysAdd(response, newRequest(dto))
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	// закрытый ключ запечатывается заново для того же публичного ключа
+	shareRepo.EXPECT().GetKeys(gomock.Any(), "ane@aaa.com").
+		Return(&repo.UserKeys{PublicKey: dto.PublicKey, PrivateKey: "v1$old"}, nil)
+	shareRepo.EXPECT().UpdatePrivateKey(gomock.Any(), "ane@aaa.com", dto.PrivateKey).Return(nil)
+	response = httptest.NewRecorder()
+	h.KeysAdd(response, newRequest(dto))
+	require.Equal(t, http.StatusOK, response.Code)
+
+	// ключи не заменяются
+	other, err := sharing.GenerateKeyPair()
+	require.NoError(t, err)
+	shareRepo.EXPECT().GetKeys(gomock.Any(), "ane@aaa.com").
+		Return(&repo.UserKeys{PublicKey: dto.PublicKey, PrivateKey: dto.PrivateKey}, nil)
+	response = httptest.NewRecorder()
+	h.KeysAdd(response, newRequest(userKeysDTO{PublicKey: sharing.EncodeKey(other.Public), PrivateKey: "v2$other"}))
+	require.Equal(t, http.StatusConflict, response.Code)
+
+	// публичный ключ проверяется
+	response = httptest.NewRecorder()
+	h.KeysAdd(response, newRequest(userKeysDTO{PublicKey: "not a key", PrivateKey: "v2$sealed"}))
+	require.Equal(t, http.StatusBadRequest, response.Code)
+
+	// ключ, запечатанный только мастер-паролем, не принимается: сервер знает мастер-пароль
+	response = httptest.NewRecorder()
+	h.KeysAdd(response, newRequest(userKeysDTO{PublicKey: dto.PublicKey, PrivateKey: "v1$sealed"}))
+	require.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+// ------- Хендлер: GET /api/user/keys/{login}
+func TestPublicKeyGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	shareRepo := mocks.NewMockIShareRepo(ctrl)
+	h := &ShareHandlers{ShareRepo: shareRepo}
+
+	shareRepo.EXPECT().GetKeys(gomock.Any(), "bob@aaa.com").
+		Return(&repo.UserKeys{PublicKey: "bob-pub", PrivateKey: "v1$bob"}, nil)
+	shareRepo.EXPECT().GetKeys(gomock.Any(), "tom@aaa.com").
+		Return(&repo.UserKeys{PublicKey: "tom-pub", PrivateKey: "v2$tom"}, nil)
+
+	for login, legacy := range map[string]bool{"bob@aaa.com": true, "tom@aaa.com": false} {
+		request := httptest.NewRequest(http.MethodGet, "/api/user/keys/"+login, nil)
+		request.Header.Set("x-user", "ane@aaa.com")
+		request.SetPathValue("login", login)
+		response := httptest.NewRecorder()
+		h.PublicKeyGet(response, request)
+		require.Equal(t, http.StatusOK, response.Code)
+
+		// закрытый ключ в старом формате сервер может открыть, клиент не шифрует для него записи
+		var key publicKeyResponseDTO
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &key))
+		require.Equal(t, legacy, key.LegacySealed, login)
+	}
+}
+
+// ------- Хендлер: PUT /api/user/share
+func TestSharePut(t *testing.T) {
+	bobKeys := &repo.UserKeys{PublicKey: "bob-pub", PrivateKey: "v2$bob"}
+
+	tests := []struct {
+		name       string
+		kind       repo.ItemKind
+		recipients []shareRecipientDTO
+		wantCode   int
+	}{
+		{"shared", repo.KindPassword, []shareRecipientDTO{{Login: "bob@aaa.com", WrappedKey: "k1"}}, http.StatusOK},
+		{"recipient without keys", repo.KindPassword, []shareRecipientDTO{{Login: "eve@aaa.com", WrappedKey: "k1"}}, http.StatusNotFound},
+		{"with oneself", repo.KindPassword, []shareRecipientDTO{{Login: "ane@aaa.com", WrappedKey: "k1"}}, http.StatusBadRequest},
+		{"twice", repo.KindPassword, []shareRecipientDTO{{Login: "bob@aaa.com", WrappedKey: "k1"},
+			{Login: "bob@aaa.com", WrappedKey: "k2"}}, http.StatusBadRequest},
+		{"no recipients", repo.KindPassword, nil, http.StatusBadRequest},
+		{"file", repo.KindFile, []shareRecipientDTO{{Login: "bob@aaa.com", WrappedKey: "k1"}}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			shareRepo := mocks.NewMockIShareRepo(ctrl)
+			h := &ShareHandlers{ShareRepo: shareRepo}
+
+			shareRepo.EXPECT().GetKeys(gomock.Any(), "bob@aaa.com").Return(bobKeys, nil).AnyTimes()
+			shareRepo.EXPECT().GetKeys(gomock.Any(), "eve@aaa.com").Return(nil, nil).AnyTimes()
+			if tt.wantCode == http.StatusOK {
+				shareRepo.EXPECT().PutShare(gomock.Any(), "ane@aaa.com", tt.kind, "mail", "ciphertext",
+					[]repo.ShareRecipient{{Login: "bob@aaa.com", WrappedKey: "k1"}}).Return(nil)
+			}
+
+			request, err := requests.
+				URL("/api/user/share").
+				Method(http.MethodPut).
+				Header("x-user", "ane@aaa.com").
+				BodyJSON(&sharePutRequestDTO{Kind: tt.kind, Title: "mail", Payload: "ciphertext", Recipients: tt.recipients}).
+				Request(context.Background())
+			require.NoError(t, err)
+
+			response := httptest.NewRecorder()
+			h.SharePut(response, request)
+			require.Equal(t, tt.wantCode, response.Code)
+		})
+	}
+}
+
+// ------- Хендлер: GET /api/user/shared/{owner}/{kind}/{title}
+func TestSharedItemGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	shareRepo := mocks.NewMockIShareRepo(ctrl)
+	h := &ShareHandlers{ShareRepo: shareRepo}
+
+	newRequest := func(login string) *http.Request {
+		request := httptest.NewRequest(http.MethodGet, "/api/user/shared/ane@aaa.com/password/mail", nil)
+		request.Header.Set("x-user", login)
+		request.SetPathValue("owner", "ane@aaa.com")
+		request.SetPathValue("kind", "password")
+		request.SetPathValue("title", "mail")
+		return request
+	}
+
+	shareRepo.EXPECT().GetSharedItem(gomock.Any(), "bob@aaa.com", "ane@aaa.com", repo.KindPassword, "mail").
+		Return(&repo.SharedPayload{Payload: "ciphertext", WrappedKey: "k1"}, nil)
+	response := httptest.NewRecorder()
+	h.SharedItemGet(response, newRequest("bob@aaa.com"))
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var item sharedPayloadResponseDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &item))
+	require.Equal(t, "ciphertext", item.Payload)
+	require.Equal(t, "k1", item.WrappedKey)
+
+	// запись, которой не поделились с пользователем, не найдена
+	shareRepo.EXPECT().GetSharedItem(gomock.Any(), "eve@aaa.com", "ane@aaa.com", repo.KindPassword, "mail").
+		Return(nil, nil)
+	response = httptest.NewRecorder()
+	h.SharedItemGet(response, newRequest("eve@aaa.com"))
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
+
+// ------- Хендлер: DELETE /api/user/share/{kind}/{title}
+func TestShareDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	shareRepo := mocks.NewMockIShareRepo(ctrl)
+	h := &ShareHandlers{ShareRepo: shareRepo}
+
+	shareRepo.EXPECT().DeleteShare(gomock.Any(), "ane@aaa.com", repo.KindCard, "visa").Return(false, nil)
+
+	request := httptest.NewRequest(http.MethodDelete, "/api/user/share/card/visa", nil)
+	request.Header.Set("x-user", "ane@aaa.com")
+	request.SetPathValue("kind", "card")
+	request.SetPathValue("title", "visa")
+
+	response := httptest.NewRecorder()
+	h.ShareDelete(response, request)
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
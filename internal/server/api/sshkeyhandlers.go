@@ -0,0 +1,321 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/adettelle/go-keeper/internal/encryption"
+	"github.com/adettelle/go-keeper/internal/repo"
+	"github.com/adettelle/go-keeper/internal/sshkey"
+)
+
+// SSHKeyHandlers manage SSH keys. Public keys and fingerprints are derived from the private keys
+// and stored in clear, the private keys are stored encrypted.
+type SSHKeyHandlers struct {
+	SSHKeyRepo ISSHKeyRepo
+	SignKey    []byte
+}
+
+func NewSSHKeyHandlers(sshKeyRepo ISSHKeyRepo, signKey []byte) *SSHKeyHandlers {
+	return &SSHKeyHandlers{
+		SSHKeyRepo: sshKeyRepo,
+		SignKey:    signKey,
+	}
+}
+
+type ISSHKeyRepo interface {
+	AddSSHKey(ctx context.Context, key repo.SSHKey, login string) error
+	GetAllSSHKeys(ctx context.Context, login string, filter repo.ItemFilter,
+		page repo.Page) ([]repo.SSHKeyToGet, *repo.ItemCursor, error)
+	GetSSHKeyByTitle(ctx context.Context, title, login string) (*repo.SSHKey, error)
+	UpdateSSHKey(ctx context.Context, title string, upd repo.SSHKeyUpdate, login string) (bool, error)
+	DeleteSSHKeyByTitle(ctx context.Context, title, login string) (bool, error)
+}
+
+type sshKeyCreateRequestDTO struct {
+	Title       string `json:"title" validate:"required,min=1"`
+	Description string `json:"description" validate:"max=1000"`
+	PrivateKey  string `json:"private_key" validate:"required"` // PrivateKey is an unencrypted PEM.
+	Comment     string `json:"comment" validate:"max=255"`
+	Confirm     bool   `json:"confirm"`
+}
+
+func (sh *SSHKeyHandlers) SSHKeyAdd(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	userLogin := r.Header.Get("x-user")
+
+	var buf bytes.Buffer
+	var req sshKeyCreateRequestDTO
+
+	// читаем тело запроса
+	_, err := buf.ReadFrom(r.Body)
+	if err != nil {
+		log.Println("error in reading body:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &req); err != nil {
+		log.Println("error in unmarshalling json:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		log.Println("error in validating:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	key, err := sshkey.Parse([]byte(req.PrivateKey), nil, req.Comment)
+	if err != nil {
+		log.Println("error in parsing ssh key:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	encrypted, err := encryption.AESEncrypt(key.PrivateKey, sh.SignKey)
+	if err != nil {
+		log.Println("error in encrypting ssh key:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = sh.SSHKeyRepo.AddSSHKey(context.Background(), repo.SSHKey{
+		Title:       req.Title,
+		Description: req.Description,
+		KeyType:     key.Type,
+		PublicKey:   key.PublicKey,
+		Fingerprint: key.Fingerprint,
+		PrivateKey:  encrypted,
+		Confirm:     req.Confirm,
+	}, userLogin)
+	if err != nil {
+		log.Println("error in adding ssh key:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+type sshKeyGetRequestDTO struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	KeyType     string `json:"key_type"`
+	PublicKey   string `json:"public_key"`
+	Fingerprint string `json:"fingerprint"`
+	Confirm     bool   `json:"confirm"`
+	ItemMetaDTO
+}
+
+func NewSSHKeyDTO(key repo.SSHKeyToGet) *sshKeyGetRequestDTO {
+	return &sshKeyGetRequestDTO{
+		Title:       key.Title,
+		Description: key.Description,
+		KeyType:     key.KeyType,
+		PublicKey:   key.PublicKey,
+		Fingerprint: key.Fingerprint,
+		Confirm:     key.Confirm,
+		ItemMetaDTO: NewItemMetaDTO(key.ItemMeta),
+	}
+}
+
+// AllSSHKeys lists the SSH keys with their public keys, without the private keys.
+func (sh *SSHKeyHandlers) AllSSHKeys(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	userLogin := r.Header.Get("x-user")
+
+	filter, err := parseItemFilter(r)
+	if err != nil {
+		log.Println("error in parsing filter:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		log.Println("error in parsing page:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	keys, next, err := sh.SSHKeyRepo.GetAllSSHKeys(context.Background(), userLogin, filter, page)
+	if err != nil {
+		log.Println("error in getting ssh keys:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	list := []*sshKeyGetRequestDTO{}
+	for _, key := range keys {
+		list = append(list, NewSSHKeyDTO(key))
+	}
+
+	resp, err := json.Marshal(list)
+	if err != nil {
+		log.Println("error in marshalling json:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err := setNextCursor(w, next); err != nil {
+		log.Println("error in marshalling cursor:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, err = w.Write(resp)
+	if err != nil {
+		log.Println("error in writing resp:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+type sshKeyGetByTitleRequestDTO struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	KeyType     string `json:"key_type"`
+	PublicKey   string `json:"public_key"`
+	Fingerprint string `json:"fingerprint"`
+	PrivateKey  string `json:"private_key"`
+	Confirm     bool   `json:"confirm"`
+}
+
+// SSHKeyGetByTitle shows the SSH key with the decrypted private key by title.
+func (sh *SSHKeyHandlers) SSHKeyGetByTitle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	userLogin := r.Header.Get("x-user")
+	title := pathValue(r, "title")
+
+	key, err := sh.SSHKeyRepo.GetSSHKeyByTitle(context.Background(), title, userLogin)
+	if err != nil {
+		log.Println("error in getting ssh key:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if key == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	private, err := encryption.AESDecrypt(key.PrivateKey, sh.SignKey)
+	if err != nil {
+		log.Println("error in decrypting ssh key:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := json.Marshal(sshKeyGetByTitleRequestDTO{
+		Title:       key.Title,
+		Description: key.Description,
+		KeyType:     key.KeyType,
+		PublicKey:   key.PublicKey,
+		Fingerprint: key.Fingerprint,
+		PrivateKey:  private,
+		Confirm:     key.Confirm,
+	})
+	if err != nil {
+		log.Println("error in marshalling json:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, err = w.Write(resp)
+	if err != nil {
+		log.Println("error in writing resp:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+type sshKeyUpdateRequestDTO struct {
+	Description *string `json:"description,omitempty" validate:"omitnil,max=1000"`
+	Confirm     *bool   `json:"confirm,omitempty"`
+}
+
+func (sh *SSHKeyHandlers) SSHKeyUpdate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	userLogin := r.Header.Get("x-user")
+	title := pathValue(r, "title")
+
+	var buf bytes.Buffer
+	var req sshKeyUpdateRequestDTO
+
+	// читаем тело запроса
+	_, err := buf.ReadFrom(r.Body)
+	if err != nil {
+		log.Println("error in reading body:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &req); err != nil {
+		log.Println("error in unmarshalling json:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		log.Println("error in validating:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	found, err := sh.SSHKeyRepo.UpdateSSHKey(context.Background(), title,
+		repo.SSHKeyUpdate{Description: req.Description, Confirm: req.Confirm}, userLogin)
+	if err != nil {
+		log.Println("error in updating ssh key:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (sh *SSHKeyHandlers) SSHKeyDeleteByTitle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	title := pathValue(r, "title")
+	userLogin := r.Header.Get("x-user")
+
+	found, err := sh.SSHKeyRepo.DeleteSSHKeyByTitle(context.Background(), title, userLogin)
+	if err != nil {
+		log.Println("error in deleting ssh key:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adettelle/go-keeper/internal/encryption"
+	"github.com/adettelle/go-keeper/internal/repo"
+	"github.com/adettelle/go-keeper/internal/sshkey"
+	"github.com/adettelle/go-keeper/mocks"
+	"github.com/carlmjohnson/requests"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// ------- Хендлер: PUT /api/user/sshkey
+func TestSSHKeyAdd(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	signKey := []byte("1234567812345678")
+	sshKeyRepo := mocks.NewMockISSHKeyRepo(ctrl)
+	h := &SSHKeyHandlers{SSHKeyRepo: sshKeyRepo, SignKey: signKey}
+
+	key, err := sshkey.Generate(sshkey.TypeEd25519, 0, "")
+	require.NoError(t, err)
+
+	var added repo.SSHKey
+	sshKeyRepo.EXPECT().AddSSHKey(gomock.Any(), gomock.Any(), "Ane").
+		DoAndReturn(func(_ context.Context, k repo.SSHKey, _ string) error {
+			added = k
+			return nil
+		})
+
+	request, err := requests.
+		URL("/api/user/sshkey").
+		Method(http.MethodPut).
+		BodyJSON(map[string]any{
+			"title": "github", "private_key": key.PrivateKey, "comment": "ane@laptop", "confirm": true,
+		}).
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	h.SSHKeyAdd(response, request)
+
+	require.Equal(t, http.StatusAccepted, response.Code)
+	require.Equal(t, "github", added.Title)
+	require.Equal(t, "ssh-ed25519", added.KeyType)
+	require.Equal(t, key.PublicKey+" ane@laptop", added.PublicKey)
+	require.Equal(t, key.Fingerprint, added.Fingerprint)
+	require.True(t, added.Confirm)
+
+	private, err := encryption.AESDecrypt(added.PrivateKey, signKey)
+	require.NoError(t, err)
+	parsed, err := sshkey.Parse([]byte(private), nil, "")
+	require.NoError(t, err)
+	require.Equal(t, key.Fingerprint, parsed.Fingerprint)
+}
+
+// ------- Хендлер: PUT /api/user/sshkey
+func TestSSHKeyAddInvalidKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	h := &SSHKeyHandlers{SSHKeyRepo: mocks.NewMockISSHKeyRepo(ctrl), SignKey: []byte("1234567812345678")}
+
+	for _, body := range []map[string]any{
+		{"title": "github", "private_key": "not a key"},
+		{"title": "github"},
+	} {
+		request, err := requests.
+			URL("/api/user/sshkey").
+			Method(http.MethodPut).
+			BodyJSON(body).
+			Header("x-user", "Ane").
+			Request(context.Background())
+		require.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		h.SSHKeyAdd(response, request)
+
+		require.Equal(t, http.StatusBadRequest, response.Code)
+	}
+}
+
+// ------- Хендлер: GET /api/user/sshkeys
+func TestAllSSHKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	sshKeyRepo := mocks.NewMockISSHKeyRepo(ctrl)
+	h := &SSHKeyHandlers{SSHKeyRepo: sshKeyRepo, SignKey: []byte("1234567812345678")}
+
+	sshKeyRepo.EXPECT().GetAllSSHKeys(gomock.Any(), "Ane", repo.ItemFilter{}, repo.Page{}).Return([]repo.SSHKeyToGet{
+		{ID: 1, Title: "github", KeyType: "ssh-ed25519", PublicKey: "ssh-ed25519 AAAA", Fingerprint: "SHA256:abc"},
+	}, nil, nil)
+
+	request, err := requests.
+		URL("/api/user/sshkeys").
+		Method(http.MethodGet).
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	h.AllSSHKeys(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+	var res []map[string]any
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &res))
+	require.Len(t, res, 1)
+	require.Equal(t, "SHA256:abc", res[0]["fingerprint"])
+	require.NotContains(t, res[0], "private_key")
+}
+
+// ------- Хендлер: DELETE /api/user/sshkey/{title}
+func TestSSHKeyDeleteNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	sshKeyRepo := mocks.NewMockISSHKeyRepo(ctrl)
+	h := &SSHKeyHandlers{SSHKeyRepo: sshKeyRepo}
+
+	sshKeyRepo.EXPECT().DeleteSSHKeyByTitle(gomock.Any(), "unknown", "Ane").Return(false, nil)
+
+	request, err := requests.
+		URL("/api/user/sshkey/unknown").
+		Method(http.MethodDelete).
+		Header("x-user", "Ane").
+		Request(context.Background())
+	require.NoError(t, err)
+	request.SetPathValue("title", "unknown")
+
+	response := httptest.NewRecorder()
+	h.SSHKeyDeleteByTitle(response, request)
+
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
@@ -0,0 +1,288 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/adettelle/go-keeper/internal/jwt"
+)
+
+const (
+	// SessionCookie holds the JWT of the web vault session. It is not readable by scripts.
+	SessionCookie = "gokeeper_session"
+	// CSRFHeader must carry the CSRF token of the session on every request authenticated by the cookie.
+	CSRFHeader = "X-CSRF-Token"
+
+	// contentSecurityPolicy allows only the files of the web vault itself: no inline scripts and styles,
+	// no third-party hosts, no framing.
+	contentSecurityPolicy = "default-src 'none'; script-src 'self'; style-src 'self'; img-src 'self' data:; " +
+		"connect-src 'self'; form-action 'self'; frame-ancestors 'none'; base-uri 'none'"
+)
+
+type IWebJwtRepo interface {
+	AddJwtToken(ctx context.Context, custID int, token string) error
+	InvalidateToken(ctx context.Context, token string) error
+	TokenIsValid(ctx context.Context, token string) (bool, error)
+}
+
+// WebHandlers serve the embedded web vault and its cookie sessions.
+// Logged in, the browser calls the same /api/user endpoints as the client: CookieAuth turns the session
+// cookie into the Authorization header once the CSRF token and the origin are checked.
+type WebHandlers struct {
+	CustomerRepo ICustomerRepo
+	JwtRepo      IWebJwtRepo
+	SignKey      []byte
+	Static       fs.FS
+}
+
+func NewWebHandlers(customerRepo ICustomerRepo, jwtRepo IWebJwtRepo, signKey []byte, static fs.FS) *WebHandlers {
+	return &WebHandlers{
+		CustomerRepo: customerRepo,
+		JwtRepo:      jwtRepo,
+		SignKey:      signKey,
+		Static:       static,
+	}
+}
+
+type webSessionResponseDTO struct {
+	Login string `json:"login"`
+	CSRF  string `json:"csrf"`
+}
+
+// csrfToken is bound to the session: it is the HMAC of the session JWT, so it needs no storage
+// and is useless with any other session.
+func (wh *WebHandlers) csrfToken(token string) string {
+	mac := hmac.New(sha256.New, wh.SignKey)
+	mac.Write([]byte("csrf:" + token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (wh *WebHandlers) validCSRF(token, csrf string) bool {
+	return csrf != "" && hmac.Equal([]byte(wh.csrfToken(token)), []byte(csrf))
+}
+
+// sameOrigin rejects cross-site requests. Browsers send Origin with every POST and with cross-origin GETs;
+// Sec-Fetch-Site, if sent, must be same-origin.
+func sameOrigin(r *http.Request) bool {
+	if site := r.Header.Get("Sec-Fetch-Site"); site != "" && site != "same-origin" {
+		return false
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return r.Method == http.MethodGet || r.Method == http.MethodHead
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "https" && u.Host == r.Host
+}
+
+// sessionToken returns the JWT of the session cookie if it is signed by the server and not invalidated.
+func (wh *WebHandlers) sessionToken(r *http.Request) (string, jwt.Customer, bool, error) {
+	cookie, err := r.Cookie(SessionCookie)
+	if err != nil {
+		return "", jwt.Customer{}, false, nil
+	}
+	cust, ok := jwt.VerifyToken(wh.SignKey, cookie.Value)
+	if !ok {
+		return "", jwt.Customer{}, false, nil
+	}
+	isValid, err := wh.JwtRepo.TokenIsValid(context.Background(), cookie.Value)
+	if err != nil {
+		return "", jwt.Customer{}, false, err
+	}
+	return cookie.Value, cust, isValid, nil
+}
+
+func (wh *WebHandlers) setSessionCookie(w http.ResponseWriter, token string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// Login checks the login and the master password like /api/user/login and starts a cookie session.
+// As with the client, the new session invalidates the previous ones of the user.
+func (wh *WebHandlers) Login(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if !sameOrigin(r) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	var buf bytes.Buffer
+	var auth authRequestDTO
+
+	_, err := buf.ReadFrom(r.Body)
+	if err != nil {
+		log.Println("error in reading body:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &auth); err != nil {
+		log.Println("error in unmarshalling json:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = validate.Struct(auth)
+	if err != nil {
+		log.Println("error in validating:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ok, custID := wh.CustomerRepo.VerifyUser(context.Background(), auth.Login, auth.Password)
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	token, err := jwt.GenerateJwtToken(wh.SignKey, auth.Login, custID)
+	if err != nil {
+		log.Println("error in generating token:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = wh.JwtRepo.AddJwtToken(context.Background(), custID, token)
+	if err != nil {
+		log.Println("error in adding jwt token:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	wh.setSessionCookie(w, token, int(jwt.TOKEN_EXP.Seconds()))
+	writeJSON(w, webSessionResponseDTO{Login: auth.Login, CSRF: wh.csrfToken(token)})
+}
+
+// Session returns the login and the CSRF token of the current session, so that a reloaded page
+// does not have to log in again.
+func (wh *WebHandlers) Session(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if !sameOrigin(r) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	token, cust, ok, err := wh.sessionToken(r)
+	if err != nil {
+		log.Println("error in checking session:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	writeJSON(w, webSessionResponseDTO{Login: cust.Login, CSRF: wh.csrfToken(token)})
+}
+
+// Logout invalidates the session token and removes the cookie.
+func (wh *WebHandlers) Logout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !sameOrigin(r) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	token, _, ok, err := wh.sessionToken(r)
+	if err != nil {
+		log.Println("error in checking session:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if ok {
+		if !wh.validCSRF(token, r.Header.Get(CSRFHeader)) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		err = wh.JwtRepo.InvalidateToken(context.Background(), token)
+		if err != nil {
+			log.Println("error in invalidating token:", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	wh.setSessionCookie(w, "", -1)
+	w.WriteHeader(http.StatusOK)
+}
+
+// CookieAuth authenticates API requests of the web vault. A request with a session cookie and
+// no Authorization header must come from the same origin and carry the CSRF token of the session;
+// the cookie is then passed on as the bearer token. Requests of the client are not affected.
+func (wh *WebHandlers) CookieAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") || r.Header.Get("Authorization") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cookie, err := r.Cookie(SessionCookie)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !sameOrigin(r) || !wh.validCSRF(cookie.Value, r.Header.Get(CSRFHeader)) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		r.Header.Set("Authorization", "Bearer "+cookie.Value)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SecurityHeaders sets the strict content security policy and the related headers on web vault responses.
+func SecurityHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("Content-Security-Policy", contentSecurityPolicy)
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("X-Frame-Options", "DENY")
+		h.Set("Referrer-Policy", "no-referrer")
+		h.Set("Cross-Origin-Opener-Policy", "same-origin")
+		h.Set("Cache-Control", "no-store")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// StaticFiles serves the embedded web vault under /web/.
+func (wh *WebHandlers) StaticFiles() http.Handler {
+	return SecurityHeaders(http.StripPrefix("/web/", http.FileServerFS(wh.Static)))
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/adettelle/go-keeper/internal/jwt"
+	"github.com/adettelle/go-keeper/mocks"
+	"github.com/carlmjohnson/requests"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWebHandlers(ctrl *gomock.Controller) (*WebHandlers, *mocks.MockICustomerRepo, *mocks.MockIWebJwtRepo) {
+	custRepo := mocks.NewMockICustomerRepo(ctrl)
+	jwtRepo := mocks.NewMockIWebJwtRepo(ctrl)
+	static := fstest.MapFS{"index.html": {Data: []byte("<!DOCTYPE html>")}}
+	return NewWebHandlers(custRepo, jwtRepo, []byte("my_key"), static), custRepo, jwtRepo
+}
+
+// --------------------------------------- web vault ---------------------------------------
+// ------- Хендлер: POST /web/login
+func TestWebLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	h, custRepo, jwtRepo := newTestWebHandlers(ctrl)
+
+	auth := authRequestDTO{Login: "sobakevich@aaa.com", Password: "my_pass"}
+
+	custRepo.EXPECT().VerifyUser(gomock.Any(), auth.Login, auth.Password).Return(true, 7)
+	jwtRepo.EXPECT().AddJwtToken(gomock.Any(), 7, gomock.Any()).Return(nil)
+
+	request, err := requests.
+		URL("https://keeper.example.com/web/login").
+		Method(http.MethodPost).
+		Header("Origin", "https://keeper.example.com").
+		BodyJSON(&auth).Request(context.Background())
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	h.Login(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	cookies := response.Result().Cookies()
+	require.Len(t, cookies, 1)
+	cookie := cookies[0]
+	require.Equal(t, SessionCookie, cookie.Name)
+	require.True(t, cookie.HttpOnly)
+	require.True(t, cookie.Secure)
+	require.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+	require.Empty(t, response.Header().Get("Authorization"))
+
+	var session webSessionResponseDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &session))
+	require.Equal(t, auth.Login, session.Login)
+	require.Equal(t, h.csrfToken(cookie.Value), session.CSRF)
+}
+
+// ------- Хендлер: POST /web/login
+func TestWebLoginCrossOrigin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	h, _, _ := newTestWebHandlers(ctrl)
+
+	for _, origin := range []string{"", "https://evil.example.com", "http://keeper.example.com"} {
+		request, err := requests.
+			URL("https://keeper.example.com/web/login").
+			Method(http.MethodPost).
+			Header("Origin", origin).
+			BodyJSON(&authRequestDTO{Login: "sobakevich@aaa.com", Password: "my_pass"}).
+			Request(context.Background())
+		require.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		h.Login(response, request)
+		require.Equal(t, http.StatusForbidden, response.Code, origin)
+	}
+}
+
+// ------- Хендлер: GET /web/session
+func TestWebSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	h, _, jwtRepo := newTestWebHandlers(ctrl)
+
+	token, err := jwt.GenerateJwtToken(h.SignKey, "sobakevich@aaa.com", 7)
+	require.NoError(t, err)
+
+	// без cookie сессии нет
+	request := httptest.NewRequest(http.MethodGet, "https://keeper.example.com/web/session", nil)
+	response := httptest.NewRecorder()
+	h.Session(response, request)
+	require.Equal(t, http.StatusUnauthorized, response.Code)
+
+	jwtRepo.EXPECT().TokenIsValid(gomock.Any(), token).Return(true, nil)
+
+	request = httptest.NewRequest(http.MethodGet, "https://keeper.example.com/web/session", nil)
+	request.AddCookie(&http.Cookie{Name: SessionCookie, Value: token})
+	response = httptest.NewRecorder()
+	h.Session(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var session webSessionResponseDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &session))
+	require.Equal(t, "sobakevich@aaa.com", session.Login)
+	require.Equal(t, h.csrfToken(token), session.CSRF)
+}
+
+// ------- Хендлер: POST /web/logout
+func TestWebLogout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	h, _, jwtRepo := newTestWebHandlers(ctrl)
+
+	token, err := jwt.GenerateJwtToken(h.SignKey, "sobakevich@aaa.com", 7)
+	require.NoError(t, err)
+
+	jwtRepo.EXPECT().TokenIsValid(gomock.Any(), token).Return(true, nil).Times(2)
+	jwtRepo.EXPECT().InvalidateToken(gomock.Any(), token).Return(nil)
+
+	newRequest := func(csrf string) *http.Request {
+		request := httptest.NewRequest(http.MethodPost, "https://keeper.example.com/web/logout", nil)
+		request.Header.Set("Origin", "https://keeper.example.com")
+		request.Header.Set(CSRFHeader, csrf)
+		request.AddCookie(&http.Cookie{Name: SessionCookie, Value: token})
+		return request
+	}
+
+	response := httptest.NewRecorder()
+	h.Logout(response, newRequest("wrong"))
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	response = httptest.NewRecorder()
+	h.Logout(response, newRequest(h.csrfToken(token)))
+	require.Equal(t, http.StatusOK, response.Code)
+	cookies := response.Result().Cookies()
+	require.Len(t, cookies, 1)
+	require.Equal(t, -1, cookies[0].MaxAge)
+}
+
+// ------- Middleware: cookie session of the web vault
+func TestCookieAuth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	h, _, _ := newTestWebHandlers(ctrl)
+
+	var gotAuth string
+	next := h.CookieAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	newRequest := func(origin, csrf string) *http.Request {
+		request := httptest.NewRequest(http.MethodPut, "https://keeper.example.com/api/user/password", strings.NewReader("{}"))
+		if origin != "" {
+			request.Header.Set("Origin", origin)
+		}
+		request.Header.Set(CSRFHeader, csrf)
+		request.AddCookie(&http.Cookie{Name: SessionCookie, Value: "session-jwt"})
+		return request
+	}
+
+	tests := []struct {
+		name     string
+		request  *http.Request
+		wantCode int
+		wantAuth string
+	}{
+		{"valid", newRequest("https://keeper.example.com", h.csrfToken("session-jwt")), http.StatusOK, "Bearer session-jwt"},
+		{"no csrf", newRequest("https://keeper.example.com", ""), http.StatusForbidden, ""},
+		{"csrf of another session", newRequest("https://keeper.example.com", h.csrfToken("other-jwt")), http.StatusForbidden, ""},
+		{"cross origin", newRequest("https://evil.example.com", h.csrfToken("session-jwt")), http.StatusForbidden, ""},
+		{"no origin", newRequest("", h.csrfToken("session-jwt")), http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAuth = ""
+			response := httptest.NewRecorder()
+			next.ServeHTTP(response, tt.request)
+			require.Equal(t, tt.wantCode, response.Code)
+			require.Equal(t, tt.wantAuth, gotAuth)
+		})
+	}
+
+	// запросы клиента с заголовком Authorization не затрагиваются
+	request := httptest.NewRequest(http.MethodGet, "https://keeper.example.com/api/user/passwords", nil)
+	request.Header.Set("Authorization", "Bearer client-jwt")
+	response := httptest.NewRecorder()
+	next.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, "Bearer client-jwt", gotAuth)
+}
+
+// ------- Хендлер: GET /web/
+func TestWebStaticFiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	h, _, _ := newTestWebHandlers(ctrl)
+
+	request := httptest.NewRequest(http.MethodGet, "https://keeper.example.com/web/", nil)
+	response := httptest.NewRecorder()
+	h.StaticFiles().ServeHTTP(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, "<!DOCTYPE html>", response.Body.String())
+	csp := response.Header().Get("Content-Security-Policy")
+	require.Contains(t, csp, "script-src 'self'")
+	require.Contains(t, csp, "frame-ancestors 'none'")
+	require.NotContains(t, csp, "unsafe-inline")
+	require.Equal(t, "nosniff", response.Header().Get("X-Content-Type-Options"))
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"net"
 	"strconv"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
@@ -27,6 +28,22 @@ type Config struct {
 	MinioSecretAccessKey string `envconfig:"SECRET_ACCESSKEY" required:"true"`
 	UseSSL               bool   `envconfig:"USE_SSL" default:"false"`
 	BucketName           string `envconfig:"BUCKET_NAME" default:"test"`
+
+	// MinioPublicURL is the address users download shared files from MinIO at, e.g. https://files.example.com.
+	// Links are made for MINIO_ENDPOINT if empty.
+	MinioPublicURL string `envconfig:"MINIO_PUBLIC_URL"`
+
+	// FileLinkLifetime is how long presigned links to download shared files are valid.
+	FileLinkLifetime time.Duration `envconfig:"FILE_LINK_LIFETIME" default:"3m"`
+
+	// BreachCorpus is a path to the sorted SHA-1 breached passwords file; the range endpoint is off if empty.
+	BreachCorpus string `envconfig:"BREACH_CORPUS"`
+
+	// ReminderWindowDays is how many days ahead reminders report cards expiring and passwords due for rotation.
+	ReminderWindowDays int `envconfig:"REMINDER_WINDOW_DAYS" default:"30"`
+
+	// WebVault turns on the web vault served at /web/ with cookie sessions.
+	WebVault bool `envconfig:"WEB_VAULT" default:"false"`
 }
 
 func New() (*Config, error) {
@@ -0,0 +1,298 @@
+// go-keeper web vault. The page calls the /api/user endpoints of the server with the session cookie;
+// every request carries the CSRF token of the session. Values are only put into the page as text.
+// Items are decrypted by the server, as for the CLI: the page does not decrypt anything itself.
+// In-browser WebCrypto decryption of vault items is out of scope, items have no client-side format.
+'use strict';
+
+const CSRF_HEADER = 'X-CSRF-Token';
+const MASK = '••••••••';
+const CLIPBOARD_CLEAR_MS = 45000;
+
+const state = { csrf: '', items: [], selected: null };
+
+const $ = (id) => document.getElementById(id);
+
+function setStatus(text, isError) {
+  const status = $('status');
+  status.textContent = text;
+  status.classList.toggle('error', Boolean(isError));
+}
+
+function el(tag, text, className) {
+  const node = document.createElement(tag);
+  if (text !== undefined) node.textContent = text;
+  if (className) node.className = className;
+  return node;
+}
+
+async function request(method, path, body) {
+  const headers = { [CSRF_HEADER]: state.csrf };
+  const options = { method, headers, credentials: 'same-origin', cache: 'no-store' };
+  if (body !== undefined) {
+    headers['Content-Type'] = 'application/json';
+    options.body = JSON.stringify(body);
+  }
+  const resp = await fetch(path, options);
+  if (resp.status === 401) {
+    showLogin();
+    throw new Error('the session has expired, log in again');
+  }
+  if (!resp.ok) {
+    throw new Error(`${method} ${path}: ${resp.status} ${resp.statusText}`);
+  }
+  return resp;
+}
+
+const getJSON = async (path) => (await request('GET', path)).json();
+const itemPath = (kind, title) => `/api/user/${kind}/${encodeURIComponent(title)}`;
+
+// ------- session
+
+function showLogin() {
+  state.csrf = '';
+  $('user').hidden = true;
+  $('vault').hidden = true;
+  $('login-form').hidden = false;
+}
+
+async function showVault(session) {
+  state.csrf = session.csrf;
+  $('login-name').textContent = session.login;
+  $('user').hidden = false;
+  $('login-form').hidden = true;
+  $('vault').hidden = false;
+  await loadItems();
+}
+
+async function restoreSession() {
+  const resp = await fetch('/web/session', { credentials: 'same-origin', cache: 'no-store' });
+  if (!resp.ok) {
+    showLogin();
+    return;
+  }
+  await showVault(await resp.json());
+}
+
+async function login(event) {
+  event.preventDefault();
+  const form = event.target;
+  const resp = await fetch('/web/login', {
+    method: 'POST',
+    credentials: 'same-origin',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ login: form.elements.login.value, pwd: form.elements.pwd.value }),
+  });
+  form.elements.pwd.value = '';
+  if (!resp.ok) {
+    setStatus(resp.status === 401 ? 'Wrong login or password' : `Login failed: ${resp.status}`, true);
+    return;
+  }
+  setStatus('');
+  await showVault(await resp.json());
+}
+
+async function logout() {
+  await fetch('/web/logout', { method: 'POST', credentials: 'same-origin', headers: { [CSRF_HEADER]: state.csrf } });
+  state.items = [];
+  state.selected = null;
+  $('items').replaceChildren();
+  $('details').replaceChildren();
+  showLogin();
+}
+
+// ------- items
+
+async function loadItems() {
+  const query = $('search').value.trim();
+  const params = new URLSearchParams({ limit: '100' });
+  if (query) params.set('q', query);
+  state.items = await getJSON(`/api/user/items/search?${params}`);
+  renderItems();
+  setStatus(`${state.items.length} items`);
+}
+
+function renderItems() {
+  const list = $('items');
+  list.replaceChildren();
+  for (const item of state.items) {
+    const li = el('li');
+    if (item.favorite) li.append(el('span', '★ ', 'favorite'));
+    li.append(el('span', item.kind, 'kind'), el('span', item.title));
+    if (state.selected && state.selected.kind === item.kind && state.selected.title === item.title) {
+      li.classList.add('selected');
+    }
+    li.addEventListener('click', () => openItem(item).catch((err) => setStatus(err.message, true)));
+    list.append(li);
+  }
+}
+
+// fields returns [name, value, secret] of the item of any kind.
+async function fields(item) {
+  switch (item.kind) {
+    case 'password': {
+      const p = await getJSON(`/api/user/password/details/${encodeURIComponent(item.title)}`);
+      return [
+        ['username', p.username, false],
+        ['password', p.pwd, true],
+        ...(p.urls || []).map((u) => ['url', u.url, false]),
+        ...(p.fields || []).map((f) => [f.name, f.value, f.type === 'hidden']),
+        ['description', p.description, false],
+      ];
+    }
+    case 'card': {
+      const c = await getJSON(itemPath('card', item.title));
+      return [
+        ['number', c.num, true], ['brand', c.brand, false], ['expiry', c.expires_at, false],
+        ['cvc', c.cvc, true], ['pin', c.pin, true], ['cardholder', c.cardholder, false],
+        ['address', c.address, false], ['description', c.description, false],
+      ];
+    }
+    case 'identity': {
+      const d = await getJSON(itemPath('identity', item.title));
+      const names = Object.keys(d.fields || {}).sort();
+      return [['type', d.doc_type, false], ...names.map((n) => [n, d.fields[n], false]), ['description', d.description, false]];
+    }
+    case 'sshkey': {
+      const k = await getJSON(itemPath('sshkey', item.title));
+      return [
+        ['public_key', k.public_key, false], ['fingerprint', k.fingerprint, false],
+        ['private_key', k.private_key, true], ['description', k.description, false],
+      ];
+    }
+    case 'note': {
+      const n = await getJSON(itemPath('note', item.title));
+      return [['text', n.text, true], ['description', n.description, false]];
+    }
+    case 'file':
+      return [['description', item.description, false]];
+  }
+  return [];
+}
+
+async function openItem(item) {
+  state.selected = item;
+  renderItems();
+  const details = $('details');
+  details.replaceChildren(el('h2', item.title));
+
+  const list = el('dl');
+  for (const [name, value, secret] of await fields(item)) {
+    if (!value) continue;
+    const dd = el('dd', secret ? MASK : value);
+    const actions = el('div', undefined, 'actions');
+    if (secret) {
+      const reveal = el('button', 'Show');
+      reveal.type = 'button';
+      reveal.addEventListener('click', () => {
+        const shown = dd.textContent !== MASK;
+        dd.textContent = shown ? MASK : value;
+        reveal.textContent = shown ? 'Show' : 'Hide';
+      });
+      actions.append(reveal);
+    }
+    const copyButton = el('button', 'Copy');
+    copyButton.type = 'button';
+    copyButton.addEventListener('click', () => copy(name, value));
+    actions.append(copyButton);
+    list.append(el('dt', name), dd, actions);
+  }
+  details.append(list);
+
+  if (item.kind === 'file') {
+    const download = el('button', 'Download');
+    download.type = 'button';
+    download.addEventListener('click', () => downloadFile(item).catch((err) => setStatus(err.message, true)));
+    details.append(download);
+  }
+  setStatus('');
+}
+
+// copy clears the clipboard later, if it still holds the copied value and the page is still open.
+async function copy(name, value) {
+  try {
+    await navigator.clipboard.writeText(value);
+  } catch (err) {
+    setStatus(`Copying failed: ${err.message}`, true);
+    return;
+  }
+  setStatus(`${name} is copied to the clipboard`);
+  setTimeout(async () => {
+    try {
+      if ((await navigator.clipboard.readText()) === value) await navigator.clipboard.writeText('');
+    } catch (err) {
+      // reading the clipboard is not allowed in the background: it is left as is
+    }
+  }, CLIPBOARD_CLEAR_MS);
+}
+
+async function downloadFile(item) {
+  const blob = await (await request('GET', itemPath('file', item.title))).blob();
+  const url = URL.createObjectURL(blob);
+  const link = el('a');
+  link.href = url;
+  link.download = item.title;
+  link.click();
+  setTimeout(() => URL.revokeObjectURL(url), 1000);
+  setStatus(`${item.title} is downloaded`);
+}
+
+// ------- new password
+
+const ALPHABET = 'ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789!#$%&*+-=?@_';
+
+// generatePassword draws the characters with the Web Crypto random generator, without modulo bias.
+function generatePassword(length) {
+  const limit = 256 - (256 % ALPHABET.length);
+  let res = '';
+  while (res.length < length) {
+    for (const b of crypto.getRandomValues(new Uint8Array(length * 2))) {
+      if (b < limit && res.length < length) res += ALPHABET[b % ALPHABET.length];
+    }
+  }
+  return res;
+}
+
+async function savePassword(event) {
+  event.preventDefault();
+  const form = event.target;
+  const body = {
+    title: form.elements.title.value,
+    username: form.elements.username.value,
+    pwd: form.elements.pwd.value,
+    description: form.elements.description.value,
+    urls: form.elements.url.value ? [{ url: form.elements.url.value }] : [],
+  };
+  await request('PUT', '/api/user/password', body);
+  form.reset();
+  form.hidden = true;
+  await loadItems();
+  setStatus(`Password ${body.title} is saved`);
+}
+
+function init() {
+  const report = (fn) => (event) => fn(event).catch((err) => setStatus(err.message, true));
+
+  $('login-form').addEventListener('submit', report(login));
+  $('logout').addEventListener('click', report(logout));
+  $('password-form').addEventListener('submit', report(savePassword));
+  $('new-password').addEventListener('click', () => { $('password-form').hidden = false; });
+  $('cancel-password').addEventListener('click', () => {
+    $('password-form').reset();
+    $('password-form').hidden = true;
+  });
+  $('generate').addEventListener('click', () => {
+    const input = $('password-form').elements.pwd;
+    input.value = generatePassword(20);
+    input.type = 'text';
+  });
+
+  let timer;
+  $('search').addEventListener('input', () => {
+    clearTimeout(timer);
+    timer = setTimeout(() => loadItems().catch((err) => setStatus(err.message, true)), 250);
+  });
+
+  restoreSession().catch((err) => setStatus(err.message, true));
+}
+
+document.addEventListener('DOMContentLoaded', init);
@@ -0,0 +1,88 @@
+// go-keeper secret link. The path holds the link id and the fragment holds the key, which the browser
+// never sends to the server. The secret is fetched only on the reveal button, so that link previews
+// do not burn it, and is decrypted here with WebCrypto (AES-256-GCM).
+'use strict';
+
+const $ = (id) => document.getElementById(id);
+
+function setStatus(text, isError) {
+  const status = $('status');
+  status.textContent = text;
+  status.classList.toggle('error', Boolean(isError));
+}
+
+function el(tag, text) {
+  const node = document.createElement(tag);
+  if (text !== undefined) node.textContent = text;
+  return node;
+}
+
+function fromBase64(s) {
+  return Uint8Array.from(atob(s), (c) => c.charCodeAt(0));
+}
+
+function fromBase64URL(s) {
+  const padded = s.replace(/-/g, '+').replace(/_/g, '/') + '='.repeat((4 - (s.length % 4)) % 4);
+  return fromBase64(padded);
+}
+
+async function decrypt(keyText, payload) {
+  const key = await crypto.subtle.importKey('raw', fromBase64URL(keyText), 'AES-GCM', false, ['decrypt']);
+  const data = fromBase64(payload);
+  const plain = await crypto.subtle.decrypt({ name: 'AES-GCM', iv: data.slice(0, 12) }, key, data.slice(12));
+  return JSON.parse(new TextDecoder().decode(plain));
+}
+
+function showSecret(item) {
+  const details = $('details');
+  details.replaceChildren(el('h2', item.title));
+  const list = el('dl');
+  for (const field of item.fields || []) {
+    const actions = el('span');
+    actions.className = 'actions';
+    const copyButton = el('button', 'Copy');
+    copyButton.type = 'button';
+    copyButton.addEventListener('click', async () => {
+      await navigator.clipboard.writeText(field.value);
+      setStatus(`${field.name} is copied`);
+    });
+    actions.append(copyButton);
+    list.append(el('dt', field.name), el('dd', field.value), actions);
+  }
+  details.append(list);
+  details.hidden = false;
+}
+
+async function reveal() {
+  const id = location.pathname.split('/').pop();
+  const keyText = location.hash.slice(1);
+  if (!id || !keyText) {
+    setStatus('The link is incomplete.', true);
+    return;
+  }
+
+  $('reveal').disabled = true;
+  const resp = await fetch(`/api/link/${encodeURIComponent(id)}`,
+    { method: 'POST', credentials: 'omit', cache: 'no-store' });
+  // the link is used up or counted: the key is not kept in the address bar and the history anymore
+  history.replaceState(null, '', location.pathname);
+  if (resp.status === 404) {
+    setStatus('The link has expired or has already been used.', true);
+    return;
+  }
+  if (!resp.ok) {
+    setStatus(`The secret can not be loaded: ${resp.status} ${resp.statusText}`, true);
+    return;
+  }
+
+  const { payload } = await resp.json();
+  try {
+    showSecret(await decrypt(keyText, payload));
+    $('intro').hidden = true;
+    setStatus('');
+  } catch (e) {
+    setStatus('The secret can not be decrypted: the link is damaged.', true);
+  }
+}
+
+$('reveal').addEventListener('click', () => reveal().catch((e) => setStatus(e.message, true)));
@@ -0,0 +1,20 @@
+// Package web embeds the single-page web vault: static HTML, script and style files without
+// inline code, so that the server can serve them under a strict content security policy.
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed static
+var static embed.FS
+
+// Static returns the files of the web vault, index.html at the root, and of the secret link page, link.html.
+func Static() fs.FS {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err) // the directory is embedded at build time
+	}
+	return files
+}
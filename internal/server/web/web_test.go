@@ -0,0 +1,24 @@
+package web
+
+import (
+	"io/fs"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The content security policy forbids inline scripts and styles: the page must only refer to its files.
+func TestStaticHasNoInlineCode(t *testing.T) {
+	files := Static()
+	for _, name := range []string{"index.html", "app.js", "app.css", "link.html", "link.js"} {
+		_, err := fs.Stat(files, name)
+		require.NoError(t, err, name)
+	}
+
+	for _, name := range []string{"index.html", "link.html"} {
+		page, err := fs.ReadFile(files, name)
+		require.NoError(t, err)
+		require.NotRegexp(t, regexp.MustCompile(`<script>|<script [^>]*>[^<]|<style|\son[a-z]+=|style=`), string(page), name)
+	}
+}
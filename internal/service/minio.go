@@ -4,29 +4,62 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log"
+	"net/url"
 	"time"
 
 	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
 )
 
 // MinioService encapsulates operations for interacting with a MinIO server.
 // It provides methods for bucket creation, file uploads, and file retrieval.
 type MinioService struct {
-	client     *minio.Client // MinIO client used for API interactions.
-	bucketName string        // Name of the bucket where objects are stored.
-	lifeTime   time.Duration // The duration for which objects in the bucket are valid.
+	client        *minio.Client // MinIO client used for API interactions.
+	presignClient *minio.Client // MinIO client used to sign links for the address users reach MinIO at.
+	bucketName    string        // Name of the bucket where objects are stored.
+	lifeTime      time.Duration // The duration for which presigned links to objects are valid.
 }
 
 func NewMinioService(client *minio.Client, bucketName string, lifeTime time.Duration) *MinioService {
 	return &MinioService{
-		client:     client,
-		bucketName: bucketName,
-		lifeTime:   lifeTime,
+		client:        client,
+		presignClient: client,
+		bucketName:    bucketName,
+		lifeTime:      lifeTime,
 	}
 }
 
+// UsePublicURL makes presigned links point at publicURL (http(s)://host[:port]), the address users
+// reach MinIO at, instead of the endpoint of the server. Signing needs the region of the bucket,
+// which is asked through the server endpoint, so the server never calls the public address.
+func (ms *MinioService) UsePublicURL(publicURL string, creds *credentials.Credentials) error {
+	u, err := url.Parse(publicURL)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+		return fmt.Errorf("public minio url %q is not http(s)://host[:port]", publicURL)
+	}
+
+	region, err := ms.client.GetBucketLocation(context.Background(), ms.bucketName)
+	if err != nil {
+		return err
+	}
+	client, err := minio.New(u.Host, &minio.Options{
+		Creds:  creds,
+		Secure: u.Scheme == "https",
+		Region: region,
+	})
+	if err != nil {
+		return err
+	}
+	ms.presignClient = client
+	return nil
+}
+
 // CreateBucket ensures that the bucket specified in the MinioService configuration exists.
 // If the bucket does not exist, it is created.
 func (ms *MinioService) CreateBucket() error {
@@ -61,3 +94,17 @@ func (ms *MinioService) Upload(fileCloudID string, reader io.Reader) error {
 func (ms *MinioService) GetObject(fileCLoudID string) (io.Reader, error) {
 	return ms.client.GetObject(context.Background(), ms.bucketName, fileCLoudID, minio.GetObjectOptions{})
 }
+
+// PresignedGetObject returns a link to download the file without credentials, valid for the lifetime
+// of the service, and the time it expires. The file is downloaded under fileName.
+func (ms *MinioService) PresignedGetObject(fileCloudID string, fileName string) (string, time.Time, error) {
+	params := url.Values{}
+	params.Set("response-content-disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+
+	expiresAt := time.Now().Add(ms.lifeTime)
+	u, err := ms.presignClient.PresignedGetObject(context.Background(), ms.bucketName, fileCloudID, ms.lifeTime, params)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return u.String(), expiresAt, nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsePublicURL(t *testing.T) {
+	// внутренний адрес MinIO отвечает только на запрос региона бакета
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; !ok {
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		_, _ = w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">eu-west-1</LocationConstraint>`))
+	}))
+	defer srv.Close()
+
+	creds := credentials.NewStaticV4("key", "secret", "")
+	client, err := minio.New(strings.TrimPrefix(srv.URL, "http://"), &minio.Options{Creds: creds})
+	require.NoError(t, err)
+
+	ms := NewMinioService(client, "test", 3*time.Minute)
+	require.NoError(t, ms.UsePublicURL("https://files.example.com", creds))
+
+	link, _, err := ms.PresignedGetObject("uuid", "contract.pdf")
+	require.NoError(t, err)
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	require.Equal(t, "https", u.Scheme)
+	require.Equal(t, "files.example.com", u.Host)
+	require.Contains(t, u.Query().Get("X-Amz-Credential"), "/eu-west-1/")
+
+	for _, bad := range []string{"files.example.com", "ftp://files.example.com", "https://files.example.com/minio"} {
+		require.Error(t, ms.UsePublicURL(bad, creds), bad)
+	}
+}
@@ -0,0 +1,245 @@
+// Package sshagent serves SSH keys from the vault over the ssh-agent protocol.
+// The agent is read-only: keys can not be added or removed by ssh-add, they are managed in the vault.
+// Keys marked for confirmation are used for signing only after the user allows it.
+package sshagent
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+
+	"github.com/adettelle/go-keeper/internal/unixsock"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+var (
+	// ErrReadOnly is returned for requests to add or remove keys.
+	ErrReadOnly = errors.New("keys are managed in the vault, the agent is read-only")
+	// ErrLocked is returned for signing requests while the agent is locked.
+	ErrLocked = errors.New("agent is locked")
+	// ErrRefused is returned if the user refused to use the key.
+	ErrRefused = errors.New("use of the key is refused")
+	// ErrUnknownKey is returned for signing requests with a key the agent does not hold.
+	ErrUnknownKey = errors.New("unknown key")
+)
+
+// Key is a vault SSH key to serve.
+type Key struct {
+	Title      string
+	PrivateKey string // PrivateKey is an unencrypted PEM.
+	Confirm    bool   // Confirm requires the user to allow every use of the key.
+}
+
+// ConfirmFunc asks the user to allow the use of the key, e.g. with a terminal prompt.
+type ConfirmFunc func(title, fingerprint string) bool
+
+type agentKey struct {
+	title   string
+	signer  ssh.Signer
+	confirm bool
+}
+
+// Agent is an agent.ExtendedAgent serving a fixed set of keys.
+type Agent struct {
+	confirm ConfirmFunc
+
+	mu         sync.Mutex
+	keys       []agentKey
+	passphrase []byte // passphrase is set while the agent is locked.
+
+	confirmMu sync.Mutex // confirmMu serializes the confirmation prompts.
+}
+
+var _ agent.ExtendedAgent = (*Agent)(nil)
+
+// New creates an agent serving the keys. confirm is called for the keys requiring confirmation;
+// if it is nil, such keys are never used.
+func New(keys []Key, confirm ConfirmFunc) (*Agent, error) {
+	a := &Agent{confirm: confirm}
+	for _, k := range keys {
+		signer, err := ssh.ParsePrivateKey([]byte(k.PrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("ssh key %q: %w", k.Title, err)
+		}
+		a.keys = append(a.keys, agentKey{title: k.Title, signer: signer, confirm: k.Confirm})
+	}
+	return a, nil
+}
+
+// List returns the public keys with the vault titles as comments, none while the agent is locked.
+func (a *Agent) List() ([]*agent.Key, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.passphrase != nil {
+		return nil, nil
+	}
+	res := make([]*agent.Key, 0, len(a.keys))
+	for _, k := range a.keys {
+		pub := k.signer.PublicKey()
+		res = append(res, &agent.Key{Format: pub.Type(), Blob: pub.Marshal(), Comment: k.title})
+	}
+	return res, nil
+}
+
+func (a *Agent) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	return a.SignWithFlags(key, data, 0)
+}
+
+// SignWithFlags signs the data with the key, asking for confirmation if the key requires it.
+// The rsa-sha2-256 and rsa-sha2-512 flags select the RSA signature algorithm.
+func (a *Agent) SignWithFlags(key ssh.PublicKey, data []byte, flags agent.SignatureFlags) (*ssh.Signature, error) {
+	k, err := a.find(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if k.confirm && !a.allow(k) {
+		log.Printf("use of ssh key %q is refused", k.title)
+		return nil, ErrRefused
+	}
+
+	var algorithm string
+	switch {
+	case flags&agent.SignatureFlagRsaSha256 != 0:
+		algorithm = ssh.KeyAlgoRSASHA256
+	case flags&agent.SignatureFlagRsaSha512 != 0:
+		algorithm = ssh.KeyAlgoRSASHA512
+	}
+	if algorithm != "" {
+		signer, ok := k.signer.(ssh.AlgorithmSigner)
+		if !ok {
+			return nil, fmt.Errorf("ssh key %q does not support %s signatures", k.title, algorithm)
+		}
+		return signer.SignWithAlgorithm(rand.Reader, data, algorithm)
+	}
+	return k.signer.Sign(rand.Reader, data)
+}
+
+func (a *Agent) find(key ssh.PublicKey) (agentKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.passphrase != nil {
+		return agentKey{}, ErrLocked
+	}
+	wanted := key.Marshal()
+	for _, k := range a.keys {
+		if bytes.Equal(k.signer.PublicKey().Marshal(), wanted) {
+			return k, nil
+		}
+	}
+	return agentKey{}, ErrUnknownKey
+}
+
+func (a *Agent) allow(k agentKey) bool {
+	if a.confirm == nil {
+		return false
+	}
+	a.confirmMu.Lock()
+	defer a.confirmMu.Unlock()
+
+	return a.confirm(k.title, ssh.FingerprintSHA256(k.signer.PublicKey()))
+}
+
+func (a *Agent) Add(agent.AddedKey) error {
+	return ErrReadOnly
+}
+
+func (a *Agent) Remove(ssh.PublicKey) error {
+	return ErrReadOnly
+}
+
+func (a *Agent) RemoveAll() error {
+	return ErrReadOnly
+}
+
+// Lock hides the keys until Unlock is called with the same passphrase.
+func (a *Agent) Lock(passphrase []byte) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.passphrase != nil {
+		return ErrLocked
+	}
+	a.passphrase = append([]byte{}, passphrase...)
+	return nil
+}
+
+func (a *Agent) Unlock(passphrase []byte) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.passphrase == nil {
+		return errors.New("agent is not locked")
+	}
+	if subtle.ConstantTimeCompare(a.passphrase, passphrase) != 1 {
+		return errors.New("incorrect passphrase")
+	}
+	a.passphrase = nil
+	return nil
+}
+
+// Signers is not used by the agent protocol; it returns the signers of the keys not requiring confirmation.
+func (a *Agent) Signers() ([]ssh.Signer, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.passphrase != nil {
+		return nil, ErrLocked
+	}
+	var res []ssh.Signer
+	for _, k := range a.keys {
+		if !k.confirm {
+			res = append(res, k.signer)
+		}
+	}
+	return res, nil
+}
+
+func (a *Agent) Extension(string, []byte) ([]byte, error) {
+	return nil, agent.ErrExtensionUnsupported
+}
+
+// Listen creates the Unix socket accessible only by the user, in a private directory.
+// A stale socket file is removed.
+func Listen(path string) (net.Listener, error) {
+	return unixsock.Listen(path)
+}
+
+// Serve serves the agent on the listener until the context is done. Connections of other users
+// are refused. The listener is closed on return.
+func Serve(ctx context.Context, l net.Listener, a *Agent) error {
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			if err := unixsock.CheckPeer(conn); err != nil {
+				log.Println("ssh agent connection is refused:", err)
+				return
+			}
+			if err := agent.ServeAgent(a, conn); err != nil && !errors.Is(err, io.EOF) {
+				log.Println("error in serving ssh agent:", err)
+			}
+		}()
+	}
+}
@@ -0,0 +1,111 @@
+package sshagent
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/adettelle/go-keeper/internal/sshkey"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+func newKey(t *testing.T, keyType sshkey.Type) (*sshkey.Key, ssh.PublicKey) {
+	t.Helper()
+	key, err := sshkey.Generate(keyType, 2048, "")
+	require.NoError(t, err)
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key.PublicKey))
+	require.NoError(t, err)
+	return key, pub
+}
+
+// serve starts the agent on a Unix socket and returns a client connected to it.
+func serve(t *testing.T, a *Agent) agent.ExtendedAgent {
+	t.Helper()
+	l, err := Listen(filepath.Join(t.TempDir(), "agent", "agent.sock"))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- Serve(ctx, l, a) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	conn, err := net.Dial("unix", l.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return agent.NewClient(conn)
+}
+
+func TestAgent(t *testing.T) {
+	ed, edPub := newKey(t, sshkey.TypeEd25519)
+	rsa, rsaPub := newKey(t, sshkey.TypeRSA)
+
+	var asked []string
+	allow := false
+	a, err := New([]Key{
+		{Title: "github", PrivateKey: ed.PrivateKey},
+		{Title: "prod", PrivateKey: rsa.PrivateKey, Confirm: true},
+	}, func(title, fingerprint string) bool {
+		asked = append(asked, title+" "+fingerprint)
+		return allow
+	})
+	require.NoError(t, err)
+
+	client := serve(t, a)
+
+	keys, err := client.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	require.Equal(t, "github", keys[0].Comment)
+	require.Equal(t, edPub.Marshal(), keys[0].Blob)
+
+	data := []byte("session id")
+	sig, err := client.Sign(edPub, data)
+	require.NoError(t, err)
+	require.NoError(t, edPub.Verify(data, sig))
+	require.Empty(t, asked)
+
+	// refused by the user
+	_, err = client.SignWithFlags(rsaPub, data, agent.SignatureFlagRsaSha256)
+	require.Error(t, err)
+	require.Equal(t, []string{"prod " + rsa.Fingerprint}, asked)
+
+	allow = true
+	sig, err = client.SignWithFlags(rsaPub, data, agent.SignatureFlagRsaSha256)
+	require.NoError(t, err)
+	require.Equal(t, ssh.KeyAlgoRSASHA256, sig.Format)
+	require.NoError(t, rsaPub.Verify(data, sig))
+
+	// read-only
+	require.Error(t, client.RemoveAll())
+
+	// locked
+	require.NoError(t, client.Lock([]byte("pass")))
+	keys, err = client.List()
+	require.NoError(t, err)
+	require.Empty(t, keys)
+	_, err = client.Sign(edPub, data)
+	require.Error(t, err)
+	require.Error(t, client.Unlock([]byte("wrong")))
+	require.NoError(t, client.Unlock([]byte("pass")))
+	_, err = client.Sign(edPub, data)
+	require.NoError(t, err)
+}
+
+func TestAgentWithoutConfirm(t *testing.T) {
+	ed, edPub := newKey(t, sshkey.TypeEd25519)
+
+	a, err := New([]Key{{Title: "github", PrivateKey: ed.PrivateKey, Confirm: true}}, nil)
+	require.NoError(t, err)
+
+	_, err = a.Sign(edPub, []byte("data"))
+	require.ErrorIs(t, err, ErrRefused)
+
+	_, err = New([]Key{{Title: "broken", PrivateKey: "not a key"}}, nil)
+	require.Error(t, err)
+}
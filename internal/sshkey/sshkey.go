@@ -0,0 +1,127 @@
+// Package sshkey generates and parses SSH private keys and derives their public keys
+// in the authorized_keys format and SHA256 fingerprints.
+package sshkey
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Type is an algorithm of generated keys.
+type Type string
+
+const (
+	TypeEd25519 Type = "ed25519"
+	TypeRSA     Type = "rsa"
+)
+
+const (
+	// DefaultRSABits is the size of generated RSA keys if none is given.
+	DefaultRSABits = 3072
+	// MinRSABits is the minimal size of generated and imported RSA keys.
+	MinRSABits = 2048
+	// MaxRSABits is the maximal size of generated RSA keys.
+	MaxRSABits = 8192
+)
+
+// ErrPassphraseRequired is returned by Parse for keys protected with a passphrase
+// if the passphrase is empty.
+var ErrPassphraseRequired = errors.New("private key is protected with a passphrase")
+
+// Key is a private key with its public part.
+type Key struct {
+	PrivateKey  string // PrivateKey is an unencrypted PEM in the OpenSSH format.
+	PublicKey   string // PublicKey is the authorized_keys line, with the comment if any.
+	Type        string // Type is the SSH key type, e.g. ssh-ed25519 or ssh-rsa.
+	Fingerprint string // Fingerprint is the SHA256 fingerprint, e.g. SHA256:Qf1...
+	Comment     string
+}
+
+// Generate creates a new key of the type. bits is the size of RSA keys, DefaultRSABits if zero;
+// it is ignored for Ed25519 keys.
+func Generate(t Type, bits int, comment string) (*Key, error) {
+	var private crypto.PrivateKey
+	switch t {
+	case TypeEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	case TypeRSA:
+		if bits == 0 {
+			bits = DefaultRSABits
+		}
+		if bits < MinRSABits || bits > MaxRSABits {
+			return nil, fmt.Errorf("rsa key size must be from %d to %d bits", MinRSABits, MaxRSABits)
+		}
+		key, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	default:
+		return nil, fmt.Errorf("unknown key type %q", t)
+	}
+	return newKey(private, comment)
+}
+
+// Parse reads a PEM private key (OpenSSH, PKCS#1, PKCS#8 or SEC 1), decrypting it with the passphrase
+// if it is protected. The key is re-encoded unencrypted in the OpenSSH format with the comment.
+func Parse(pemBytes []byte, passphrase []byte, comment string) (*Key, error) {
+	var (
+		private any
+		err     error
+	)
+	if len(passphrase) > 0 {
+		private, err = ssh.ParseRawPrivateKeyWithPassphrase(pemBytes, passphrase)
+	} else {
+		private, err = ssh.ParseRawPrivateKey(pemBytes)
+	}
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		return nil, ErrPassphraseRequired
+	}
+	if err != nil {
+		return nil, err
+	}
+	// ed25519 ключи разбираются в указатель
+	if key, ok := private.(*ed25519.PrivateKey); ok {
+		private = *key
+	}
+	if key, ok := private.(*rsa.PrivateKey); ok && key.N.BitLen() < MinRSABits {
+		return nil, fmt.Errorf("rsa key must be at least %d bits", MinRSABits)
+	}
+	return newKey(private, comment)
+}
+
+func newKey(private crypto.PrivateKey, comment string) (*Key, error) {
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		return nil, err
+	}
+	block, err := ssh.MarshalPrivateKey(private, comment)
+	if err != nil {
+		return nil, err
+	}
+	pub := signer.PublicKey()
+	authorized := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub)))
+	if comment != "" {
+		authorized += " " + comment
+	}
+	return &Key{
+		PrivateKey:  string(pem.EncodeToMemory(block)),
+		PublicKey:   authorized,
+		Type:        pub.Type(),
+		Fingerprint: ssh.FingerprintSHA256(pub),
+		Comment:     comment,
+	}, nil
+}
@@ -0,0 +1,73 @@
+package sshkey
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestGenerate(t *testing.T) {
+	key, err := Generate(TypeEd25519, 0, "ane@laptop")
+	require.NoError(t, err)
+	require.Equal(t, ssh.KeyAlgoED25519, key.Type)
+	require.True(t, strings.HasPrefix(key.PublicKey, "ssh-ed25519 "))
+	require.True(t, strings.HasSuffix(key.PublicKey, " ane@laptop"))
+	require.True(t, strings.HasPrefix(key.Fingerprint, "SHA256:"))
+
+	// the private key matches the public one
+	parsed, err := Parse([]byte(key.PrivateKey), nil, "ane@laptop")
+	require.NoError(t, err)
+	require.Equal(t, key.PublicKey, parsed.PublicKey)
+	require.Equal(t, key.Fingerprint, parsed.Fingerprint)
+
+	key, err = Generate(TypeRSA, 2048, "")
+	require.NoError(t, err)
+	require.Equal(t, ssh.KeyAlgoRSA, key.Type)
+
+	_, err = Generate(TypeRSA, 1024, "")
+	require.Error(t, err)
+	_, err = Generate("dsa", 0, "")
+	require.Error(t, err)
+}
+
+func TestParse(t *testing.T) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	encrypted, err := ssh.MarshalPrivateKeyWithPassphrase(private, "", []byte("secret"))
+	require.NoError(t, err)
+	pemBytes := pem.EncodeToMemory(encrypted)
+
+	_, err = Parse(pemBytes, nil, "")
+	require.ErrorIs(t, err, ErrPassphraseRequired)
+	_, err = Parse(pemBytes, []byte("wrong"), "")
+	require.Error(t, err)
+
+	key, err := Parse(pemBytes, []byte("secret"), "")
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(private)
+	require.NoError(t, err)
+	require.Equal(t, ssh.FingerprintSHA256(signer.PublicKey()), key.Fingerprint)
+	// stored unencrypted
+	_, err = ssh.ParseRawPrivateKey([]byte(key.PrivateKey))
+	require.NoError(t, err)
+
+	_, err = Parse([]byte("not a key"), nil, "")
+	require.Error(t, err)
+}
+
+func TestParseWeakRSA(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)})
+
+	_, err = Parse(pemBytes, nil, "")
+	require.Error(t, err)
+}
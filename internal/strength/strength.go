@@ -0,0 +1,413 @@
+// Package strength provides a zxcvbn-style password strength estimation.
+// A password is split into known patterns (common passwords, dictionary words, sequences,
+// repeats, keyboard runs and years); the number of guesses an attacker needs is estimated
+// for the cheapest combination of patterns and brute-forced characters.
+// The estimation is done locally, so passwords never leave the client.
+package strength
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/adettelle/go-keeper/internal/passgen"
+)
+
+// maxLength limits the analysed part of the password; longer passwords are strong anyway.
+const maxLength = 100
+
+//go:embed common_passwords.txt
+var commonPasswordsList []byte
+
+// dictionaries map lower case words to their rank (the number of guesses to find them).
+var (
+	commonPasswords = rankedList(commonPasswordsList)
+	dictionaryWords = wordsDictionary(passgen.Words())
+)
+
+func rankedList(data []byte) map[string]int {
+	res := map[string]int{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if _, ok := res[word]; word != "" && !ok {
+			res[word] = len(res) + 1
+		}
+	}
+	return res
+}
+
+// wordsDictionary treats every word of an unordered list as equally likely.
+func wordsDictionary(words []string) map[string]int {
+	res := make(map[string]int, len(words))
+	for _, word := range words {
+		res[word] = len(words)
+	}
+	return res
+}
+
+// l33t maps common character substitutions back to letters.
+var l33t = map[rune]rune{
+	'4': 'a', '@': 'a', '8': 'b', '3': 'e', '6': 'g', '1': 'i', '!': 'i',
+	'0': 'o', '$': 's', '5': 's', '7': 't', '+': 't', '2': 'z',
+}
+
+var keyboardRows = []string{"qwertyuiop", "asdfghjkl", "zxcvbnm", "1234567890", "!@#$%^&*()"}
+
+// Pattern is a kind of a password part recognized by the estimator.
+type Pattern string
+
+const (
+	PatternCommon     Pattern = "common password"
+	PatternDictionary Pattern = "dictionary word"
+	PatternUserInput  Pattern = "personal information"
+	PatternSequence   Pattern = "sequence"
+	PatternRepeat     Pattern = "repeated characters"
+	PatternKeyboard   Pattern = "keyboard pattern"
+	PatternYear       Pattern = "year"
+)
+
+var warnings = map[Pattern]string{
+	PatternCommon:     "This is a very common password.",
+	PatternDictionary: "A word by itself is easy to guess.",
+	PatternUserInput:  "The password contains its own title or other personal information.",
+	PatternSequence:   "Sequences like abc or 6543 are easy to guess.",
+	PatternRepeat:     "Repeats like aaa are easy to guess.",
+	PatternKeyboard:   "Straight rows of keys are easy to guess.",
+	PatternYear:       "Years are easy to guess.",
+}
+
+// Result is the outcome of a strength estimation.
+type Result struct {
+	GuessesLog10 float64   // GuessesLog10 is log10 of the estimated number of guesses.
+	Score        int       // Score is 0 (too guessable) to 4 (very unguessable), like in zxcvbn.
+	Patterns     []Pattern // Patterns are the recognized weak parts of the password.
+	Warning      string    // Warning explains the main weakness, empty for strong passwords.
+}
+
+// Weak reports whether the password should be replaced.
+func (r Result) Weak() bool {
+	return r.Score < 3
+}
+
+type match struct {
+	i, j    int // i and j are the bounds of the matched part [i, j) in runes.
+	guesses float64
+	pattern Pattern
+}
+
+// Estimate estimates the strength of the password. userInputs, such as the entry title
+// or the user login, are treated as the most likely dictionary words.
+func Estimate(password string, userInputs ...string) Result {
+	runes := []rune(password)
+	if len(runes) > maxLength {
+		runes = runes[:maxLength]
+	}
+	n := len(runes)
+	if n == 0 {
+		return Result{Warning: "The password is empty."}
+	}
+
+	user := map[string]int{}
+	for _, input := range userInputs {
+		for _, word := range strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if len([]rune(word)) >= 3 {
+				user[word] = 1
+			}
+		}
+	}
+
+	matches := findMatches(runes, user)
+
+	// best[k] is log10 of the minimal number of guesses for the first k runes
+	bruteforce := math.Log2(float64(cardinality(runes))) / math.Log2(10)
+	best := make([]float64, n+1)
+	prev := make([]*match, n+1)
+	for k := 1; k <= n; k++ {
+		best[k] = math.Inf(1)
+	}
+	for i := 0; i < n; i++ {
+		if best[i]+bruteforce < best[i+1] {
+			best[i+1] = best[i] + bruteforce
+			prev[i+1] = nil
+		}
+		for idx := range matches {
+			m := &matches[idx]
+			if m.i != i {
+				continue
+			}
+			if cost := best[i] + math.Log10(m.guesses); cost < best[m.j] {
+				best[m.j] = cost
+				prev[m.j] = m
+			}
+		}
+	}
+
+	res := Result{GuessesLog10: best[n]}
+	res.Score = score(res.GuessesLog10)
+
+	// walk back to collect the patterns of the cheapest split; the longest one gives the warning
+	longest := 0
+	for k := n; k > 0; {
+		m := prev[k]
+		if m == nil {
+			k--
+			continue
+		}
+		res.Patterns = append([]Pattern{m.pattern}, res.Patterns...)
+		if m.j-m.i > longest && res.Score < 3 {
+			longest = m.j - m.i
+			res.Warning = warnings[m.pattern]
+		}
+		k = m.i
+	}
+	if res.Warning == "" && res.Score < 3 {
+		res.Warning = "The password is too short."
+	}
+	return res
+}
+
+// score converts the number of guesses to the zxcvbn 0-4 scale.
+func score(guessesLog10 float64) int {
+	switch {
+	case guessesLog10 < 3:
+		return 0
+	case guessesLog10 < 6:
+		return 1
+	case guessesLog10 < 8:
+		return 2
+	case guessesLog10 < 10:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// cardinality returns the size of the alphabet the password characters come from.
+func cardinality(runes []rune) int {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range runes {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < unicode.MaxASCII:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+	res := 0
+	if lower {
+		res += 26
+	}
+	if upper {
+		res += 26
+	}
+	if digit {
+		res += 10
+	}
+	if symbol {
+		res += 33
+	}
+	if other {
+		res += 100
+	}
+	return res
+}
+
+func findMatches(runes []rune, user map[string]int) []match {
+	var res []match
+	res = append(res, dictionaryMatches(runes, user)...)
+	res = append(res, sequenceMatches(runes)...)
+	res = append(res, repeatMatches(runes)...)
+	res = append(res, keyboardMatches(runes)...)
+	res = append(res, yearMatches(runes)...)
+	return res
+}
+
+func dictionaryMatches(runes []rune, user map[string]int) []match {
+	var res []match
+	n := len(runes)
+	lower := toLower(runes)
+
+	unleeted := make([]rune, n)
+	for k, r := range lower {
+		if sub, ok := l33t[r]; ok {
+			unleeted[k] = sub
+		} else {
+			unleeted[k] = r
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		for j := i + 3; j <= n; j++ {
+			variations := caseVariations(runes[i:j])
+
+			word := string(lower[i:j])
+			if m, ok := lookup(word, user); ok {
+				m.i, m.j = i, j
+				m.guesses *= variations
+				res = append(res, m)
+			}
+
+			leeted := string(unleeted[i:j])
+			if leeted == word {
+				continue
+			}
+			if m, ok := lookup(leeted, user); ok {
+				subs := 0
+				for k := i; k < j; k++ {
+					if unleeted[k] != lower[k] {
+						subs++
+					}
+				}
+				m.i, m.j = i, j
+				m.guesses *= variations * math.Pow(2, float64(subs))
+				res = append(res, m)
+			}
+		}
+	}
+	return res
+}
+
+// lookup finds a word in the user inputs and dictionaries.
+func lookup(word string, user map[string]int) (match, bool) {
+	if rank, ok := user[word]; ok {
+		return match{guesses: float64(rank), pattern: PatternUserInput}, true
+	}
+	if rank, ok := commonPasswords[word]; ok {
+		return match{guesses: float64(rank), pattern: PatternCommon}, true
+	}
+	if rank, ok := dictionaryWords[word]; ok {
+		return match{guesses: float64(rank), pattern: PatternDictionary}, true
+	}
+	return match{}, false
+}
+
+// toLower lower cases every rune keeping the positions.
+func toLower(runes []rune) []rune {
+	res := make([]rune, len(runes))
+	for k, r := range runes {
+		res[k] = unicode.ToLower(r)
+	}
+	return res
+}
+
+// caseVariations returns the number of ways upper case letters could be placed in the word.
+func caseVariations(word []rune) float64 {
+	upper, lower := 0, 0
+	for _, r := range word {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+	switch {
+	case upper == 0:
+		return 1
+	case lower == 0 || (upper == 1 && unicode.IsUpper(word[0])):
+		return 2
+	default:
+		return math.Pow(2, float64(upper+1))
+	}
+}
+
+func sequenceMatches(runes []rune) []match {
+	var res []match
+	n := len(runes)
+	for i := 0; i < n-2; {
+		delta := runes[i+1] - runes[i]
+		if delta != 1 && delta != -1 {
+			i++
+			continue
+		}
+		j := i + 2
+		for j < n && runes[j]-runes[j-1] == delta {
+			j++
+		}
+		if j-i >= 3 {
+			base := 26.0
+			switch {
+			case strings.ContainsRune("aAzZ019", runes[i]):
+				base = 4
+			case unicode.IsDigit(runes[i]):
+				base = 10
+			}
+			if delta < 0 {
+				base *= 2
+			}
+			res = append(res, match{i: i, j: j, guesses: base * float64(j-i), pattern: PatternSequence})
+		}
+		i = j - 1
+	}
+	return res
+}
+
+func repeatMatches(runes []rune) []match {
+	var res []match
+	n := len(runes)
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && runes[j] == runes[i] {
+			j++
+		}
+		if j-i >= 3 {
+			res = append(res, match{
+				i: i, j: j, guesses: float64(cardinality(runes[i:i+1]) * (j - i)), pattern: PatternRepeat,
+			})
+		}
+		i = j
+	}
+	return res
+}
+
+func keyboardMatches(runes []rune) []match {
+	var res []match
+	lower := toLower(runes)
+	n := len(runes)
+	for i := 0; i < n; i++ {
+		for j := i + 4; j <= n; j++ {
+			if !onKeyboardRow(string(lower[i:j])) {
+				break
+			}
+			res = append(res, match{i: i, j: j, guesses: 100 * float64(j-i), pattern: PatternKeyboard})
+		}
+	}
+	return res
+}
+
+func onKeyboardRow(part string) bool {
+	reversed := []rune(part)
+	for a, b := 0, len(reversed)-1; a < b; a, b = a+1, b-1 {
+		reversed[a], reversed[b] = reversed[b], reversed[a]
+	}
+	for _, row := range keyboardRows {
+		if strings.Contains(row, part) || strings.Contains(row, string(reversed)) {
+			return true
+		}
+	}
+	return false
+}
+
+func yearMatches(runes []rune) []match {
+	var res []match
+	for i := 0; i+4 <= len(runes); i++ {
+		part := string(runes[i : i+4])
+		if (strings.HasPrefix(part, "19") || strings.HasPrefix(part, "20")) &&
+			unicode.IsDigit(runes[i+2]) && unicode.IsDigit(runes[i+3]) {
+			res = append(res, match{i: i, j: i + 4, guesses: 120, pattern: PatternYear})
+		}
+	}
+	return res
+}
@@ -0,0 +1,56 @@
+package strength
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateWeak(t *testing.T) {
+	tests := []struct {
+		password string
+		pattern  Pattern
+	}{
+		{password: "password", pattern: PatternCommon},
+		{password: "P@ssw0rd", pattern: PatternCommon},
+		{password: "qwerty123", pattern: PatternCommon},
+		{password: "abcdefgh", pattern: PatternSequence},
+		{password: "zzzzzzzzzz", pattern: PatternRepeat},
+		{password: "asdfghjk", pattern: PatternKeyboard},
+		{password: "Sunshine1987", pattern: PatternCommon},
+		{password: "1987", pattern: PatternYear},
+		{password: "87654321", pattern: PatternSequence},
+		{password: "Bamboo", pattern: PatternDictionary},
+	}
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			res := Estimate(tt.password)
+			require.True(t, res.Weak(), "score %d", res.Score)
+			require.Contains(t, res.Patterns, tt.pattern)
+			require.NotEmpty(t, res.Warning)
+		})
+	}
+}
+
+func TestEstimateStrong(t *testing.T) {
+	for _, password := range []string{"3:%v4yM<93+sSzQ(eCfK", "Emptiness-Viewpoint-Badness-Treachery", "k7#Qz!p2Lm9x"} {
+		res := Estimate(password)
+		require.Equal(t, 4, res.Score, password)
+		require.False(t, res.Weak())
+		require.Empty(t, res.Warning)
+	}
+}
+
+func TestEstimateUserInputs(t *testing.T) {
+	withoutInputs := Estimate("gitlabxk29")
+	withInputs := Estimate("gitlabxk29", "gitlab")
+
+	require.Less(t, withInputs.GuessesLog10, withoutInputs.GuessesLog10)
+	require.Contains(t, withInputs.Patterns, PatternUserInput)
+}
+
+func TestEstimateEmpty(t *testing.T) {
+	res := Estimate("")
+	require.Equal(t, 0, res.Score)
+	require.NotEmpty(t, res.Warning)
+}
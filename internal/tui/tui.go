@@ -0,0 +1,410 @@
+// Package tui is a full-screen terminal interface to browse the vault: fuzzy search over items
+// of all kinds, revealing and copying fields, editing entries and downloading files.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+)
+
+// Field is a field of a vault item as shown in the detail view.
+type Field struct {
+	Name     string
+	Value    string
+	Secret   bool // Secret values are masked until revealed.
+	Editable bool
+}
+
+// FieldFileName is the field of file items with the original file name.
+const FieldFileName = "file_name"
+
+// Vault is the data source of the interface.
+type Vault interface {
+	// Items returns the items of all kinds.
+	Items(ctx context.Context) ([]keeperclient.Item, error)
+	// Fields returns the fields of the item.
+	Fields(ctx context.Context, item keeperclient.Item) ([]Field, error)
+	// SetField changes an editable field of the item.
+	SetField(ctx context.Context, item keeperclient.Item, name, value string) error
+	// Download saves the file item to the path.
+	Download(ctx context.Context, item keeperclient.Item, path string) error
+	// Copy copies the value to the clipboard.
+	Copy(name, value string) error
+}
+
+// Run runs the interface until the user quits.
+func Run(vault Vault) error {
+	_, err := tea.NewProgram(New(vault), tea.WithAltScreen()).Run()
+	return err
+}
+
+type mode int
+
+const (
+	modeList mode = iota
+	modeDetail
+	modeEdit
+	modeDownload
+)
+
+const mask = "••••••••"
+
+// Model is the bubbletea model of the interface.
+type Model struct {
+	vault Vault
+	mode  mode
+
+	items   []keeperclient.Item
+	matches []int // matches are indexes of the items matching the search, best first.
+	cursor  int
+	search  textinput.Model
+
+	item     keeperclient.Item
+	fields   []Field
+	field    int
+	revealed map[string]bool
+	input    textinput.Model
+
+	status string
+	height int
+}
+
+// New creates the model; the items are loaded on Init.
+func New(vault Vault) Model {
+	search := textinput.New()
+	search.Prompt = "Search: "
+	search.Placeholder = "title, description, folder or tag"
+	search.Focus()
+
+	return Model{vault: vault, search: search, input: textinput.New(), height: 24}
+}
+
+type itemsMsg struct {
+	items []keeperclient.Item
+	err   error
+}
+
+type fieldsMsg struct {
+	item   keeperclient.Item
+	fields []Field
+	err    error
+}
+
+// doneMsg reports the result of an action. The item is reloaded after a change.
+type doneMsg struct {
+	status string
+	err    error
+	reload bool
+}
+
+func (m Model) loadItems() tea.Msg {
+	items, err := m.vault.Items(context.Background())
+	return itemsMsg{items: items, err: err}
+}
+
+func (m Model) loadFields(item keeperclient.Item) tea.Cmd {
+	return func() tea.Msg {
+		fields, err := m.vault.Fields(context.Background(), item)
+		return fieldsMsg{item: item, fields: fields, err: err}
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, m.loadItems)
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		return m, nil
+
+	case itemsMsg:
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		m.items = msg.items
+		m.filter()
+		m.status = fmt.Sprintf("%d items", len(m.items))
+		return m, nil
+
+	case fieldsMsg:
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		if m.mode == modeList || m.item.Title != msg.item.Title || m.item.Kind != msg.item.Kind {
+			m.revealed = map[string]bool{}
+			m.field = 0
+		}
+		m.item, m.fields, m.mode = msg.item, msg.fields, modeDetail
+		m.field = min(m.field, max(len(m.fields)-1, 0))
+		return m, nil
+
+	case doneMsg:
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		m.status = msg.status
+		if msg.reload {
+			return m, m.loadFields(m.item)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		switch m.mode {
+		case modeList:
+			return m.updateList(msg)
+		case modeDetail:
+			return m.updateDetail(msg)
+		case modeEdit, modeDownload:
+			return m.updateInput(msg)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		if m.search.Value() == "" {
+			return m, tea.Quit
+		}
+		m.search.SetValue("")
+		m.filter()
+		return m, nil
+	case tea.KeyUp, tea.KeyCtrlP:
+		m.cursor = max(m.cursor-1, 0)
+		return m, nil
+	case tea.KeyDown, tea.KeyCtrlN:
+		m.cursor = min(m.cursor+1, max(len(m.matches)-1, 0))
+		return m, nil
+	case tea.KeyCtrlR:
+		m.status = "Loading..."
+		return m, m.loadItems
+	case tea.KeyEnter:
+		if len(m.matches) == 0 {
+			return m, nil
+		}
+		m.status = ""
+		return m, m.loadFields(m.items[m.matches[m.cursor]])
+	}
+
+	var cmd tea.Cmd
+	m.search, cmd = m.search.Update(msg)
+	m.filter()
+	return m, cmd
+}
+
+func (m Model) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "backspace", "left", "h":
+		m.mode = modeList
+		m.status = ""
+		return m, nil
+	case "q":
+		return m, tea.Quit
+	case "up", "k":
+		m.field = max(m.field-1, 0)
+		return m, nil
+	case "down", "j":
+		m.field = min(m.field+1, max(len(m.fields)-1, 0))
+		return m, nil
+	}
+	if len(m.fields) == 0 {
+		return m, nil
+	}
+	f := m.fields[m.field]
+
+	switch msg.String() {
+	case "r", " ":
+		m.revealed[f.Name] = !m.revealed[f.Name]
+	case "c", "y":
+		return m, func() tea.Msg {
+			err := m.vault.Copy(f.Name, f.Value)
+			return doneMsg{status: f.Name + " is copied to the clipboard", err: err}
+		}
+	case "e", "enter":
+		if !f.Editable {
+			m.status = f.Name + " can not be edited"
+			return m, nil
+		}
+		m.mode = modeEdit
+		m.input = textinput.New()
+		m.input.Prompt = f.Name + ": "
+		m.input.SetValue(f.Value)
+		if f.Secret && !m.revealed[f.Name] {
+			m.input.EchoMode = textinput.EchoPassword
+		}
+		m.status = "enter saves, esc cancels"
+		return m, m.input.Focus()
+	case "d":
+		if m.item.Kind != keeperclient.KindFile {
+			return m, nil
+		}
+		m.mode = modeDownload
+		m.input = textinput.New()
+		m.input.Prompt = "Save to: "
+		m.input.SetValue(fileName(m.fields, m.item.Title))
+		m.status = "enter downloads, esc cancels"
+		return m, m.input.Focus()
+	}
+	return m, nil
+}
+
+func (m Model) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = modeDetail
+		m.status = ""
+		return m, nil
+	case tea.KeyEnter:
+		value, item := m.input.Value(), m.item
+		if m.mode == modeDownload {
+			m.mode = modeDetail
+			return m, func() tea.Msg {
+				err := m.vault.Download(context.Background(), item, value)
+				return doneMsg{status: "File is saved to " + value, err: err}
+			}
+		}
+		m.mode = modeDetail
+		name := m.fields[m.field].Name
+		return m, func() tea.Msg {
+			err := m.vault.SetField(context.Background(), item, name, value)
+			return doneMsg{status: name + " is updated", err: err, reload: true}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// itemSource is the searched text of the items.
+type itemSource []keeperclient.Item
+
+func (s itemSource) String(i int) string {
+	it := s[i]
+	return strings.Join(append([]string{it.Title, it.Description, it.Folder}, it.Tags...), " ")
+}
+
+func (s itemSource) Len() int {
+	return len(s)
+}
+
+// filter fuzzy-matches the items against the search, best matches first.
+func (m *Model) filter() {
+	m.matches = m.matches[:0]
+	if pattern := m.search.Value(); pattern != "" {
+		for _, match := range fuzzy.FindFrom(pattern, itemSource(m.items)) {
+			m.matches = append(m.matches, match.Index)
+		}
+	} else {
+		for i := range m.items {
+			m.matches = append(m.matches, i)
+		}
+	}
+	m.cursor = min(m.cursor, max(len(m.matches)-1, 0))
+}
+
+func fileName(fields []Field, def string) string {
+	for _, f := range fields {
+		if f.Name == FieldFileName && f.Value != "" {
+			return f.Value
+		}
+	}
+	return def
+}
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	selectedStyle = lipgloss.NewStyle().Bold(true).Reverse(true)
+	faintStyle    = lipgloss.NewStyle().Faint(true)
+)
+
+func (m Model) View() string {
+	var b strings.Builder
+	switch m.mode {
+	case modeList:
+		b.WriteString(titleStyle.Render("go-keeper") + "\n")
+		b.WriteString(m.search.View() + "\n\n")
+		b.WriteString(m.listView())
+		b.WriteString("\n" + m.status + "\n")
+		b.WriteString(faintStyle.Render("↑/↓ move • enter open • ctrl+r reload • esc clear/quit"))
+	default:
+		b.WriteString(titleStyle.Render(fmt.Sprintf("%s %s", m.item.Kind, m.item.Title)) + "\n\n")
+		b.WriteString(m.detailView())
+		if m.mode == modeEdit || m.mode == modeDownload {
+			b.WriteString("\n" + m.input.View() + "\n")
+		}
+		b.WriteString("\n" + m.status + "\n")
+		help := "↑/↓ move • r reveal • c copy • e edit"
+		if m.item.Kind == keeperclient.KindFile {
+			help += " • d download"
+		}
+		b.WriteString(faintStyle.Render(help + " • esc back • q quit"))
+	}
+	return b.String()
+}
+
+func (m Model) listView() string {
+	if len(m.matches) == 0 {
+		return faintStyle.Render("no items") + "\n"
+	}
+
+	// keep the cursor on the screen: header, search and status lines take 6 rows
+	rows := max(m.height-6, 1)
+	start := 0
+	if m.cursor >= rows {
+		start = m.cursor - rows + 1
+	}
+
+	var b strings.Builder
+	for i := start; i < len(m.matches) && i < start+rows; i++ {
+		it := m.items[m.matches[i]]
+		mark := " "
+		if it.Favorite {
+			mark = "★"
+		}
+		line := fmt.Sprintf("%s %-9s %-30s %s", mark, it.Kind, it.Title, faintStyle.Render(it.Description))
+		if i == m.cursor {
+			line = selectedStyle.Render(fmt.Sprintf("%s %-9s %-30s", mark, it.Kind, it.Title)) + " " +
+				faintStyle.Render(it.Description)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+func (m Model) detailView() string {
+	if len(m.fields) == 0 {
+		return faintStyle.Render("no fields") + "\n"
+	}
+	var b strings.Builder
+	for i, f := range m.fields {
+		value := f.Value
+		if f.Secret && !m.revealed[f.Name] && value != "" {
+			value = mask
+		} else if first, _, multiline := strings.Cut(value, "\n"); multiline {
+			value = first + " …"
+		}
+		line := fmt.Sprintf("%-16s %s", f.Name, value)
+		if i == m.field {
+			line = selectedStyle.Render(fmt.Sprintf("%-16s", f.Name)) + " " + value
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adettelle/go-keeper/pkg/keeperclient"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeVault struct {
+	items  []keeperclient.Item
+	fields map[string][]Field
+	copied string
+	set    map[string]string
+}
+
+func (v *fakeVault) Items(context.Context) ([]keeperclient.Item, error) {
+	return v.items, nil
+}
+
+func (v *fakeVault) Fields(_ context.Context, item keeperclient.Item) ([]Field, error) {
+	return v.fields[item.Title], nil
+}
+
+func (v *fakeVault) SetField(_ context.Context, item keeperclient.Item, name, value string) error {
+	v.set[item.Title+"/"+name] = value
+	for i, f := range v.fields[item.Title] {
+		if f.Name == name {
+			v.fields[item.Title][i].Value = value
+		}
+	}
+	return nil
+}
+
+func (v *fakeVault) Download(context.Context, keeperclient.Item, string) error {
+	return nil
+}
+
+func (v *fakeVault) Copy(_, value string) error {
+	v.copied = value
+	return nil
+}
+
+func newFakeVault() *fakeVault {
+	return &fakeVault{
+		items: []keeperclient.Item{
+			{Kind: keeperclient.KindPassword, Title: "github", Description: "work"},
+			{Kind: keeperclient.KindCard, Title: "visa"},
+			{Kind: keeperclient.KindPassword, Title: "gitlab"},
+		},
+		fields: map[string][]Field{
+			"gitlab": {
+				{Name: "username", Value: "ane", Editable: true},
+				{Name: "password", Value: "s3cret", Secret: true, Editable: true},
+			},
+		},
+		set: map[string]string{},
+	}
+}
+
+// send applies the message and runs the returned commands synchronously. Commands which do not finish
+// at once, such as cursor blinking, are dropped.
+func send(t *testing.T, m tea.Model, msg tea.Msg) tea.Model {
+	t.Helper()
+	m, cmd := m.Update(msg)
+	for cmd != nil {
+		done := make(chan tea.Msg, 1)
+		go func() { done <- cmd() }()
+
+		var next tea.Msg
+		select {
+		case next = <-done:
+		case <-time.After(20 * time.Millisecond):
+		}
+		if next == nil {
+			break
+		}
+		if _, ok := next.(tea.BatchMsg); ok {
+			break
+		}
+		m, cmd = m.Update(next)
+	}
+	return m
+}
+
+func typeText(t *testing.T, m tea.Model, s string) tea.Model {
+	for _, r := range s {
+		m = send(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return m
+}
+
+func TestBrowse(t *testing.T) {
+	vault := newFakeVault()
+	var m tea.Model = New(vault)
+	m = send(t, m, m.(Model).loadItems())
+	require.Len(t, m.(Model).matches, 3)
+
+	m = typeText(t, m, "gtlb")
+	require.Equal(t, []int{2}, m.(Model).matches)
+
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, modeDetail, m.(Model).mode)
+	require.Contains(t, m.View(), mask)
+	require.NotContains(t, m.View(), "s3cret")
+
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	m = typeText(t, m, "r")
+	require.Contains(t, m.View(), "s3cret")
+
+	m = typeText(t, m, "c")
+	require.Equal(t, "s3cret", vault.copied)
+
+	m = typeText(t, m, "e")
+	require.Equal(t, modeEdit, m.(Model).mode)
+	for range "s3cret" {
+		m = send(t, m, tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	m = typeText(t, m, "n3w")
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, "n3w", vault.set["gitlab/password"])
+	require.Equal(t, modeDetail, m.(Model).mode)
+	require.True(t, strings.Contains(m.View(), "n3w"), "field stays revealed after reload")
+
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	require.Equal(t, modeList, m.(Model).mode)
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	require.Len(t, m.(Model).matches, 3)
+}
+
+func TestReadOnlyField(t *testing.T) {
+	vault := newFakeVault()
+	vault.fields["gitlab"][0].Editable = false
+
+	var m tea.Model = New(vault)
+	m = send(t, m, m.(Model).loadItems())
+	m = typeText(t, m, "gitlab")
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	m = typeText(t, m, "e")
+	require.Equal(t, modeDetail, m.(Model).mode)
+	require.Contains(t, m.(Model).status, "can not be edited")
+}
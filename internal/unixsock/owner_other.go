@@ -0,0 +1,12 @@
+//go:build !unix
+
+package unixsock
+
+import (
+	"errors"
+	"os"
+)
+
+func fileOwner(os.FileInfo) (int, error) {
+	return 0, errors.New("file owner is not available on this platform")
+}
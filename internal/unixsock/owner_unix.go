@@ -0,0 +1,18 @@
+//go:build unix
+
+package unixsock
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// fileOwner returns the user id of the file owner.
+func fileOwner(fi os.FileInfo) (int, error) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, errors.New("file owner is not available")
+	}
+	return int(st.Uid), nil
+}
@@ -0,0 +1,35 @@
+//go:build darwin || freebsd
+
+package unixsock
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+func checkPeerSupported() error {
+	return nil
+}
+
+// peerUID returns the user id of the peer process with LOCAL_PEERCRED.
+func peerUID(conn *net.UnixConn) (int, error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var (
+		cred    *unix.Xucred
+		credErr error
+	)
+	err = raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptXucred(int(fd), unix.SOL_LOCAL, unix.LOCAL_PEERCRED)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if credErr != nil {
+		return 0, credErr
+	}
+	return int(cred.Uid), nil
+}
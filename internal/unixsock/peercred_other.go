@@ -0,0 +1,20 @@
+//go:build !linux && !darwin && !freebsd
+
+package unixsock
+
+import (
+	"errors"
+	"net"
+)
+
+var errPeerCredUnsupported = errors.New("socket peer credentials are not available on this platform")
+
+// checkPeerSupported refuses to use sockets without peer credentials: the socket permissions alone
+// are not checked on every platform.
+func checkPeerSupported() error {
+	return errPeerCredUnsupported
+}
+
+func peerUID(*net.UnixConn) (int, error) {
+	return 0, errPeerCredUnsupported
+}
@@ -0,0 +1,111 @@
+// Package unixsock creates and dials the Unix sockets of the local agents so that only the user
+// can reach them. Sockets live in a private directory: owned by the user, mode 0700 and not a
+// symlink, which closes the window between creating a socket and restricting its mode, and keeps
+// other users from planting a directory in a shared place such as /tmp. Both ends also check
+// the user id of the peer process with the socket peer credentials.
+package unixsock
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// PrivateDir checks that dir is a directory of the user that nobody else can access.
+// It is created if it does not exist yet.
+func PrivateDir(dir string) error {
+	if err := os.Mkdir(dir, 0o700); err != nil && !errors.Is(err, os.ErrExist) {
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return err
+		}
+	}
+	return checkPrivateDir(dir)
+}
+
+func checkPrivateDir(dir string) error {
+	fi, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if fi.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("socket directory %s is a symlink", dir)
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("socket directory %s is not a directory", dir)
+	}
+	if perm := fi.Mode().Perm(); perm != 0o700 {
+		return fmt.Errorf("socket directory %s has mode %#o, want 0700", dir, perm)
+	}
+	uid, err := fileOwner(fi)
+	if err != nil {
+		return err
+	}
+	if uid != os.Getuid() {
+		return fmt.Errorf("socket directory %s is owned by uid %d, not by you", dir, uid)
+	}
+	return nil
+}
+
+// Listen creates the Unix socket in the private directory of path. A stale socket file is removed.
+// It fails if peer credentials are not available, as the peers could not be checked.
+func Listen(path string) (net.Listener, error) {
+	if err := checkPeerSupported(); err != nil {
+		return nil, err
+	}
+	if err := PrivateDir(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Dial connects to the socket if its directory is private and the process serving it runs as the user.
+func Dial(path string, timeout time.Duration) (net.Conn, error) {
+	if err := checkPeerSupported(); err != nil {
+		return nil, err
+	}
+	if err := checkPrivateDir(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	conn, err := net.DialTimeout("unix", path, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckPeer(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// CheckPeer checks that the peer process of the connection runs as the same user.
+func CheckPeer(conn net.Conn) error {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return errors.New("not a unix socket connection")
+	}
+	uid, err := peerUID(unixConn)
+	if err != nil {
+		return err
+	}
+	if uid != os.Getuid() {
+		return fmt.Errorf("peer uid %d differs from uid %d", uid, os.Getuid())
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package unixsock
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivateDir(t *testing.T) {
+	base := t.TempDir()
+
+	dir := filepath.Join(base, "gokeeper")
+	require.NoError(t, PrivateDir(dir))
+	// повторная проверка существующего каталога
+	require.NoError(t, PrivateDir(dir))
+
+	// каталог, созданный заранее с широкими правами, не принимается
+	open := filepath.Join(base, "open")
+	require.NoError(t, os.Mkdir(open, 0o755))
+	require.NoError(t, os.Chmod(open, 0o755))
+	require.ErrorContains(t, PrivateDir(open), "mode")
+
+	// как и символическая ссылка на приватный каталог
+	link := filepath.Join(base, "link")
+	require.NoError(t, os.Symlink(dir, link))
+	require.ErrorContains(t, PrivateDir(link), "symlink")
+}
+
+func TestListenAndDial(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "agent", "agent.sock")
+	l, err := Listen(socket)
+	require.NoError(t, err)
+	defer l.Close()
+
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			_ = CheckPeer(conn)
+			conn.Close()
+		}
+	}()
+
+	conn, err := Dial(socket, time.Second)
+	require.NoError(t, err)
+	conn.Close()
+
+	fi, err := os.Stat(socket)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+
+	// сокет в общем каталоге не используется
+	shared := filepath.Join(t.TempDir(), "shared")
+	require.NoError(t, os.Mkdir(shared, 0o777))
+	require.NoError(t, os.Chmod(shared, 0o777))
+	_, err = Listen(filepath.Join(shared, "agent.sock"))
+	require.Error(t, err)
+	_, err = Dial(filepath.Join(shared, "agent.sock"), time.Second)
+	require.Error(t, err)
+}
@@ -0,0 +1,136 @@
+// Package urlmatch matches site URLs of password entries against a visited URL
+// by registrable domain, host or exact URL, and builds blind indexes of them,
+// so that the server can look entries up without storing domains in plain text.
+package urlmatch
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// Match rules of password URLs.
+const (
+	RuleDomain = "domain" // RuleDomain matches any site of the same registrable domain, e.g. mail.example.co.uk and example.co.uk.
+	RuleHost   = "host"   // RuleHost matches the same host and port.
+	RuleExact  = "exact"  // RuleExact matches the same URL, ignoring the fragment.
+	RuleNever  = "never"  // RuleNever disables matching of the URL.
+)
+
+// Key is a URL split into the parts the rules compare.
+type Key struct {
+	Scheme string // Scheme is the lower case scheme, e.g. "https".
+	Domain string // Domain is the registrable domain (eTLD+1), or the host name for IP addresses and single-label hosts.
+	Host   string // Host is the lower case host name with the port, if it is not the default one.
+	URL    string // URL is the normalized URL without the fragment.
+}
+
+// Parse normalizes the URL and splits it into the Key.
+func Parse(rawURL string) (Key, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return Key{}, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return Key{}, fmt.Errorf("url %q must have a scheme and a host", rawURL)
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	hostname := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	host := hostname
+	if port := u.Port(); port != "" && !(scheme == "http" && port == "80") && !(scheme == "https" && port == "443") {
+		host = net.JoinHostPort(hostname, port)
+	} else if strings.Contains(hostname, ":") {
+		host = "[" + hostname + "]" // IPv6
+	}
+
+	domain := hostname
+	if net.ParseIP(hostname) == nil {
+		if d, err := publicsuffix.EffectiveTLDPlusOne(hostname); err == nil {
+			domain = d
+		}
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	normalized := scheme + "://" + host + path
+	if u.RawQuery != "" {
+		normalized += "?" + u.RawQuery
+	}
+
+	return Key{Scheme: scheme, Domain: domain, Host: host, URL: normalized}, nil
+}
+
+// Matches reports whether the stored URL matches the target one according to the rule.
+// The schemes must be the same, except that an http URL also matches the https one: the credentials
+// of an https site are never given to a plain http one, where they would be sent in cleartext.
+func Matches(rule string, stored, target Key) bool {
+	if stored.Scheme != target.Scheme && !(stored.Scheme == "http" && target.Scheme == "https") {
+		return false
+	}
+	switch rule {
+	case RuleDomain, "":
+		return stored.Domain == target.Domain
+	case RuleHost:
+		return stored.Host == target.Host
+	case RuleExact:
+		return stored.URL == target.URL
+	default:
+		return false
+	}
+}
+
+// Specificity orders the rules from the loosest to the strictest, so that the best match can be chosen.
+func Specificity(rule string) int {
+	switch rule {
+	case RuleExact:
+		return 3
+	case RuleHost:
+		return 2
+	case RuleDomain, "":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Index is a blind index of a Key: keyed hashes of its parts.
+// Equal parts give equal hashes, but the parts cannot be recovered without the key.
+type Index struct {
+	Domain string
+	Host   string
+	URL    string
+}
+
+// IndexKey derives the blind index key from the server secret,
+// so that the secret itself is not used for two purposes.
+func IndexKey(secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("go-keeper url blind index"))
+	return mac.Sum(nil)
+}
+
+// NewIndex returns the blind index of the key.
+func NewIndex(indexKey []byte, key Key) Index {
+	return Index{
+		Domain: hash(indexKey, "domain", key.Domain),
+		Host:   hash(indexKey, "host", key.Host),
+		URL:    hash(indexKey, "url", key.URL),
+	}
+}
+
+func hash(indexKey []byte, part, value string) string {
+	mac := hmac.New(sha256.New, indexKey)
+	mac.Write([]byte(part))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
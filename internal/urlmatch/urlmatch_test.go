@@ -0,0 +1,94 @@
+package urlmatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		url  string
+		want Key
+	}{
+		{
+			url:  "https://Mail.Example.co.uk/inbox?x=1#top",
+			want: Key{Scheme: "https", Domain: "example.co.uk", Host: "mail.example.co.uk", URL: "https://mail.example.co.uk/inbox?x=1"},
+		},
+		{
+			url:  "https://github.com:443",
+			want: Key{Scheme: "https", Domain: "github.com", Host: "github.com", URL: "https://github.com/"},
+		},
+		{
+			url:  "http://localhost:8080/login",
+			want: Key{Scheme: "http", Domain: "localhost", Host: "localhost:8080", URL: "http://localhost:8080/login"},
+		},
+		{
+			url:  "https://192.168.1.1/",
+			want: Key{Scheme: "https", Domain: "192.168.1.1", Host: "192.168.1.1", URL: "https://192.168.1.1/"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, err := Parse(tt.url)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := Parse("github.com/login")
+	require.Error(t, err)
+}
+
+func TestMatches(t *testing.T) {
+	stored, err := Parse("https://accounts.example.com/login")
+	require.NoError(t, err)
+
+	sameDomain, err := Parse("https://mail.example.com/")
+	require.NoError(t, err)
+	sameHost, err := Parse("https://accounts.example.com/settings")
+	require.NoError(t, err)
+	sameURL, err := Parse("https://accounts.example.com/login#form")
+	require.NoError(t, err)
+
+	require.True(t, Matches(RuleDomain, stored, sameDomain))
+	require.False(t, Matches(RuleHost, stored, sameDomain))
+	require.True(t, Matches(RuleHost, stored, sameHost))
+	require.False(t, Matches(RuleExact, stored, sameHost))
+	require.True(t, Matches(RuleExact, stored, sameURL))
+	require.False(t, Matches(RuleNever, stored, sameURL))
+}
+
+func TestMatchesScheme(t *testing.T) {
+	secure, err := Parse("https://github.com")
+	require.NoError(t, err)
+	plain, err := Parse("http://github.com")
+	require.NoError(t, err)
+	ssh, err := Parse("ssh://github.com")
+	require.NoError(t, err)
+
+	// учётные данные https-сайта не отдаются по http
+	require.False(t, Matches(RuleDomain, secure, plain))
+	require.False(t, Matches(RuleHost, secure, plain))
+	require.False(t, Matches(RuleHost, secure, ssh))
+	// а данные http-сайта подходят и для https
+	require.True(t, Matches(RuleHost, plain, secure))
+	require.True(t, Matches(RuleHost, secure, secure))
+}
+
+func TestIndex(t *testing.T) {
+	a, err := Parse("https://mail.example.com/")
+	require.NoError(t, err)
+	b, err := Parse("https://www.example.com/")
+	require.NoError(t, err)
+
+	key := IndexKey([]byte("secret"))
+	ia, ib := NewIndex(key, a), NewIndex(key, b)
+
+	require.Equal(t, ia.Domain, ib.Domain)
+	require.NotEqual(t, ia.Host, ib.Host)
+	require.NotContains(t, ia.Domain, "example")
+
+	other := NewIndex(IndexKey([]byte("other")), a)
+	require.NotEqual(t, ia.Domain, other.Domain)
+}
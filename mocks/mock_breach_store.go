@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/adettelle/go-keeper/internal/server/api (interfaces: IBreachCorpus)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	breach "github.com/adettelle/go-keeper/internal/breach"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIBreachCorpus is a mock of IBreachCorpus interface.
+type MockIBreachCorpus struct {
+	ctrl     *gomock.Controller
+	recorder *MockIBreachCorpusMockRecorder
+}
+
+// MockIBreachCorpusMockRecorder is the mock recorder for MockIBreachCorpus.
+type MockIBreachCorpusMockRecorder struct {
+	mock *MockIBreachCorpus
+}
+
+// NewMockIBreachCorpus creates a new mock instance.
+func NewMockIBreachCorpus(ctrl *gomock.Controller) *MockIBreachCorpus {
+	mock := &MockIBreachCorpus{ctrl: ctrl}
+	mock.recorder = &MockIBreachCorpusMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIBreachCorpus) EXPECT() *MockIBreachCorpusMockRecorder {
+	return m.recorder
+}
+
+// Range mocks base method.
+func (m *MockIBreachCorpus) Range(arg0 string) ([]breach.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Range", arg0)
+	ret0, _ := ret[0].([]breach.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Range indicates an expected call of Range.
+func (mr *MockIBreachCorpusMockRecorder) Range(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Range", reflect.TypeOf((*MockIBreachCorpus)(nil).Range), arg0)
+}
@@ -36,17 +36,17 @@ func (m *MockICardRepo) EXPECT() *MockICardRepoMockRecorder {
 }
 
 // AddCard mocks base method.
-func (m *MockICardRepo) AddCard(arg0 context.Context, arg1, arg2, arg3, arg4, arg5, arg6 string) error {
+func (m *MockICardRepo) AddCard(arg0 context.Context, arg1 repo.Card, arg2 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddCard", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret := m.ctrl.Call(m, "AddCard", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddCard indicates an expected call of AddCard.
-func (mr *MockICardRepoMockRecorder) AddCard(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+func (mr *MockICardRepoMockRecorder) AddCard(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCard", reflect.TypeOf((*MockICardRepo)(nil).AddCard), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCard", reflect.TypeOf((*MockICardRepo)(nil).AddCard), arg0, arg1, arg2)
 }
 
 // DeleteCardByTitle mocks base method.
@@ -64,18 +64,19 @@ func (mr *MockICardRepoMockRecorder) DeleteCardByTitle(arg0, arg1, arg2 interfac
 }
 
 // GetAllCards mocks base method.
-func (m *MockICardRepo) GetAllCards(arg0 context.Context, arg1 string) ([]repo.CardToGet, error) {
+func (m *MockICardRepo) GetAllCards(arg0 context.Context, arg1 string, arg2 repo.ItemFilter, arg3 repo.Page) ([]repo.CardToGet, *repo.ItemCursor, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllCards", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetAllCards", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].([]repo.CardToGet)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(*repo.ItemCursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetAllCards indicates an expected call of GetAllCards.
-func (mr *MockICardRepoMockRecorder) GetAllCards(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockICardRepoMockRecorder) GetAllCards(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCards", reflect.TypeOf((*MockICardRepo)(nil).GetAllCards), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCards", reflect.TypeOf((*MockICardRepo)(nil).GetAllCards), arg0, arg1, arg2, arg3)
 }
 
 // GetCardByTitle mocks base method.
@@ -94,15 +95,15 @@ func (mr *MockICardRepoMockRecorder) GetCardByTitle(arg0, arg1, arg2 interface{}
 }
 
 // UpdateCard mocks base method.
-func (m *MockICardRepo) UpdateCard(arg0 context.Context, arg1 string, arg2, arg3, arg4, arg5 *string, arg6 int) error {
+func (m *MockICardRepo) UpdateCard(arg0 context.Context, arg1 string, arg2 repo.CardUpdate, arg3 int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateCard", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret := m.ctrl.Call(m, "UpdateCard", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateCard indicates an expected call of UpdateCard.
-func (mr *MockICardRepoMockRecorder) UpdateCard(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+func (mr *MockICardRepoMockRecorder) UpdateCard(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCard", reflect.TypeOf((*MockICardRepo)(nil).UpdateCard), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCard", reflect.TypeOf((*MockICardRepo)(nil).UpdateCard), arg0, arg1, arg2, arg3)
 }
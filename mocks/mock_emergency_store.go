@@ -0,0 +1,202 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/adettelle/go-keeper/internal/server/api (interfaces: IEmergencyRepo)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	repo "github.com/adettelle/go-keeper/internal/repo"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIEmergencyRepo is a mock of IEmergencyRepo interface.
+type MockIEmergencyRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIEmergencyRepoMockRecorder
+}
+
+// MockIEmergencyRepoMockRecorder is the mock recorder for MockIEmergencyRepo.
+type MockIEmergencyRepoMockRecorder struct {
+	mock *MockIEmergencyRepo
+}
+
+// NewMockIEmergencyRepo creates a new mock instance.
+func NewMockIEmergencyRepo(ctrl *gomock.Controller) *MockIEmergencyRepo {
+	mock := &MockIEmergencyRepo{ctrl: ctrl}
+	mock.recorder = &MockIEmergencyRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIEmergencyRepo) EXPECT() *MockIEmergencyRepoMockRecorder {
+	return m.recorder
+}
+
+// DeleteContact mocks base method.
+func (m *MockIEmergencyRepo) DeleteContact(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteContact", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteContact indicates an expected call of DeleteContact.
+func (mr *MockIEmergencyRepoMockRecorder) DeleteContact(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteContact", reflect.TypeOf((*MockIEmergencyRepo)(nil).DeleteContact), arg0, arg1, arg2)
+}
+
+// GetContact mocks base method.
+func (m *MockIEmergencyRepo) GetContact(arg0 context.Context, arg1, arg2 string) (*repo.TrustedContact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContact", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*repo.TrustedContact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContact indicates an expected call of GetContact.
+func (mr *MockIEmergencyRepoMockRecorder) GetContact(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContact", reflect.TypeOf((*MockIEmergencyRepo)(nil).GetContact), arg0, arg1, arg2)
+}
+
+// GetContacts mocks base method.
+func (m *MockIEmergencyRepo) GetContacts(arg0 context.Context, arg1 string) ([]repo.TrustedContact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContacts", arg0, arg1)
+	ret0, _ := ret[0].([]repo.TrustedContact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContacts indicates an expected call of GetContacts.
+func (mr *MockIEmergencyRepoMockRecorder) GetContacts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContacts", reflect.TypeOf((*MockIEmergencyRepo)(nil).GetContacts), arg0, arg1)
+}
+
+// GetEvents mocks base method.
+func (m *MockIEmergencyRepo) GetEvents(arg0 context.Context, arg1 string, arg2 int) ([]repo.EmergencyEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvents", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]repo.EmergencyEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvents indicates an expected call of GetEvents.
+func (mr *MockIEmergencyRepoMockRecorder) GetEvents(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockIEmergencyRepo)(nil).GetEvents), arg0, arg1, arg2)
+}
+
+// GetGrantors mocks base method.
+func (m *MockIEmergencyRepo) GetGrantors(arg0 context.Context, arg1 string) ([]repo.TrustedContact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGrantors", arg0, arg1)
+	ret0, _ := ret[0].([]repo.TrustedContact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGrantors indicates an expected call of GetGrantors.
+func (mr *MockIEmergencyRepoMockRecorder) GetGrantors(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGrantors", reflect.TypeOf((*MockIEmergencyRepo)(nil).GetGrantors), arg0, arg1)
+}
+
+// GrantDue mocks base method.
+func (m *MockIEmergencyRepo) GrantDue(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantDue", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GrantDue indicates an expected call of GrantDue.
+func (mr *MockIEmergencyRepoMockRecorder) GrantDue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantDue", reflect.TypeOf((*MockIEmergencyRepo)(nil).GrantDue), arg0, arg1)
+}
+
+// PutContact mocks base method.
+func (m *MockIEmergencyRepo) PutContact(arg0 context.Context, arg1, arg2 string, arg3 time.Duration, arg4 repo.EmergencyKit) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutContact", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutContact indicates an expected call of PutContact.
+func (mr *MockIEmergencyRepoMockRecorder) PutContact(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutContact", reflect.TypeOf((*MockIEmergencyRepo)(nil).PutContact), arg0, arg1, arg2, arg3, arg4)
+}
+
+// RejectAccess mocks base method.
+func (m *MockIEmergencyRepo) RejectAccess(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectAccess", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RejectAccess indicates an expected call of RejectAccess.
+func (mr *MockIEmergencyRepoMockRecorder) RejectAccess(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectAccess", reflect.TypeOf((*MockIEmergencyRepo)(nil).RejectAccess), arg0, arg1, arg2)
+}
+
+// RequestAccess mocks base method.
+func (m *MockIEmergencyRepo) RequestAccess(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestAccess", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestAccess indicates an expected call of RequestAccess.
+func (mr *MockIEmergencyRepoMockRecorder) RequestAccess(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestAccess", reflect.TypeOf((*MockIEmergencyRepo)(nil).RequestAccess), arg0, arg1, arg2)
+}
+
+// TakeKit mocks base method.
+func (m *MockIEmergencyRepo) TakeKit(arg0 context.Context, arg1, arg2 string) (*repo.EmergencyKit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeKit", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*repo.EmergencyKit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeKit indicates an expected call of TakeKit.
+func (mr *MockIEmergencyRepoMockRecorder) TakeKit(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeKit", reflect.TypeOf((*MockIEmergencyRepo)(nil).TakeKit), arg0, arg1, arg2)
+}
+
+// TakeNotifications mocks base method.
+func (m *MockIEmergencyRepo) TakeNotifications(arg0 context.Context, arg1 string) ([]repo.EmergencyEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeNotifications", arg0, arg1)
+	ret0, _ := ret[0].([]repo.EmergencyEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeNotifications indicates an expected call of TakeNotifications.
+func (mr *MockIEmergencyRepoMockRecorder) TakeNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeNotifications", reflect.TypeOf((*MockIEmergencyRepo)(nil).TakeNotifications), arg0, arg1)
+}
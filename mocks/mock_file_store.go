@@ -79,18 +79,19 @@ func (mr *MockIFileRepoMockRecorder) FileExists(arg0, arg1, arg2 interface{}) *g
 }
 
 // GetAllFiles mocks base method.
-func (m *MockIFileRepo) GetAllFiles(arg0 context.Context, arg1 string) ([]repo.FileToGet, error) {
+func (m *MockIFileRepo) GetAllFiles(arg0 context.Context, arg1 string, arg2 repo.ItemFilter, arg3 repo.Page) ([]repo.FileToGet, *repo.ItemCursor, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllFiles", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetAllFiles", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].([]repo.FileToGet)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(*repo.ItemCursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetAllFiles indicates an expected call of GetAllFiles.
-func (mr *MockIFileRepoMockRecorder) GetAllFiles(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockIFileRepoMockRecorder) GetAllFiles(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllFiles", reflect.TypeOf((*MockIFileRepo)(nil).GetAllFiles), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllFiles", reflect.TypeOf((*MockIFileRepo)(nil).GetAllFiles), arg0, arg1, arg2, arg3)
 }
 
 // GetFileCoudIDByTitle mocks base method.
@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/adettelle/go-keeper/internal/server/api (interfaces: IIdentityRepo)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	repo "github.com/adettelle/go-keeper/internal/repo"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIIdentityRepo is a mock of IIdentityRepo interface.
+type MockIIdentityRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIIdentityRepoMockRecorder
+}
+
+// MockIIdentityRepoMockRecorder is the mock recorder for MockIIdentityRepo.
+type MockIIdentityRepoMockRecorder struct {
+	mock *MockIIdentityRepo
+}
+
+// NewMockIIdentityRepo creates a new mock instance.
+func NewMockIIdentityRepo(ctrl *gomock.Controller) *MockIIdentityRepo {
+	mock := &MockIIdentityRepo{ctrl: ctrl}
+	mock.recorder = &MockIIdentityRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIIdentityRepo) EXPECT() *MockIIdentityRepoMockRecorder {
+	return m.recorder
+}
+
+// AddIdentity mocks base method.
+func (m *MockIIdentityRepo) AddIdentity(arg0 context.Context, arg1 repo.Identity, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddIdentity", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddIdentity indicates an expected call of AddIdentity.
+func (mr *MockIIdentityRepoMockRecorder) AddIdentity(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddIdentity", reflect.TypeOf((*MockIIdentityRepo)(nil).AddIdentity), arg0, arg1, arg2)
+}
+
+// DeleteIdentityByTitle mocks base method.
+func (m *MockIIdentityRepo) DeleteIdentityByTitle(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdentityByTitle", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteIdentityByTitle indicates an expected call of DeleteIdentityByTitle.
+func (mr *MockIIdentityRepoMockRecorder) DeleteIdentityByTitle(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdentityByTitle", reflect.TypeOf((*MockIIdentityRepo)(nil).DeleteIdentityByTitle), arg0, arg1, arg2)
+}
+
+// GetAllIdentities mocks base method.
+func (m *MockIIdentityRepo) GetAllIdentities(arg0 context.Context, arg1 string, arg2 repo.ItemFilter, arg3 repo.Page) ([]repo.IdentityToGet, *repo.ItemCursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllIdentities", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]repo.IdentityToGet)
+	ret1, _ := ret[1].(*repo.ItemCursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllIdentities indicates an expected call of GetAllIdentities.
+func (mr *MockIIdentityRepoMockRecorder) GetAllIdentities(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllIdentities", reflect.TypeOf((*MockIIdentityRepo)(nil).GetAllIdentities), arg0, arg1, arg2, arg3)
+}
+
+// GetIdentityByTitle mocks base method.
+func (m *MockIIdentityRepo) GetIdentityByTitle(arg0 context.Context, arg1, arg2 string) (*repo.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdentityByTitle", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*repo.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdentityByTitle indicates an expected call of GetIdentityByTitle.
+func (mr *MockIIdentityRepoMockRecorder) GetIdentityByTitle(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdentityByTitle", reflect.TypeOf((*MockIIdentityRepo)(nil).GetIdentityByTitle), arg0, arg1, arg2)
+}
+
+// UpdateIdentity mocks base method.
+func (m *MockIIdentityRepo) UpdateIdentity(arg0 context.Context, arg1 string, arg2 repo.IdentityUpdate, arg3 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIdentity", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateIdentity indicates an expected call of UpdateIdentity.
+func (mr *MockIIdentityRepoMockRecorder) UpdateIdentity(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIdentity", reflect.TypeOf((*MockIIdentityRepo)(nil).UpdateIdentity), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/adettelle/go-keeper/internal/server/api (interfaces: IItemRepo)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	repo "github.com/adettelle/go-keeper/internal/repo"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIItemRepo is a mock of IItemRepo interface.
+type MockIItemRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIItemRepoMockRecorder
+}
+
+// MockIItemRepoMockRecorder is the mock recorder for MockIItemRepo.
+type MockIItemRepoMockRecorder struct {
+	mock *MockIItemRepo
+}
+
+// NewMockIItemRepo creates a new mock instance.
+func NewMockIItemRepo(ctrl *gomock.Controller) *MockIItemRepo {
+	mock := &MockIItemRepo{ctrl: ctrl}
+	mock.recorder = &MockIItemRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIItemRepo) EXPECT() *MockIItemRepoMockRecorder {
+	return m.recorder
+}
+
+// GetAllFolders mocks base method.
+func (m *MockIItemRepo) GetAllFolders(arg0 context.Context, arg1 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllFolders", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllFolders indicates an expected call of GetAllFolders.
+func (mr *MockIItemRepoMockRecorder) GetAllFolders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllFolders", reflect.TypeOf((*MockIItemRepo)(nil).GetAllFolders), arg0, arg1)
+}
+
+// SearchItems mocks base method.
+func (m *MockIItemRepo) SearchItems(arg0 context.Context, arg1 string, arg2 repo.ItemFilter, arg3 repo.Page) ([]repo.Item, *repo.ItemCursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchItems", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]repo.Item)
+	ret1, _ := ret[1].(*repo.ItemCursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchItems indicates an expected call of SearchItems.
+func (mr *MockIItemRepoMockRecorder) SearchItems(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchItems", reflect.TypeOf((*MockIItemRepo)(nil).SearchItems), arg0, arg1, arg2, arg3)
+}
+
+// UpdateItemMeta mocks base method.
+func (m *MockIItemRepo) UpdateItemMeta(arg0 context.Context, arg1 repo.ItemKind, arg2 string, arg3 repo.ItemMetaUpdate, arg4 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemMeta", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItemMeta indicates an expected call of UpdateItemMeta.
+func (mr *MockIItemRepoMockRecorder) UpdateItemMeta(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemMeta", reflect.TypeOf((*MockIItemRepo)(nil).UpdateItemMeta), arg0, arg1, arg2, arg3, arg4)
+}
@@ -0,0 +1,105 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/adettelle/go-keeper/internal/server/api (interfaces: ILinkRepo,ILinkFileRepo)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	repo "github.com/adettelle/go-keeper/internal/repo"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockILinkRepo is a mock of ILinkRepo interface.
+type MockILinkRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockILinkRepoMockRecorder
+}
+
+// MockILinkRepoMockRecorder is the mock recorder for MockILinkRepo.
+type MockILinkRepoMockRecorder struct {
+	mock *MockILinkRepo
+}
+
+// NewMockILinkRepo creates a new mock instance.
+func NewMockILinkRepo(ctrl *gomock.Controller) *MockILinkRepo {
+	mock := &MockILinkRepo{ctrl: ctrl}
+	mock.recorder = &MockILinkRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILinkRepo) EXPECT() *MockILinkRepoMockRecorder {
+	return m.recorder
+}
+
+// AddLink mocks base method.
+func (m *MockILinkRepo) AddLink(arg0 context.Context, arg1, arg2, arg3 string, arg4 int, arg5 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLink", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddLink indicates an expected call of AddLink.
+func (mr *MockILinkRepoMockRecorder) AddLink(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLink", reflect.TypeOf((*MockILinkRepo)(nil).AddLink), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// TakeLink mocks base method.
+func (m *MockILinkRepo) TakeLink(arg0 context.Context, arg1 string) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeLink", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TakeLink indicates an expected call of TakeLink.
+func (mr *MockILinkRepoMockRecorder) TakeLink(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeLink", reflect.TypeOf((*MockILinkRepo)(nil).TakeLink), arg0, arg1)
+}
+
+// MockILinkFileRepo is a mock of ILinkFileRepo interface.
+type MockILinkFileRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockILinkFileRepoMockRecorder
+}
+
+// MockILinkFileRepoMockRecorder is the mock recorder for MockILinkFileRepo.
+type MockILinkFileRepoMockRecorder struct {
+	mock *MockILinkFileRepo
+}
+
+// NewMockILinkFileRepo creates a new mock instance.
+func NewMockILinkFileRepo(ctrl *gomock.Controller) *MockILinkFileRepo {
+	mock := &MockILinkFileRepo{ctrl: ctrl}
+	mock.recorder = &MockILinkFileRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILinkFileRepo) EXPECT() *MockILinkFileRepoMockRecorder {
+	return m.recorder
+}
+
+// GetFileObject mocks base method.
+func (m *MockILinkFileRepo) GetFileObject(arg0 context.Context, arg1, arg2 string) (*repo.FileObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileObject", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*repo.FileObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileObject indicates an expected call of GetFileObject.
+func (mr *MockILinkFileRepoMockRecorder) GetFileObject(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileObject", reflect.TypeOf((*MockILinkFileRepo)(nil).GetFileObject), arg0, arg1, arg2)
+}
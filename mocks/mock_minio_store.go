@@ -7,6 +7,7 @@ package mocks
 import (
 	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -49,6 +50,22 @@ func (mr *MockIMinioServiceMockRecorder) GetObject(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObject", reflect.TypeOf((*MockIMinioService)(nil).GetObject), arg0)
 }
 
+// PresignedGetObject mocks base method.
+func (m *MockIMinioService) PresignedGetObject(arg0, arg1 string) (string, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PresignedGetObject", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PresignedGetObject indicates an expected call of PresignedGetObject.
+func (mr *MockIMinioServiceMockRecorder) PresignedGetObject(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PresignedGetObject", reflect.TypeOf((*MockIMinioService)(nil).PresignedGetObject), arg0, arg1)
+}
+
 // Upload mocks base method.
 func (m *MockIMinioService) Upload(arg0 string, arg1 io.Reader) error {
 	m.ctrl.T.Helper()
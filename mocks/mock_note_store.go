@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/adettelle/go-keeper/internal/server/api (interfaces: INoteRepo)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	repo "github.com/adettelle/go-keeper/internal/repo"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockINoteRepo is a mock of INoteRepo interface.
+type MockINoteRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockINoteRepoMockRecorder
+}
+
+// MockINoteRepoMockRecorder is the mock recorder for MockINoteRepo.
+type MockINoteRepoMockRecorder struct {
+	mock *MockINoteRepo
+}
+
+// NewMockINoteRepo creates a new mock instance.
+func NewMockINoteRepo(ctrl *gomock.Controller) *MockINoteRepo {
+	mock := &MockINoteRepo{ctrl: ctrl}
+	mock.recorder = &MockINoteRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockINoteRepo) EXPECT() *MockINoteRepoMockRecorder {
+	return m.recorder
+}
+
+// AddNote mocks base method.
+func (m *MockINoteRepo) AddNote(arg0 context.Context, arg1 repo.Note, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNote", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddNote indicates an expected call of AddNote.
+func (mr *MockINoteRepoMockRecorder) AddNote(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNote", reflect.TypeOf((*MockINoteRepo)(nil).AddNote), arg0, arg1, arg2)
+}
+
+// DeleteNoteByTitle mocks base method.
+func (m *MockINoteRepo) DeleteNoteByTitle(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNoteByTitle", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteNoteByTitle indicates an expected call of DeleteNoteByTitle.
+func (mr *MockINoteRepoMockRecorder) DeleteNoteByTitle(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNoteByTitle", reflect.TypeOf((*MockINoteRepo)(nil).DeleteNoteByTitle), arg0, arg1, arg2)
+}
+
+// GetAllNotes mocks base method.
+func (m *MockINoteRepo) GetAllNotes(arg0 context.Context, arg1 string, arg2 repo.ItemFilter, arg3 repo.Page) ([]repo.NoteToGet, *repo.ItemCursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllNotes", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]repo.NoteToGet)
+	ret1, _ := ret[1].(*repo.ItemCursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllNotes indicates an expected call of GetAllNotes.
+func (mr *MockINoteRepoMockRecorder) GetAllNotes(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllNotes", reflect.TypeOf((*MockINoteRepo)(nil).GetAllNotes), arg0, arg1, arg2, arg3)
+}
+
+// GetNoteByTitle mocks base method.
+func (m *MockINoteRepo) GetNoteByTitle(arg0 context.Context, arg1, arg2 string) (*repo.Note, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNoteByTitle", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*repo.Note)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNoteByTitle indicates an expected call of GetNoteByTitle.
+func (mr *MockINoteRepoMockRecorder) GetNoteByTitle(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNoteByTitle", reflect.TypeOf((*MockINoteRepo)(nil).GetNoteByTitle), arg0, arg1, arg2)
+}
+
+// UpdateNote mocks base method.
+func (m *MockINoteRepo) UpdateNote(arg0 context.Context, arg1 string, arg2 repo.NoteUpdate, arg3 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNote", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNote indicates an expected call of UpdateNote.
+func (mr *MockINoteRepoMockRecorder) UpdateNote(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNote", reflect.TypeOf((*MockINoteRepo)(nil).UpdateNote), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,243 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/adettelle/go-keeper/internal/server/api (interfaces: IOrgRepo)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	access "github.com/adettelle/go-keeper/internal/access"
+	repo "github.com/adettelle/go-keeper/internal/repo"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIOrgRepo is a mock of IOrgRepo interface.
+type MockIOrgRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOrgRepoMockRecorder
+}
+
+// MockIOrgRepoMockRecorder is the mock recorder for MockIOrgRepo.
+type MockIOrgRepoMockRecorder struct {
+	mock *MockIOrgRepo
+}
+
+// NewMockIOrgRepo creates a new mock instance.
+func NewMockIOrgRepo(ctrl *gomock.Controller) *MockIOrgRepo {
+	mock := &MockIOrgRepo{ctrl: ctrl}
+	mock.recorder = &MockIOrgRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOrgRepo) EXPECT() *MockIOrgRepoMockRecorder {
+	return m.recorder
+}
+
+// CollectionExists mocks base method.
+func (m *MockIOrgRepo) CollectionExists(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectionExists", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectionExists indicates an expected call of CollectionExists.
+func (mr *MockIOrgRepoMockRecorder) CollectionExists(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectionExists", reflect.TypeOf((*MockIOrgRepo)(nil).CollectionExists), arg0, arg1, arg2)
+}
+
+// CountOwners mocks base method.
+func (m *MockIOrgRepo) CountOwners(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOwners", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOwners indicates an expected call of CountOwners.
+func (mr *MockIOrgRepoMockRecorder) CountOwners(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOwners", reflect.TypeOf((*MockIOrgRepo)(nil).CountOwners), arg0, arg1)
+}
+
+// CreateCollection mocks base method.
+func (m *MockIOrgRepo) CreateCollection(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollection", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCollection indicates an expected call of CreateCollection.
+func (mr *MockIOrgRepoMockRecorder) CreateCollection(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollection", reflect.TypeOf((*MockIOrgRepo)(nil).CreateCollection), arg0, arg1, arg2)
+}
+
+// CreateOrg mocks base method.
+func (m *MockIOrgRepo) CreateOrg(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrg", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrg indicates an expected call of CreateOrg.
+func (mr *MockIOrgRepoMockRecorder) CreateOrg(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrg", reflect.TypeOf((*MockIOrgRepo)(nil).CreateOrg), arg0, arg1, arg2)
+}
+
+// DeleteCollection mocks base method.
+func (m *MockIOrgRepo) DeleteCollection(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockIOrgRepoMockRecorder) DeleteCollection(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockIOrgRepo)(nil).DeleteCollection), arg0, arg1, arg2)
+}
+
+// DeleteMember mocks base method.
+func (m *MockIOrgRepo) DeleteMember(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMember indicates an expected call of DeleteMember.
+func (mr *MockIOrgRepoMockRecorder) DeleteMember(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMember", reflect.TypeOf((*MockIOrgRepo)(nil).DeleteMember), arg0, arg1, arg2)
+}
+
+// DeleteOrg mocks base method.
+func (m *MockIOrgRepo) DeleteOrg(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrg", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOrg indicates an expected call of DeleteOrg.
+func (mr *MockIOrgRepoMockRecorder) DeleteOrg(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrg", reflect.TypeOf((*MockIOrgRepo)(nil).DeleteOrg), arg0, arg1)
+}
+
+// GetCollectionAccess mocks base method.
+func (m *MockIOrgRepo) GetCollectionAccess(arg0 context.Context, arg1, arg2, arg3 string) (*repo.CollectionAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollectionAccess", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*repo.CollectionAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollectionAccess indicates an expected call of GetCollectionAccess.
+func (mr *MockIOrgRepoMockRecorder) GetCollectionAccess(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollectionAccess", reflect.TypeOf((*MockIOrgRepo)(nil).GetCollectionAccess), arg0, arg1, arg2, arg3)
+}
+
+// GetCollections mocks base method.
+func (m *MockIOrgRepo) GetCollections(arg0 context.Context, arg1 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollections", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollections indicates an expected call of GetCollections.
+func (mr *MockIOrgRepoMockRecorder) GetCollections(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollections", reflect.TypeOf((*MockIOrgRepo)(nil).GetCollections), arg0, arg1)
+}
+
+// GetMembers mocks base method.
+func (m *MockIOrgRepo) GetMembers(arg0 context.Context, arg1 string) ([]repo.OrgMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembers", arg0, arg1)
+	ret0, _ := ret[0].([]repo.OrgMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembers indicates an expected call of GetMembers.
+func (mr *MockIOrgRepoMockRecorder) GetMembers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembers", reflect.TypeOf((*MockIOrgRepo)(nil).GetMembers), arg0, arg1)
+}
+
+// GetOrgs mocks base method.
+func (m *MockIOrgRepo) GetOrgs(arg0 context.Context, arg1 string) ([]repo.OrgMembership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrgs", arg0, arg1)
+	ret0, _ := ret[0].([]repo.OrgMembership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrgs indicates an expected call of GetOrgs.
+func (mr *MockIOrgRepoMockRecorder) GetOrgs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrgs", reflect.TypeOf((*MockIOrgRepo)(nil).GetOrgs), arg0, arg1)
+}
+
+// GetRole mocks base method.
+func (m *MockIOrgRepo) GetRole(arg0 context.Context, arg1, arg2 string) (access.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRole", arg0, arg1, arg2)
+	ret0, _ := ret[0].(access.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRole indicates an expected call of GetRole.
+func (mr *MockIOrgRepoMockRecorder) GetRole(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRole", reflect.TypeOf((*MockIOrgRepo)(nil).GetRole), arg0, arg1, arg2)
+}
+
+// OrgExists mocks base method.
+func (m *MockIOrgRepo) OrgExists(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrgExists", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OrgExists indicates an expected call of OrgExists.
+func (mr *MockIOrgRepoMockRecorder) OrgExists(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrgExists", reflect.TypeOf((*MockIOrgRepo)(nil).OrgExists), arg0, arg1)
+}
+
+// SetMember mocks base method.
+func (m *MockIOrgRepo) SetMember(arg0 context.Context, arg1, arg2 string, arg3 access.Role) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMember", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetMember indicates an expected call of SetMember.
+func (mr *MockIOrgRepoMockRecorder) SetMember(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMember", reflect.TypeOf((*MockIOrgRepo)(nil).SetMember), arg0, arg1, arg2, arg3)
+}
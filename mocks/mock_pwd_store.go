@@ -36,17 +36,17 @@ func (m *MockIPwdRepo) EXPECT() *MockIPwdRepoMockRecorder {
 }
 
 // CreatePassword mocks base method.
-func (m *MockIPwdRepo) CreatePassword(arg0 context.Context, arg1, arg2, arg3, arg4 string) error {
+func (m *MockIPwdRepo) CreatePassword(arg0 context.Context, arg1, arg2, arg3 string, arg4 repo.PasswordDetails, arg5 repo.ItemMeta, arg6 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreatePassword", arg0, arg1, arg2, arg3, arg4)
+	ret := m.ctrl.Call(m, "CreatePassword", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreatePassword indicates an expected call of CreatePassword.
-func (mr *MockIPwdRepoMockRecorder) CreatePassword(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+func (mr *MockIPwdRepoMockRecorder) CreatePassword(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePassword", reflect.TypeOf((*MockIPwdRepo)(nil).CreatePassword), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePassword", reflect.TypeOf((*MockIPwdRepo)(nil).CreatePassword), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 }
 
 // DeletePassword mocks base method.
@@ -64,18 +64,19 @@ func (mr *MockIPwdRepoMockRecorder) DeletePassword(arg0, arg1, arg2 interface{})
 }
 
 // GetAllPasswords mocks base method.
-func (m *MockIPwdRepo) GetAllPasswords(arg0 context.Context, arg1 string) ([]repo.Password, error) {
+func (m *MockIPwdRepo) GetAllPasswords(arg0 context.Context, arg1 string, arg2 repo.ItemFilter, arg3 repo.Page) ([]repo.Password, *repo.ItemCursor, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllPasswords", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetAllPasswords", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].([]repo.Password)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(*repo.ItemCursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetAllPasswords indicates an expected call of GetAllPasswords.
-func (mr *MockIPwdRepoMockRecorder) GetAllPasswords(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockIPwdRepoMockRecorder) GetAllPasswords(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllPasswords", reflect.TypeOf((*MockIPwdRepo)(nil).GetAllPasswords), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllPasswords", reflect.TypeOf((*MockIPwdRepo)(nil).GetAllPasswords), arg0, arg1, arg2, arg3)
 }
 
 // GetPasswordByTitle mocks base method.
@@ -93,16 +94,47 @@ func (mr *MockIPwdRepoMockRecorder) GetPasswordByTitle(arg0, arg1, arg2 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordByTitle", reflect.TypeOf((*MockIPwdRepo)(nil).GetPasswordByTitle), arg0, arg1, arg2)
 }
 
+// GetPasswordDetails mocks base method.
+func (m *MockIPwdRepo) GetPasswordDetails(arg0 context.Context, arg1, arg2 string) (*repo.PasswordEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasswordDetails", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*repo.PasswordEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordDetails indicates an expected call of GetPasswordDetails.
+func (mr *MockIPwdRepoMockRecorder) GetPasswordDetails(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordDetails", reflect.TypeOf((*MockIPwdRepo)(nil).GetPasswordDetails), arg0, arg1, arg2)
+}
+
+// MatchPasswords mocks base method.
+func (m *MockIPwdRepo) MatchPasswords(arg0 context.Context, arg1 string, arg2 repo.URLIndex) ([]repo.PasswordEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchPasswords", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]repo.PasswordEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchPasswords indicates an expected call of MatchPasswords.
+func (mr *MockIPwdRepoMockRecorder) MatchPasswords(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchPasswords", reflect.TypeOf((*MockIPwdRepo)(nil).MatchPasswords), arg0, arg1, arg2)
+}
+
 // UpdatePassword mocks base method.
-func (m *MockIPwdRepo) UpdatePassword(arg0 context.Context, arg1 string, arg2, arg3 *string, arg4 int) error {
+func (m *MockIPwdRepo) UpdatePassword(arg0 context.Context, arg1 string, arg2, arg3 *string, arg4 repo.PasswordDetailsUpdate, arg5 int) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdatePassword", arg0, arg1, arg2, arg3, arg4)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UpdatePassword", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UpdatePassword indicates an expected call of UpdatePassword.
-func (mr *MockIPwdRepoMockRecorder) UpdatePassword(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+func (mr *MockIPwdRepoMockRecorder) UpdatePassword(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockIPwdRepo)(nil).UpdatePassword), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockIPwdRepo)(nil).UpdatePassword), arg0, arg1, arg2, arg3, arg4, arg5)
 }
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/adettelle/go-keeper/internal/server/api (interfaces: IReminderRepo)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	repo "github.com/adettelle/go-keeper/internal/repo"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIReminderRepo is a mock of IReminderRepo interface.
+type MockIReminderRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReminderRepoMockRecorder
+}
+
+// MockIReminderRepoMockRecorder is the mock recorder for MockIReminderRepo.
+type MockIReminderRepoMockRecorder struct {
+	mock *MockIReminderRepo
+}
+
+// NewMockIReminderRepo creates a new mock instance.
+func NewMockIReminderRepo(ctrl *gomock.Controller) *MockIReminderRepo {
+	mock := &MockIReminderRepo{ctrl: ctrl}
+	mock.recorder = &MockIReminderRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReminderRepo) EXPECT() *MockIReminderRepoMockRecorder {
+	return m.recorder
+}
+
+// GetCardExpiries mocks base method.
+func (m *MockIReminderRepo) GetCardExpiries(arg0 context.Context, arg1 string) ([]repo.CardExpiry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardExpiries", arg0, arg1)
+	ret0, _ := ret[0].([]repo.CardExpiry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardExpiries indicates an expected call of GetCardExpiries.
+func (mr *MockIReminderRepoMockRecorder) GetCardExpiries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardExpiries", reflect.TypeOf((*MockIReminderRepo)(nil).GetCardExpiries), arg0, arg1)
+}
+
+// GetPasswordsDue mocks base method.
+func (m *MockIReminderRepo) GetPasswordsDue(arg0 context.Context, arg1 string, arg2 time.Time) ([]repo.PasswordRotation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasswordsDue", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]repo.PasswordRotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordsDue indicates an expected call of GetPasswordsDue.
+func (mr *MockIReminderRepoMockRecorder) GetPasswordsDue(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordsDue", reflect.TypeOf((*MockIReminderRepo)(nil).GetPasswordsDue), arg0, arg1, arg2)
+}
@@ -0,0 +1,153 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/adettelle/go-keeper/internal/server/api (interfaces: IShareRepo)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	repo "github.com/adettelle/go-keeper/internal/repo"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIShareRepo is a mock of IShareRepo interface.
+type MockIShareRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIShareRepoMockRecorder
+}
+
+// MockIShareRepoMockRecorder is the mock recorder for MockIShareRepo.
+type MockIShareRepoMockRecorder struct {
+	mock *MockIShareRepo
+}
+
+// NewMockIShareRepo creates a new mock instance.
+func NewMockIShareRepo(ctrl *gomock.Controller) *MockIShareRepo {
+	mock := &MockIShareRepo{ctrl: ctrl}
+	mock.recorder = &MockIShareRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIShareRepo) EXPECT() *MockIShareRepoMockRecorder {
+	return m.recorder
+}
+
+// AddKeys mocks base method.
+func (m *MockIShareRepo) AddKeys(arg0 context.Context, arg1 string, arg2 repo.UserKeys) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddKeys", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddKeys indicates an expected call of AddKeys.
+func (mr *MockIShareRepoMockRecorder) AddKeys(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddKeys", reflect.TypeOf((*MockIShareRepo)(nil).AddKeys), arg0, arg1, arg2)
+}
+
+// DeleteShare mocks base method.
+func (m *MockIShareRepo) DeleteShare(arg0 context.Context, arg1 string, arg2 repo.ItemKind, arg3 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteShare", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteShare indicates an expected call of DeleteShare.
+func (mr *MockIShareRepoMockRecorder) DeleteShare(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShare", reflect.TypeOf((*MockIShareRepo)(nil).DeleteShare), arg0, arg1, arg2, arg3)
+}
+
+// GetKeys mocks base method.
+func (m *MockIShareRepo) GetKeys(arg0 context.Context, arg1 string) (*repo.UserKeys, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeys", arg0, arg1)
+	ret0, _ := ret[0].(*repo.UserKeys)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeys indicates an expected call of GetKeys.
+func (mr *MockIShareRepoMockRecorder) GetKeys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeys", reflect.TypeOf((*MockIShareRepo)(nil).GetKeys), arg0, arg1)
+}
+
+// GetSharedItem mocks base method.
+func (m *MockIShareRepo) GetSharedItem(arg0 context.Context, arg1, arg2 string, arg3 repo.ItemKind, arg4 string) (*repo.SharedPayload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedItem", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*repo.SharedPayload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedItem indicates an expected call of GetSharedItem.
+func (mr *MockIShareRepoMockRecorder) GetSharedItem(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedItem", reflect.TypeOf((*MockIShareRepo)(nil).GetSharedItem), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetSharedWithMe mocks base method.
+func (m *MockIShareRepo) GetSharedWithMe(arg0 context.Context, arg1 string) ([]repo.SharedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedWithMe", arg0, arg1)
+	ret0, _ := ret[0].([]repo.SharedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedWithMe indicates an expected call of GetSharedWithMe.
+func (mr *MockIShareRepoMockRecorder) GetSharedWithMe(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedWithMe", reflect.TypeOf((*MockIShareRepo)(nil).GetSharedWithMe), arg0, arg1)
+}
+
+// GetShares mocks base method.
+func (m *MockIShareRepo) GetShares(arg0 context.Context, arg1 string) ([]repo.OwnedShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShares", arg0, arg1)
+	ret0, _ := ret[0].([]repo.OwnedShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShares indicates an expected call of GetShares.
+func (mr *MockIShareRepoMockRecorder) GetShares(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShares", reflect.TypeOf((*MockIShareRepo)(nil).GetShares), arg0, arg1)
+}
+
+// PutShare mocks base method.
+func (m *MockIShareRepo) PutShare(arg0 context.Context, arg1 string, arg2 repo.ItemKind, arg3, arg4 string, arg5 []repo.ShareRecipient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutShare", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutShare indicates an expected call of PutShare.
+func (mr *MockIShareRepoMockRecorder) PutShare(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutShare", reflect.TypeOf((*MockIShareRepo)(nil).PutShare), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// UpdatePrivateKey mocks base method.
+func (m *MockIShareRepo) UpdatePrivateKey(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePrivateKey", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePrivateKey indicates an expected call of UpdatePrivateKey.
+func (mr *MockIShareRepoMockRecorder) UpdatePrivateKey(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePrivateKey", reflect.TypeOf((*MockIShareRepo)(nil).UpdatePrivateKey), arg0, arg1, arg2)
+}
@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/adettelle/go-keeper/internal/server/api (interfaces: ISSHKeyRepo)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	repo "github.com/adettelle/go-keeper/internal/repo"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockISSHKeyRepo is a mock of ISSHKeyRepo interface.
+type MockISSHKeyRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockISSHKeyRepoMockRecorder
+}
+
+// MockISSHKeyRepoMockRecorder is the mock recorder for MockISSHKeyRepo.
+type MockISSHKeyRepoMockRecorder struct {
+	mock *MockISSHKeyRepo
+}
+
+// NewMockISSHKeyRepo creates a new mock instance.
+func NewMockISSHKeyRepo(ctrl *gomock.Controller) *MockISSHKeyRepo {
+	mock := &MockISSHKeyRepo{ctrl: ctrl}
+	mock.recorder = &MockISSHKeyRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISSHKeyRepo) EXPECT() *MockISSHKeyRepoMockRecorder {
+	return m.recorder
+}
+
+// AddSSHKey mocks base method.
+func (m *MockISSHKeyRepo) AddSSHKey(arg0 context.Context, arg1 repo.SSHKey, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSSHKey", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddSSHKey indicates an expected call of AddSSHKey.
+func (mr *MockISSHKeyRepoMockRecorder) AddSSHKey(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSSHKey", reflect.TypeOf((*MockISSHKeyRepo)(nil).AddSSHKey), arg0, arg1, arg2)
+}
+
+// DeleteSSHKeyByTitle mocks base method.
+func (m *MockISSHKeyRepo) DeleteSSHKeyByTitle(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSSHKeyByTitle", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSSHKeyByTitle indicates an expected call of DeleteSSHKeyByTitle.
+func (mr *MockISSHKeyRepoMockRecorder) DeleteSSHKeyByTitle(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSSHKeyByTitle", reflect.TypeOf((*MockISSHKeyRepo)(nil).DeleteSSHKeyByTitle), arg0, arg1, arg2)
+}
+
+// GetAllSSHKeys mocks base method.
+func (m *MockISSHKeyRepo) GetAllSSHKeys(arg0 context.Context, arg1 string, arg2 repo.ItemFilter, arg3 repo.Page) ([]repo.SSHKeyToGet, *repo.ItemCursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllSSHKeys", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]repo.SSHKeyToGet)
+	ret1, _ := ret[1].(*repo.ItemCursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllSSHKeys indicates an expected call of GetAllSSHKeys.
+func (mr *MockISSHKeyRepoMockRecorder) GetAllSSHKeys(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllSSHKeys", reflect.TypeOf((*MockISSHKeyRepo)(nil).GetAllSSHKeys), arg0, arg1, arg2, arg3)
+}
+
+// GetSSHKeyByTitle mocks base method.
+func (m *MockISSHKeyRepo) GetSSHKeyByTitle(arg0 context.Context, arg1, arg2 string) (*repo.SSHKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSSHKeyByTitle", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*repo.SSHKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSSHKeyByTitle indicates an expected call of GetSSHKeyByTitle.
+func (mr *MockISSHKeyRepoMockRecorder) GetSSHKeyByTitle(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSSHKeyByTitle", reflect.TypeOf((*MockISSHKeyRepo)(nil).GetSSHKeyByTitle), arg0, arg1, arg2)
+}
+
+// UpdateSSHKey mocks base method.
+func (m *MockISSHKeyRepo) UpdateSSHKey(arg0 context.Context, arg1 string, arg2 repo.SSHKeyUpdate, arg3 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSSHKey", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSSHKey indicates an expected call of UpdateSSHKey.
+func (mr *MockISSHKeyRepoMockRecorder) UpdateSSHKey(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSSHKey", reflect.TypeOf((*MockISSHKeyRepo)(nil).UpdateSSHKey), arg0, arg1, arg2, arg3)
+}
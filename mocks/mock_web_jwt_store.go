@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/adettelle/go-keeper/internal/server/api (interfaces: IWebJwtRepo)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIWebJwtRepo is a mock of IWebJwtRepo interface.
+type MockIWebJwtRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIWebJwtRepoMockRecorder
+}
+
+// MockIWebJwtRepoMockRecorder is the mock recorder for MockIWebJwtRepo.
+type MockIWebJwtRepoMockRecorder struct {
+	mock *MockIWebJwtRepo
+}
+
+// NewMockIWebJwtRepo creates a new mock instance.
+func NewMockIWebJwtRepo(ctrl *gomock.Controller) *MockIWebJwtRepo {
+	mock := &MockIWebJwtRepo{ctrl: ctrl}
+	mock.recorder = &MockIWebJwtRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIWebJwtRepo) EXPECT() *MockIWebJwtRepoMockRecorder {
+	return m.recorder
+}
+
+// AddJwtToken mocks base method.
+func (m *MockIWebJwtRepo) AddJwtToken(arg0 context.Context, arg1 int, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddJwtToken", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddJwtToken indicates an expected call of AddJwtToken.
+func (mr *MockIWebJwtRepoMockRecorder) AddJwtToken(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddJwtToken", reflect.TypeOf((*MockIWebJwtRepo)(nil).AddJwtToken), arg0, arg1, arg2)
+}
+
+// InvalidateToken mocks base method.
+func (m *MockIWebJwtRepo) InvalidateToken(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateToken indicates an expected call of InvalidateToken.
+func (mr *MockIWebJwtRepoMockRecorder) InvalidateToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateToken", reflect.TypeOf((*MockIWebJwtRepo)(nil).InvalidateToken), arg0, arg1)
+}
+
+// TokenIsValid mocks base method.
+func (m *MockIWebJwtRepo) TokenIsValid(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TokenIsValid", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TokenIsValid indicates an expected call of TokenIsValid.
+func (mr *MockIWebJwtRepoMockRecorder) TokenIsValid(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TokenIsValid", reflect.TypeOf((*MockIWebJwtRepo)(nil).TokenIsValid), arg0, arg1)
+}
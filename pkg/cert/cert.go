@@ -16,6 +16,12 @@ import (
 )
 
 func MustGenCert(prefix string) {
+	MustGenCertFiles(fmt.Sprintf("./keys/%s_cert.pem", prefix), fmt.Sprintf("./keys/%s_privatekey.pem", prefix))
+}
+
+// MustGenCertFiles generates a self-signed certificate and its private key
+// and writes them to the given paths.
+func MustGenCertFiles(certPath, privateKeyPath string) {
 	// var prefix string
 
 	// flag.StringVar(&prefix, "p", prefix, "certificate prefix")
@@ -81,7 +87,7 @@ func MustGenCert(prefix string) {
 		log.Fatal(err)
 	}
 
-	fileCert, err := os.OpenFile(fmt.Sprintf("./keys/%s_cert.pem", prefix), os.O_CREATE|os.O_WRONLY, 0777)
+	fileCert, err := os.OpenFile(certPath, os.O_CREATE|os.O_WRONLY, 0600)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -92,11 +98,11 @@ func MustGenCert(prefix string) {
 		log.Fatal(err)
 	}
 
-	filePrivateKey, err := os.OpenFile(fmt.Sprintf("./keys/%s_privatekey.pem", prefix), os.O_CREATE|os.O_WRONLY, 0777)
+	filePrivateKey, err := os.OpenFile(privateKeyPath, os.O_CREATE|os.O_WRONLY, 0600)
 	if err != nil {
 		log.Fatal("OP", err)
 	}
-	defer fileCert.Close()
+	defer filePrivateKey.Close()
 
 	_, err = filePrivateKey.Write(privateKeyPEM.Bytes())
 	if err != nil {
@@ -0,0 +1,56 @@
+package keeperclient
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// BreachEntry is a breached password hash of a range: the SHA-1 hex without the prefix
+// and the number of times the password appears in breaches.
+type BreachEntry struct {
+	Suffix string
+	Count  int
+}
+
+// BreachRange returns breached password hashes starting with the 5 characters SHA-1 hex prefix.
+// Only the prefix is sent to the server, the full hash is compared locally.
+// It fails with ErrNotFound if the server has no breach corpus configured.
+func (c *Client) BreachRange(ctx context.Context, prefix string) ([]BreachEntry, error) {
+	const op = "breach range"
+
+	rb, err := c.newAuthRequest(op, "/api/breach/range/"+url.PathEscape(prefix))
+	if err != nil {
+		return nil, err
+	}
+
+	var body string
+
+	err = rb.
+		Method(http.MethodGet).
+		ToString(&body).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+
+	var res []BreachEntry
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		suffix, count, ok := strings.Cut(line, ":")
+		n, err := strconv.Atoi(count)
+		if !ok || err != nil {
+			return nil, &Error{Op: op, Err: fmt.Errorf("malformed range line %q", line)}
+		}
+		res = append(res, BreachEntry{Suffix: suffix, Count: n})
+	}
+	return res, nil
+}
@@ -0,0 +1,152 @@
+package keeperclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type CardToGet struct {
+	Title       string `json:"title"`
+	Num         string `json:"num"` // Num is masked, e.g. **** 4242.
+	Brand       string `json:"brand"`
+	Description string `json:"description"`
+	ItemMeta
+}
+
+// Cards returns info of the user cards matching the options. Card numbers are masked by the server.
+// Favorites go first. The next page cursor is returned, empty on the last page.
+func (c *Client) Cards(ctx context.Context, opts ListOptions) ([]CardToGet, string, error) {
+	const op = "list cards"
+
+	rb, err := c.newAuthRequest(op, "/api/user/cards")
+	if err != nil {
+		return nil, "", err
+	}
+
+	var cards []CardToGet
+	headers := http.Header{}
+
+	err = opts.apply(rb).
+		ToJSON(&cards).
+		CopyHeaders(headers).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, "", wrapErr(op, err)
+	}
+	return cards, headers.Get(NextCursorHeader), nil
+}
+
+type CardToGetByTitle struct {
+	Num         string `json:"num"`
+	Brand       string `json:"brand"`
+	Expire      string `json:"expires_at"`
+	Cvc         string `json:"cvc"`
+	Holder      string `json:"cardholder"`
+	Address     string `json:"address"`
+	Pin         string `json:"pin"`
+	Description string `json:"description"`
+}
+
+// Card returns card details by title.
+func (c *Client) Card(ctx context.Context, title string) (*CardToGetByTitle, error) {
+	const op = "get card"
+
+	rb, err := c.newAuthRequest(op, "/api/user/card/"+url.PathEscape(title))
+	if err != nil {
+		return nil, err
+	}
+
+	var card CardToGetByTitle
+
+	err = rb.
+		Method(http.MethodGet).
+		ToJSON(&card).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return &card, nil
+}
+
+// CardToAdd is a new card. The number may contain spaces or dashes between digit groups;
+// the expiry date is MM/YY or MMYY and must not be in the past.
+type CardToAdd struct {
+	Num         string `json:"num" validate:"required,card_number"`
+	Expire      string `json:"expires_at" validate:"required,card_expiry"`
+	Cvc         string `json:"cvc" validate:"required,numeric,min=3,max=4"`
+	Holder      string `json:"cardholder,omitempty" validate:"max=255"`
+	Address     string `json:"address,omitempty" validate:"max=1000"`
+	Pin         string `json:"pin,omitempty" validate:"omitempty,numeric,min=4,max=12"`
+	Title       string `json:"title" validate:"required,min=1"`
+	Description string `json:"description"`
+}
+
+// AddCard stores a new card.
+func (c *Client) AddCard(ctx context.Context, card CardToAdd) error {
+	const op = "add card"
+
+	err := validate.Struct(card)
+	if err != nil {
+		return &Error{Op: op, Err: err}
+	}
+
+	rb, err := c.newAuthRequest(op, "/api/user/card")
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&card).
+		Method(http.MethodPut).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+type CardToUpdate struct {
+	Num         string `json:"num,omitempty" validate:"omitempty,card_number"`
+	Expire      string `json:"expires_at,omitempty" validate:"omitempty,card_expiry"`
+	Cvc         string `json:"cvc,omitempty" validate:"omitempty,numeric,min=3,max=4"`
+	Holder      string `json:"cardholder,omitempty" validate:"max=255"`
+	Address     string `json:"address,omitempty" validate:"max=1000"`
+	Pin         string `json:"pin,omitempty" validate:"omitempty,numeric,min=4,max=12"`
+	Description string `json:"description,omitempty"`
+}
+
+// UpdateCard updates card's number, date of expire, cvc, cardholder, address, PIN and description by title.
+// Empty fields are left unchanged.
+func (c *Client) UpdateCard(ctx context.Context, title string, card CardToUpdate) error {
+	const op = "update card"
+
+	err := validate.Struct(card)
+	if err != nil {
+		return &Error{Op: op, Err: err}
+	}
+
+	rb, err := c.newAuthRequest(op, "/api/user/card/update/"+url.PathEscape(title))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&card).
+		Method(http.MethodPost).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// DeleteCard deletes the card by title.
+func (c *Client) DeleteCard(ctx context.Context, title string) error {
+	const op = "delete card"
+
+	rb, err := c.newAuthRequest(op, "/api/user/card/"+url.PathEscape(title))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		Method(http.MethodDelete).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
@@ -0,0 +1,187 @@
+// Package keeperclient is a Go SDK for the go-keeper HTTP API.
+// It lets other programs register users, log in and manage passwords, cards, files, identity documents,
+// SSH keys and secure notes without going through the command line client.
+package keeperclient
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/adettelle/go-keeper/internal/identity"
+	"github.com/adettelle/go-keeper/internal/paycard"
+	"github.com/carlmjohnson/requests"
+	"github.com/go-playground/validator/v10"
+)
+
+// use a single instance of Validate, it caches struct info
+var validate *validator.Validate = newValidator()
+
+// newValidator creates the validator with the card_number, card_expiry and document_number tags,
+// checked the same way as on the server.
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	_ = v.RegisterValidation("card_number", func(fl validator.FieldLevel) bool {
+		return paycard.Validate(paycard.Normalize(fl.Field().String())) == nil
+	})
+	_ = v.RegisterValidation("card_expiry", func(fl validator.FieldLevel) bool {
+		_, err := paycard.ValidateExpiry(fl.Field().String(), time.Now())
+		return err == nil
+	})
+	_ = v.RegisterValidation("document_number", func(fl validator.FieldLevel) bool {
+		return identity.ValidDocNumber(fl.Field().String())
+	})
+	return v
+}
+
+// TokenSource supplies the JWT token ("Bearer ...") used for authorized requests.
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// TokenFunc adapts an ordinary function to the TokenSource interface.
+type TokenFunc func() (string, error)
+
+func (f TokenFunc) Token() (string, error) {
+	return f()
+}
+
+// StaticToken returns a TokenSource that always returns the given token.
+func StaticToken(token string) TokenSource {
+	return TokenFunc(func() (string, error) {
+		return token, nil
+	})
+}
+
+// Config holds the settings of a Client.
+type Config struct {
+	// BaseURL is the server address, e.g. "https://localhost:8080".
+	// If the scheme is omitted, https is used.
+	BaseURL string
+	// TLSConfig is used for https connections. It is ignored if Transport is set.
+	TLSConfig *tls.Config
+	// Transport is used to make requests. If nil, a transport with TLSConfig is created.
+	Transport http.RoundTripper
+	// Tokens supplies the JWT token for authorized requests.
+	Tokens TokenSource
+	// Collection is the shared collection, "org/collection", the items are read from and written to.
+	// The personal vault is used if empty.
+	Collection string
+}
+
+// Client is a go-keeper API client. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	transport  http.RoundTripper
+	tokens     TokenSource
+	collection string
+}
+
+// New creates a Client from the given configuration.
+func New(cfg Config) (*Client, error) {
+	if cfg.BaseURL == "" {
+		return nil, errors.New("keeperclient: base url is required")
+	}
+
+	baseURL := cfg.BaseURL
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "https://" + baseURL
+	}
+
+	transport := cfg.Transport
+	if transport == nil {
+		transport = &http.Transport{TLSClientConfig: cfg.TLSConfig}
+	}
+
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		transport:  transport,
+		tokens:     cfg.Tokens,
+		collection: cfg.Collection,
+	}, nil
+}
+
+// collectionHeader selects the shared collection of a request.
+const collectionHeader = "X-Collection"
+
+// newRequest creates a request builder for the given API path.
+func (c *Client) newRequest(path string) *requests.Builder {
+	return requests.
+		URL(c.baseURL + path).
+		Transport(c.transport)
+}
+
+// newAuthRequest creates a request builder for the given API path with the Authorization header set,
+// and the collection header if a shared collection is selected. Errors are already wrapped for operation op.
+func (c *Client) newAuthRequest(op, path string) (*requests.Builder, error) {
+	if c.tokens == nil {
+		return nil, &Error{Op: op, Err: ErrUnauthorized}
+	}
+	token, err := c.tokens.Token()
+	if err != nil {
+		return nil, &Error{Op: op, Err: err}
+	}
+	rb := c.newRequest(path).Header("Authorization", token)
+	if c.collection != "" {
+		rb.Header(collectionHeader, c.collection)
+	}
+	return rb, nil
+}
+
+var (
+	// ErrBadRequest is returned when the server rejects the request data.
+	ErrBadRequest = errors.New("bad request")
+	// ErrUnauthorized is returned when the user is not logged in or the token has expired.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotFound is returned when the requested item does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when an item with the same title already exists.
+	ErrConflict = errors.New("already exists")
+)
+
+// Error describes a failed API call.
+type Error struct {
+	Op         string // Op is the operation that failed, e.g. "get password".
+	StatusCode int    // StatusCode is the HTTP status returned by the server, 0 if there was no response.
+	Err        error  // Err is the underlying error.
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s: %v (status %d)", e.Op, e.Err, e.StatusCode)
+	}
+	return fmt.Sprintf("%s: %v", e.Op, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// wrapErr converts an error returned by the requests library into an *Error,
+// mapping well-known HTTP statuses to the package sentinel errors.
+func wrapErr(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var respErr *requests.ResponseError
+	if !errors.As(err, &respErr) {
+		return &Error{Op: op, Err: err}
+	}
+
+	code := respErr.StatusCode
+	switch code {
+	case http.StatusBadRequest:
+		err = ErrBadRequest
+	case http.StatusUnauthorized, http.StatusForbidden:
+		err = ErrUnauthorized
+	case http.StatusNotFound:
+		err = ErrNotFound
+	case http.StatusConflict:
+		err = ErrConflict
+	}
+	return &Error{Op: op, StatusCode: code, Err: err}
+}
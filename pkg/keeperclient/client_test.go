@@ -0,0 +1,248 @@
+package keeperclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordsSendsToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/user/passwords", r.URL.Path)
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`[{"title":"vk","description":"pass for vk"}]`))
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{BaseURL: srv.URL, Tokens: StaticToken("Bearer token")})
+	require.NoError(t, err)
+
+	pwds, next, err := c.Passwords(context.Background(), ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, next)
+	require.Equal(t, []PasswordToGet{{Title: "vk", Description: "pass for vk"}}, pwds)
+}
+
+func TestPasswordNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{BaseURL: srv.URL, Tokens: StaticToken("Bearer token")})
+	require.NoError(t, err)
+
+	_, err = c.Password(context.Background(), "unknown")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	require.Equal(t, "get password", apiErr.Op)
+}
+
+func TestPasswordTitleEscaped(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/user/password/work%2Fvk%20mail", r.URL.EscapedPath())
+		_, _ = w.Write([]byte(`{"title":"work/vk mail","pwd":"secret"}`))
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{BaseURL: srv.URL, Tokens: StaticToken("Bearer token")})
+	require.NoError(t, err)
+
+	_, err = c.Password(context.Background(), "work/vk mail")
+	require.NoError(t, err)
+}
+
+func TestLoginReturnsToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Authorization", "Bearer new_token")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{BaseURL: srv.URL})
+	require.NoError(t, err)
+
+	token, err := c.Login(context.Background(), CustomerToLogin{Login: "user@user.com", Password: "pass"})
+	require.NoError(t, err)
+	require.Equal(t, "Bearer new_token", token)
+}
+
+func TestNoTokenSource(t *testing.T) {
+	c, err := New(Config{BaseURL: "localhost:8080"})
+	require.NoError(t, err)
+
+	err = c.DeleteCard(context.Background(), "card")
+	require.ErrorIs(t, err, ErrUnauthorized)
+}
+
+func TestAddCardValidation(t *testing.T) {
+	c, err := New(Config{BaseURL: "localhost:8080", Tokens: StaticToken("Bearer token")})
+	require.NoError(t, err)
+
+	err = c.AddCard(context.Background(), CardToAdd{Num: "123", Title: "card"})
+	require.Error(t, err)
+}
+
+func TestBreachRange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/breach/range/5BAA6", r.URL.Path)
+		_, _ = w.Write([]byte("1E4C9B93F3F0682250B6CF8331B7EE68FD8:9659365\r\n1E56B40C0FB9F63A6E1C2D1D8F2C6E5A5B0:2\r\n"))
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{BaseURL: srv.URL, Tokens: StaticToken("Bearer token")})
+	require.NoError(t, err)
+
+	entries, err := c.BreachRange(context.Background(), "5BAA6")
+	require.NoError(t, err)
+	require.Equal(t, []BreachEntry{
+		{Suffix: "1E4C9B93F3F0682250B6CF8331B7EE68FD8", Count: 9659365},
+		{Suffix: "1E56B40C0FB9F63A6E1C2D1D8F2C6E5A5B0", Count: 2},
+	}, entries)
+}
+
+func TestPasswordsListOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "work/prod", r.URL.Query().Get("folder"))
+		require.Equal(t, []string{"prod", "db"}, r.URL.Query()["tag"])
+		require.Equal(t, "true", r.URL.Query().Get("favorite"))
+		_, _ = w.Write([]byte(`[{"title":"db","folder":"work/prod","tags":["prod","db"],"favorite":true}]`))
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{BaseURL: srv.URL, Tokens: StaticToken("Bearer token")})
+	require.NoError(t, err)
+
+	pwds, _, err := c.Passwords(context.Background(), ListOptions{
+		Folder: "work/prod", Tags: []string{"prod", "db"}, FavoritesOnly: true})
+	require.NoError(t, err)
+	require.Equal(t, []PasswordToGet{{Title: "db",
+		ItemMeta: ItemMeta{Folder: "work/prod", Tags: []string{"prod", "db"}, Favorite: true}}}, pwds)
+}
+
+func TestSearchItemsPaged(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/user/items/search", r.URL.Path)
+		require.Equal(t, "bank", r.URL.Query().Get("q"))
+		require.Equal(t, "-created", r.URL.Query().Get("sort"))
+		require.Equal(t, "1", r.URL.Query().Get("limit"))
+		require.Equal(t, "prev", r.URL.Query().Get("cursor"))
+		w.Header().Set(NextCursorHeader, "next")
+		_, _ = w.Write([]byte(`[{"kind":"card","title":"bank1","tags":[]}]`))
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{BaseURL: srv.URL, Tokens: StaticToken("Bearer token")})
+	require.NoError(t, err)
+
+	items, next, err := c.SearchItems(context.Background(), ListOptions{
+		Search: "bank", Sort: SortCreatedDesc, Limit: 1, Cursor: "prev"})
+	require.NoError(t, err)
+	require.Equal(t, "next", next)
+	require.Equal(t, []Item{{Kind: KindCard, Title: "bank1", ItemMeta: ItemMeta{Tags: []string{}}}}, items)
+}
+
+func TestPasswordDetails(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/user/password/details/github", r.URL.Path)
+		_, _ = w.Write([]byte(`{"title":"github","pwd":"secret","username":"ane",` +
+			`"urls":[{"url":"https://github.com","match":"host"}],` +
+			`"fields":[{"name":"pin","type":"hidden","value":"1234"}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{BaseURL: srv.URL, Tokens: StaticToken("Bearer token")})
+	require.NoError(t, err)
+
+	details, err := c.PasswordDetails(context.Background(), "github")
+	require.NoError(t, err)
+	require.Equal(t, "ane", details.Username)
+	require.Equal(t, []PasswordURL{{URL: "https://github.com", Match: MatchHost}}, details.URLs)
+
+	pin, ok := details.Field("pin")
+	require.True(t, ok)
+	require.Equal(t, "1234", pin.Value)
+
+	_, ok = details.Field("otp")
+	require.False(t, ok)
+}
+
+func TestMatchPasswords(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/user/passwords/match", r.URL.Path)
+		require.Equal(t, "https://github.com/login", r.URL.Query().Get("url"))
+		_, _ = w.Write([]byte(`[{"title":"github","username":"ane","urls":[{"url":"https://github.com","match":"domain"}]}]`))
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{BaseURL: srv.URL, Tokens: StaticToken("Bearer token")})
+	require.NoError(t, err)
+
+	matches, err := c.MatchPasswords(context.Background(), "https://github.com/login")
+	require.NoError(t, err)
+	require.Equal(t, []PasswordMatch{{
+		Title:    "github",
+		Username: "ane",
+		URLs:     []PasswordURL{{URL: "https://github.com", Match: MatchDomain}},
+	}}, matches)
+}
+
+func TestAddCardAmex(t *testing.T) {
+	var got CardToAdd
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{BaseURL: srv.URL, Tokens: StaticToken("Bearer token")})
+	require.NoError(t, err)
+
+	card := CardToAdd{Num: "3782 822463 10005", Expire: "12/40", Cvc: "1234", Holder: "ANE DOE", Title: "amex"}
+	require.NoError(t, c.AddCard(context.Background(), card))
+	require.Equal(t, card, got)
+
+	card.Expire = "01/20"
+	require.Error(t, c.AddCard(context.Background(), card))
+}
+
+func TestCollectionHeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "acme/staging", r.Header.Get("X-Collection"))
+		_, _ = w.Write([]byte(`["db"]`))
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{BaseURL: srv.URL, Tokens: StaticToken("Bearer token"), Collection: "acme/staging"})
+	require.NoError(t, err)
+
+	folders, err := c.Folders(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"db"}, folders)
+}
+
+func TestOpenLinkWithoutLogin(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/api/link/abc", r.URL.Path)
+		require.Empty(t, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"payload":"ciphertext"}`))
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{BaseURL: srv.URL})
+	require.NoError(t, err)
+
+	payload, err := c.OpenLink(context.Background(), "abc")
+	require.NoError(t, err)
+	require.Equal(t, "ciphertext", payload)
+	require.Equal(t, srv.URL+"/s/abc#key", c.LinkURL("abc", "key"))
+}
@@ -0,0 +1,212 @@
+package keeperclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TrustedContact is a contact designated for emergency access and the state of its access.
+// Status is "idle", "requested" or "granted"; GrantAt is when a pending request is granted.
+type TrustedContact struct {
+	Owner       string     `json:"owner"`
+	Contact     string     `json:"contact"`
+	WaitSeconds int        `json:"wait_seconds"`
+	Status      string     `json:"status"`
+	RequestedAt *time.Time `json:"requested_at,omitempty"`
+	GrantAt     *time.Time `json:"grant_at,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// EmergencyKit is the vault of the owner encrypted with the emergency key, and the key wrapped
+// for the contact. It is encrypted and decrypted by the caller, see the sharing package.
+type EmergencyKit struct {
+	Payload    string `json:"payload"`
+	WrappedKey string `json:"wrapped_key"`
+}
+
+// EmergencyEvent is a recorded step of emergency access.
+type EmergencyEvent struct {
+	Owner     string    `json:"owner"`
+	Contact   string    `json:"contact"`
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type trustedContactPut struct {
+	Login       string `json:"login"`
+	WaitSeconds int    `json:"wait_seconds"`
+	EmergencyKit
+}
+
+// PutTrustedContact designates the trusted contact or renews its kit and waiting period.
+// It returns ErrNotFound if the contact has no sharing keys.
+func (c *Client) PutTrustedContact(ctx context.Context, login string, wait time.Duration, kit EmergencyKit) error {
+	const op = "put trusted contact"
+
+	rb, err := c.newAuthRequest(op, "/api/user/emergency/contact")
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&trustedContactPut{Login: login, WaitSeconds: int(wait.Seconds()), EmergencyKit: kit}).
+		Method(http.MethodPut).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// TrustedContacts returns the trusted contacts of the user.
+func (c *Client) TrustedContacts(ctx context.Context) ([]TrustedContact, error) {
+	const op = "list trusted contacts"
+
+	rb, err := c.newAuthRequest(op, "/api/user/emergency/contacts")
+	if err != nil {
+		return nil, err
+	}
+
+	var contacts []TrustedContact
+
+	err = rb.
+		ToJSON(&contacts).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return contacts, nil
+}
+
+// DeleteTrustedContact removes the trusted contact with its kit.
+func (c *Client) DeleteTrustedContact(ctx context.Context, login string) error {
+	const op = "delete trusted contact"
+
+	rb, err := c.newAuthRequest(op, "/api/user/emergency/contact/"+url.PathEscape(login))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		Method(http.MethodDelete).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// RejectEmergencyAccess rejects the request of the contact during its waiting period.
+// It returns ErrConflict if there is no request or the waiting period has passed.
+func (c *Client) RejectEmergencyAccess(ctx context.Context, login string) error {
+	const op = "reject emergency access"
+
+	rb, err := c.newAuthRequest(op, "/api/user/emergency/contact/"+url.PathEscape(login)+"/reject")
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		Method(http.MethodPost).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// Grantors returns the users who designated the user as their trusted contact.
+func (c *Client) Grantors(ctx context.Context) ([]TrustedContact, error) {
+	const op = "list grantors"
+
+	rb, err := c.newAuthRequest(op, "/api/user/emergency/grantors")
+	if err != nil {
+		return nil, err
+	}
+
+	var grantors []TrustedContact
+
+	err = rb.
+		ToJSON(&grantors).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return grantors, nil
+}
+
+// RequestEmergencyAccess starts the waiting period for emergency access to the vault of the owner.
+// It returns ErrConflict if access is already requested or granted.
+func (c *Client) RequestEmergencyAccess(ctx context.Context, owner string) error {
+	const op = "request emergency access"
+
+	rb, err := c.newAuthRequest(op, "/api/user/emergency/grantor/"+url.PathEscape(owner)+"/request")
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		Method(http.MethodPost).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// EmergencyKit returns the emergency kit of the owner. It returns ErrUnauthorized
+// while access is not granted.
+func (c *Client) EmergencyKit(ctx context.Context, owner string) (*EmergencyKit, error) {
+	const op = "get emergency kit"
+
+	rb, err := c.newAuthRequest(op, "/api/user/emergency/grantor/"+url.PathEscape(owner)+"/kit")
+	if err != nil {
+		return nil, err
+	}
+
+	var kit EmergencyKit
+
+	err = rb.
+		ToJSON(&kit).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return &kit, nil
+}
+
+// EmergencyEvents returns the latest steps of emergency access of the user, newest first.
+func (c *Client) EmergencyEvents(ctx context.Context) ([]EmergencyEvent, error) {
+	const op = "list emergency events"
+
+	rb, err := c.newAuthRequest(op, "/api/user/emergency/events")
+	if err != nil {
+		return nil, err
+	}
+
+	var events []EmergencyEvent
+
+	err = rb.
+		ToJSON(&events).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return events, nil
+}
+
+// Notifications returns the steps of emergency access the user has not been notified of yet.
+// Each one is returned once.
+func (c *Client) Notifications(ctx context.Context) ([]EmergencyEvent, error) {
+	const op = "get notifications"
+
+	rb, err := c.newAuthRequest(op, "/api/user/notifications")
+	if err != nil {
+		return nil, err
+	}
+
+	var events []EmergencyEvent
+
+	err = rb.
+		ToJSON(&events).
+		Method(http.MethodPost).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return events, nil
+}
@@ -0,0 +1,119 @@
+package keeperclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type FileToGetAll struct {
+	FileName    string `json:"file_name"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ItemMeta
+}
+
+// Files returns info of the user files matching the options. Favorites go first.
+// The next page cursor is returned, empty on the last page.
+func (c *Client) Files(ctx context.Context, opts ListOptions) ([]FileToGetAll, string, error) {
+	const op = "list files"
+
+	rb, err := c.newAuthRequest(op, "/api/user/files")
+	if err != nil {
+		return nil, "", err
+	}
+
+	var files []FileToGetAll
+	headers := http.Header{}
+
+	err = opts.apply(rb).
+		ToJSON(&files).
+		CopyHeaders(headers).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, "", wrapErr(op, err)
+	}
+	return files, headers.Get(NextCursorHeader), nil
+}
+
+// File writes the content of the file stored under the given title to w.
+func (c *Client) File(ctx context.Context, title string, w io.Writer) error {
+	const op = "get file"
+
+	rb, err := c.newAuthRequest(op, "/api/user/file/"+url.PathEscape(title))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		Method(http.MethodGet).
+		ToWriter(w).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+type FileToAdd struct {
+	FileName    string
+	Title       string
+	Description string
+}
+
+// AddFile uploads size bytes read from r and stores them under the given title.
+func (c *Client) AddFile(ctx context.Context, file FileToAdd, r io.Reader, size int64) error {
+	const op = "add file"
+
+	rb, err := c.newAuthRequest(op, "/api/user/file")
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		Header("x-file-name", file.FileName).
+		Header("x-file-title", file.Title).
+		Header("x-file-description", file.Description).
+		Header("Content-Length", strconv.FormatInt(size, 10)).
+		BodyReader(r).
+		Method(http.MethodPut).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+type FileToUpdate struct {
+	FileName    string `json:"fname,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// UpdateFile updates file's name and description by title.
+// Empty fields are left unchanged.
+func (c *Client) UpdateFile(ctx context.Context, title string, file FileToUpdate) error {
+	const op = "update file"
+
+	rb, err := c.newAuthRequest(op, "/api/user/file/update/"+url.PathEscape(title))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&file).
+		Method(http.MethodPost).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// DeleteFile deletes the file by title.
+func (c *Client) DeleteFile(ctx context.Context, title string) error {
+	const op = "delete file"
+
+	rb, err := c.newAuthRequest(op, "/api/user/file/"+url.PathEscape(title))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		Method(http.MethodDelete).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
@@ -0,0 +1,156 @@
+package keeperclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/adettelle/go-keeper/internal/identity"
+)
+
+// DocType is a type of identity documents; its fields are described by identity.Schema.
+type DocType = identity.DocType
+
+// Identity document types.
+const (
+	DocPassport      = identity.DocPassport
+	DocDriverLicense = identity.DocDriverLicense
+	DocNationalID    = identity.DocNationalID
+	DocAddress       = identity.DocAddress
+)
+
+type IdentityToGet struct {
+	DocType     DocType `json:"doc_type"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	ItemMeta
+}
+
+// Identities returns info of the user identity documents matching the options, without the document fields.
+// Favorites go first. The next page cursor is returned, empty on the last page.
+func (c *Client) Identities(ctx context.Context, opts ListOptions) ([]IdentityToGet, string, error) {
+	const op = "list identities"
+
+	rb, err := c.newAuthRequest(op, "/api/user/identities")
+	if err != nil {
+		return nil, "", err
+	}
+
+	var identities []IdentityToGet
+	headers := http.Header{}
+
+	err = opts.apply(rb).
+		ToJSON(&identities).
+		CopyHeaders(headers).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, "", wrapErr(op, err)
+	}
+	return identities, headers.Get(NextCursorHeader), nil
+}
+
+type IdentityToGetByTitle struct {
+	DocType     DocType           `json:"doc_type"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Fields      map[string]string `json:"fields"`
+}
+
+// Identity returns the identity document with its fields by title.
+func (c *Client) Identity(ctx context.Context, title string) (*IdentityToGetByTitle, error) {
+	const op = "get identity"
+
+	rb, err := c.newAuthRequest(op, "/api/user/identity/"+url.PathEscape(title))
+	if err != nil {
+		return nil, err
+	}
+
+	var doc IdentityToGetByTitle
+
+	err = rb.
+		Method(http.MethodGet).
+		ToJSON(&doc).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return &doc, nil
+}
+
+// IdentityToAdd is a new identity document. Fields are checked against the schema of the document type:
+// dates are YYYY-MM-DD, countries are ISO 3166-1 alpha-2 codes.
+type IdentityToAdd struct {
+	DocType     DocType           `json:"doc_type" validate:"required"`
+	Title       string            `json:"title" validate:"required,min=1"`
+	Description string            `json:"description" validate:"max=1000"`
+	Fields      map[string]string `json:"fields"`
+}
+
+// AddIdentity stores a new identity document.
+func (c *Client) AddIdentity(ctx context.Context, doc IdentityToAdd) error {
+	const op = "add identity"
+
+	err := validate.Struct(doc)
+	if err == nil {
+		_, err = identity.Validate(doc.DocType, doc.Fields, validate.Var)
+	}
+	if err != nil {
+		return &Error{Op: op, Err: err}
+	}
+
+	rb, err := c.newAuthRequest(op, "/api/user/identity")
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&doc).
+		Method(http.MethodPut).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// IdentityToUpdate changes the identity document. Fields with a value are set, fields with nil are removed,
+// the others are left unchanged. Nil Description is left unchanged.
+type IdentityToUpdate struct {
+	Description *string            `json:"description,omitempty" validate:"omitnil,max=1000"`
+	Fields      map[string]*string `json:"fields,omitempty"`
+}
+
+// UpdateIdentity updates the identity document fields and description by title.
+// The resulting fields are checked against the schema by the server.
+func (c *Client) UpdateIdentity(ctx context.Context, title string, doc IdentityToUpdate) error {
+	const op = "update identity"
+
+	err := validate.Struct(doc)
+	if err != nil {
+		return &Error{Op: op, Err: err}
+	}
+
+	rb, err := c.newAuthRequest(op, "/api/user/identity/update/"+url.PathEscape(title))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&doc).
+		Method(http.MethodPost).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// DeleteIdentity deletes the identity document by title.
+func (c *Client) DeleteIdentity(ctx context.Context, title string) error {
+	const op = "delete identity"
+
+	rb, err := c.newAuthRequest(op, "/api/user/identity/"+url.PathEscape(title))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		Method(http.MethodDelete).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
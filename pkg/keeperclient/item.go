@@ -0,0 +1,153 @@
+package keeperclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/carlmjohnson/requests"
+)
+
+// ItemKind is a type of vault items.
+type ItemKind string
+
+const (
+	KindPassword ItemKind = "password"
+	KindCard     ItemKind = "card"
+	KindFile     ItemKind = "file"
+	KindIdentity ItemKind = "identity"
+	KindSSHKey   ItemKind = "sshkey"
+	KindNote     ItemKind = "note"
+)
+
+// ItemMeta is the folder, tags and favorite mark of an item, returned in listings.
+type ItemMeta struct {
+	Folder   string   `json:"folder"` // Folder is a slash separated path, e.g. "work/prod".
+	Tags     []string `json:"tags"`
+	Favorite bool     `json:"favorite"`
+}
+
+// Sort orders of item listings. Favorites always go first.
+const (
+	SortTitle       = "title"
+	SortTitleDesc   = "-title"
+	SortCreated     = "created"
+	SortCreatedDesc = "-created"
+)
+
+// NextCursorHeader is the response header with the cursor of the next page.
+const NextCursorHeader = "X-Next-Cursor"
+
+// ListOptions filter and paginate item listings. Zero value lists all the items at once.
+type ListOptions struct {
+	Folder        string   // Folder lists items of the folder and its subfolders.
+	Tags          []string // Tags lists items having all of the tags.
+	FavoritesOnly bool
+	Search        string // Search lists items with the substring in the title, description or a tag.
+	Sort          string // Sort is one of Sort* constants, by title if empty.
+	Limit         int    // Limit is the page size; zero lists all the items.
+	Cursor        string // Cursor is the next page cursor returned with the previous page.
+}
+
+// apply adds the options to the request query.
+func (o ListOptions) apply(rb *requests.Builder) *requests.Builder {
+	if o.Folder != "" {
+		rb = rb.Param("folder", o.Folder)
+	}
+	if len(o.Tags) > 0 {
+		rb = rb.Param("tag", o.Tags...)
+	}
+	if o.FavoritesOnly {
+		rb = rb.Param("favorite", strconv.FormatBool(true))
+	}
+	if o.Search != "" {
+		rb = rb.Param("q", o.Search)
+	}
+	if o.Sort != "" {
+		rb = rb.Param("sort", o.Sort)
+	}
+	if o.Limit > 0 {
+		rb = rb.ParamInt("limit", o.Limit)
+	}
+	if o.Cursor != "" {
+		rb = rb.Param("cursor", o.Cursor)
+	}
+	return rb
+}
+
+// Item is a vault item of any kind found by SearchItems.
+type Item struct {
+	Kind        ItemKind `json:"kind"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	ItemMeta
+}
+
+// SearchItems returns a page of items of all kinds matching the options and the next page cursor,
+// empty on the last page.
+func (c *Client) SearchItems(ctx context.Context, opts ListOptions) ([]Item, string, error) {
+	const op = "search items"
+
+	rb, err := c.newAuthRequest(op, "/api/user/items/search")
+	if err != nil {
+		return nil, "", err
+	}
+
+	var items []Item
+	headers := http.Header{}
+
+	err = opts.apply(rb).
+		ToJSON(&items).
+		CopyHeaders(headers).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, "", wrapErr(op, err)
+	}
+	return items, headers.Get(NextCursorHeader), nil
+}
+
+// ItemMetaUpdate holds the item meta fields to change; nil fields remain unchanged.
+type ItemMetaUpdate struct {
+	Folder   *string   `json:"folder,omitempty"`
+	Tags     *[]string `json:"tags,omitempty"`
+	Favorite *bool     `json:"favorite,omitempty"`
+}
+
+// UpdateItemMeta moves an item to another folder, replaces its tags or (un)marks it as favorite.
+func (c *Client) UpdateItemMeta(ctx context.Context, kind ItemKind, title string, upd ItemMetaUpdate) error {
+	const op = "update item meta"
+
+	rb, err := c.newAuthRequest(op, "/api/user/"+string(kind)+"/meta/"+url.PathEscape(title))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&upd).
+		Method(http.MethodPost).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// Folders returns all folders used by the user items.
+func (c *Client) Folders(ctx context.Context) ([]string, error) {
+	const op = "list folders"
+
+	rb, err := c.newAuthRequest(op, "/api/user/folders")
+	if err != nil {
+		return nil, err
+	}
+
+	var folders []string
+
+	err = rb.
+		ToJSON(&folders).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return folders, nil
+}
@@ -0,0 +1,101 @@
+package keeperclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Link is a secret link created on the server.
+type Link struct {
+	ID        string    `json:"id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// FileLink is a presigned link to download a file from the object storage.
+type FileLink struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type linkCreate struct {
+	Payload string `json:"payload"`
+	TTL     int    `json:"ttl_seconds"`
+	Views   int    `json:"views"`
+}
+
+type linkPayload struct {
+	Payload string `json:"payload"`
+}
+
+type fileLinkCreate struct {
+	Title string `json:"title"`
+}
+
+// CreateLink stores the secret, encrypted by the caller, for the number of views until the ttl passes.
+// The server accepts ttl from a minute to a week and up to 100 views.
+func (c *Client) CreateLink(ctx context.Context, payload string, ttl time.Duration, views int) (*Link, error) {
+	const op = "create link"
+
+	rb, err := c.newAuthRequest(op, "/api/user/link")
+	if err != nil {
+		return nil, err
+	}
+
+	var link Link
+
+	err = rb.
+		BodyJSON(&linkCreate{Payload: payload, TTL: int(ttl.Seconds()), Views: views}).
+		ToJSON(&link).
+		Method(http.MethodPost).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return &link, nil
+}
+
+// LinkURL returns the address of the link page with the key in the fragment, which browsers do not send.
+func (c *Client) LinkURL(id, key string) string {
+	return c.baseURL + "/s/" + id + "#" + key
+}
+
+// OpenLink returns the encrypted secret of the link and uses up one of its views.
+// It returns ErrNotFound if the link has expired or has been used up. No login is needed.
+func (c *Client) OpenLink(ctx context.Context, id string) (string, error) {
+	const op = "open link"
+
+	var payload linkPayload
+
+	err := c.newRequest("/api/link/" + url.PathEscape(id)).
+		ToJSON(&payload).
+		Method(http.MethodPost).
+		Fetch(ctx)
+	if err != nil {
+		return "", wrapErr(op, err)
+	}
+	return payload.Payload, nil
+}
+
+// CreateFileLink returns a presigned link to download the file, valid for the lifetime configured on the server.
+func (c *Client) CreateFileLink(ctx context.Context, title string) (*FileLink, error) {
+	const op = "create file link"
+
+	rb, err := c.newAuthRequest(op, "/api/user/link/file")
+	if err != nil {
+		return nil, err
+	}
+
+	var link FileLink
+
+	err = rb.
+		BodyJSON(&fileLinkCreate{Title: title}).
+		ToJSON(&link).
+		Method(http.MethodPost).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return &link, nil
+}
@@ -0,0 +1,134 @@
+package keeperclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type NoteToGet struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ItemMeta
+}
+
+// Notes returns the user secure notes matching the options, without their text.
+// Favorites go first. The next page cursor is returned, empty on the last page.
+func (c *Client) Notes(ctx context.Context, opts ListOptions) ([]NoteToGet, string, error) {
+	const op = "list notes"
+
+	rb, err := c.newAuthRequest(op, "/api/user/notes")
+	if err != nil {
+		return nil, "", err
+	}
+
+	var notes []NoteToGet
+	headers := http.Header{}
+
+	err = opts.apply(rb).
+		ToJSON(&notes).
+		CopyHeaders(headers).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, "", wrapErr(op, err)
+	}
+	return notes, headers.Get(NextCursorHeader), nil
+}
+
+type NoteToGetByTitle struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Text        string `json:"text"`
+}
+
+// Note returns the secure note with its text by title.
+func (c *Client) Note(ctx context.Context, title string) (*NoteToGetByTitle, error) {
+	const op = "get note"
+
+	rb, err := c.newAuthRequest(op, "/api/user/note/"+url.PathEscape(title))
+	if err != nil {
+		return nil, err
+	}
+
+	var note NoteToGetByTitle
+
+	err = rb.
+		Method(http.MethodGet).
+		ToJSON(&note).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return &note, nil
+}
+
+// NoteToAdd is a new secure note. The text is free-form, up to 64K characters.
+type NoteToAdd struct {
+	Title       string `json:"title" validate:"required,min=1"`
+	Description string `json:"description" validate:"max=1000"`
+	Text        string `json:"text" validate:"required,max=65536"`
+}
+
+// AddNote stores a new secure note.
+func (c *Client) AddNote(ctx context.Context, note NoteToAdd) error {
+	const op = "add note"
+
+	err := validate.Struct(note)
+	if err != nil {
+		return &Error{Op: op, Err: err}
+	}
+
+	rb, err := c.newAuthRequest(op, "/api/user/note")
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&note).
+		Method(http.MethodPut).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// NoteToUpdate changes the note text and description; nil fields are left unchanged.
+type NoteToUpdate struct {
+	Text        *string `json:"text,omitempty" validate:"omitnil,min=1,max=65536"`
+	Description *string `json:"description,omitempty" validate:"omitnil,max=1000"`
+}
+
+// UpdateNote updates the note text and description by title.
+func (c *Client) UpdateNote(ctx context.Context, title string, note NoteToUpdate) error {
+	const op = "update note"
+
+	err := validate.Struct(note)
+	if err != nil {
+		return &Error{Op: op, Err: err}
+	}
+
+	rb, err := c.newAuthRequest(op, "/api/user/note/update/"+url.PathEscape(title))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&note).
+		Method(http.MethodPost).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// DeleteNote deletes the secure note by title.
+func (c *Client) DeleteNote(ctx context.Context, title string) error {
+	const op = "delete note"
+
+	rb, err := c.newAuthRequest(op, "/api/user/note/"+url.PathEscape(title))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		Method(http.MethodDelete).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
@@ -0,0 +1,192 @@
+package keeperclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Role is the role of a member in an organization: owners manage everything, admins manage collections,
+// editors and viewers, editors change items of the collections and viewers only read them.
+type Role string
+
+// Roles of organization members.
+const (
+	RoleOwner  Role = "owner"
+	RoleAdmin  Role = "admin"
+	RoleEditor Role = "editor"
+	RoleViewer Role = "viewer"
+)
+
+// Org is an organization of the user with the role of the user in it.
+type Org struct {
+	Name string `json:"name"`
+	Role Role   `json:"role"`
+}
+
+// Member is a member of an organization.
+type Member struct {
+	Login string `json:"login"`
+	Name  string `json:"name"`
+	Role  Role   `json:"role"`
+}
+
+type orgName struct {
+	Name string `json:"name"`
+}
+
+// CreateOrg creates the organization with the user as its owner.
+func (c *Client) CreateOrg(ctx context.Context, name string) error {
+	const op = "create organization"
+
+	rb, err := c.newAuthRequest(op, "/api/user/org")
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&orgName{Name: name}).
+		Method(http.MethodPut).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// Orgs returns the organizations of the user.
+func (c *Client) Orgs(ctx context.Context) ([]Org, error) {
+	const op = "list organizations"
+
+	rb, err := c.newAuthRequest(op, "/api/user/orgs")
+	if err != nil {
+		return nil, err
+	}
+
+	var orgs []Org
+
+	err = rb.
+		ToJSON(&orgs).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return orgs, nil
+}
+
+// DeleteOrg deletes the organization. It must have no collections.
+func (c *Client) DeleteOrg(ctx context.Context, name string) error {
+	const op = "delete organization"
+
+	rb, err := c.newAuthRequest(op, "/api/user/org/"+url.PathEscape(name))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		Method(http.MethodDelete).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// Members returns the members of the organization, owners first.
+func (c *Client) Members(ctx context.Context, org string) ([]Member, error) {
+	const op = "list members"
+
+	rb, err := c.newAuthRequest(op, "/api/user/org/"+url.PathEscape(org)+"/members")
+	if err != nil {
+		return nil, err
+	}
+
+	var members []Member
+
+	err = rb.
+		ToJSON(&members).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return members, nil
+}
+
+// SetMember adds the user to the organization with the role or changes the role of a member.
+func (c *Client) SetMember(ctx context.Context, org, login string, role Role) error {
+	const op = "set member"
+
+	rb, err := c.newAuthRequest(op, "/api/user/org/"+url.PathEscape(org)+"/member")
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&Member{Login: login, Role: role}).
+		Method(http.MethodPut).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// RemoveMember removes the member from the organization.
+func (c *Client) RemoveMember(ctx context.Context, org, login string) error {
+	const op = "remove member"
+
+	rb, err := c.newAuthRequest(op, "/api/user/org/"+url.PathEscape(org)+"/member/"+url.PathEscape(login))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		Method(http.MethodDelete).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// CreateCollection creates a shared collection in the organization.
+func (c *Client) CreateCollection(ctx context.Context, org, name string) error {
+	const op = "create collection"
+
+	rb, err := c.newAuthRequest(op, "/api/user/org/"+url.PathEscape(org)+"/collection")
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&orgName{Name: name}).
+		Method(http.MethodPut).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// Collections returns the names of the collections of the organization.
+func (c *Client) Collections(ctx context.Context, org string) ([]string, error) {
+	const op = "list collections"
+
+	rb, err := c.newAuthRequest(op, "/api/user/org/"+url.PathEscape(org)+"/collections")
+	if err != nil {
+		return nil, err
+	}
+
+	var collections []string
+
+	err = rb.
+		ToJSON(&collections).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return collections, nil
+}
+
+// DeleteCollection deletes the collection. It must have no items.
+func (c *Client) DeleteCollection(ctx context.Context, org, name string) error {
+	const op = "delete collection"
+
+	rb, err := c.newAuthRequest(op, "/api/user/org/"+url.PathEscape(org)+"/collection/"+url.PathEscape(name))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		Method(http.MethodDelete).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
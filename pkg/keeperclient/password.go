@@ -0,0 +1,238 @@
+package keeperclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type PasswordToGet struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	ChangedAt   time.Time `json:"changed_at"`
+	ItemMeta
+}
+
+// Passwords returns info (title, description, folder and tags) of the user passwords
+// matching the options. Favorites go first. The next page cursor is returned, empty on the last page.
+func (c *Client) Passwords(ctx context.Context, opts ListOptions) ([]PasswordToGet, string, error) {
+	const op = "list passwords"
+
+	rb, err := c.newAuthRequest(op, "/api/user/passwords")
+	if err != nil {
+		return nil, "", err
+	}
+
+	var pwds []PasswordToGet
+	headers := http.Header{}
+
+	err = opts.apply(rb).
+		ToJSON(&pwds).
+		CopyHeaders(headers).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, "", wrapErr(op, err)
+	}
+	return pwds, headers.Get(NextCursorHeader), nil
+}
+
+// Password returns the password stored under the given title.
+func (c *Client) Password(ctx context.Context, title string) (string, error) {
+	const op = "get password"
+
+	rb, err := c.newAuthRequest(op, "/api/user/password/"+url.PathEscape(title))
+	if err != nil {
+		return "", err
+	}
+
+	var pwd string
+
+	err = rb.
+		Method(http.MethodGet).
+		ToString(&pwd).
+		Fetch(ctx)
+	if err != nil {
+		return "", wrapErr(op, err)
+	}
+	return pwd, nil
+}
+
+// Match rules of password URLs.
+const (
+	MatchDomain = "domain" // MatchDomain matches any site of the same registrable domain.
+	MatchHost   = "host"   // MatchHost matches the same host and port.
+	MatchExact  = "exact"  // MatchExact matches the exact URL.
+	MatchNever  = "never"  // MatchNever disables matching of the URL.
+)
+
+// Types of password custom fields.
+const (
+	FieldText    = "text"
+	FieldHidden  = "hidden"
+	FieldBoolean = "boolean"
+)
+
+// PasswordURL is a site address of a password entry.
+type PasswordURL struct {
+	URL   string `json:"url"`
+	Match string `json:"match,omitempty"` // Match defaults to MatchDomain.
+}
+
+// PasswordField is a custom field of a password entry.
+type PasswordField struct {
+	Name  string `json:"name"`
+	Type  string `json:"type,omitempty"` // Type defaults to FieldText.
+	Value string `json:"value"`
+}
+
+// PasswordDetails is a password entry with its username, URLs and custom fields.
+type PasswordDetails struct {
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Password    string          `json:"pwd"`
+	Username    string          `json:"username"`
+	ChangedAt   time.Time       `json:"changed_at"`
+	URLs        []PasswordURL   `json:"urls"`
+	Fields      []PasswordField `json:"fields"`
+	MaxAgeDays  int             `json:"max_age_days"` // MaxAgeDays is 0 when the password never expires.
+}
+
+// Field returns the value of the custom field by name.
+func (d *PasswordDetails) Field(name string) (PasswordField, bool) {
+	for _, f := range d.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return PasswordField{}, false
+}
+
+// PasswordDetails returns the password entry stored under the given title with all its details.
+func (c *Client) PasswordDetails(ctx context.Context, title string) (*PasswordDetails, error) {
+	const op = "get password details"
+
+	rb, err := c.newAuthRequest(op, "/api/user/password/details/"+url.PathEscape(title))
+	if err != nil {
+		return nil, err
+	}
+
+	var details PasswordDetails
+
+	err = rb.
+		Method(http.MethodGet).
+		ToJSON(&details).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return &details, nil
+}
+
+type PwdToAdd struct {
+	Password    string          `json:"pwd" validate:"required,min=1"`
+	Title       string          `json:"title" validate:"required,min=1"`
+	Description string          `json:"description"`
+	Username    string          `json:"username,omitempty"`
+	URLs        []PasswordURL   `json:"urls,omitempty"`
+	Fields      []PasswordField `json:"fields,omitempty"`
+	MaxAgeDays  int             `json:"max_age_days,omitempty" validate:"min=0,max=3650"`
+	Folder      string          `json:"folder,omitempty"`
+	Tags        []string        `json:"tags,omitempty"`
+}
+
+// AddPassword stores a new password, put into the folder and tagged at once.
+func (c *Client) AddPassword(ctx context.Context, pwd PwdToAdd) error {
+	const op = "add password"
+
+	err := validate.Struct(pwd)
+	if err != nil {
+		return &Error{Op: op, Err: err}
+	}
+
+	rb, err := c.newAuthRequest(op, "/api/user/password")
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&pwd).
+		Method(http.MethodPut).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+type PasswordToUpdate struct {
+	Password    string           `json:"pwd,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Username    *string          `json:"username,omitempty"`
+	URLs        *[]PasswordURL   `json:"urls,omitempty"`   // URLs replace all the previous ones.
+	Fields      *[]PasswordField `json:"fields,omitempty"` // Fields replace all the previous ones.
+	MaxAgeDays  *int             `json:"max_age_days,omitempty"`
+}
+
+// UpdatePassword changes the password, its description and details by title.
+// Empty fields are left unchanged.
+func (c *Client) UpdatePassword(ctx context.Context, title string, pwd PasswordToUpdate) error {
+	const op = "update password"
+
+	rb, err := c.newAuthRequest(op, "/api/user/password/update/"+url.PathEscape(title))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&pwd).
+		Method(http.MethodPost).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// DeletePassword deletes the password by title.
+func (c *Client) DeletePassword(ctx context.Context, title string) error {
+	const op = "delete password"
+
+	rb, err := c.newAuthRequest(op, "/api/user/password/"+url.PathEscape(title))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		Method(http.MethodDelete).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// PasswordMatch is a password entry matching a URL. The password itself and the values
+// of hidden fields are not included.
+type PasswordMatch struct {
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Username    string          `json:"username"`
+	URLs        []PasswordURL   `json:"urls"`
+	Fields      []PasswordField `json:"fields"`
+}
+
+// MatchPasswords returns the password entries having a URL that matches siteURL according to its match rule.
+// The server looks entries up by a blind index, so it does not store the domains in plain text.
+func (c *Client) MatchPasswords(ctx context.Context, siteURL string) ([]PasswordMatch, error) {
+	const op = "match passwords"
+
+	rb, err := c.newAuthRequest(op, "/api/user/passwords/match")
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []PasswordMatch
+
+	err = rb.
+		Param("url", siteURL).
+		Method(http.MethodGet).
+		ToJSON(&matches).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return matches, nil
+}
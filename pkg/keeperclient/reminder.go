@@ -0,0 +1,49 @@
+package keeperclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Reasons of the reminders.
+const (
+	ReasonCardExpiry       = "card_expiry"
+	ReasonPasswordRotation = "password_rotation"
+)
+
+// Reminder is a card which expires or a password which reaches its max age.
+// Overdue is set if the card already expired or the password must already be changed.
+type Reminder struct {
+	Kind    ItemKind  `json:"kind"`
+	Title   string    `json:"title"`
+	Reason  string    `json:"reason"`
+	DueAt   time.Time `json:"due_at"`
+	Overdue bool      `json:"overdue"`
+}
+
+// Reminders returns the reminders due within days, the earliest first.
+// If days is negative, the server default window is used.
+func (c *Client) Reminders(ctx context.Context, days int) ([]Reminder, error) {
+	const op = "reminders"
+
+	rb, err := c.newAuthRequest(op, "/api/user/reminders")
+	if err != nil {
+		return nil, err
+	}
+	if days >= 0 {
+		rb.Param("days", strconv.Itoa(days))
+	}
+
+	var reminders []Reminder
+
+	err = rb.
+		Method(http.MethodGet).
+		ToJSON(&reminders).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return reminders, nil
+}
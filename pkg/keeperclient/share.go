@@ -0,0 +1,216 @@
+package keeperclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// UserKeys are the sharing keys of the user: the public key and the private key sealed with the vault key,
+// which is derived from the master password and the account key. They are encrypted and decrypted
+// by the caller, see the sharing package.
+type UserKeys struct {
+	PublicKey  string `json:"public_key"`
+	PrivateKey string `json:"private_key"`
+}
+
+// ShareRecipient is a user an item is shared with and the item key wrapped for the user.
+type ShareRecipient struct {
+	Login      string `json:"login"`
+	WrappedKey string `json:"wrapped_key"`
+}
+
+// Share is an item encrypted with its item key and the recipients it is shared with.
+type Share struct {
+	Kind       ItemKind         `json:"kind"`
+	Title      string           `json:"title"`
+	Payload    string           `json:"payload"`
+	Recipients []ShareRecipient `json:"recipients"`
+}
+
+// OwnedShare is an item shared by the user with the logins of its recipients.
+type OwnedShare struct {
+	Kind       ItemKind  `json:"kind"`
+	Title      string    `json:"title"`
+	Recipients []string  `json:"recipients"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// SharedItem is an item shared with the user.
+type SharedItem struct {
+	Owner     string    `json:"owner"`
+	Kind      ItemKind  `json:"kind"`
+	Title     string    `json:"title"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SharedPayload is the encrypted item shared with the user and the item key wrapped for the user.
+type SharedPayload struct {
+	Payload    string    `json:"payload"`
+	WrappedKey string    `json:"wrapped_key"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// UserPublicKey is the public key of a user to share items with.
+type UserPublicKey struct {
+	Login     string `json:"login"`
+	PublicKey string `json:"public_key"`
+	// LegacySealed is set while the private key of the user is sealed with the master password alone,
+	// which the server receives at login. Items must not be encrypted for such a key.
+	LegacySealed bool `json:"legacy_sealed,omitempty"`
+}
+
+// Keys returns the sharing keys of the user. It returns ErrNotFound if the user has none yet.
+func (c *Client) Keys(ctx context.Context) (*UserKeys, error) {
+	const op = "get keys"
+
+	rb, err := c.newAuthRequest(op, "/api/user/keys")
+	if err != nil {
+		return nil, err
+	}
+
+	var keys UserKeys
+
+	err = rb.
+		ToJSON(&keys).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return &keys, nil
+}
+
+// AddKeys stores the sharing keys of the user, or the private key sealed again for the same public key.
+// It returns ErrConflict if the user already has other keys.
+func (c *Client) AddKeys(ctx context.Context, keys UserKeys) error {
+	const op = "add keys"
+
+	rb, err := c.newAuthRequest(op, "/api/user/keys")
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&keys).
+		Method(http.MethodPut).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// PublicKey returns the public key of the user with the login. It returns ErrNotFound
+// if there is no such user or the user has no sharing keys.
+func (c *Client) PublicKey(ctx context.Context, login string) (*UserPublicKey, error) {
+	const op = "get public key"
+
+	rb, err := c.newAuthRequest(op, "/api/user/keys/"+url.PathEscape(login))
+	if err != nil {
+		return nil, err
+	}
+
+	var key UserPublicKey
+
+	err = rb.
+		ToJSON(&key).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return &key, nil
+}
+
+// PutShare shares the item with the recipients, replacing the previous ciphertext and recipients.
+func (c *Client) PutShare(ctx context.Context, share Share) error {
+	const op = "share item"
+
+	rb, err := c.newAuthRequest(op, "/api/user/share")
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&share).
+		Method(http.MethodPut).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// Shares returns the items shared by the user.
+func (c *Client) Shares(ctx context.Context) ([]OwnedShare, error) {
+	const op = "list shares"
+
+	rb, err := c.newAuthRequest(op, "/api/user/shares")
+	if err != nil {
+		return nil, err
+	}
+
+	var shares []OwnedShare
+
+	err = rb.
+		ToJSON(&shares).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return shares, nil
+}
+
+// DeleteShare stops sharing the item with everybody.
+func (c *Client) DeleteShare(ctx context.Context, kind ItemKind, title string) error {
+	const op = "delete share"
+
+	rb, err := c.newAuthRequest(op, "/api/user/share/"+string(kind)+"/"+url.PathEscape(title))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		Method(http.MethodDelete).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// SharedWithMe returns the items other users shared with the user.
+func (c *Client) SharedWithMe(ctx context.Context) ([]SharedItem, error) {
+	const op = "list shared items"
+
+	rb, err := c.newAuthRequest(op, "/api/user/shared")
+	if err != nil {
+		return nil, err
+	}
+
+	var items []SharedItem
+
+	err = rb.
+		ToJSON(&items).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return items, nil
+}
+
+// SharedItem returns the encrypted item the owner shared with the user.
+func (c *Client) SharedItem(ctx context.Context, owner string, kind ItemKind, title string) (*SharedPayload, error) {
+	const op = "get shared item"
+
+	rb, err := c.newAuthRequest(op, "/api/user/shared/"+url.PathEscape(owner)+"/"+string(kind)+"/"+url.PathEscape(title))
+	if err != nil {
+		return nil, err
+	}
+
+	var item SharedPayload
+
+	err = rb.
+		ToJSON(&item).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return &item, nil
+}
@@ -0,0 +1,144 @@
+package keeperclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type SSHKeyToGet struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	KeyType     string `json:"key_type"`    // KeyType is the SSH key type, e.g. ssh-ed25519.
+	PublicKey   string `json:"public_key"`  // PublicKey is the authorized_keys line.
+	Fingerprint string `json:"fingerprint"` // Fingerprint is the SHA256 fingerprint.
+	Confirm     bool   `json:"confirm"`     // Confirm asks the agent to confirm every use of the key.
+	ItemMeta
+}
+
+// SSHKeys returns the user SSH keys matching the options, without private keys.
+// Favorites go first. The next page cursor is returned, empty on the last page.
+func (c *Client) SSHKeys(ctx context.Context, opts ListOptions) ([]SSHKeyToGet, string, error) {
+	const op = "list ssh keys"
+
+	rb, err := c.newAuthRequest(op, "/api/user/sshkeys")
+	if err != nil {
+		return nil, "", err
+	}
+
+	var keys []SSHKeyToGet
+	headers := http.Header{}
+
+	err = opts.apply(rb).
+		ToJSON(&keys).
+		CopyHeaders(headers).
+		Method(http.MethodGet).
+		Fetch(ctx)
+	if err != nil {
+		return nil, "", wrapErr(op, err)
+	}
+	return keys, headers.Get(NextCursorHeader), nil
+}
+
+type SSHKeyToGetByTitle struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	KeyType     string `json:"key_type"`
+	PublicKey   string `json:"public_key"`
+	Fingerprint string `json:"fingerprint"`
+	PrivateKey  string `json:"private_key"` // PrivateKey is an unencrypted PEM in the OpenSSH format.
+	Confirm     bool   `json:"confirm"`
+}
+
+// SSHKey returns the SSH key with its private key by title.
+func (c *Client) SSHKey(ctx context.Context, title string) (*SSHKeyToGetByTitle, error) {
+	const op = "get ssh key"
+
+	rb, err := c.newAuthRequest(op, "/api/user/sshkey/"+url.PathEscape(title))
+	if err != nil {
+		return nil, err
+	}
+
+	var key SSHKeyToGetByTitle
+
+	err = rb.
+		Method(http.MethodGet).
+		ToJSON(&key).
+		Fetch(ctx)
+	if err != nil {
+		return nil, wrapErr(op, err)
+	}
+	return &key, nil
+}
+
+// SSHKeyToAdd is a new SSH key. The public key and the fingerprint are derived by the server.
+type SSHKeyToAdd struct {
+	Title       string `json:"title" validate:"required,min=1"`
+	Description string `json:"description" validate:"max=1000"`
+	PrivateKey  string `json:"private_key" validate:"required"` // PrivateKey is an unencrypted PEM.
+	Comment     string `json:"comment,omitempty" validate:"max=255"`
+	Confirm     bool   `json:"confirm"`
+}
+
+// AddSSHKey stores a new SSH key.
+func (c *Client) AddSSHKey(ctx context.Context, key SSHKeyToAdd) error {
+	const op = "add ssh key"
+
+	err := validate.Struct(key)
+	if err != nil {
+		return &Error{Op: op, Err: err}
+	}
+
+	rb, err := c.newAuthRequest(op, "/api/user/sshkey")
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&key).
+		Method(http.MethodPut).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// SSHKeyToUpdate changes the SSH key description and confirmation; nil fields are left unchanged.
+type SSHKeyToUpdate struct {
+	Description *string `json:"description,omitempty" validate:"omitnil,max=1000"`
+	Confirm     *bool   `json:"confirm,omitempty"`
+}
+
+// UpdateSSHKey updates the SSH key description and confirmation by title.
+func (c *Client) UpdateSSHKey(ctx context.Context, title string, key SSHKeyToUpdate) error {
+	const op = "update ssh key"
+
+	err := validate.Struct(key)
+	if err != nil {
+		return &Error{Op: op, Err: err}
+	}
+
+	rb, err := c.newAuthRequest(op, "/api/user/sshkey/update/"+url.PathEscape(title))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		BodyJSON(&key).
+		Method(http.MethodPost).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
+
+// DeleteSSHKey deletes the SSH key by title.
+func (c *Client) DeleteSSHKey(ctx context.Context, title string) error {
+	const op = "delete ssh key"
+
+	rb, err := c.newAuthRequest(op, "/api/user/sshkey/"+url.PathEscape(title))
+	if err != nil {
+		return err
+	}
+
+	err = rb.
+		Method(http.MethodDelete).
+		Fetch(ctx)
+	return wrapErr(op, err)
+}
@@ -0,0 +1,60 @@
+package keeperclient
+
+import (
+	"context"
+	"net/http"
+)
+
+type CustomerToReg struct {
+	Name           string `json:"name" validate:"required,min=1"`
+	Login          string `json:"login" validate:"required,email"`
+	MasterPassword string `json:"masterpassword" validate:"required,min=3"`
+	Authentication bool   `json:"signin"`
+}
+
+// Register registers a new user.
+func (c *Client) Register(ctx context.Context, customer CustomerToReg) error {
+	const op = "register"
+
+	err := validate.Struct(customer)
+	if err != nil {
+		return &Error{Op: op, Err: err}
+	}
+
+	err = c.newRequest("/api/user/register").
+		CheckStatus(http.StatusOK).
+		Method(http.MethodPost).
+		BodyJSON(&customer).
+		Fetch(ctx)
+
+	return wrapErr(op, err)
+}
+
+type CustomerToLogin struct {
+	Login    string `json:"login" validate:"required,email"`
+	Password string `json:"pwd" validate:"required,min=3"`
+}
+
+// Login authenticates the user and returns the JWT token ("Bearer ...") issued by the server.
+// The caller is responsible for storing the token and providing it back through the TokenSource.
+func (c *Client) Login(ctx context.Context, customer CustomerToLogin) (string, error) {
+	const op = "login"
+
+	err := validate.Struct(customer)
+	if err != nil {
+		return "", &Error{Op: op, Err: err}
+	}
+
+	headers := http.Header{}
+
+	err = c.newRequest("/api/user/login").
+		CopyHeaders(headers).
+		CheckStatus(http.StatusOK).
+		BodyJSON(&customer).
+		Fetch(ctx)
+	if err != nil {
+		return "", wrapErr(op, err)
+	}
+
+	return headers.Get("Authorization"), nil
+}
@@ -0,0 +1,64 @@
+package sharing
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+// Secret links are encrypted with AES-256-GCM rather than secretbox, so that the link page can decrypt
+// them in the browser with WebCrypto. The key is only kept in the fragment of the link, which browsers
+// do not send to the server.
+
+// NewLinkKey creates a new random link key, encoded for the URL fragment.
+func NewLinkKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(key), nil
+}
+
+func linkAEAD(linkKey string) (cipher.AEAD, error) {
+	key, err := base64.RawURLEncoding.DecodeString(linkKey)
+	if err != nil || len(key) != KeySize {
+		return nil, fmt.Errorf("invalid link key")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// SealLink encrypts the secret with the link key. The result is base64 of the nonce and the ciphertext.
+func SealLink(linkKey string, plain []byte) (string, error) {
+	aead, err := linkAEAD(linkKey)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plain, nil)), nil
+}
+
+// OpenLink decrypts the secret sealed by SealLink.
+func OpenLink(linkKey string, ciphertext string) ([]byte, error) {
+	aead, err := linkAEAD(linkKey)
+	if err != nil {
+		return nil, err
+	}
+	b, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(b) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	plain, err := aead.Open(nil, b[:aead.NonceSize()], b[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
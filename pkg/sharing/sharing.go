@@ -0,0 +1,288 @@
+// Package sharing holds the client-side cryptography of item sharing between users and of secret links.
+//
+// Every user has an X25519 key pair. The public key is kept on the server as is; the private key
+// is kept there sealed with the vault key. The master password alone is not enough to derive it:
+// the server receives the master password at every login. The vault key is derived from the master
+// password and the account key, a random key created with the key pair, which is kept on the devices
+// of the user and never sent. A shared item is encrypted with its own random item key, and the item key
+// is wrapped (sealed anonymously) for the public key of every recipient. The server stores only
+// ciphertexts and, without the account keys, can not read shared items.
+package sharing
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// KeySize is the size of public, private and item keys.
+const KeySize = 32
+
+const (
+	// sealedKeyVersion seals with the master password and the account key. Keys of legacyKeyVersion
+	// are sealed with the master password alone and can only be opened to be sealed again.
+	sealedKeyVersion = "v2"
+	legacyKeyVersion = "v1"
+	saltSize         = 16
+	nonceSize        = 24
+
+	// argon2id parameters of the vault key.
+	argonTime    = 3
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+)
+
+// ErrDecrypt is returned when a ciphertext can not be opened: the key or the master password is wrong,
+// or the data is damaged.
+var ErrDecrypt = errors.New("can not decrypt")
+
+// ErrLegacyKey is returned when the private key is sealed with the master password alone.
+// It is opened with OpenLegacyPrivateKey and must be sealed again with an account key.
+var ErrLegacyKey = errors.New("private key is sealed with the master password alone")
+
+// Key is a public, private or item key.
+type Key [KeySize]byte
+
+// KeyPair is the sharing key pair of a user.
+type KeyPair struct {
+	Public  Key
+	Private Key
+}
+
+// GenerateKeyPair creates a new random key pair.
+func GenerateKeyPair() (*KeyPair, error) {
+	pub, priv, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyPair{Public: *pub, Private: *priv}, nil
+}
+
+// Matches reports whether the private key belongs to the public one.
+func (kp *KeyPair) Matches() bool {
+	pub, err := curve25519.X25519(kp.Private[:], curve25519.Basepoint)
+	return err == nil && subtle.ConstantTimeCompare(pub, kp.Public[:]) == 1
+}
+
+// NewItemKey creates a new random item key.
+func NewItemKey() (Key, error) {
+	var key Key
+	_, err := rand.Read(key[:])
+	return key, err
+}
+
+// EncodeKey encodes a public key for the server.
+func EncodeKey(key Key) string {
+	return base64.StdEncoding.EncodeToString(key[:])
+}
+
+// DecodeKey decodes a public key received from the server.
+func DecodeKey(s string) (Key, error) {
+	var key Key
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(b) != KeySize {
+		return key, fmt.Errorf("invalid key")
+	}
+	copy(key[:], b)
+	return key, nil
+}
+
+// Fingerprint is a short form of the public key for users to compare out of band.
+func Fingerprint(pub Key) string {
+	sum := sha256.Sum256(pub[:])
+	return "SHA256:" + hex.EncodeToString(sum[:8])
+}
+
+// NewAccountKey creates a new random account key.
+func NewAccountKey() (Key, error) {
+	var key Key
+	_, err := rand.Read(key[:])
+	return key, err
+}
+
+const accountKeyPrefix = "GK2"
+
+var accountKeyEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// FormatAccountKey encodes the account key for the user to write down: "GK2-" and groups of base32.
+func FormatAccountKey(key Key) string {
+	s := accountKeyEncoding.EncodeToString(key[:])
+	groups := []string{accountKeyPrefix}
+	for len(s) > 0 {
+		n := min(len(s), 6)
+		groups = append(groups, s[:n])
+		s = s[n:]
+	}
+	return strings.Join(groups, "-")
+}
+
+// ParseAccountKey decodes the account key formatted by FormatAccountKey. Case, spaces and dashes
+// do not matter.
+func ParseAccountKey(s string) (Key, error) {
+	var key Key
+	s = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(s)))
+	rest, ok := strings.CutPrefix(s, accountKeyPrefix)
+	if !ok {
+		return key, fmt.Errorf("invalid account key")
+	}
+	b, err := accountKeyEncoding.DecodeString(rest)
+	if err != nil || len(b) != KeySize {
+		return key, fmt.Errorf("invalid account key")
+	}
+	copy(key[:], b)
+	return key, nil
+}
+
+// vaultKey derives the vault key from the master password and the account key. The server may know
+// the master password, so the key is bound to the account key it never receives.
+func vaultKey(masterPassword string, accountKey Key, salt []byte) Key {
+	passwordKey := legacyVaultKey(masterPassword, salt)
+	mac := hmac.New(sha256.New, accountKey[:])
+	mac.Write(passwordKey[:])
+
+	var key Key
+	copy(key[:], mac.Sum(nil))
+	return key
+}
+
+func legacyVaultKey(masterPassword string, salt []byte) Key {
+	var key Key
+	copy(key[:], argon2.IDKey([]byte(masterPassword), salt, argonTime, argonMemory, argonThreads, KeySize))
+	return key
+}
+
+func newNonce() (*[nonceSize]byte, error) {
+	var nonce [nonceSize]byte
+	_, err := rand.Read(nonce[:])
+	return &nonce, err
+}
+
+// SealPrivateKey encrypts the private key with the vault key derived from the master password
+// and the account key. The result is "v2$" followed by base64 of the salt, the nonce and the ciphertext.
+func SealPrivateKey(priv Key, masterPassword string, accountKey Key) (string, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := vaultKey(masterPassword, accountKey, salt)
+
+	nonce, err := newNonce()
+	if err != nil {
+		return "", err
+	}
+	out := append(salt, nonce[:]...)
+	out = secretbox.Seal(out, priv[:], nonce, (*[KeySize]byte)(&key))
+	return sealedKeyVersion + "$" + base64.StdEncoding.EncodeToString(out), nil
+}
+
+// IsLegacySealed reports whether the private key is sealed with the master password alone.
+func IsLegacySealed(sealed string) bool {
+	version, _, _ := strings.Cut(sealed, "$")
+	return version == legacyKeyVersion
+}
+
+// OpenPrivateKey decrypts the private key sealed by SealPrivateKey.
+// It returns ErrDecrypt if the master password or the account key is wrong,
+// and ErrLegacyKey if the key is sealed with the master password alone.
+func OpenPrivateKey(sealed string, masterPassword string, accountKey Key) (Key, error) {
+	if IsLegacySealed(sealed) {
+		return Key{}, ErrLegacyKey
+	}
+	return openPrivateKey(sealed, sealedKeyVersion, func(salt []byte) Key {
+		return vaultKey(masterPassword, accountKey, salt)
+	})
+}
+
+// OpenLegacyPrivateKey decrypts the private key sealed with the master password alone by older clients,
+// so that it is sealed again with SealPrivateKey.
+func OpenLegacyPrivateKey(sealed string, masterPassword string) (Key, error) {
+	return openPrivateKey(sealed, legacyKeyVersion, func(salt []byte) Key {
+		return legacyVaultKey(masterPassword, salt)
+	})
+}
+
+func openPrivateKey(sealed string, wantVersion string, vaultKey func(salt []byte) Key) (Key, error) {
+	var priv Key
+
+	version, data, ok := strings.Cut(sealed, "$")
+	if !ok || version != wantVersion {
+		return priv, fmt.Errorf("unknown private key format")
+	}
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil || len(b) < saltSize+nonceSize {
+		return priv, ErrDecrypt
+	}
+
+	key := vaultKey(b[:saltSize])
+	var nonce [nonceSize]byte
+	copy(nonce[:], b[saltSize:saltSize+nonceSize])
+
+	plain, ok := secretbox.Open(nil, b[saltSize+nonceSize:], &nonce, (*[KeySize]byte)(&key))
+	if !ok || len(plain) != KeySize {
+		return priv, ErrDecrypt
+	}
+	copy(priv[:], plain)
+	return priv, nil
+}
+
+// Encrypt encrypts the item with the item key. The result is base64 of the nonce and the ciphertext.
+func Encrypt(itemKey Key, plain []byte) (string, error) {
+	nonce, err := newNonce()
+	if err != nil {
+		return "", err
+	}
+	out := secretbox.Seal(nonce[:], plain, nonce, (*[KeySize]byte)(&itemKey))
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// Decrypt decrypts the item encrypted by Encrypt.
+func Decrypt(itemKey Key, ciphertext string) ([]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(b) < nonceSize {
+		return nil, ErrDecrypt
+	}
+	var nonce [nonceSize]byte
+	copy(nonce[:], b[:nonceSize])
+
+	plain, ok := secretbox.Open(nil, b[nonceSize:], &nonce, (*[KeySize]byte)(&itemKey))
+	if !ok {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
+
+// WrapKey encrypts the item key for the recipient, so that only the owner of the private key can open it.
+func WrapKey(itemKey Key, recipient Key) (string, error) {
+	out, err := box.SealAnonymous(nil, itemKey[:], (*[KeySize]byte)(&recipient), rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// UnwrapKey decrypts the item key wrapped for the key pair.
+func UnwrapKey(wrapped string, keys *KeyPair) (Key, error) {
+	var itemKey Key
+	b, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return itemKey, ErrDecrypt
+	}
+	plain, ok := box.OpenAnonymous(nil, b, (*[KeySize]byte)(&keys.Public), (*[KeySize]byte)(&keys.Private))
+	if !ok || len(plain) != KeySize {
+		return itemKey, ErrDecrypt
+	}
+	copy(itemKey[:], plain)
+	return itemKey, nil
+}
@@ -0,0 +1,157 @@
+package sharing
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+func TestPrivateKey(t *testing.T) {
+	keys, err := GenerateKeyPair()
+	require.NoError(t, err)
+	accountKey, err := NewAccountKey()
+	require.NoError(t, err)
+
+	sealed, err := SealPrivateKey(keys.Private, "master", accountKey)
+	require.NoError(t, err)
+	require.NotContains(t, sealed, EncodeKey(keys.Private))
+	require.False(t, IsLegacySealed(sealed))
+
+	priv, err := OpenPrivateKey(sealed, "master", accountKey)
+	require.NoError(t, err)
+	require.Equal(t, keys.Private, priv)
+
+	_, err = OpenPrivateKey(sealed, "wrong", accountKey)
+	require.ErrorIs(t, err, ErrDecrypt)
+
+	// the server knows the master password, but not the account key
+	otherKey, err := NewAccountKey()
+	require.NoError(t, err)
+	_, err = OpenPrivateKey(sealed, "master", otherKey)
+	require.ErrorIs(t, err, ErrDecrypt)
+	_, err = OpenLegacyPrivateKey(sealed, "master")
+	require.Error(t, err)
+}
+
+func TestLegacyPrivateKey(t *testing.T) {
+	keys, err := GenerateKeyPair()
+	require.NoError(t, err)
+
+	// older clients sealed the key with the master password alone
+	salt := make([]byte, saltSize)
+	nonce, err := newNonce()
+	require.NoError(t, err)
+	key := legacyVaultKey("master", salt)
+	out := secretbox.Seal(append(salt, nonce[:]...), keys.Private[:], nonce, (*[KeySize]byte)(&key))
+	sealed := legacyKeyVersion + "$" + base64.StdEncoding.EncodeToString(out)
+	require.True(t, IsLegacySealed(sealed))
+
+	accountKey, err := NewAccountKey()
+	require.NoError(t, err)
+	_, err = OpenPrivateKey(sealed, "master", accountKey)
+	require.ErrorIs(t, err, ErrLegacyKey)
+
+	priv, err := OpenLegacyPrivateKey(sealed, "master")
+	require.NoError(t, err)
+	require.Equal(t, keys.Private, priv)
+}
+
+func TestAccountKey(t *testing.T) {
+	key, err := NewAccountKey()
+	require.NoError(t, err)
+
+	formatted := FormatAccountKey(key)
+	require.True(t, strings.HasPrefix(formatted, "GK2-"))
+
+	parsed, err := ParseAccountKey(formatted)
+	require.NoError(t, err)
+	require.Equal(t, key, parsed)
+
+	// case, spaces and dashes do not matter
+	parsed, err = ParseAccountKey(" " + strings.ToLower(strings.ReplaceAll(formatted, "-", " ")) + "\n")
+	require.NoError(t, err)
+	require.Equal(t, key, parsed)
+
+	for _, bad := range []string{"", "GK2-ABC", strings.TrimPrefix(formatted, "GK2-"), formatted + "AB"} {
+		_, err := ParseAccountKey(bad)
+		require.Error(t, err, bad)
+	}
+}
+
+func TestShareItem(t *testing.T) {
+	ane, err := GenerateKeyPair()
+	require.NoError(t, err)
+	bob, err := GenerateKeyPair()
+	require.NoError(t, err)
+
+	itemKey, err := NewItemKey()
+	require.NoError(t, err)
+	ciphertext, err := Encrypt(itemKey, []byte(`{"password":"secret"}`))
+	require.NoError(t, err)
+
+	wrapped, err := WrapKey(itemKey, bob.Public)
+	require.NoError(t, err)
+
+	got, err := UnwrapKey(wrapped, bob)
+	require.NoError(t, err)
+	plain, err := Decrypt(got, ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, `{"password":"secret"}`, string(plain))
+
+	// the key wrapped for bob is useless to anybody else
+	_, err = UnwrapKey(wrapped, ane)
+	require.ErrorIs(t, err, ErrDecrypt)
+
+	// a rotated item key does not open the new ciphertext with the old key
+	newKey, err := NewItemKey()
+	require.NoError(t, err)
+	rotated, err := Encrypt(newKey, plain)
+	require.NoError(t, err)
+	_, err = Decrypt(itemKey, rotated)
+	require.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestDecodeKey(t *testing.T) {
+	keys, err := GenerateKeyPair()
+	require.NoError(t, err)
+
+	pub, err := DecodeKey(EncodeKey(keys.Public))
+	require.NoError(t, err)
+	require.Equal(t, keys.Public, pub)
+
+	_, err = DecodeKey("c2hvcnQ=")
+	require.Error(t, err)
+}
+
+func TestLink(t *testing.T) {
+	key, err := NewLinkKey()
+	require.NoError(t, err)
+
+	ciphertext, err := SealLink(key, []byte("s3cret"))
+	require.NoError(t, err)
+
+	plain, err := OpenLink(key, ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", string(plain))
+
+	other, err := NewLinkKey()
+	require.NoError(t, err)
+	_, err = OpenLink(other, ciphertext)
+	require.ErrorIs(t, err, ErrDecrypt)
+
+	_, err = OpenLink("short", ciphertext)
+	require.Error(t, err)
+}
+
+func TestKeyPairMatches(t *testing.T) {
+	keys, err := GenerateKeyPair()
+	require.NoError(t, err)
+	require.True(t, keys.Matches())
+
+	other, err := GenerateKeyPair()
+	require.NoError(t, err)
+	require.False(t, (&KeyPair{Public: keys.Public, Private: other.Private}).Matches())
+}